@@ -2,32 +2,105 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/robfig/cron/v3"
+
+	"github.com/thomas/mavt/internal/appstore"
 	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/demo"
+	"github.com/thomas/mavt/internal/discover"
+	"github.com/thomas/mavt/internal/doctor"
+	"github.com/thomas/mavt/internal/federation"
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/internal/importer"
+	"github.com/thomas/mavt/internal/mdm"
 	"github.com/thomas/mavt/internal/notifier"
+	"github.com/thomas/mavt/internal/recovery"
+	"github.com/thomas/mavt/internal/replay"
+	"github.com/thomas/mavt/internal/report"
 	"github.com/thomas/mavt/internal/server"
 	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/telemetry"
 	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
 	"github.com/thomas/mavt/internal/version"
+	"github.com/thomas/mavt/pkg/models"
 )
 
 var (
 	addApp         = flag.String("add", "", "Add an app to track by bundle ID")
+	searchAdd      = flag.String("search", "", "Search the App Store by name, pick a result and track it")
 	listApps       = flag.Bool("list", false, "List all tracked apps")
-	checkNow       = flag.Bool("check", false, "Check for updates immediately")
+	checkNow       = flag.Bool("check", false, "Check for updates immediately (exit 0 no updates, 10 updates found, 1 error)")
+	checkOutput    = flag.String("output", "text", "Output for -check: text or json (the updates found, on stdout)")
 	runDaemon      = flag.Bool("daemon", false, "Run as a daemon (continuous monitoring)")
 	showUpdates    = flag.String("updates", "", "Show version history for a bundle ID")
 	recentDuration = flag.String("recent", "", "Show recent updates (e.g., '24h', '7d')")
+	recentVendor   = flag.String("vendor", "", "With -recent, only show updates to apps from this vendor (developer name)")
+	recentBundleID = flag.String("bundle-id", "", "With -recent, only show updates to this app")
+	recentUnacked  = flag.Bool("unacknowledged", false, "With -recent, only show updates nobody has acknowledged")
+	recentAssignee = flag.String("assignee", "", "With -recent, only show updates assigned to this person")
+	recentTag      = flag.String("tag", "", "With -recent, only show updates to apps in applications with one of these comma-separated tags")
+	recentSeverity = flag.String("severity", "", "With -recent, only show updates of these comma-separated severities (critical, major, minor, patch)")
 	showVersion    = flag.Bool("version", false, "Show version information")
+	appCountry     = flag.String("country", "", "Storefront country for -add and -search (e.g., 'JP'), overrides MAVT_COUNTRY")
+	appLang        = flag.String("lang", "", "Release notes language for -add and -search (e.g., 'ja_jp'), overrides MAVT_LANGUAGE")
+	parseRaw       = flag.String("parse-raw", "", "Parse an archived raw API response (.json.gz) and print the result")
+	importCSV      = flag.String("import", "", "Import apps to track from an MDM/CSV export")
+	backfillCSV    = flag.String("backfill", "", "Backfill past versions from a CSV (bundle ID, version, release date, notes), tracking apps not yet tracked; with -add, only for that app")
+	dryRun         = flag.Bool("dry-run", false, "Preview -import or -discover without tracking anything")
+	discoverApps   = flag.Bool("discover", false, "Find Mac App Store apps installed in /Applications and offer to track them")
+	assumeYes      = flag.Bool("yes", false, "Answer yes to prompts (e.g., track everything found by -discover)")
+	healthcheck    = flag.Bool("healthcheck", false, "Check the running daemon's /readyz endpoint and exit non-zero if unhealthy")
+	healthStorage  = flag.Bool("healthcheck-storage", false, "With -healthcheck, check storage directly instead of the daemon")
+	archiveApp     = flag.String("archive", "", "Stop tracking an app but keep its history")
+	unarchiveApp   = flag.String("unarchive", "", "Restore an archived app")
+	purgeApp       = flag.String("purge", "", "Permanently delete an app and all its history")
+	labelApp       = flag.String("label", "", "Set an app's display name and notes from -display-name and -notes (omitted values are cleared)")
+	displayName    = flag.String("display-name", "", "Custom display name for -label")
+	appNotes       = flag.String("notes", "", "Free-form notes for -label (e.g., 'Used by Sales, contact J. Doe')")
+	showArchived   = flag.Bool("archived", false, "With -list, list archived apps instead")
+	reportSince    = flag.String("report", "", "Print a changelog report of updates in this period (e.g., '7d', '30d')")
+	reportFormat   = flag.String("format", "md", "Format for -report: md or html")
+	reportGroupBy  = flag.String("group-by", "", "Group -report by application (product) instead of listing apps, or summarize it per application tag (e.g. competitors) with 'tag'")
+	runDoctor      = flag.Bool("doctor", false, "Diagnose common problems (permissions, storage, connectivity, clock, config)")
+	validateConfig = flag.Bool("validate-config", false, "Validate configuration and exit (0 valid, 1 invalid, 2 valid with warnings)")
+	printConfig    = flag.Bool("print-config", false, "Print the effective configuration with secrets masked")
+	migrateTo      = flag.String("migrate", "", "Copy all stored data to another data directory and verify the copy")
+	migrateKey     = flag.String("migrate-key-file", "", "With -migrate, encrypt the copy with this key file")
+	encryptData    = flag.Bool("encrypt-data", false, "Rewrite all stored records so they are encrypted with MAVT_ENCRYPTION_KEY_FILE")
+	dedupeHistory  = flag.Bool("dedupe-history", false, "Remove duplicate version updates from stored history")
+	syncUpstreams  = flag.String("sync-upstreams", "", "Pull apps and updates within this period (e.g., '7d') from MAVT_UPSTREAMS once and exit")
+	mergeApps      = flag.Bool("merge", false, "Merge the history of a renamed app: -merge <old-bundle-id> <new-bundle-id>")
+	replayDir      = flag.String("replay", "", "Run check cycles against recorded lookup responses in this directory (e.g., data/raw) instead of the App Store, printing what would be notified")
+)
+
+// Leases held by the leading replica when MAVT_LEADER_ELECTION is enabled
+const (
+	checkLease        = "check"
+	reportLease       = "report"
+	upstreamSyncLease = "upstream-sync"
+	releaseLease      = "release-check"
 )
 
+// releaseCheckInterval is how often the daemon looks for a newer MAVT release
+const releaseCheckInterval = 24 * time.Hour
+
 func main() {
 	flag.Parse()
 
@@ -43,82 +116,659 @@ func main() {
 		return
 	}
 
+	// Replay an archived response against the parser (no config or storage needed)
+	if *parseRaw != "" {
+		handleParseRaw(*parseRaw)
+		return
+	}
+
+	// Diagnostics run before config loading so a broken config is reported, not fatal
+	if *runDoctor {
+		handleDoctor()
+		return
+	}
+
+	// Check configuration before a deployment or restart
+	if *validateConfig {
+		handleValidateConfig()
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	if *printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
+		}
+		return
+	}
+
+	// Tune the transport shared by every outbound HTTP client
+	userAgent := cfg.HTTPUserAgent
+	if userAgent == "" {
+		userAgent = "mavt/" + version.Version
+	}
+	if err := httpclient.Configure(httpclient.Options{
+		Timeout:       cfg.HTTPTimeout,
+		MaxIdleConns:  cfg.HTTPMaxIdleConns,
+		TLSMinVersion: cfg.HTTPTLSMinVersion,
+		CAFile:        cfg.HTTPCAFile,
+		UserAgent:     userAgent,
+		RateLimits:    cfg.RateLimits(),
+	}); err != nil {
+		log.Fatalf("Failed to configure HTTP client: %v", err)
+	}
+
+	// Health probe for Docker/Kubernetes; exits before any other setup
+	if *healthcheck {
+		handleHealthcheck(cfg, *healthStorage)
+		return
+	}
+
+	// Replays use temporary storage and send nothing, so they run before setup
+	if *replayDir != "" {
+		handleReplay(cfg, *replayDir)
+		return
+	}
+
+	// Report recovered panics to Sentry if configured
+	if err := recovery.Init(cfg.SentryDSN); err != nil {
+		log.Printf("Panic reporting disabled: %v", err)
+	}
+	defer recovery.Flush()
+
+	// Initialize tracing (no-op unless OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := telemetry.Setup(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+	if telemetry.Enabled() {
+		log.Printf("OpenTelemetry tracing enabled")
+	}
+
+	// Demo mode runs the daemon on sample data in memory instead of real storage
+	if cfg.Demo && *runDaemon {
+		handleDemo(cfg)
+		return
+	}
+
 	// Initialize storage
-	store, err := storage.NewStorage(cfg.DataDir)
+	store, err := storage.Open(cfg.DataDir, cfg.EncryptionKeyFile)
 	if err != nil {
 		log.Fatalf("Failed to initialize storage: %v", err)
 	}
+	store.SetHistoryCompression(cfg.HistoryCompressAfter)
 
 	// Initialize notifier
 	notify := notifier.NewNotifier(cfg.AppriseURL)
-	if notify.IsEnabled() {
-		log.Printf("Notifications enabled via Apprise")
+	if cfg.AppriseURL != "" {
+		log.Printf("Notifications enabled via Apprise (%s mode)", cfg.AppriseMode)
+	}
+	notify.SetAppriseMode(cfg.AppriseMode)
+	targets := make([]notifier.Target, 0, len(cfg.AppriseTargets))
+	for _, target := range cfg.AppriseTargets {
+		targets = append(targets, notifier.Target{URL: target.URL, Mode: target.Mode})
+	}
+	notify.SetTargets(targets)
+	if cfg.WebhookURL != "" {
+		webhook := notifier.NewWebhook(cfg.WebhookURL, cfg.WebhookFormat)
+		webhook.SetSecret(cfg.WebhookSecret)
+		webhook.SetEvents(cfg.WebhookEvents)
+		webhook.SetDeliveryHandler(func(delivery *models.WebhookDelivery) {
+			if err := store.SaveWebhookDelivery(delivery); err != nil {
+				log.Printf("Failed to log webhook delivery: %v", err)
+			}
+		})
+		notify.SetWebhook(webhook)
+		log.Printf("Outbound webhook enabled (%s format)", cfg.WebhookFormat)
 	}
+	notify.SetPublicURL(cfg.PublicURL)
+	notify.SetNotesDisplay(cfg.TranslateNotify)
+	notify.SetLanguage(cfg.UILanguage)
+	notify.SetVendors(func(developer string) *models.Vendor {
+		vendor, err := store.GetVendor(developer)
+		if err != nil {
+			log.Printf("Failed to get vendor %q: %v", developer, err)
+		}
+		return vendor
+	})
 
 	// Initialize tracker
 	tr := tracker.NewTracker(cfg, store, notify)
+	var deployer *mdm.Deployer
+	if pusher := mdm.New(cfg); pusher != nil {
+		deployer = mdm.NewDeployer(cfg.MDMProvider, pusher, cfg.MDMGroups, tr.RecordDeployment)
+		tr.Events().Subscribe(deployer.HandleEvent, models.EventApprovedUpdate)
+		log.Printf("Approved updates are pushed via %s", cfg.MDMProvider)
+	}
 
 	// Handle commands
 	switch {
 	case *addApp != "":
-		handleAddApp(tr, *addApp)
+		handleAddApp(tr, *addApp, *appCountry, *appLang)
+		if *backfillCSV != "" {
+			handleBackfill(tr, *backfillCSV, *addApp)
+		}
+	case *searchAdd != "":
+		handleSearchAdd(tr, cfg, *searchAdd, *appCountry, *appLang)
+	case *archiveApp != "":
+		handleArchive(tr, *archiveApp)
+	case *unarchiveApp != "":
+		handleUnarchive(tr, *unarchiveApp)
+	case *purgeApp != "":
+		handlePurge(tr, *purgeApp)
+	case *labelApp != "":
+		handleLabel(tr, *labelApp, *displayName, *appNotes)
+	case *migrateTo != "":
+		handleMigrate(store, *migrateTo, *migrateKey)
+	case *encryptData:
+		handleEncryptData(store, cfg)
+	case *dedupeHistory:
+		handleDedupeHistory(store)
+	case *mergeApps:
+		handleMerge(tr, flag.Args())
+	case *syncUpstreams != "":
+		handleSyncUpstreams(tr, cfg, *syncUpstreams)
+	case *backfillCSV != "":
+		handleBackfill(tr, *backfillCSV, "")
+	case *importCSV != "":
+		handleImport(tr, *importCSV, *dryRun)
+	case *discoverApps:
+		handleDiscover(tr, *dryRun, *assumeYes)
 	case *listApps:
-		handleListApps(tr)
+		handleListApps(tr, *showArchived)
 	case *showUpdates != "":
 		handleShowUpdates(store, *showUpdates)
+	case *reportSince != "":
+		handleReport(tr, *reportSince, *reportFormat, *reportGroupBy)
 	case *recentDuration != "":
-		handleRecentUpdates(store, *recentDuration)
+		handleRecentUpdates(tr, *recentDuration, tracker.UpdateFilter{
+			BundleIDs:      config.ParseList(*recentBundleID),
+			Vendors:        nonEmpty(strings.TrimSpace(*recentVendor)),
+			Tags:           config.ParseList(*recentTag),
+			Severities:     config.ParseList(*recentSeverity),
+			Unacknowledged: *recentUnacked,
+			Assignee:       strings.TrimSpace(*recentAssignee),
+		})
 	case *checkNow:
-		handleCheckNow(tr)
+		handleCheck(context.Background(), tr, *checkOutput)
 	case *runDaemon:
-		handleDaemon(tr, cfg)
+		handleDaemon(tr, cfg, deployer)
 	default:
 		// If apps are specified in config, track them on startup
-		if len(cfg.Apps) > 0 {
-			log.Printf("Tracking %d apps from configuration", len(cfg.Apps))
-			for _, bundleID := range cfg.Apps {
-				if err := tr.TrackApp(bundleID); err != nil {
-					log.Printf("Error tracking %s: %v", bundleID, err)
-				}
-			}
-		}
+		syncConfiguredApps(tr, cfg)
 
 		// Default: check once and exit
-		handleCheckNow(tr)
+		handleCheckNow(context.Background(), tr)
 	}
 }
 
-func handleAddApp(tr *tracker.Tracker, bundleID string) {
+// syncConfiguredApps tracks the apps listed in MAVT_APPS. In managed mode it
+// also stops tracking any app that is no longer listed.
+func syncConfiguredApps(tr *tracker.Tracker, cfg *config.Config) {
+	if len(cfg.Apps) == 0 {
+		return
+	}
+
+	log.Printf("Tracking %d apps from configuration", len(cfg.Apps))
+	for _, bundleID := range cfg.Apps {
+		locale := cfg.AppLocales[bundleID]
+		err := tr.TrackAppWithLocale(bundleID, locale.Country, locale.Language)
+		if err != nil && !errors.Is(err, tracker.ErrAlreadyTracked) {
+			log.Printf("Error tracking %s: %v", bundleID, err)
+		}
+	}
+
+	if cfg.AppsMode != config.AppsModeManaged {
+		return
+	}
+
+	removed, err := tr.ReconcileApps(cfg.Apps)
+	if err != nil {
+		log.Printf("Error reconciling tracked apps with configuration: %v", err)
+	}
+	for _, bundleID := range removed {
+		log.Printf("Archived %s (not in MAVT_APPS)", bundleID)
+	}
+}
+
+func handleDoctor() {
+	cfg, err := config.Load()
+	findings := doctor.Run(context.Background(), cfg, err)
+
+	icons := map[string]string{doctor.StatusOK: "✅", doctor.StatusWarn: "⚠️ ", doctor.StatusFail: "❌"}
+	for _, finding := range findings {
+		fmt.Printf("%s %-10s %s\n", icons[finding.Status], finding.Check, finding.Message)
+		if finding.Hint != "" {
+			fmt.Printf("   %-10s → %s\n", "", finding.Hint)
+		}
+	}
+
+	if doctor.Failed(findings) {
+		os.Exit(1)
+	}
+}
+
+func handleValidateConfig() {
+	_, err := config.Load()
+	warnings := config.Warnings()
+
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		fmt.Println("Configuration is invalid")
+		os.Exit(1)
+	}
+	if len(warnings) > 0 {
+		fmt.Printf("Configuration is valid with %d warning(s)\n", len(warnings))
+		os.Exit(2)
+	}
+	fmt.Println("Configuration is valid")
+}
+
+func handleHealthcheck(cfg *config.Config, storageOnly bool) {
+	if storageOnly {
+		// Open without migrating: the probe runs beside the server and
+		// doesn't have its encryption key or lease
+		store, err := storage.OpenReadOnly(cfg.DataDir)
+		if err == nil {
+			err = store.Ping()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("healthy")
+		return
+	}
+
+	// The server usually binds all interfaces; probe it over loopback
+	host := cfg.ServerHost
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://%s/readyz", net.JoinHostPort(host, strconv.Itoa(cfg.ServerPort))))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "unhealthy: /readyz returned status %d\n", resp.StatusCode)
+		os.Exit(1)
+	}
+	fmt.Println("healthy")
+}
+
+func handleAddApp(tr *tracker.Tracker, bundleID, country, lang string) {
 	log.Printf("Adding app to tracking: %s", bundleID)
-	if err := tr.TrackApp(bundleID); err != nil {
+	err := tr.TrackAppWithLocale(bundleID, country, lang)
+	if errors.Is(err, tracker.ErrAlreadyTracked) {
+		log.Println("App is already tracked")
+		return
+	}
+	if err != nil {
 		log.Fatalf("Failed to add app: %v", err)
 	}
 	log.Println("App successfully added to tracking")
 }
 
-func handleListApps(tr *tracker.Tracker) {
+// searchResultLimit is how many App Store matches -search offers to track
+const searchResultLimit = 10
+
+func handleSearchAdd(tr *tracker.Tracker, cfg *config.Config, term, country, lang string) {
+	// Search the chosen storefront, but only store the locale on the app when
+	// it was given explicitly so the app otherwise follows the defaults
+	searchCountry, searchLang := country, lang
+	if searchCountry == "" {
+		searchCountry = cfg.Country
+	}
+	if searchLang == "" {
+		searchLang = cfg.Language
+	}
+
+	results, err := appstore.NewClientWithLocale(searchCountry, searchLang).SearchApps(term, searchResultLimit)
+	if err != nil {
+		log.Fatalf("Failed to search the App Store: %v", err)
+	}
+	if len(results) == 0 {
+		fmt.Printf("No apps found for %q\n", term)
+		return
+	}
+
+	fmt.Printf("Found %d apps for %q:\n\n", len(results), term)
+	for i, app := range results {
+		fmt.Printf("  %2d. %s by %s\n      %s v%s\n", i+1, app.TrackName, app.ArtistName, app.BundleID, app.Version)
+	}
+	fmt.Println()
+
+	fmt.Printf("Track which app? [1-%d, Enter to cancel] ", len(results))
+	var answer string
+	fmt.Scanln(&answer)
+	if strings.TrimSpace(answer) == "" {
+		fmt.Println("Nothing tracked")
+		return
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(results) {
+		log.Fatalf("Invalid selection: %s", answer)
+	}
+
+	handleAddApp(tr, results[choice-1].BundleID, country, lang)
+}
+
+func handleArchive(tr *tracker.Tracker, bundleID string) {
+	if err := tr.RemoveApp(bundleID); err != nil {
+		log.Fatalf("Failed to archive app: %v", err)
+	}
+	fmt.Printf("Archived %s (history kept; restore with -unarchive)\n", bundleID)
+}
+
+func handleUnarchive(tr *tracker.Tracker, bundleID string) {
+	if err := tr.UnarchiveApp(bundleID); err != nil {
+		log.Fatalf("Failed to unarchive app: %v", err)
+	}
+	fmt.Printf("Restored %s\n", bundleID)
+}
+
+func handlePurge(tr *tracker.Tracker, bundleID string) {
+	if err := tr.PurgeApp(bundleID); err != nil {
+		log.Fatalf("Failed to purge app: %v", err)
+	}
+	fmt.Printf("Permanently deleted %s and its history\n", bundleID)
+}
+
+func handleLabel(tr *tracker.Tracker, bundleID, displayName, notes string) {
+	if err := tr.SetAppLabel(bundleID, displayName, notes); err != nil {
+		log.Fatalf("Failed to label app: %v", err)
+	}
+	if displayName == "" && notes == "" {
+		fmt.Printf("Cleared display name and notes for %s\n", bundleID)
+		return
+	}
+	fmt.Printf("Updated display name and notes for %s\n", bundleID)
+}
+
+func handleMigrate(store *storage.Storage, targetDir, keyFile string) {
+	target, err := storage.Open(targetDir, keyFile)
+	if err != nil {
+		log.Fatalf("Failed to open target storage: %v", err)
+	}
+
+	stats, err := store.CopyTo(target)
+	if err != nil {
+		log.Fatalf("Failed to migrate data: %v", err)
+	}
+	fmt.Printf("Copied %d files, with %d apps, %d version updates, %d reviews and %d snapshots, to %s (verified)\n",
+		stats.Files, stats.Apps, stats.Updates, stats.Reviews, stats.Snapshots, targetDir)
+}
+
+func handleEncryptData(store *storage.Storage, cfg *config.Config) {
+	if cfg.EncryptionKeyFile == "" {
+		log.Fatalf("MAVT_ENCRYPTION_KEY_FILE must be set to encrypt stored data")
+	}
+
+	written, err := store.RewriteRecords()
+	if err != nil {
+		log.Fatalf("Failed to encrypt stored data: %v", err)
+	}
+	fmt.Printf("Encrypted %d stored record(s)\n", written)
+}
+
+func handleDedupeHistory(store *storage.Storage) {
+	removed, err := store.DedupeVersionUpdates()
+	if err != nil {
+		log.Fatalf("Failed to dedupe version history: %v", err)
+	}
+	fmt.Printf("Removed %d duplicate version update(s)\n", removed)
+}
+
+func handleSyncUpstreams(tr *tracker.Tracker, cfg *config.Config, sinceStr string) {
+	if len(cfg.Upstreams) == 0 {
+		log.Fatalf("No upstream instances configured (set MAVT_UPSTREAMS)")
+	}
+
+	since, err := config.ParseDuration(sinceStr)
+	if err != nil {
+		log.Fatalf("Invalid duration format: %v", err)
+	}
+
+	for _, upstream := range cfg.Upstreams {
+		syncUpstream(context.Background(), tr, federation.NewClient(upstream), since)
+	}
+}
+
+func handleMerge(tr *tracker.Tracker, args []string) {
+	if len(args) != 2 {
+		log.Fatalf("Usage: mavt -merge <old-bundle-id> <new-bundle-id>")
+	}
+
+	if err := tr.MergeApps(args[0], args[1]); err != nil {
+		log.Fatalf("Failed to merge apps: %v", err)
+	}
+	fmt.Printf("Merged history of %s into %s\n", args[0], args[1])
+}
+
+// handleBackfill adds past versions from a history CSV to each app in it, or
+// only to onlyBundleID if set. Apps that aren't tracked yet are tracked first.
+func handleBackfill(tr *tracker.Tracker, path, onlyBundleID string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open backfill file: %v", err)
+	}
+	defer file.Close()
+
+	history, err := importer.ParseHistoryCSV(file)
+	if err != nil {
+		log.Fatalf("Failed to parse backfill file: %v", err)
+	}
+
+	bundleIDs := make([]string, 0, len(history))
+	for bundleID := range history {
+		if onlyBundleID == "" || bundleID == onlyBundleID {
+			bundleIDs = append(bundleIDs, bundleID)
+		}
+	}
+	sort.Strings(bundleIDs)
+	if len(bundleIDs) == 0 {
+		fmt.Println("No versions to backfill")
+		return
+	}
+
 	apps, err := tr.GetTrackedApps()
 	if err != nil {
 		log.Fatalf("Failed to get tracked apps: %v", err)
 	}
+	tracked := make(map[string]bool)
+	for _, app := range apps {
+		tracked[app.BundleID] = true
+	}
+
+	total := 0
+	for _, bundleID := range bundleIDs {
+		if !tracked[bundleID] {
+			if err := tr.TrackApp(bundleID); err != nil {
+				log.Printf("Error tracking %s: %v", bundleID, err)
+				continue
+			}
+		}
+
+		added, err := tr.BackfillHistory(bundleID, history[bundleID])
+		if err != nil {
+			log.Printf("Error backfilling %s: %v", bundleID, err)
+			continue
+		}
+		fmt.Printf("  %s: %d of %d version(s) added\n", bundleID, added, len(history[bundleID]))
+		total += added
+	}
+
+	fmt.Printf("\nBackfilled %d version(s) for %d app(s)\n", total, len(bundleIDs))
+}
+
+func handleImport(tr *tracker.Tracker, path string, dryRun bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open import file: %v", err)
+	}
+	defer file.Close()
+
+	entries, err := importer.ParseCSV(file)
+	if err != nil {
+		log.Fatalf("Failed to parse import file: %v", err)
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: resolving %d apps (nothing will be tracked)\n\n", len(entries))
+	} else {
+		fmt.Printf("Importing %d apps\n\n", len(entries))
+	}
+
+	results, err := importer.Run(tr, entries, dryRun)
+	if err != nil {
+		log.Fatalf("Failed to import apps: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+
+		source := result.BundleID
+		if source == "" {
+			source = result.Name
+		}
+
+		if result.Error != "" {
+			fmt.Printf("  row %d: %s -> %s (%s)\n", result.Row, source, result.Status, result.Error)
+			continue
+		}
+		fmt.Printf("  row %d: %s -> %s %s v%s [%s]\n", result.Row, source,
+			result.ResolvedName, result.ResolvedBundleID, result.Version, result.Status)
+	}
+
+	fmt.Printf("\n%d tracked, %d would track, %d already tracked, %d not found, %d failed\n",
+		counts[importer.StatusTracked], counts[importer.StatusWouldTrack], counts[importer.StatusAlreadyTracked],
+		counts[importer.StatusNotFound], counts[importer.StatusFailed])
+}
+
+func handleDiscover(tr *tracker.Tracker, dryRun, assumeYes bool) {
+	found, err := discover.Scan(discover.DefaultDir)
+	if err != nil {
+		log.Fatalf("Failed to scan for installed apps: %v", err)
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("No Mac App Store apps found in %s\n", discover.DefaultDir)
+		return
+	}
+
+	fmt.Printf("Found %d Mac App Store apps in %s:\n\n", len(found), discover.DefaultDir)
+	for _, app := range found {
+		fmt.Printf("  %s (%s) v%s\n", app.Name, app.BundleID, app.Version)
+	}
+	fmt.Println()
+
+	if dryRun {
+		return
+	}
+
+	if !assumeYes {
+		fmt.Printf("Track all %d apps? [y/N] ", len(found))
+		var answer string
+		fmt.Scanln(&answer)
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Println("Nothing tracked")
+			return
+		}
+	}
+
+	tracked := 0
+	for _, app := range found {
+		err := tr.TrackApp(app.BundleID)
+		if errors.Is(err, tracker.ErrAlreadyTracked) {
+			log.Printf("%s is already tracked", app.BundleID)
+			continue
+		}
+		if err != nil {
+			log.Printf("Error tracking %s: %v", app.BundleID, err)
+			continue
+		}
+		tracked++
+	}
+
+	fmt.Printf("Tracked %d of %d apps\n", tracked, len(found))
+}
+
+func handleListApps(tr *tracker.Tracker, archived bool) {
+	getApps := tr.GetTrackedApps
+	if archived {
+		getApps = tr.GetArchivedApps
+	}
+
+	apps, err := getApps()
+	if err != nil {
+		log.Fatalf("Failed to get tracked apps: %v", err)
+	}
 
 	if len(apps) == 0 {
-		fmt.Println("No apps are currently being tracked")
+		if archived {
+			fmt.Println("No apps are archived")
+		} else {
+			fmt.Println("No apps are currently being tracked")
+		}
 		return
 	}
 
-	fmt.Printf("Tracking %d apps:\n\n", len(apps))
+	if archived {
+		fmt.Printf("%d archived apps:\n\n", len(apps))
+	} else {
+		fmt.Printf("Tracking %d apps:\n\n", len(apps))
+	}
 	for _, app := range apps {
-		fmt.Printf("📱 %s\n", app.TrackName)
+		fmt.Printf("📱 %s\n", app.Name())
+		if app.DisplayName != "" {
+			fmt.Printf("   App Store Name: %s\n", app.TrackName)
+		}
+		if app.Notes != "" {
+			fmt.Printf("   Notes: %s\n", app.Notes)
+		}
 		fmt.Printf("   Bundle ID: %s\n", app.BundleID)
 		fmt.Printf("   Version: %s\n", app.Version)
 		fmt.Printf("   Developer: %s\n", app.ArtistName)
+		if app.Genre != "" {
+			fmt.Printf("   Genre: %s\n", app.Genre)
+		}
+		if app.ContentRating != "" {
+			fmt.Printf("   Content Rating: %s\n", app.ContentRating)
+		}
+		if app.Country != "" || app.Language != "" {
+			fmt.Printf("   Storefront: %s %s\n", app.Country, app.Language)
+		}
+		if federation.IsUpstreamSource(app.Source) {
+			fmt.Printf("   Synced From: %s\n", strings.TrimPrefix(app.Source, federation.SourcePrefix))
+		}
+		if app.MovedTo != "" {
+			fmt.Printf("   ⚠️  Moved to: %s (run: mavt -merge %s %s)\n", app.MovedTo, app.BundleID, app.MovedTo)
+		}
+		if app.ArchivedAt != nil {
+			fmt.Printf("   Archived: %s\n", app.ArchivedAt.Format(time.RFC1123))
+		}
 		fmt.Printf("   Last Checked: %s\n", app.LastChecked.Format(time.RFC1123))
 		fmt.Printf("   Tracking Since: %s\n\n", app.FirstDiscovered.Format(time.RFC1123))
 	}
@@ -135,9 +785,16 @@ func handleShowUpdates(store *storage.Storage, bundleID string) {
 		return
 	}
 
-	fmt.Printf("Version history for %s:\n\n", bundleID)
+	fmt.Printf("Version history for %s:\n", bundleID)
+	if cadence := models.ReleaseCadence(updates); cadence > 0 {
+		fmt.Printf("Releases a new version every %.1f days on average\n", cadence.Hours()/24)
+	}
+	fmt.Println()
 	for _, update := range updates {
-		fmt.Printf("🔄 %s -> %s (%s)\n", update.OldVersion, update.NewVersion, update.UpdatedAt.Format(time.RFC1123))
+		fmt.Printf("🔄 %s (detected %s)\n", update.Change(), update.UpdatedAt.Format(time.RFC1123))
+		if update.ReleasedAt != nil {
+			fmt.Printf("   Released: %s\n", update.ReleasedAt.Format(time.RFC1123))
+		}
 		if update.ReleaseNotes != "" {
 			fmt.Printf("   Release Notes: %s\n", update.ReleaseNotes)
 		}
@@ -145,17 +802,42 @@ func handleShowUpdates(store *storage.Storage, bundleID string) {
 	}
 }
 
-func handleRecentUpdates(store *storage.Storage, durationStr string) {
-	duration, err := time.ParseDuration(durationStr)
+// nonEmpty returns a list of value, or nil if it is empty
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+func handleRecentUpdates(tr *tracker.Tracker, durationStr string, filter tracker.UpdateFilter) {
+	duration, err := config.ParseDuration(durationStr)
 	if err != nil {
 		log.Fatalf("Invalid duration format: %v", err)
 	}
+	for _, severity := range filter.Severities {
+		if !slices.Contains(models.Severities, severity) {
+			log.Fatalf("Invalid -severity: %s (must be %s)", severity, strings.Join(models.Severities, ", "))
+		}
+	}
 
-	updates, err := store.GetRecentUpdates(duration)
+	updates, err := tr.GetRecentUpdates(duration)
 	if err != nil {
 		log.Fatalf("Failed to get recent updates: %v", err)
 	}
 
+	matcher, err := tr.NewUpdateMatcher(filter)
+	if err != nil {
+		log.Fatalf("Failed to look up vendors and tags: %v", err)
+	}
+	matched := updates[:0]
+	for _, update := range updates {
+		if matcher.Matches(update) {
+			matched = append(matched, update)
+		}
+	}
+	updates = matched
+
 	if len(updates) == 0 {
 		fmt.Printf("No updates found in the last %s\n", durationStr)
 		return
@@ -163,30 +845,202 @@ func handleRecentUpdates(store *storage.Storage, durationStr string) {
 
 	fmt.Printf("Updates in the last %s:\n\n", durationStr)
 	for _, update := range updates {
-		fmt.Printf("🔄 %s: %s -> %s (%s)\n",
-			update.TrackName, update.OldVersion, update.NewVersion,
-			update.UpdatedAt.Format(time.RFC1123))
+		fmt.Printf("🔄 %s: %s (%s)\n",
+			update.Name(), update.Change(), update.UpdatedAt.Format(time.RFC1123))
 	}
 }
 
-func handleCheckNow(tr *tracker.Tracker) {
-	log.Println("Checking for updates...")
-	updates, err := tr.CheckForUpdates()
+func handleReport(tr *tracker.Tracker, sinceStr, format, groupBy string) {
+	since, err := config.ParseDuration(sinceStr)
+	if err != nil {
+		log.Fatalf("Invalid duration format: %v", err)
+	}
+	if groupBy != "" && groupBy != config.ReportGroupByApplication && groupBy != config.ReportGroupByTag {
+		log.Fatalf("Invalid -group-by: %s (must be application or tag)", groupBy)
+	}
+
+	updates, err := tr.GetRecentUpdates(since)
+	if err != nil {
+		log.Fatalf("Failed to get recent updates: %v", err)
+	}
+
+	now := time.Now()
+	var rpt report.Document = report.New(updates, now.Add(-since), now)
+	if groupBy != "" {
+		applications, err := getApplications(tr)
+		if err != nil {
+			log.Fatalf("Failed to get applications: %v", err)
+		}
+		if groupBy == config.ReportGroupByTag {
+			apps, err := tr.GetTrackedApps()
+			if err != nil {
+				log.Fatalf("Failed to get apps: %v", err)
+			}
+			rpt = report.NewByTag(updates, now.Add(-since), now, apps, applications)
+		} else {
+			rpt = report.NewByApplication(updates, now.Add(-since), now, applications)
+		}
+	}
+	if err := rpt.Write(os.Stdout, format); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+// getApplications returns the applications to group a report by
+func getApplications(tr *tracker.Tracker) ([]models.Application, error) {
+	statuses, err := tr.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+	applications := make([]models.Application, 0, len(statuses))
+	for _, status := range statuses {
+		applications = append(applications, status.Application)
+	}
+	return applications, nil
+}
+
+func handleParseRaw(path string) {
+	body, err := storage.ReadRawResponse(path)
+	if err != nil {
+		log.Fatalf("Failed to read raw response: %v", err)
+	}
+
+	app, err := appstore.ParseLookupResponse(body)
+	if err != nil {
+		log.Fatalf("Failed to parse raw response: %v", err)
+	}
+	if app == nil {
+		fmt.Println("Response contains no results")
+		return
+	}
+
+	data, err := json.MarshalIndent(app, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to format parsed app: %v", err)
+	}
+	fmt.Println(string(data))
+}
+
+func handleReplay(cfg *config.Config, dir string) {
+	source, err := replay.LoadFixtures(dir)
+	if err != nil {
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	cycles, err := replay.Run(context.Background(), cfg, source)
+	if err != nil {
+		log.Fatalf("Replay failed: %v", err)
+	}
+
+	updates, notifications := 0, 0
+	for _, cycle := range cycles {
+		if cycle.Number == 0 {
+			fmt.Printf("Cycle 0: tracked %d app(s)\n", len(cycle.Tracked))
+			for _, failure := range cycle.Errors {
+				fmt.Printf("  error: %s\n", failure)
+			}
+		} else {
+			fmt.Printf("Cycle %d: %d update(s)\n", cycle.Number, len(cycle.Updates))
+		}
+		for _, update := range cycle.Updates {
+			fmt.Printf("  update: %s: %s\n", update.Name(), update.Change())
+		}
+		for _, event := range cycle.Events {
+			if event.Type == models.EventVersionUpdate {
+				fmt.Printf("  notify %s: %d update(s)\n", event.Type, len(event.Updates))
+			} else {
+				fmt.Printf("  notify %s: %s\n", event.Type, event.Summary)
+			}
+		}
+		for _, alert := range cycle.Alerts {
+			fmt.Printf("  notify %s\n", alert)
+		}
+		updates += len(cycle.Updates)
+		notifications += len(cycle.Events) + len(cycle.Alerts)
+	}
+
+	fmt.Printf("\nReplayed %d cycle(s): %d update(s), %d notification(s)\n", len(cycles), updates, notifications)
+}
+
+func handleCheckNow(ctx context.Context, tr *tracker.Tracker) {
+	if _, err := runCheck(ctx, tr); err != nil {
+		log.Fatalf("Failed to check for updates: %v", err)
+	}
+}
+
+// exitUpdatesFound is the -check exit code when updates were found, so cron
+// jobs and CI pipelines can branch on the result
+const exitUpdatesFound = 10
+
+// handleCheck runs -check: a single check cycle that exits 0 when there are no
+// updates, exitUpdatesFound when there are, and 1 on errors. With json output
+// the updates found are written to stdout.
+func handleCheck(ctx context.Context, tr *tracker.Tracker, output string) {
+	if output != "text" && output != "json" {
+		log.Fatalf("Invalid -output %q: must be text or json", output)
+	}
+
+	updates, err := runCheck(ctx, tr)
 	if err != nil {
 		log.Fatalf("Failed to check for updates: %v", err)
 	}
 
+	if output == "json" {
+		if updates == nil {
+			updates = []models.VersionUpdate{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(updates); err != nil {
+			log.Fatalf("Failed to write updates: %v", err)
+		}
+	}
+
+	if len(updates) > 0 {
+		os.Exit(exitUpdatesFound)
+	}
+}
+
+// runCheck runs a single check cycle, logs any updates found and returns them
+func runCheck(ctx context.Context, tr *tracker.Tracker) ([]models.VersionUpdate, error) {
+	log.Println("Checking for updates...")
+	updates, err := tr.CheckForUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(updates) == 0 {
 		log.Println("No updates found")
 	} else {
 		log.Printf("Found %d update(s):", len(updates))
 		for _, update := range updates {
-			log.Printf("  - %s: %s -> %s", update.TrackName, update.OldVersion, update.NewVersion)
+			log.Printf("  - %s: %s", update.Name(), update.Change())
 		}
 	}
+
+	return updates, nil
+}
+
+func handleDemo(cfg *config.Config) {
+	cfg = demo.Config(cfg)
+
+	sample := demo.New(time.Now().UnixNano())
+	store := trackertest.NewMemStore()
+	if err := sample.Seed(store, time.Now()); err != nil {
+		log.Fatalf("Failed to seed demo data: %v", err)
+	}
+
+	// Nothing is sent in demo mode
+	tr := tracker.NewTracker(cfg, store, notifier.NewNotifier(""))
+	tr.SetSource(sample.Source())
+
+	log.Printf("Demo mode: serving sample apps from memory, publishing a fake release every %s", demo.ReleaseInterval)
+	go sample.Run(context.Background(), demo.ReleaseInterval)
+
+	handleDaemon(tr, cfg, nil)
 }
 
-func handleDaemon(tr *tracker.Tracker, cfg *config.Config) {
+func handleDaemon(tr *tracker.Tracker, cfg *config.Config, deployer *mdm.Deployer) {
 	log.Printf("MAVT v%s - Starting daemon mode (check interval: %s)", version.Version, cfg.CheckInterval)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -202,28 +1056,341 @@ func handleDaemon(tr *tracker.Tracker, cfg *config.Config) {
 		cancel()
 	}()
 
+	// Load every tracked app into the cache so the UI is served from stored
+	// data straight away, before any App Store lookups
+	apps, err := tr.GetTrackedApps()
+	if err != nil {
+		log.Printf("Failed to load tracked apps: %v", err)
+	} else {
+		log.Printf("Loaded %d tracked apps from storage", len(apps))
+	}
+
+	// Email changelog reports on their own schedule, separate from update notifications
+	if len(cfg.ReportRecipients) > 0 {
+		mailer := notifier.NewMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		go runReportSchedule(ctx, tr, mailer, cfg)
+	}
+
+	// Aggregate apps and updates from other MAVT instances
+	if len(cfg.Upstreams) > 0 {
+		go runUpstreamSync(ctx, tr, cfg)
+	}
+
 	// Start HTTP server in a goroutine
-	srv := server.NewServer(tr, cfg.CheckInterval)
+	// Checks started from the web interface or API, at most one queued
+	checkNow := make(chan struct{}, 1)
+
+	srv := server.NewServer(tr, cfg)
+	srv.SetCheckTrigger(func() bool {
+		select {
+		case checkNow <- struct{}{}:
+			return true
+		default:
+			return false
+		}
+	})
+	if deployer != nil {
+		srv.SetDeployer(deployer)
+	}
+	if cfg.ReleaseCheck {
+		releases := version.NewReleaseChecker()
+		srv.SetReleaseChecker(releases)
+		go runReleaseCheck(ctx, tr, releases, cfg.ReleaseNotify)
+	}
 	go func() {
 		if err := srv.Start(cfg.ServerHost, cfg.ServerPort); err != nil {
 			log.Printf("HTTP server error: %v", err)
 		}
 	}()
 
-	// Initial check
-	handleCheckNow(tr)
+	// Apply MAVT_APPS before the first check; this looks each app up, so it
+	// runs after the HTTP server has started. Check progress is at /api/status.
+	syncConfiguredApps(tr, cfg)
 
-	// Periodic checks
-	ticker := time.NewTicker(cfg.CheckInterval)
+	// Keep the check loop running even if it panics outside a single app check
+	for {
+		err := recovery.Run("check loop", func() error {
+			checkLoop(ctx, tr, cfg.CheckInterval, checkNow)
+			return nil
+		})
+		if ctx.Err() != nil {
+			log.Println("Daemon stopped")
+			return
+		}
+
+		log.Printf("Check loop crashed, restarting in 10s: %v", err)
+		select {
+		case <-ctx.Done():
+			log.Println("Daemon stopped")
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// runUpstreamSync pulls apps and updates from each upstream instance once per
+// sync interval until ctx is cancelled. Each sync fetches updates from the last
+// two intervals so a missed or slow sync doesn't drop any.
+func runUpstreamSync(ctx context.Context, tr *tracker.Tracker, cfg *config.Config) {
+	log.Printf("Syncing from %d upstream instance(s) every %s", len(cfg.Upstreams), cfg.UpstreamSyncInterval)
+
+	clients := make([]*federation.Client, 0, len(cfg.Upstreams))
+	for _, upstream := range cfg.Upstreams {
+		clients = append(clients, federation.NewClient(upstream))
+	}
+
+	ticker := time.NewTicker(cfg.UpstreamSyncInterval)
 	defer ticker.Stop()
 
 	for {
+		if tr.Lead(upstreamSyncLease, 2*cfg.UpstreamSyncInterval) {
+			for _, client := range clients {
+				syncUpstream(ctx, tr, client, 2*cfg.UpstreamSyncInterval)
+			}
+		}
+
 		select {
 		case <-ctx.Done():
-			log.Println("Daemon stopped")
 			return
 		case <-ticker.C:
-			handleCheckNow(tr)
 		}
 	}
 }
+
+// runReleaseCheck looks for a newer MAVT release once a day until ctx is
+// cancelled. With notify set, the leading replica sends one notification per
+// new release.
+func runReleaseCheck(ctx context.Context, tr *tracker.Tracker, releases *version.ReleaseChecker, notify bool) {
+	ticker := time.NewTicker(releaseCheckInterval)
+	defer ticker.Stop()
+
+	notified := ""
+	for {
+		release, err := releases.Check(ctx)
+		if err != nil {
+			log.Printf("Failed to check for a new MAVT release: %v", err)
+		} else if release != nil && release.Version != notified {
+			log.Printf("MAVT %s is available (running %s): %s", release.Version, version.Version, release.URL)
+			if notify && tr.Lead(releaseLease, 2*releaseCheckInterval) {
+				tr.NotifyRelease(release)
+			}
+			notified = release.Version
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncUpstream pulls from one upstream instance, logging the outcome
+func syncUpstream(ctx context.Context, tr *tracker.Tracker, client *federation.Client, since time.Duration) {
+	err := recovery.Run("upstream sync "+client.Source(), func() error {
+		apps, updates, err := tr.SyncUpstream(ctx, client, since)
+		if err != nil {
+			return err
+		}
+		log.Printf("Synced %d apps and %d new update(s) from %s", apps, updates, client.Source())
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to sync from %s: %v", client.Source(), err)
+	}
+}
+
+// runReportSchedule emails a changelog report, grouped by vendor, to the report
+// recipients each time the cron schedule fires until ctx is cancelled
+func runReportSchedule(ctx context.Context, tr *tracker.Tracker, mailer *notifier.Mailer, cfg *config.Config) {
+	schedule, err := cron.ParseStandard(cfg.ReportSchedule)
+	if err != nil {
+		log.Printf("Scheduled reports disabled: invalid schedule: %v", err)
+		return
+	}
+	log.Printf("Scheduled report emails enabled (%s) for %d recipient(s)", cfg.ReportSchedule, len(cfg.ReportRecipients))
+
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Replicas fire together; the first to take the lease sends the report
+		if !tr.Lead(reportLease, time.Hour) {
+			continue
+		}
+
+		if err := recovery.Run("report email", func() error {
+			return sendReport(tr, mailer, cfg.ReportRecipients, cfg.ReportPeriod, cfg.ReportGroupBy)
+		}); err != nil {
+			log.Printf("Failed to send scheduled report: %v", err)
+		}
+	}
+}
+
+// sendReport emails an HTML changelog of updates within period, grouped by
+// vendor or by application
+func sendReport(tr *tracker.Tracker, mailer *notifier.Mailer, recipients []string, period time.Duration, groupBy string) error {
+	updates, err := tr.GetRecentUpdates(period)
+	if err != nil {
+		return fmt.Errorf("failed to get recent updates: %w", err)
+	}
+
+	rpt, err := groupReport(tr, updates, period, groupBy)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	if err := rpt.Write(&body, report.FormatHTML); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	now := time.Now()
+	subject := fmt.Sprintf("MAVT: %d app updates (%s to %s)", rpt.AppCount(),
+		now.Add(-period).Format("2006-01-02"), now.Format("2006-01-02"))
+	if err := mailer.SendHTML(recipients, subject, body.String()); err != nil {
+		return err
+	}
+
+	log.Printf("Sent scheduled report (%d apps updated) to %d recipient(s)", rpt.AppCount(), len(recipients))
+	return nil
+}
+
+// groupReport builds the scheduled report of updates within period, grouped
+// by application, summarized by application tag, or grouped by vendor with
+// each vendor's recorded details
+func groupReport(tr *tracker.Tracker, updates []models.VersionUpdate, period time.Duration, groupBy string) (report.Document, error) {
+	now := time.Now()
+	switch groupBy {
+	case config.ReportGroupByApplication, config.ReportGroupByTag:
+		applications, err := getApplications(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get applications: %w", err)
+		}
+		if groupBy == config.ReportGroupByApplication {
+			return report.NewByApplication(updates, now.Add(-period), now, applications), nil
+		}
+		apps, err := tr.GetTrackedApps()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get apps: %w", err)
+		}
+		return report.NewByTag(updates, now.Add(-period), now, apps, applications), nil
+	}
+
+	vendors := make(map[string]string)
+	for _, getApps := range []func() ([]*models.AppInfo, error){tr.GetTrackedApps, tr.GetArchivedApps} {
+		apps, err := getApps()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get apps: %w", err)
+		}
+		for _, app := range apps {
+			vendors[app.BundleID] = app.ArtistName
+		}
+	}
+
+	vendorDetails, err := tr.GetVendors()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vendors: %w", err)
+	}
+	detailsByName := make(map[string]*models.Vendor, len(vendorDetails))
+	for i := range vendorDetails {
+		detailsByName[strings.ToLower(vendorDetails[i].Name)] = &vendorDetails[i]
+	}
+
+	return report.NewByVendor(updates, now.Add(-period), now, func(bundleID string) string {
+		return vendors[bundleID]
+	}).WithVendors(func(name string) *models.Vendor {
+		return detailsByName[strings.ToLower(name)]
+	}), nil
+}
+
+// checkLoop runs an initial check and then one check per interval until ctx is
+// cancelled, plus one whenever checkNow receives. Failed cycles are logged and
+// retried on the next tick.
+func checkLoop(ctx context.Context, tr *tracker.Tracker, interval time.Duration, checkNow <-chan struct{}) {
+	leading := false
+	check := func() {
+		// With leader election, only the replica holding the check lease runs
+		// cycles. The lease outlives one interval so the leader keeps it, and
+		// expires after two so a standby takes over if the leader dies.
+		wasLeading := leading
+		leading = tr.Lead(checkLease, 2*interval)
+		if leading != wasLeading {
+			if leading {
+				log.Printf("Acquired check lease; this instance now runs checks")
+			} else {
+				log.Printf("Check lease held by another instance; standing by")
+			}
+		}
+		if leading {
+			runDaemonCheck(ctx, tr)
+		}
+	}
+
+	// Resume the schedule from before a restart rather than checking every app at boot
+	if wait := resumeDelay(tr, interval); wait > 0 {
+		log.Printf("Last check cycle was recent; next check in %s", wait.Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		case <-checkNow:
+		}
+	}
+	check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if leading {
+				tr.ReleaseLead(checkLease)
+			}
+			return
+		case <-ticker.C:
+			check()
+		case <-checkNow:
+			log.Println("Check requested through the API")
+			check()
+			ticker.Reset(interval)
+		}
+	}
+}
+
+// resumeDelay returns how long to wait before the first check so that it runs
+// one interval after the last completed cycle, which may predate a restart
+func resumeDelay(tr *tracker.Tracker, interval time.Duration) time.Duration {
+	state, err := tr.SchedulerState()
+	if err != nil {
+		log.Printf("Failed to load scheduler state: %v", err)
+		return 0
+	}
+	if state.LastCycleEnd.IsZero() {
+		return 0
+	}
+
+	wait := time.Until(state.LastCycleEnd.Add(interval))
+	if wait > interval {
+		// The clock went backwards; don't wait longer than one interval
+		return interval
+	}
+	return wait
+}
+
+// runDaemonCheck runs a check cycle, logging failures and panics instead of exiting
+func runDaemonCheck(ctx context.Context, tr *tracker.Tracker) {
+	if err := recovery.Run("check cycle", func() error {
+		_, err := runCheck(ctx, tr)
+		return err
+	}); err != nil {
+		log.Printf("Check cycle failed: %v", err)
+	}
+}
@@ -1,34 +1,59 @@
 package appstore
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/internal/telemetry"
 	"github.com/thomas/mavt/pkg/models"
 )
 
 const (
-	lookupURL = "https://itunes.apple.com/lookup"
-	searchURL = "https://itunes.apple.com/search"
+	lookupURL  = "https://itunes.apple.com/lookup"
+	searchURL  = "https://itunes.apple.com/search"
+	reviewsURL = "https://itunes.apple.com/%s/rss/customerreviews/id=%d/sortby=mostrecent/json"
 )
 
+// ErrNotFound is returned when a lookup has no results
+var ErrNotFound = errors.New("app not found")
+
 // Client handles communication with the App Store API
 type Client struct {
 	httpClient *http.Client
 	country    string
+	lang       string
+	rawHandler RawResponseHandler
+}
+
+// RawResponseHandler receives the raw JSON body of every successful bundle ID lookup
+type RawResponseHandler func(bundleID string, body []byte)
+
+// newHTTPClient returns a traced client on the shared transport, limited to
+// the "appstore" source's rate limit
+func newHTTPClient() *http.Client {
+	client := httpclient.NewForSource("appstore", 30*time.Second)
+	client.Transport = otelhttp.NewTransport(client.Transport)
+	return client
 }
 
 // NewClient creates a new App Store API client
 func NewClient() *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		country: "us",
+		httpClient: newHTTPClient(),
+		country:    "us",
 	}
 }
 
@@ -38,62 +63,164 @@ func NewClientWithCountry(country string) *Client {
 		country = "us"
 	}
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		country: country,
+		httpClient: newHTTPClient(),
+		country:    country,
 	}
 }
 
+// NewClientWithLocale creates a new App Store API client with a specific country and
+// language (e.g. "ja_jp"), so release notes are returned in that language
+func NewClientWithLocale(country, lang string) *Client {
+	c := NewClientWithCountry(country)
+	c.lang = lang
+	return c
+}
+
+// SetRawResponseHandler registers a handler that receives raw lookup responses,
+// e.g. to archive them for debugging parser issues
+func (c *Client) SetRawResponseHandler(handler RawResponseHandler) {
+	c.rawHandler = handler
+}
+
 // iTunesResponse represents the response from iTunes API
 type iTunesResponse struct {
-	ResultCount int          `json:"resultCount"`
-	Results     []iTunesApp  `json:"results"`
+	ResultCount int         `json:"resultCount"`
+	Results     []iTunesApp `json:"results"`
 }
 
 // iTunesApp represents an app in the iTunes API response
 type iTunesApp struct {
-	TrackID              int64     `json:"trackId"`
-	BundleID             string    `json:"bundleId"`
-	TrackName            string    `json:"trackName"`
-	Version              string    `json:"version"`
-	CurrentVersionReleaseDate string `json:"currentVersionReleaseDate"`
-	ReleaseNotes         string    `json:"releaseNotes"`
-	ArtistName           string    `json:"artistName"`
-	MinimumOsVersion     string    `json:"minimumOsVersion"`
-	FileSizeBytes        string    `json:"fileSizeBytes"`
-	Price                float64   `json:"price"`
-	Currency             string    `json:"currency"`
+	TrackID                            int64    `json:"trackId"`
+	BundleID                           string   `json:"bundleId"`
+	TrackName                          string   `json:"trackName"`
+	Version                            string   `json:"version"`
+	CurrentVersionReleaseDate          string   `json:"currentVersionReleaseDate"`
+	ReleaseNotes                       string   `json:"releaseNotes"`
+	ArtistName                         string   `json:"artistName"`
+	MinimumOsVersion                   string   `json:"minimumOsVersion"`
+	FileSizeBytes                      string   `json:"fileSizeBytes"`
+	Price                              float64  `json:"price"`
+	Currency                           string   `json:"currency"`
+	PrimaryGenreName                   string   `json:"primaryGenreName"`
+	ContentAdvisoryRating              string   `json:"contentAdvisoryRating"`
+	SupportedDevices                   []string `json:"supportedDevices"`
+	LanguageCodes                      []string `json:"languageCodesISO2A"`
+	AverageUserRating                  float64  `json:"averageUserRating"`
+	UserRatingCount                    int64    `json:"userRatingCount"`
+	AverageUserRatingForCurrentVersion float64  `json:"averageUserRatingForCurrentVersion"`
+	UserRatingCountForCurrentVersion   int64    `json:"userRatingCountForCurrentVersion"`
+	Description                        string   `json:"description"`
+	ScreenshotURLs                     []string `json:"screenshotUrls"`
+	IPadScreenshotURLs                 []string `json:"ipadScreenshotUrls"`
+	ArtworkURL100                      string   `json:"artworkUrl100"`
 }
 
 // LookupByBundleID fetches app information by bundle ID
 func (c *Client) LookupByBundleID(bundleID string) (*models.AppInfo, error) {
+	return c.LookupByBundleIDWithLocale(context.Background(), bundleID, "", "")
+}
+
+// LookupByBundleIDWithLocale fetches app information by bundle ID from a specific
+// storefront and language, falling back to the client defaults when empty
+func (c *Client) LookupByBundleIDWithLocale(ctx context.Context, bundleID, country, lang string) (*models.AppInfo, error) {
+	ctx, span := telemetry.StartSpan(ctx, "appstore.lookup", trace.WithAttributes(
+		attribute.String("app.bundle_id", bundleID),
+	))
+	defer span.End()
+
+	if country == "" {
+		country = c.country
+	}
+	if lang == "" {
+		lang = c.lang
+	}
+
 	params := url.Values{}
 	params.Add("bundleId", bundleID)
 	params.Add("entity", "software")
+	params.Add("country", country)
+	if lang != "" {
+		params.Add("lang", lang)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", lookupURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, requestError("fetch app info", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if c.rawHandler != nil {
+		c.rawHandler(bundleID, body)
+	}
+
+	app, err := ParseLookupResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, bundleID)
+	}
+
+	return app, nil
+}
+
+// Ping checks that the iTunes lookup API is reachable and returns the server
+// time from the response's Date header (zero if missing)
+func (c *Client) Ping(ctx context.Context) (time.Time, error) {
+	params := url.Values{}
+	params.Add("bundleId", "com.apple.Pages")
 	params.Add("country", c.country)
 
-	resp, err := c.httpClient.Get(lookupURL + "?" + params.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", lookupURL+"?"+params.Encode(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch app info: %w", err)
+		return time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to reach App Store API: %w", err)
 	}
 	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return time.Time{}, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	serverTime, _ := http.ParseTime(resp.Header.Get("Date"))
+	return serverTime, nil
+}
+
+// ParseLookupResponse parses a raw iTunes lookup response body and returns the
+// first result, or nil if the response has no results. It is exported so that
+// archived responses can be replayed against the parser.
+func ParseLookupResponse(body []byte) (*models.AppInfo, error) {
+	if err := checkBody(body); err != nil {
+		return nil, err
 	}
 
 	var itunesResp iTunesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&itunesResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(body, &itunesResp); err != nil {
+		return nil, decodeError(err)
 	}
 
-	if itunesResp.ResultCount == 0 {
-		return nil, fmt.Errorf("app not found: %s", bundleID)
+	if itunesResp.ResultCount == 0 || len(itunesResp.Results) == 0 {
+		return nil, nil
 	}
 
-	return c.convertToAppInfo(itunesResp.Results[0])
+	return convertToAppInfo(itunesResp.Results[0])
 }
 
 // LookupByTrackID fetches app information by track ID
@@ -102,28 +229,31 @@ func (c *Client) LookupByTrackID(trackID int64) (*models.AppInfo, error) {
 	params.Add("id", fmt.Sprintf("%d", trackID))
 	params.Add("entity", "software")
 	params.Add("country", c.country)
+	if c.lang != "" {
+		params.Add("lang", c.lang)
+	}
 
 	resp, err := c.httpClient.Get(lookupURL + "?" + params.Encode())
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch app info: %w", err)
+		return nil, requestError("fetch app info", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
 	}
 
 	var itunesResp iTunesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&itunesResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(body, &itunesResp); err != nil {
+		return nil, decodeError(err)
 	}
 
 	if itunesResp.ResultCount == 0 {
-		return nil, fmt.Errorf("app not found: %d", trackID)
+		return nil, fmt.Errorf("%w: %d", ErrNotFound, trackID)
 	}
 
-	return c.convertToAppInfo(itunesResp.Results[0])
+	return convertToAppInfo(itunesResp.Results[0])
 }
 
 // SearchApps searches for apps by name/term
@@ -140,26 +270,29 @@ func (c *Client) SearchApps(term string, limit int) ([]*models.AppInfo, error) {
 	params.Add("entity", "software")
 	params.Add("country", c.country)
 	params.Add("limit", fmt.Sprintf("%d", limit))
+	if c.lang != "" {
+		params.Add("lang", c.lang)
+	}
 
 	resp, err := c.httpClient.Get(searchURL + "?" + params.Encode())
 	if err != nil {
-		return nil, fmt.Errorf("failed to search apps: %w", err)
+		return nil, requestError("search apps", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
 	}
 
 	var itunesResp iTunesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&itunesResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(body, &itunesResp); err != nil {
+		return nil, decodeError(err)
 	}
 
 	var apps []*models.AppInfo
 	for _, itunesApp := range itunesResp.Results {
-		app, err := c.convertToAppInfo(itunesApp)
+		app, err := convertToAppInfo(itunesApp)
 		if err != nil {
 			continue
 		}
@@ -169,8 +302,95 @@ func (c *Client) SearchApps(term string, limit int) ([]*models.AppInfo, error) {
 	return apps, nil
 }
 
+// reviewsFeed represents the customer reviews RSS feed in JSON form
+type reviewsFeed struct {
+	Feed struct {
+		Entry json.RawMessage `json:"entry"`
+	} `json:"feed"`
+}
+
+// feedLabel is the {"label": "..."} wrapper used by every field in the feed
+type feedLabel struct {
+	Label string `json:"label"`
+}
+
+// reviewEntry represents a single review in the customer reviews feed
+type reviewEntry struct {
+	ID     feedLabel `json:"id"`
+	Author struct {
+		Name feedLabel `json:"name"`
+	} `json:"author"`
+	Title   feedLabel `json:"title"`
+	Content feedLabel `json:"content"`
+	Rating  feedLabel `json:"im:rating"`
+	Version feedLabel `json:"im:version"`
+	Updated feedLabel `json:"updated"`
+}
+
+// FetchReviews fetches the most recent customer reviews for an app by track ID
+// from a specific storefront, falling back to the client default when empty
+func (c *Client) FetchReviews(trackID int64, country string) ([]models.Review, error) {
+	if country == "" {
+		country = c.country
+	}
+
+	resp, err := c.httpClient.Get(fmt.Sprintf(reviewsURL, country, trackID))
+	if err != nil {
+		return nil, requestError("fetch reviews", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed reviewsFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, decodeError(err)
+	}
+
+	// The feed returns a single object instead of an array when there is only one entry
+	var entries []reviewEntry
+	if len(feed.Feed.Entry) > 0 {
+		if err := json.Unmarshal(feed.Feed.Entry, &entries); err != nil {
+			var single reviewEntry
+			if err := json.Unmarshal(feed.Feed.Entry, &single); err != nil {
+				return nil, fmt.Errorf("failed to decode review entries: %w", err)
+			}
+			entries = []reviewEntry{single}
+		}
+	}
+
+	var reviews []models.Review
+	for _, entry := range entries {
+		rating, err := strconv.Atoi(entry.Rating.Label)
+		if err != nil {
+			// Entries without a rating are app metadata, not reviews
+			continue
+		}
+
+		updatedAt, err := time.Parse(time.RFC3339, entry.Updated.Label)
+		if err != nil {
+			updatedAt = time.Now()
+		}
+
+		reviews = append(reviews, models.Review{
+			ID:        entry.ID.Label,
+			Author:    entry.Author.Name.Label,
+			Title:     entry.Title.Label,
+			Content:   entry.Content.Label,
+			Rating:    rating,
+			Version:   entry.Version.Label,
+			UpdatedAt: updatedAt,
+		})
+	}
+
+	return reviews, nil
+}
+
 // convertToAppInfo converts iTunes API response to internal model
-func (c *Client) convertToAppInfo(app iTunesApp) (*models.AppInfo, error) {
+func convertToAppInfo(app iTunesApp) (*models.AppInfo, error) {
 	releaseDate, err := time.Parse(time.RFC3339, app.CurrentVersionReleaseDate)
 	if err != nil {
 		releaseDate = time.Now()
@@ -180,18 +400,41 @@ func (c *Client) convertToAppInfo(app iTunesApp) (*models.AppInfo, error) {
 	fmt.Sscanf(app.FileSizeBytes, "%d", &fileSize)
 
 	return &models.AppInfo{
-		BundleID:        app.BundleID,
-		TrackID:         app.TrackID,
-		TrackName:       app.TrackName,
-		Version:         app.Version,
-		ReleaseDate:     releaseDate,
-		ReleaseNotes:    app.ReleaseNotes,
-		ArtistName:      app.ArtistName,
-		MinOSVersion:    app.MinimumOsVersion,
-		FileSizeBytes:   fileSize,
-		Price:           app.Price,
-		Currency:        app.Currency,
-		LastChecked:     time.Now(),
-		FirstDiscovered: time.Now(),
+		BundleID:         app.BundleID,
+		TrackID:          app.TrackID,
+		TrackName:        app.TrackName,
+		Version:          app.Version,
+		ReleaseDate:      releaseDate,
+		ReleaseNotes:     app.ReleaseNotes,
+		ArtistName:       app.ArtistName,
+		MinOSVersion:     app.MinimumOsVersion,
+		FileSizeBytes:    fileSize,
+		Price:            app.Price,
+		Currency:         app.Currency,
+		Genre:            app.PrimaryGenreName,
+		ContentRating:    app.ContentAdvisoryRating,
+		SupportedDevices: app.SupportedDevices,
+		LanguageCodes:    app.LanguageCodes,
+		Description:      app.Description,
+		ScreenshotCount:  len(app.ScreenshotURLs) + len(app.IPadScreenshotURLs),
+		IconURL:          app.ArtworkURL100,
+		Rating:           ratingSnapshot(app),
+		LastChecked:      time.Now(),
+		FirstDiscovered:  time.Now(),
 	}, nil
 }
+
+// ratingSnapshot returns the app's ratings, or nil if it has none
+func ratingSnapshot(app iTunesApp) *models.RatingSnapshot {
+	if app.UserRatingCount == 0 && app.UserRatingCountForCurrentVersion == 0 {
+		return nil
+	}
+	return &models.RatingSnapshot{
+		AverageRating:               app.AverageUserRating,
+		RatingCount:                 app.UserRatingCount,
+		Version:                     app.Version,
+		CurrentVersionAverageRating: app.AverageUserRatingForCurrentVersion,
+		CurrentVersionRatingCount:   app.UserRatingCountForCurrentVersion,
+		CapturedAt:                  time.Now(),
+	}
+}
@@ -0,0 +1,181 @@
+package appstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of App Store API failure, matched with errors.Is. Lookups that find
+// nothing return ErrNotFound.
+var (
+	// ErrThrottled means Apple is rate limiting requests; back off before
+	// retrying
+	ErrThrottled = errors.New("rate limited by the App Store")
+
+	// ErrInvalid means the request was rejected or the response can't be
+	// used; retrying won't help
+	ErrInvalid = errors.New("invalid App Store request or response")
+
+	// ErrTransient means the request failed in a way that is likely to work
+	// if retried, e.g. a network error or a server error
+	ErrTransient = errors.New("temporary App Store error")
+)
+
+// maxErrorSnippet is how much of an unexpected response body an APIError keeps
+const maxErrorSnippet = 200
+
+// APIError is a failed App Store API request. It wraps one of ErrThrottled,
+// ErrInvalid, ErrTransient or ErrNotFound.
+type APIError struct {
+	Kind       error
+	StatusCode int
+
+	// Message is Apple's errorMessage, or the start of an unexpected body
+	Message string
+
+	// RetryAfter is how long Apple asked to wait, from the Retry-After
+	// header; zero if it didn't say
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := e.Kind.Error()
+	if e.StatusCode != 0 && e.StatusCode != http.StatusOK {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// RetryAfter returns how long Apple asked to wait before retrying, or zero if
+// err didn't come with a Retry-After
+func RetryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// errorBody is the JSON Apple returns for rejected requests, sometimes with
+// status 200, e.g. {"errorMessage": "Invalid value(s) for key(s): [country]"}
+type errorBody struct {
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// readResponse reads a response body, returning an APIError if the response
+// isn't a usable JSON result
+func readResponse(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError("read response", err)
+	}
+	if err := checkResponse(resp, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// checkResponse classifies a response by its status and body, returning nil
+// if it looks like a usable JSON result
+func checkResponse(resp *http.Response, body []byte) error {
+	status := resp.StatusCode
+	switch {
+	case status == http.StatusTooManyRequests:
+		return &APIError{Kind: ErrThrottled, StatusCode: status, Message: snippet(body), RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	case status == http.StatusNotFound:
+		return &APIError{Kind: ErrNotFound, StatusCode: status}
+	case status == http.StatusRequestTimeout || status >= 500:
+		return &APIError{Kind: ErrTransient, StatusCode: status, Message: snippet(body), RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	case status == http.StatusForbidden && looksThrottled(body):
+		return &APIError{Kind: ErrThrottled, StatusCode: status, Message: snippet(body)}
+	case status != http.StatusOK:
+		return &APIError{Kind: ErrInvalid, StatusCode: status, Message: errorMessage(body)}
+	}
+	return checkBody(body)
+}
+
+// checkBody classifies a 200 response body that isn't a usable JSON result:
+// an HTML throttle or error page, an errorMessage, or anything else that
+// isn't JSON
+func checkBody(body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return &APIError{Kind: ErrTransient, StatusCode: http.StatusOK, Message: "empty response"}
+	}
+	if trimmed[0] != '{' {
+		if looksThrottled(trimmed) {
+			return &APIError{Kind: ErrThrottled, StatusCode: http.StatusOK, Message: snippet(trimmed)}
+		}
+		return &APIError{Kind: ErrTransient, StatusCode: http.StatusOK, Message: "non-JSON response: " + snippet(trimmed)}
+	}
+
+	var errBody errorBody
+	if json.Unmarshal(trimmed, &errBody) == nil && errBody.ErrorMessage != "" {
+		return &APIError{Kind: ErrInvalid, StatusCode: http.StatusOK, Message: errBody.ErrorMessage}
+	}
+	return nil
+}
+
+// decodeError wraps a JSON decode failure of a body that passed checkBody
+func decodeError(err error) error {
+	return &APIError{Kind: ErrInvalid, StatusCode: http.StatusOK, Message: "failed to decode response: " + err.Error()}
+}
+
+// requestError wraps a failure to send a request or read its response
+func requestError(action string, err error) error {
+	return fmt.Errorf("failed to %s: %w", action, &APIError{Kind: ErrTransient, Message: err.Error()})
+}
+
+// looksThrottled reports whether a body is one of Apple's rate limiting pages
+func looksThrottled(body []byte) bool {
+	lower := strings.ToLower(string(body))
+	return strings.Contains(lower, "too many requests") || strings.Contains(lower, "rate limit")
+}
+
+// errorMessage returns Apple's errorMessage from body, or the start of body
+func errorMessage(body []byte) string {
+	var errBody errorBody
+	if json.Unmarshal(body, &errBody) == nil && errBody.ErrorMessage != "" {
+		return errBody.ErrorMessage
+	}
+	return snippet(body)
+}
+
+// snippet returns the start of body on one line
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > maxErrorSnippet {
+		s = s[:maxErrorSnippet] + "..."
+	}
+	return s
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+package appstore
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// storePageURL is an app's public App Store page. The lookup API doesn't
+	// list in-app purchases, so they are read from the page.
+	storePageURL = "https://apps.apple.com/%s/app/id%d"
+
+	// inAppPurchasesHeading marks the in-app purchase list on a store page
+	inAppPurchasesHeading = "In-App Purchases"
+
+	// maxHeadingGap is how far after its heading the list may start, so an
+	// unrelated list further down the page isn't mistaken for it
+	maxHeadingGap = 2000
+)
+
+var (
+	listPattern = regexp.MustCompile(`(?is)<(ol|ul)\b.*?</(?:ol|ul)>`)
+	itemPattern = regexp.MustCompile(`(?is)<li\b[^>]*>(.*?)</li>`)
+	tagPattern  = regexp.MustCompile(`(?s)<[^>]*>`)
+
+	// trackIDSegmentPattern matches the "id389801252" path segment that ends
+	// a store page URL, and countrySegmentPattern the storefront before "app"
+	trackIDSegmentPattern = regexp.MustCompile(`^id([0-9]+)$`)
+	countrySegmentPattern = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+)
+
+// storeHosts are the hosts App Store page links use
+var storeHosts = map[string]bool{"apps.apple.com": true, "itunes.apple.com": true}
+
+// ParseStoreURL returns the track ID and storefront of an App Store page
+// link, e.g. https://apps.apple.com/gb/app/instagram/id389801252. The
+// storefront is lowercase, and empty for links without one.
+func ParseStoreURL(rawURL string) (trackID int64, country string, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || !storeHosts[strings.ToLower(u.Hostname())] {
+		return 0, "", fmt.Errorf("not an App Store link: %q", rawURL)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	match := trackIDSegmentPattern.FindStringSubmatch(segments[len(segments)-1])
+	if match == nil {
+		return 0, "", fmt.Errorf("App Store link has no app ID: %q", rawURL)
+	}
+	trackID, err = strconv.ParseInt(match[1], 10, 64)
+	if err != nil || trackID <= 0 {
+		return 0, "", fmt.Errorf("App Store link has an invalid app ID: %q", rawURL)
+	}
+
+	if len(segments) > 1 && countrySegmentPattern.MatchString(segments[0]) {
+		country = strings.ToLower(segments[0])
+	}
+	return trackID, country, nil
+}
+
+// FetchInAppPurchases reads the in-app purchases listed on an app's store
+// page in a storefront, falling back to the client default when empty. The
+// page is always fetched in English, which the list is found by. An app with
+// none returns an empty list.
+func (c *Client) FetchInAppPurchases(ctx context.Context, trackID int64, country string) ([]models.InAppPurchase, error) {
+	if country == "" {
+		country = c.country
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(storePageURL, strings.ToLower(country), trackID)+"?l=en", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, requestError("fetch store page", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError("read store page", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, checkResponse(resp, body)
+	}
+
+	return ParseInAppPurchases(string(body))
+}
+
+// ParseInAppPurchases extracts the in-app purchase list from a store page.
+// It returns an empty list if the page has none, and an error if the page
+// mentions in-app purchases but the list can't be read, e.g. because the
+// page layout changed.
+func ParseInAppPurchases(page string) ([]models.InAppPurchase, error) {
+	purchases := []models.InAppPurchase{}
+
+	// The heading also appears in the "Offers In-App Purchases" badge and in
+	// page metadata; the list follows the last occurrence
+	i := strings.LastIndex(page, inAppPurchasesHeading)
+	if i < 0 {
+		return purchases, nil
+	}
+
+	loc := listPattern.FindStringIndex(page[i:])
+	if loc == nil || loc[0] > maxHeadingGap {
+		return nil, &APIError{Kind: ErrInvalid, StatusCode: http.StatusOK, Message: "in-app purchase list not found on store page"}
+	}
+
+	list := page[i+loc[0] : i+loc[1]]
+	for _, item := range itemPattern.FindAllStringSubmatch(list, -1) {
+		var texts []string
+		for _, text := range strings.Split(tagPattern.ReplaceAllString(item[1], "\n"), "\n") {
+			if text = strings.Join(strings.Fields(html.UnescapeString(text)), " "); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		if len(texts) < 2 {
+			continue
+		}
+		purchases = append(purchases, models.InAppPurchase{
+			Name:  texts[0],
+			Price: texts[len(texts)-1],
+		})
+	}
+
+	if len(purchases) == 0 {
+		return nil, &APIError{Kind: ErrInvalid, StatusCode: http.StatusOK, Message: "in-app purchase list on store page has no readable entries"}
+	}
+	return purchases, nil
+}
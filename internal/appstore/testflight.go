@@ -0,0 +1,85 @@
+package appstore
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const testFlightHost = "testflight.apple.com"
+
+var (
+	testFlightPathPattern = regexp.MustCompile(`^/join/[A-Za-z0-9]+/?$`)
+
+	// betaBuildPattern matches a build as TestFlight writes it, e.g.
+	// "Version 3.2 (45)"; a bare "version" could be anything on the page
+	betaBuildPattern = regexp.MustCompile(`(?i)\bversion\s+(\d+(?:\.\d+)+)\s*\(\s*(\d+)\s*\)`)
+)
+
+// ValidTestFlightURL reports whether link is a public TestFlight invitation,
+// e.g. https://testflight.apple.com/join/AbCd1234
+func ValidTestFlightURL(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && u.Scheme == "https" && strings.EqualFold(u.Host, testFlightHost) && testFlightPathPattern.MatchString(u.Path)
+}
+
+// FetchTestFlight reads the state of a public TestFlight beta from its join
+// page. The page is always fetched in English, which the state is found by.
+func (c *Client) FetchTestFlight(ctx context.Context, link string) (*models.BetaStatus, error) {
+	if !ValidTestFlightURL(link) {
+		return nil, fmt.Errorf("invalid TestFlight link: %s", link)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, requestError("fetch TestFlight page", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError("read TestFlight page", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, checkResponse(resp, body)
+	}
+
+	return ParseTestFlight(string(body))
+}
+
+// ParseTestFlight extracts a beta's state, and its build if shown, from a
+// TestFlight join page
+func ParseTestFlight(page string) (*models.BetaStatus, error) {
+	text := strings.Join(strings.Fields(html.UnescapeString(tagPattern.ReplaceAllString(page, " "))), " ")
+	lower := strings.ToLower(text)
+
+	status := &models.BetaStatus{}
+	switch {
+	case strings.Contains(lower, "this beta is full"):
+		status.State = models.BetaFull
+	case strings.Contains(lower, "isn't accepting any new testers"), strings.Contains(lower, "isn’t accepting any new testers"):
+		status.State = models.BetaClosed
+	case strings.Contains(lower, "join the") && strings.Contains(lower, "beta"):
+		status.State = models.BetaOpen
+	default:
+		return nil, &APIError{Kind: ErrInvalid, StatusCode: http.StatusOK, Message: "beta state not found on TestFlight page"}
+	}
+
+	if m := betaBuildPattern.FindStringSubmatch(text); m != nil {
+		status.Version, status.Build = m[1], m[2]
+	}
+	return status, nil
+}
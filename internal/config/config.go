@@ -2,10 +2,22 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/thomas/mavt/internal/customsource"
+	"github.com/thomas/mavt/internal/fleet"
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/internal/i18n"
+	"github.com/thomas/mavt/internal/packages"
+	"github.com/thomas/mavt/pkg/models"
 )
 
 // Config holds application configuration
@@ -13,24 +25,351 @@ type Config struct {
 	// Data directory for storing app info and updates
 	DataDir string
 
+	// File holding a 256-bit key; when set, stored records are encrypted with AES-GCM
+	EncryptionKeyFile string
+
 	// Apps to track (bundle IDs)
 	Apps []string
 
+	// How Apps is applied on startup: "additive" only adds apps, "managed"
+	// also removes tracked apps that are no longer listed
+	AppsMode string
+
 	// Check interval for polling
 	CheckInterval time.Duration
 
 	// Log level (debug, info, warn, error)
 	LogLevel string
 
+	// Outbound HTTP settings shared by the App Store client, notifications,
+	// webhooks and other integrations. A zero timeout keeps each client's
+	// default (30s for the App Store, 10s for notifications).
+	HTTPTimeout       time.Duration
+	HTTPMaxIdleConns  int
+	HTTPTLSMinVersion string
+	HTTPCAFile        string
+	HTTPUserAgent     string
+
 	// Server settings (for future HTTP API)
 	ServerPort int
 	ServerHost string
 
-	// Apprise notification URL
-	AppriseURL string
+	// Base URL the web interface is reached at, for links in notifications
+	PublicURL string
+
+	// Language of the web interface and notifications; see i18n.Languages
+	UILanguage string
+
+	// Apprise notification URL. AppriseMode is how it is notified of updates
+	// found together: in one notification ("batch") or one each
+	// ("individual"). AppriseTargets are further Apprise service URLs sent
+	// through it, each in its own mode.
+	AppriseURL     string
+	AppriseMode    string
+	AppriseTargets []AppriseTarget
+
+	// Outbound webhook URL and payload format (json or flat), the secret
+	// deliveries are signed with and the event types it is sent
+	WebhookURL    string
+	WebhookFormat string
+	WebhookSecret string
+	WebhookEvents []string
 
 	// App Store country/region (ISO 3166-1 alpha-2 code)
 	Country string
+
+	// Storefronts to try, in order, when an app isn't found in its own
+	FallbackCountries []string
+
+	// Release notes language (e.g. "ja_jp"), empty for the storefront default
+	Language string
+
+	// Per-app storefront/language overrides keyed by bundle ID
+	AppLocales map[string]AppLocale
+
+	// The device fleet apps must stay compatible with, from the
+	// MAVT_FLEET_PROFILE file overridden by MAVT_FLEET_MIN_OS and
+	// MAVT_FLEET_DEVICES. Apps whose minimum OS rises above its oldest OS, or
+	// that drop support for one of its devices, trigger a compatibility alert.
+	Fleet fleet.Profile
+
+	// Apple OS platforms whose releases are tracked (e.g. iOS, macOS)
+	OSPlatforms []string
+
+	// npm and PyPI packages whose releases are tracked (e.g. npm:react)
+	Packages []packages.Package
+
+	// Custom JSON endpoint sources, read from the MAVT_CUSTOM_SOURCES file
+	CustomSources []customsource.Definition
+
+	// Per-source settings from MAVT_<SOURCE>_ENABLED, MAVT_<SOURCE>_RATE_LIMIT
+	// and MAVT_NPM_TOKEN, keyed by the names in SourceNames
+	Sources map[string]SourceConfig
+
+	// SearchCacheTTL is how long search results are reused for the same
+	// query, so searching doesn't hit sources on every keystroke; 0 disables
+	SearchCacheTTL time.Duration
+
+	// Jamf Pro API client credentials for installed-vs-latest compliance reports
+	JamfURL          string
+	JamfClientID     string
+	JamfClientSecret string
+
+	// MDM that approved updates are pushed to ("jamf", "intune" or
+	// "simplemdm"; empty disables pushing) and the device groups to push to.
+	// Jamf uses the Jamf Pro credentials above.
+	MDMProvider string
+	MDMGroups   []string
+
+	// Microsoft Intune (Graph API) app registration credentials
+	IntuneTenantID     string
+	IntuneClientID     string
+	IntuneClientSecret string
+
+	// SimpleMDM API key
+	SimpleMDMAPIKey string
+
+	// Machine translation of release notes into TranslateTarget through
+	// TranslateProvider ("libretranslate" or "deepl"; empty disables it).
+	// TranslateURL is the LibreTranslate server, or overrides the DeepL API
+	// endpoint. TranslateNotify picks which notes notifications show.
+	TranslateProvider string
+	TranslateURL      string
+	TranslateAPIKey   string
+	TranslateTarget   string
+	TranslateNotify   string
+
+	// SMTP server for scheduled report emails
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Scheduled changelog report emails: recipients, a standard 5-field cron
+	// schedule, how far back each report covers and whether apps are grouped
+	// by vendor or by application
+	ReportRecipients []string
+	ReportSchedule   string
+	ReportPeriod     time.Duration
+	ReportGroupBy    string
+
+	// Sentry DSN for reporting recovered panics (optional)
+	SentryDSN string
+
+	// Slack app signing secret; enables the /mavt slash command endpoint
+	SlackSigningSecret string
+
+	// Token for POST /api/quicktrack, which tracks the app of an App Store
+	// link from a browser extension or bookmarklet; empty disables it.
+	// QuickTrackOrigins are the browser origins allowed to call it.
+	QuickTrackToken   string
+	QuickTrackOrigins []string
+
+	// Version updates older than this are gzip-compressed; zero disables
+	HistoryCompressAfter time.Duration
+
+	// Leader election between daemon replicas sharing a data directory: only
+	// the replica holding the lease runs checks, syncs and scheduled reports.
+	// InstanceID names this replica and defaults to the hostname.
+	LeaderElection bool
+	InstanceID     string
+
+	// Base URLs of other MAVT instances whose tracked apps and updates are
+	// pulled into this one, and how often
+	Upstreams            []string
+	UpstreamSyncInterval time.Duration
+
+	// Daily check of the GitHub releases feed for a newer MAVT version, and
+	// whether to send a notification when one is found
+	ReleaseCheck  bool
+	ReleaseNotify bool
+
+	// Raw API response archiving for debugging
+	ArchiveRawResponses bool
+	RawRetention        time.Duration
+
+	// Record edited release notes or a changed release date for an unchanged
+	// version as a re-release
+	DetectReReleases bool
+
+	// SnapshotInterval is how often an app's full metadata is snapshotted
+	// when only its ratings change; any other change is snapshotted when
+	// it is seen. 0 disables snapshots.
+	SnapshotInterval time.Duration
+
+	// Whether rollbacks (the App Store returning an older version than the
+	// stored one) are notified; they are recorded either way
+	NotifyRollbacks bool
+
+	// Whether detected updates start pending approval, so approved_update
+	// webhooks are only sent once someone approves them. ApprovalToken must
+	// be sent as a bearer token to approve, reject or deploy an update.
+	RequireApproval bool
+	ApprovalToken   string
+
+	// Change-freeze periods during which updates are recorded but not notified
+	// or sent to the webhook; the held updates are sent as one digest when a
+	// window ends
+	MaintenanceWindows []MaintenanceWindow
+
+	// Customer review tracking
+	TrackReviews         bool
+	ReviewAlertThreshold int
+	ReviewAlertWindow    time.Duration
+
+	// Read in-app purchases from each app's store page and report changes
+	TrackInAppPurchases bool
+
+	// Alert when an update grows an app's download by at least this
+	// percentage; 0 disables the alert
+	SizeGrowthAlertPercent int
+
+	// Demo mode: the daemon serves seeded sample apps from memory and
+	// publishes fake releases on an accelerated timeline
+	Demo bool
+}
+
+// Sources with settings in Config.Sources; their variables are named after
+// them in uppercase, e.g. MAVT_APPSTORE_RATE_LIMIT
+const (
+	SourceAppStore = "appstore"
+	SourceNPM      = "npm"
+	SourcePyPI     = "pypi"
+	SourceOS       = "os"
+	SourceCustom   = "custom"
+)
+
+// SourceNames lists the sources with settings
+var SourceNames = []string{SourceAppStore, SourceNPM, SourcePyPI, SourceOS, SourceCustom}
+
+// SourceConfig holds one source's settings
+type SourceConfig struct {
+	// Enabled sources are checked and searched; disabling one pauses it
+	// without removing what it tracks
+	Enabled bool
+
+	// RateLimit caps the source's requests per minute; 0 means no limit
+	RateLimit int
+
+	// Token authenticates requests, e.g. for private npm packages
+	Token string
+}
+
+// String summarizes the settings with the token masked, e.g. for printing
+// the configuration
+func (s SourceConfig) String() string {
+	parts := []string{"enabled"}
+	if !s.Enabled {
+		parts[0] = "disabled"
+	}
+	if s.RateLimit > 0 {
+		parts = append(parts, fmt.Sprintf("%d/min", s.RateLimit))
+	}
+	if s.Token != "" {
+		parts = append(parts, "token ********")
+	}
+	return strings.Join(parts, " ")
+}
+
+// RateLimits returns each source's requests per minute limit, for the HTTP
+// client
+func (c *Config) RateLimits() map[string]int {
+	limits := make(map[string]int, len(c.Sources))
+	for source, settings := range c.Sources {
+		limits[source] = settings.RateLimit
+	}
+	return limits
+}
+
+// Supported values for MAVT_MDM_PROVIDER
+const (
+	MDMProviderJamf      = "jamf"
+	MDMProviderIntune    = "intune"
+	MDMProviderSimpleMDM = "simplemdm"
+)
+
+// Supported values for MAVT_TRANSLATE_PROVIDER
+const (
+	TranslateProviderLibre = "libretranslate"
+	TranslateProviderDeepL = "deepl"
+)
+
+// Supported values for MAVT_APPS_MODE
+const (
+	AppsModeAdditive = "additive"
+	AppsModeManaged  = "managed"
+)
+
+// Supported values for MAVT_REPORT_GROUP_BY. ReportGroupByTag summarizes
+// the report per application tag, e.g. to watch competitors.
+const (
+	ReportGroupByVendor      = "vendor"
+	ReportGroupByApplication = "application"
+	ReportGroupByTag         = "tag"
+)
+
+// AppriseTarget is an Apprise service URL, or several separated by commas,
+// notified in Mode: "batch" or "individual"
+type AppriseTarget struct {
+	Mode string
+	URL  string
+}
+
+// AppLocale overrides the storefront and release notes language for a single app
+type AppLocale struct {
+	Country  string
+	Language string
+}
+
+// MaintenanceWindow is a change-freeze period from Start (inclusive) to End
+// (exclusive) covering the listed apps and the apps in applications with any
+// of the listed tags, or all apps if both are empty
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+	Apps  []string
+	Tags  []string
+}
+
+// maintenanceTagPrefix marks a tag in a maintenance window's scope
+const maintenanceTagPrefix = "tag:"
+
+// Covers reports whether an update at t to bundleID, whose application is
+// tagged tags, falls within the window
+func (w MaintenanceWindow) Covers(bundleID string, tags []string, t time.Time) bool {
+	if t.Before(w.Start) || !t.Before(w.End) {
+		return false
+	}
+	if len(w.Apps) == 0 && len(w.Tags) == 0 {
+		return true
+	}
+	for _, app := range w.Apps {
+		if strings.EqualFold(app, bundleID) {
+			return true
+		}
+	}
+	for _, tag := range w.Tags {
+		for _, appTag := range tags {
+			if strings.EqualFold(tag, appTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String formats the window as it is written in MAVT_MAINTENANCE_WINDOWS
+func (w MaintenanceWindow) String() string {
+	s := w.Start.UTC().Format(time.RFC3339) + "/" + w.End.UTC().Format(time.RFC3339)
+	scope := append([]string{}, w.Apps...)
+	for _, tag := range w.Tags {
+		scope = append(scope, maintenanceTagPrefix+tag)
+	}
+	if len(scope) > 0 {
+		s += "@" + strings.Join(scope, "|")
+	}
+	return s
 }
 
 // Load loads configuration from environment variables
@@ -38,17 +377,197 @@ func Load() (*Config, error) {
 	config := &Config{
 		DataDir:       getEnv("MAVT_DATA_DIR", "./data"),
 		CheckInterval: parseDuration(getEnv("MAVT_CHECK_INTERVAL", "1h"), 1*time.Hour),
+		AppsMode:      strings.ToLower(getEnv("MAVT_APPS_MODE", AppsModeAdditive)),
 		LogLevel:      getEnv("MAVT_LOG_LEVEL", "info"),
 		ServerPort:    parseInt(getEnv("MAVT_SERVER_PORT", "8080"), 8080),
 		ServerHost:    getEnv("MAVT_SERVER_HOST", "0.0.0.0"),
+		PublicURL:     strings.TrimRight(getEnv("MAVT_PUBLIC_URL", ""), "/"),
+		UILanguage:    strings.ToLower(getEnv("MAVT_LANG", i18n.DefaultLanguage)),
 		AppriseURL:    getEnv("MAVT_APPRISE_URL", ""),
+		AppriseMode:   strings.ToLower(getEnv("MAVT_APPRISE_MODE", "batch")),
+		WebhookURL:    getEnv("MAVT_WEBHOOK_URL", ""),
+		WebhookFormat: strings.ToLower(getEnv("MAVT_WEBHOOK_FORMAT", "json")),
+		WebhookSecret: getEnv("MAVT_WEBHOOK_SECRET", ""),
+		WebhookEvents: ParseList(getEnv("MAVT_WEBHOOK_EVENTS", models.EventVersionUpdate)),
 		Country:       getEnv("MAVT_COUNTRY", "AU"),
+		Language:      getEnv("MAVT_LANGUAGE", ""),
+
+		EncryptionKeyFile: getEnv("MAVT_ENCRYPTION_KEY_FILE", ""),
+
+		JamfURL:          getEnv("MAVT_JAMF_URL", ""),
+		JamfClientID:     getEnv("MAVT_JAMF_CLIENT_ID", ""),
+		JamfClientSecret: getEnv("MAVT_JAMF_CLIENT_SECRET", ""),
+
+		MDMProvider: strings.ToLower(getEnv("MAVT_MDM_PROVIDER", "")),
+		MDMGroups:   ParseList(getEnv("MAVT_MDM_GROUPS", "")),
+
+		IntuneTenantID:     getEnv("MAVT_INTUNE_TENANT_ID", ""),
+		IntuneClientID:     getEnv("MAVT_INTUNE_CLIENT_ID", ""),
+		IntuneClientSecret: getEnv("MAVT_INTUNE_CLIENT_SECRET", ""),
+
+		SimpleMDMAPIKey: getEnv("MAVT_SIMPLEMDM_API_KEY", ""),
+
+		TranslateProvider: strings.ToLower(getEnv("MAVT_TRANSLATE_PROVIDER", "")),
+		TranslateURL:      getEnv("MAVT_TRANSLATE_URL", ""),
+		TranslateAPIKey:   getEnv("MAVT_TRANSLATE_API_KEY", ""),
+		TranslateTarget:   getEnv("MAVT_TRANSLATE_TARGET", "en"),
+		TranslateNotify:   strings.ToLower(getEnv("MAVT_TRANSLATE_NOTIFY", "translated")),
+
+		SMTPHost:     getEnv("MAVT_SMTP_HOST", ""),
+		SMTPPort:     parseInt(getEnv("MAVT_SMTP_PORT", "587"), 587),
+		SMTPUsername: getEnv("MAVT_SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("MAVT_SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("MAVT_SMTP_FROM", ""),
+
+		ReportSchedule: getEnv("MAVT_REPORT_SCHEDULE", "0 8 * * 1"),
+		ReportGroupBy:  strings.ToLower(getEnv("MAVT_REPORT_GROUP_BY", ReportGroupByVendor)),
+
+		SentryDSN: getEnv("MAVT_SENTRY_DSN", ""),
+
+		SlackSigningSecret: getEnv("MAVT_SLACK_SIGNING_SECRET", ""),
+
+		QuickTrackToken:   getEnv("MAVT_QUICKTRACK_TOKEN", ""),
+		QuickTrackOrigins: ParseList(getEnv("MAVT_QUICKTRACK_ORIGINS", "https://apps.apple.com")),
+
+		LeaderElection: parseBool(getEnv("MAVT_LEADER_ELECTION", "false"), false),
+		InstanceID:     getEnv("MAVT_INSTANCE_ID", defaultInstanceID()),
+
+		UpstreamSyncInterval: parseDuration(getEnv("MAVT_UPSTREAM_SYNC_INTERVAL", "15m"), 15*time.Minute),
+
+		ReleaseCheck:  parseBool(getEnv("MAVT_RELEASE_CHECK", "true"), true),
+		ReleaseNotify: parseBool(getEnv("MAVT_RELEASE_NOTIFY", "false"), false),
+
+		ArchiveRawResponses: parseBool(getEnv("MAVT_ARCHIVE_RAW", "false"), false),
+		RawRetention:        parseDuration(getEnv("MAVT_ARCHIVE_RAW_RETENTION", "168h"), 168*time.Hour),
+
+		DetectReReleases: parseBool(getEnv("MAVT_DETECT_RERELEASES", "false"), false),
+		SnapshotInterval: parseDuration(getEnv("MAVT_SNAPSHOT_INTERVAL", "24h"), 24*time.Hour),
+		NotifyRollbacks:  parseBool(getEnv("MAVT_NOTIFY_ROLLBACKS", "true"), true),
+		RequireApproval:  parseBool(getEnv("MAVT_REQUIRE_APPROVAL", "false"), false),
+		ApprovalToken:    getEnv("MAVT_APPROVAL_TOKEN", ""),
+
+		TrackReviews:         parseBool(getEnv("MAVT_TRACK_REVIEWS", "false"), false),
+		ReviewAlertThreshold: parseInt(getEnv("MAVT_REVIEW_ALERT_THRESHOLD", "5"), 5),
+		ReviewAlertWindow:    parseDuration(getEnv("MAVT_REVIEW_ALERT_WINDOW", "72h"), 72*time.Hour),
+
+		TrackInAppPurchases: parseBool(getEnv("MAVT_TRACK_IN_APP_PURCHASES", "false"), false),
+
+		SizeGrowthAlertPercent: parseInt(getEnv("MAVT_SIZE_GROWTH_ALERT_PERCENT", "0"), 0),
+
+		Demo: parseBool(getEnv("MAVT_DEMO", "false"), false),
+
+		HTTPTimeout:       parseDuration(getEnv("MAVT_HTTP_TIMEOUT", "0"), 0),
+		HTTPMaxIdleConns:  parseInt(getEnv("MAVT_HTTP_MAX_IDLE_CONNS", "100"), 100),
+		HTTPTLSMinVersion: getEnv("MAVT_HTTP_TLS_MIN_VERSION", "1.2"),
+		HTTPCAFile:        getEnv("MAVT_HTTP_CA_FILE", ""),
+		HTTPUserAgent:     getEnv("MAVT_HTTP_USER_AGENT", ""),
+	}
+
+	config.SearchCacheTTL = parseDuration(getEnv("MAVT_SEARCH_CACHE_TTL", "5m"), 5*time.Minute)
+
+	config.Sources = make(map[string]SourceConfig, len(SourceNames))
+	for _, source := range SourceNames {
+		prefix := "MAVT_" + strings.ToUpper(source) + "_"
+		config.Sources[source] = SourceConfig{
+			Enabled:   parseBool(getEnv(prefix+"ENABLED", "true"), true),
+			RateLimit: parseInt(getEnv(prefix+"RATE_LIMIT", "0"), 0),
+		}
+	}
+	if token := getEnv("MAVT_NPM_TOKEN", ""); token != "" {
+		npm := config.Sources[SourceNPM]
+		npm.Token = token
+		config.Sources[SourceNPM] = npm
+	}
+
+	// Parse OS platforms to track from environment
+	if osEnv := getEnv("MAVT_TRACK_OS", ""); osEnv != "" {
+		config.OSPlatforms = ParseList(osEnv)
+	}
+
+	// Parse packages to track from environment
+	if packagesEnv := getEnv("MAVT_TRACK_PACKAGES", ""); packagesEnv != "" {
+		for _, spec := range ParseList(packagesEnv) {
+			pkg, err := packages.ParsePackage(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MAVT_TRACK_PACKAGES: %w", err)
+			}
+			config.Packages = append(config.Packages, pkg)
+		}
+	}
+
+	if sourcesFile := getEnv("MAVT_CUSTOM_SOURCES", ""); sourcesFile != "" {
+		definitions, err := customsource.LoadDefinitions(sourcesFile)
+		if err != nil {
+			return nil, err
+		}
+		config.CustomSources = definitions
+	}
+
+	// Parse fallback storefronts from environment
+	if fallbackEnv := getEnv("MAVT_FALLBACK_COUNTRIES", ""); fallbackEnv != "" {
+		for _, country := range ParseList(fallbackEnv) {
+			config.FallbackCountries = append(config.FallbackCountries, strings.ToUpper(country))
+		}
+	}
+
+	// Parse Apprise targets from environment
+	if targetsEnv := getEnv("MAVT_APPRISE_TARGETS", ""); targetsEnv != "" {
+		targets, err := parseAppriseTargets(targetsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAVT_APPRISE_TARGETS: %w", err)
+		}
+		config.AppriseTargets = targets
+	}
+
+	// Parse upstream instances to aggregate from environment
+	if upstreamsEnv := getEnv("MAVT_UPSTREAMS", ""); upstreamsEnv != "" {
+		config.Upstreams = ParseList(upstreamsEnv)
+	}
+
+	// Parse report recipients and period from environment
+	if recipientsEnv := getEnv("MAVT_REPORT_RECIPIENTS", ""); recipientsEnv != "" {
+		config.ReportRecipients = ParseList(recipientsEnv)
+	}
+	period, err := ParseDuration(getEnv("MAVT_REPORT_PERIOD", "7d"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MAVT_REPORT_PERIOD: %w", err)
+	}
+	config.ReportPeriod = period
+
+	if compressEnv := getEnv("MAVT_HISTORY_COMPRESS_AFTER", ""); compressEnv != "" {
+		compressAfter, err := ParseDuration(compressEnv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAVT_HISTORY_COMPRESS_AFTER: %w", err)
+		}
+		config.HistoryCompressAfter = compressAfter
+	}
+
+	if windowsEnv := getEnv("MAVT_MAINTENANCE_WINDOWS", ""); windowsEnv != "" {
+		windows, err := parseMaintenanceWindows(windowsEnv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAVT_MAINTENANCE_WINDOWS: %w", err)
+		}
+		config.MaintenanceWindows = windows
+	}
+
+	if profileFile := getEnv("MAVT_FLEET_PROFILE", ""); profileFile != "" {
+		profile, err := fleet.LoadProfile(profileFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Fleet = profile
+	}
+	if minOS := getEnv("MAVT_FLEET_MIN_OS", ""); minOS != "" {
+		config.Fleet.MinOSVersion = minOS
+	}
+	if devices := getEnv("MAVT_FLEET_DEVICES", ""); devices != "" {
+		config.Fleet.Devices = ParseList(devices)
 	}
 
 	// Parse apps list from environment
 	appsEnv := getEnv("MAVT_APPS", "")
 	if appsEnv != "" {
-		config.Apps = parseAppsList(appsEnv)
+		config.Apps, config.AppLocales = parseAppsList(appsEnv)
 	}
 
 	if err := config.Validate(); err != nil {
@@ -79,9 +598,181 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log level: %s (must be debug, info, warn, or error)", c.LogLevel)
 	}
 
+	if c.AppsMode != AppsModeAdditive && c.AppsMode != AppsModeManaged {
+		return fmt.Errorf("invalid apps mode: %s (must be additive or managed)", c.AppsMode)
+	}
+
+	// Guard against an unset MAVT_APPS wiping every tracked app
+	if c.AppsMode == AppsModeManaged && len(c.Apps) == 0 {
+		return fmt.Errorf("MAVT_APPS must list at least one app when MAVT_APPS_MODE is managed")
+	}
+
+	if c.WebhookFormat != "json" && c.WebhookFormat != "flat" {
+		return fmt.Errorf("invalid webhook format: %s (must be json or flat)", c.WebhookFormat)
+	}
+
+	for _, eventType := range c.WebhookEvents {
+		if eventType != "all" && !slices.Contains(models.EventTypes, eventType) {
+			return fmt.Errorf("invalid webhook event type: %s (must be all or one of %s)", eventType, strings.Join(models.EventTypes, ", "))
+		}
+	}
+
+	if c.HTTPTimeout < 0 {
+		return fmt.Errorf("MAVT_HTTP_TIMEOUT must not be negative")
+	}
+	if c.HTTPMaxIdleConns < 1 {
+		return fmt.Errorf("MAVT_HTTP_MAX_IDLE_CONNS must be at least 1")
+	}
+	if _, ok := httpclient.TLSVersions[c.HTTPTLSMinVersion]; !ok {
+		return fmt.Errorf("invalid MAVT_HTTP_TLS_MIN_VERSION %q: must be 1.2 or 1.3", c.HTTPTLSMinVersion)
+	}
+	if c.HTTPCAFile != "" {
+		if _, err := os.Stat(c.HTTPCAFile); err != nil {
+			return fmt.Errorf("invalid MAVT_HTTP_CA_FILE: %w", err)
+		}
+	}
+
+	if c.SearchCacheTTL < 0 {
+		return fmt.Errorf("MAVT_SEARCH_CACHE_TTL must not be negative")
+	}
+	if c.SnapshotInterval < 0 {
+		return fmt.Errorf("MAVT_SNAPSHOT_INTERVAL must not be negative")
+	}
+
+	for _, source := range SourceNames {
+		if c.Sources[source].RateLimit < 0 {
+			return fmt.Errorf("invalid MAVT_%s_RATE_LIMIT %d: must be 0 (no limit) or more", strings.ToUpper(source), c.Sources[source].RateLimit)
+		}
+	}
+
+	for _, country := range c.FallbackCountries {
+		if len(country) != 2 {
+			return fmt.Errorf("invalid fallback country %q: must be a 2-letter ISO 3166-1 code", country)
+		}
+	}
+
+	if c.JamfURL != "" && (c.JamfClientID == "" || c.JamfClientSecret == "") {
+		return fmt.Errorf("MAVT_JAMF_CLIENT_ID and MAVT_JAMF_CLIENT_SECRET are required when MAVT_JAMF_URL is set")
+	}
+
+	if c.RequireApproval && c.ApprovalToken == "" {
+		return fmt.Errorf("MAVT_APPROVAL_TOKEN is required when MAVT_REQUIRE_APPROVAL is enabled, so only people holding it can approve updates")
+	}
+
+	if c.MDMProvider != "" {
+		if !c.RequireApproval {
+			return fmt.Errorf("MAVT_REQUIRE_APPROVAL must be enabled when MAVT_MDM_PROVIDER is set, so only approved updates are pushed")
+		}
+		switch c.MDMProvider {
+		case MDMProviderJamf:
+			if c.JamfURL == "" {
+				return fmt.Errorf("MAVT_JAMF_URL is required when MAVT_MDM_PROVIDER is jamf")
+			}
+		case MDMProviderIntune:
+			if c.IntuneTenantID == "" || c.IntuneClientID == "" || c.IntuneClientSecret == "" {
+				return fmt.Errorf("MAVT_INTUNE_TENANT_ID, MAVT_INTUNE_CLIENT_ID and MAVT_INTUNE_CLIENT_SECRET are required when MAVT_MDM_PROVIDER is intune")
+			}
+			if len(c.MDMGroups) == 0 {
+				return fmt.Errorf("MAVT_MDM_GROUPS is required when MAVT_MDM_PROVIDER is intune")
+			}
+		case MDMProviderSimpleMDM:
+			if c.SimpleMDMAPIKey == "" {
+				return fmt.Errorf("MAVT_SIMPLEMDM_API_KEY is required when MAVT_MDM_PROVIDER is simplemdm")
+			}
+		default:
+			return fmt.Errorf("invalid MDM provider: %s (must be jamf, intune or simplemdm)", c.MDMProvider)
+		}
+	}
+
+	switch c.TranslateProvider {
+	case "":
+	case TranslateProviderLibre:
+		if c.TranslateURL == "" {
+			return fmt.Errorf("MAVT_TRANSLATE_URL is required when MAVT_TRANSLATE_PROVIDER is libretranslate")
+		}
+	case TranslateProviderDeepL:
+		if c.TranslateAPIKey == "" {
+			return fmt.Errorf("MAVT_TRANSLATE_API_KEY is required when MAVT_TRANSLATE_PROVIDER is deepl")
+		}
+	default:
+		return fmt.Errorf("invalid translation provider: %s (must be libretranslate or deepl)", c.TranslateProvider)
+	}
+	if c.TranslateProvider != "" && c.TranslateTarget == "" {
+		return fmt.Errorf("MAVT_TRANSLATE_TARGET is required when MAVT_TRANSLATE_PROVIDER is set")
+	}
+	if c.AppriseMode != "batch" && c.AppriseMode != "individual" {
+		return fmt.Errorf("invalid MAVT_APPRISE_MODE: %s (must be batch or individual)", c.AppriseMode)
+	}
+	if len(c.AppriseTargets) > 0 && c.AppriseURL == "" {
+		return fmt.Errorf("MAVT_APPRISE_URL is required when MAVT_APPRISE_TARGETS is set")
+	}
+
+	if c.TranslateNotify != "translated" && c.TranslateNotify != "original" && c.TranslateNotify != "both" {
+		return fmt.Errorf("invalid MAVT_TRANSLATE_NOTIFY: %s (must be translated, original or both)", c.TranslateNotify)
+	}
+
+	if len(c.ReportRecipients) > 0 {
+		if c.SMTPHost == "" || c.SMTPFrom == "" {
+			return fmt.Errorf("MAVT_SMTP_HOST and MAVT_SMTP_FROM are required when MAVT_REPORT_RECIPIENTS is set")
+		}
+		if _, err := cron.ParseStandard(c.ReportSchedule); err != nil {
+			return fmt.Errorf("invalid report schedule %q: %w", c.ReportSchedule, err)
+		}
+		if c.ReportPeriod <= 0 {
+			return fmt.Errorf("report period must be positive")
+		}
+		if c.ReportGroupBy != ReportGroupByVendor && c.ReportGroupBy != ReportGroupByApplication && c.ReportGroupBy != ReportGroupByTag {
+			return fmt.Errorf("invalid report grouping: %s (must be vendor, application or tag)", c.ReportGroupBy)
+		}
+	}
+
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid public URL: %s (must be an http or https URL)", c.PublicURL)
+		}
+	}
+
+	if !i18n.Supported(c.UILanguage) {
+		return fmt.Errorf("invalid MAVT_LANG: %s (must be one of %s)", c.UILanguage, strings.Join(i18n.Languages(), ", "))
+	}
+
+	for _, upstream := range c.Upstreams {
+		u, err := url.Parse(upstream)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid upstream URL: %s (must be an http or https URL)", upstream)
+		}
+	}
+	if len(c.Upstreams) > 0 && c.UpstreamSyncInterval < 1*time.Minute {
+		return fmt.Errorf("upstream sync interval must be at least 1 minute")
+	}
+
+	for _, window := range c.MaintenanceWindows {
+		if !window.End.After(window.Start) {
+			return fmt.Errorf("maintenance window %s must end after it starts", window)
+		}
+	}
+
+	if c.TrackReviews && c.ReviewAlertThreshold < 1 {
+		return fmt.Errorf("review alert threshold must be at least 1")
+	}
+
+	if c.SizeGrowthAlertPercent < 0 {
+		return fmt.Errorf("size growth alert percent must not be negative")
+	}
+
 	return nil
 }
 
+// defaultInstanceID identifies this process for leader election: the hostname,
+// which is unique per container or pod
+func defaultInstanceID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return fmt.Sprintf("mavt-%d", os.Getpid())
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -98,22 +789,151 @@ func parseInt(s string, defaultValue int) int {
 	return defaultValue
 }
 
+// parseBool parses a boolean from a string, returning default on error
+func parseBool(s string, defaultValue bool) bool {
+	if val, err := strconv.ParseBool(s); err == nil {
+		return val
+	}
+	return defaultValue
+}
+
+// durationUnits are the calendar units ParseDuration accepts on top of
+// time.ParseDuration's, checked in order so "mo" isn't read as minutes
+var durationUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"mo", 30 * 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+}
+
+// ParseDuration parses a duration string like time.ParseDuration, additionally
+// accepting a whole number of days ("7d"), weeks ("2w") or 30-day months ("3mo")
+func ParseDuration(s string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, u := range durationUnits {
+		if count, ok := strings.CutSuffix(trimmed, u.suffix); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 || int64(n) > math.MaxInt64/int64(u.unit) {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * u.unit, nil
+		}
+	}
+	return time.ParseDuration(trimmed)
+}
+
 // parseDuration parses a duration string, returning default on error
 func parseDuration(s string, defaultValue time.Duration) time.Duration {
-	if dur, err := time.ParseDuration(s); err == nil {
+	if dur, err := ParseDuration(s); err == nil {
 		return dur
 	}
 	return defaultValue
 }
 
-// parseAppsList parses a comma-separated list of app bundle IDs
-func parseAppsList(s string) []string {
+// ParseList parses a comma-separated list, dropping empty entries
+func ParseList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// parseAppriseTargets parses whitespace-separated Apprise targets written
+// mode=url, e.g. "individual=ntfy://ntfy.sh/mavt-{topic}". The URL may list
+// several service URLs separated by commas, sharing the mode.
+func parseAppriseTargets(s string) ([]AppriseTarget, error) {
+	var targets []AppriseTarget
+	for _, entry := range strings.Fields(s) {
+		mode, url, ok := strings.Cut(entry, "=")
+		mode = strings.ToLower(mode)
+		if !ok || url == "" || (mode != "batch" && mode != "individual") {
+			return nil, fmt.Errorf("%s (must be batch=<url> or individual=<url>)", entry)
+		}
+		if mode == "batch" && strings.Contains(url, "{topic}") {
+			return nil, fmt.Errorf("%s ({topic} needs individual mode)", entry)
+		}
+		targets = append(targets, AppriseTarget{Mode: mode, URL: url})
+	}
+	return targets, nil
+}
+
+// parseAppsList parses a comma-separated list of app bundle IDs. Each entry may
+// carry a storefront and language override as bundleID@country:lang
+// (e.g. "jp.naver.line@JP:ja_jp").
+func parseAppsList(s string) ([]string, map[string]AppLocale) {
 	parts := strings.Split(s, ",")
 	var apps []string
+	locales := make(map[string]AppLocale)
 	for _, part := range parts {
-		if trimmed := strings.TrimSpace(part); trimmed != "" {
-			apps = append(apps, trimmed)
+		trimmed := strings.TrimSpace(part)
+		if trimmed == "" {
+			continue
+		}
+
+		bundleID, locale, hasLocale := strings.Cut(trimmed, "@")
+		if hasLocale {
+			country, lang, _ := strings.Cut(locale, ":")
+			locales[bundleID] = AppLocale{
+				Country:  strings.TrimSpace(country),
+				Language: strings.TrimSpace(lang),
+			}
+		}
+		apps = append(apps, bundleID)
+	}
+	return apps, locales
+}
+
+// parseMaintenanceWindows parses a comma-separated list of maintenance windows
+// written as start/end, optionally followed by @ and a |-separated list of
+// bundle IDs and application tags written tag:name (e.g.
+// "2025-12-20/2026-01-05@com.example.crm|tag:finance"). Times are RFC 3339
+// or YYYY-MM-DD (midnight UTC); the end is exclusive.
+func parseMaintenanceWindows(s string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, entry := range ParseList(s) {
+		period, apps, _ := strings.Cut(entry, "@")
+		startStr, endStr, ok := strings.Cut(period, "/")
+		if !ok {
+			return nil, fmt.Errorf("%q must be written as start/end", entry)
 		}
+
+		start, err := parseWindowTime(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: invalid start: %w", entry, err)
+		}
+		end, err := parseWindowTime(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: invalid end: %w", entry, err)
+		}
+
+		window := MaintenanceWindow{Start: start, End: end}
+		for _, app := range strings.Split(apps, "|") {
+			trimmed := strings.TrimSpace(app)
+			if tag, ok := strings.CutPrefix(trimmed, maintenanceTagPrefix); ok {
+				if tag = strings.TrimSpace(tag); tag == "" {
+					return nil, fmt.Errorf("%q: %s needs a tag name", entry, maintenanceTagPrefix)
+				}
+				window.Tags = append(window.Tags, tag)
+			} else if trimmed != "" {
+				window.Apps = append(window.Apps, trimmed)
+			}
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// parseWindowTime parses a maintenance window bound given as RFC 3339 or as a
+// date (YYYY-MM-DD, midnight UTC)
+func parseWindowTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
 	}
-	return apps
+	return time.Parse("2006-01-02", s)
 }
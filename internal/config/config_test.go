@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowScope(t *testing.T) {
+	windows, err := parseMaintenanceWindows("2025-12-20/2026-01-05@com.example.crm|tag:Finance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 1 {
+		t.Fatalf("got %d windows, want 1", len(windows))
+	}
+	window := windows[0]
+	if got, want := window.String(), "2025-12-20T00:00:00Z/2026-01-05T00:00:00Z@com.example.crm|tag:Finance"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	during := time.Date(2025, 12, 24, 12, 0, 0, 0, time.UTC)
+	after := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		bundleID string
+		tags     []string
+		at       time.Time
+		want     bool
+	}{
+		{"listed app", "com.example.crm", nil, during, true},
+		{"tagged application", "com.example.bank", []string{"tier-1", "finance"}, during, true},
+		{"other app", "com.example.mail", []string{"tier-1"}, during, false},
+		{"after the end", "com.example.crm", nil, after, false},
+	}
+	for _, tt := range tests {
+		if got := window.Covers(tt.bundleID, tt.tags, tt.at); got != tt.want {
+			t.Errorf("%s: Covers = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := parseMaintenanceWindows("2025-12-20/2026-01-05@tag:"); err == nil {
+		t.Error("want an error for a tag without a name")
+	}
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thomas/mavt/internal/customsource"
+	"github.com/thomas/mavt/internal/packages"
+)
+
+// Environment variables that are parsed leniently by Load, falling back to the
+// default on bad input. Warnings reports values that would be ignored.
+var (
+	intEnvVars      = []string{"MAVT_SERVER_PORT", "MAVT_SMTP_PORT", "MAVT_REVIEW_ALERT_THRESHOLD", "MAVT_HTTP_MAX_IDLE_CONNS", "MAVT_SIZE_GROWTH_ALERT_PERCENT"}
+	boolEnvVars     = []string{"MAVT_ARCHIVE_RAW", "MAVT_TRACK_REVIEWS", "MAVT_LEADER_ELECTION", "MAVT_RELEASE_CHECK", "MAVT_RELEASE_NOTIFY", "MAVT_DETECT_RERELEASES", "MAVT_NOTIFY_ROLLBACKS", "MAVT_REQUIRE_APPROVAL", "MAVT_DEMO", "MAVT_TRACK_IN_APP_PURCHASES"}
+	durationEnvVars = []string{"MAVT_CHECK_INTERVAL", "MAVT_HTTP_TIMEOUT", "MAVT_ARCHIVE_RAW_RETENTION", "MAVT_REVIEW_ALERT_WINDOW", "MAVT_UPSTREAM_SYNC_INTERVAL", "MAVT_SEARCH_CACHE_TTL", "MAVT_SNAPSHOT_INTERVAL"}
+)
+
+// knownEnvVars lists every MAVT_* variable read by Load
+var knownEnvVars = map[string]bool{
+	"MAVT_DATA_DIR": true, "MAVT_ENCRYPTION_KEY_FILE": true, "MAVT_APPS": true, "MAVT_APPS_MODE": true, "MAVT_CHECK_INTERVAL": true,
+	"MAVT_LOG_LEVEL": true, "MAVT_SERVER_PORT": true, "MAVT_SERVER_HOST": true, "MAVT_PUBLIC_URL": true, "MAVT_LANG": true,
+	"MAVT_APPRISE_URL": true, "MAVT_APPRISE_MODE": true, "MAVT_APPRISE_TARGETS": true, "MAVT_WEBHOOK_URL": true, "MAVT_WEBHOOK_FORMAT": true, "MAVT_WEBHOOK_SECRET": true, "MAVT_WEBHOOK_EVENTS": true,
+	"MAVT_COUNTRY": true, "MAVT_FALLBACK_COUNTRIES": true, "MAVT_LANGUAGE": true, "MAVT_FLEET_MIN_OS": true, "MAVT_FLEET_DEVICES": true, "MAVT_FLEET_PROFILE": true, "MAVT_TRACK_OS": true, "MAVT_TRACK_PACKAGES": true, "MAVT_CUSTOM_SOURCES": true,
+	"MAVT_JAMF_URL": true, "MAVT_JAMF_CLIENT_ID": true, "MAVT_JAMF_CLIENT_SECRET": true,
+	"MAVT_MDM_PROVIDER": true, "MAVT_MDM_GROUPS": true,
+	"MAVT_INTUNE_TENANT_ID": true, "MAVT_INTUNE_CLIENT_ID": true, "MAVT_INTUNE_CLIENT_SECRET": true, "MAVT_SIMPLEMDM_API_KEY": true,
+	"MAVT_TRANSLATE_PROVIDER": true, "MAVT_TRANSLATE_URL": true, "MAVT_TRANSLATE_API_KEY": true, "MAVT_TRANSLATE_TARGET": true, "MAVT_TRANSLATE_NOTIFY": true,
+	"MAVT_SMTP_HOST": true, "MAVT_SMTP_PORT": true, "MAVT_SMTP_USERNAME": true,
+	"MAVT_SMTP_PASSWORD": true, "MAVT_SMTP_FROM": true,
+	"MAVT_REPORT_RECIPIENTS": true, "MAVT_REPORT_SCHEDULE": true, "MAVT_REPORT_PERIOD": true, "MAVT_REPORT_GROUP_BY": true,
+	"MAVT_SENTRY_DSN": true, "MAVT_SLACK_SIGNING_SECRET": true,
+	"MAVT_QUICKTRACK_TOKEN": true, "MAVT_QUICKTRACK_ORIGINS": true,
+	"MAVT_HISTORY_COMPRESS_AFTER": true,
+	"MAVT_UPSTREAMS":              true, "MAVT_UPSTREAM_SYNC_INTERVAL": true,
+	"MAVT_LEADER_ELECTION": true, "MAVT_INSTANCE_ID": true,
+	"MAVT_RELEASE_CHECK": true, "MAVT_RELEASE_NOTIFY": true,
+	"MAVT_DETECT_RERELEASES": true, "MAVT_SNAPSHOT_INTERVAL": true, "MAVT_NOTIFY_ROLLBACKS": true, "MAVT_REQUIRE_APPROVAL": true, "MAVT_APPROVAL_TOKEN": true, "MAVT_MAINTENANCE_WINDOWS": true,
+	"MAVT_ARCHIVE_RAW": true, "MAVT_ARCHIVE_RAW_RETENTION": true,
+	"MAVT_TRACK_REVIEWS": true, "MAVT_REVIEW_ALERT_THRESHOLD": true, "MAVT_REVIEW_ALERT_WINDOW": true,
+	"MAVT_TRACK_IN_APP_PURCHASES": true, "MAVT_SIZE_GROWTH_ALERT_PERCENT": true,
+	"MAVT_DEMO":         true,
+	"MAVT_HTTP_TIMEOUT": true, "MAVT_HTTP_MAX_IDLE_CONNS": true, "MAVT_HTTP_TLS_MIN_VERSION": true,
+	"MAVT_HTTP_CA_FILE": true, "MAVT_HTTP_USER_AGENT": true, "MAVT_SEARCH_CACHE_TTL": true,
+}
+
+func init() {
+	for _, source := range SourceNames {
+		prefix := "MAVT_" + strings.ToUpper(source) + "_"
+		knownEnvVars[prefix+"ENABLED"] = true
+		knownEnvVars[prefix+"RATE_LIMIT"] = true
+		boolEnvVars = append(boolEnvVars, prefix+"ENABLED")
+		intEnvVars = append(intEnvVars, prefix+"RATE_LIMIT")
+	}
+	knownEnvVars["MAVT_NPM_TOKEN"] = true
+}
+
+// secretFields are masked entirely by Print; urlFields keep only scheme and host
+var (
+	secretFields = map[string]bool{"JamfClientSecret": true, "IntuneClientSecret": true, "SimpleMDMAPIKey": true, "TranslateAPIKey": true, "SMTPPassword": true, "SlackSigningSecret": true, "QuickTrackToken": true, "ApprovalToken": true, "WebhookSecret": true, "SentryDSN": true}
+	urlFields    = map[string]bool{"AppriseURL": true, "WebhookURL": true, "TranslateURL": true, "Upstreams": true}
+)
+
+// Warnings returns problems in the environment that Load tolerates but that are
+// probably mistakes: values that fail to parse and fall back to their default,
+// and unrecognised MAVT_* variables (usually typos)
+func Warnings() []string {
+	var warnings []string
+
+	for _, key := range intEnvVars {
+		if value := os.Getenv(key); value != "" {
+			if _, err := strconv.Atoi(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s=%q is not an integer; the default is used", key, value))
+			}
+		}
+	}
+	for _, key := range boolEnvVars {
+		if value := os.Getenv(key); value != "" {
+			if _, err := strconv.ParseBool(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s=%q is not a boolean; the default is used", key, value))
+			}
+		}
+	}
+	for _, key := range durationEnvVars {
+		if value := os.Getenv(key); value != "" {
+			if _, err := ParseDuration(value); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s=%q is not a duration (e.g. 30m, 4h, 7d); the default is used", key, value))
+			}
+		}
+	}
+
+	var unknown []string
+	for _, env := range os.Environ() {
+		key, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(key, "MAVT_") && !knownEnvVars[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		warnings = append(warnings, fmt.Sprintf("%s is not a recognised setting", key))
+	}
+
+	return warnings
+}
+
+// Print writes the effective configuration, one field per line, with secrets
+// masked so the output is safe to paste into tickets
+func (c *Config) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		value := formatValue(v.Field(i).Interface())
+
+		switch {
+		case value == "":
+		case secretFields[name]:
+			value = "********"
+		case urlFields[name]:
+			urls := strings.Split(value, ",")
+			for i := range urls {
+				urls[i] = maskURL(urls[i])
+			}
+			value = strings.Join(urls, ",")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\n", name, value)
+	}
+
+	return tw.Flush()
+}
+
+// formatValue renders a config value for Print
+func formatValue(value interface{}) string {
+	switch val := value.(type) {
+	case []string:
+		return strings.Join(val, ",")
+	case map[string]AppLocale:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s@%s:%s", key, val[key].Country, val[key].Language))
+		}
+		return strings.Join(parts, ",")
+	case []AppriseTarget:
+		// Service URLs carry their credentials, so keep only scheme and host
+		parts := make([]string, 0, len(val))
+		for _, target := range val {
+			urls := strings.Split(target.URL, ",")
+			for i := range urls {
+				urls[i] = maskURL(urls[i])
+			}
+			parts = append(parts, target.Mode+"="+strings.Join(urls, ","))
+		}
+		return strings.Join(parts, " ")
+	case []packages.Package:
+		parts := make([]string, 0, len(val))
+		for _, pkg := range val {
+			parts = append(parts, pkg.String())
+		}
+		return strings.Join(parts, ",")
+	case []customsource.Definition:
+		parts := make([]string, 0, len(val))
+		for _, definition := range val {
+			parts = append(parts, definition.String())
+		}
+		return strings.Join(parts, ",")
+	case map[string]SourceConfig:
+		parts := make([]string, 0, len(val))
+		for _, source := range SourceNames {
+			if settings, ok := val[source]; ok {
+				parts = append(parts, source+"="+settings.String())
+			}
+		}
+		return strings.Join(parts, ",")
+	case []MaintenanceWindow:
+		parts := make([]string, 0, len(val))
+		for _, window := range val {
+			parts = append(parts, window.String())
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// maskURL keeps the scheme and host of a URL, which may carry tokens in its
+// path or credentials
+func maskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "********"
+	}
+	return u.Scheme + "://" + u.Host + "/********"
+}
@@ -0,0 +1,203 @@
+// Package customsource tracks releases from arbitrary JSON endpoints, such as
+// internal or vendor APIs, described by a URL and JSONPath expressions rather
+// than code
+package customsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// Source identifies custom source pseudo-apps in storage
+	Source = "custom"
+
+	// bundleIDPrefix is prepended to a definition's ID to build its pseudo bundle ID
+	bundleIDPrefix = "custom."
+
+	// maxResponseSize caps how much of an endpoint's response is read
+	maxResponseSize = 5 << 20
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Definition describes a custom source
+type Definition struct {
+	// ID identifies the source; it must be lowercase letters, digits, ".",
+	// "_" or "-" since releases are stored under it
+	ID string `json:"id"`
+
+	// Name is shown in place of an app name; it defaults to the ID
+	Name string `json:"name,omitempty"`
+
+	// URL is fetched with GET on each check and must return JSON
+	URL string `json:"url"`
+
+	// VersionPath and NotesPath are JSONPath expressions selecting the
+	// current version and, optionally, its release notes, e.g.
+	// "$.releases[0].version"
+	VersionPath string `json:"version_path"`
+	NotesPath   string `json:"notes_path,omitempty"`
+
+	// AuthHeader is an optional "Name: value" header sent with the request,
+	// e.g. "Authorization: Bearer ${VENDOR_TOKEN}". ${VAR} references are
+	// expanded from the environment so tokens can stay out of the file.
+	AuthHeader string `json:"auth_header,omitempty"`
+}
+
+// LoadDefinitions reads and validates definitions from a JSON file holding an
+// array of them
+func LoadDefinitions(file string) ([]Definition, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom sources: %w", err)
+	}
+
+	var definitions []Definition
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to parse custom sources %s: %w", file, err)
+	}
+
+	seen := make(map[string]bool, len(definitions))
+	for i := range definitions {
+		if err := definitions[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid custom source %d in %s: %w", i+1, file, err)
+		}
+		if seen[definitions[i].ID] {
+			return nil, fmt.Errorf("duplicate custom source %q in %s", definitions[i].ID, file)
+		}
+		seen[definitions[i].ID] = true
+	}
+	return definitions, nil
+}
+
+// Validate checks a definition is complete and its paths parse
+func (d *Definition) Validate() error {
+	if !idPattern.MatchString(d.ID) {
+		return fmt.Errorf("id %q must be lowercase letters, digits, '.', '_' or '-'", d.ID)
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url %q must be an http or https URL", d.URL)
+	}
+	if d.VersionPath == "" {
+		return fmt.Errorf("version_path is required")
+	}
+	if _, err := parsePath(d.VersionPath); err != nil {
+		return fmt.Errorf("invalid version_path: %w", err)
+	}
+	if d.NotesPath != "" {
+		if _, err := parsePath(d.NotesPath); err != nil {
+			return fmt.Errorf("invalid notes_path: %w", err)
+		}
+	}
+	if d.AuthHeader != "" {
+		if name, _, ok := strings.Cut(d.AuthHeader, ":"); !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("auth_header must be \"Name: value\"")
+		}
+	}
+	return nil
+}
+
+// BundleID returns the pseudo bundle ID a source's releases are stored under
+func (d Definition) BundleID() string {
+	return bundleIDPrefix + d.ID
+}
+
+// String describes the source without its credentials, e.g. for printing
+// the configuration
+func (d Definition) String() string {
+	host := ""
+	if u, err := url.Parse(d.URL); err == nil {
+		host = u.Host
+	}
+	return d.ID + "@" + host
+}
+
+// Client evaluates custom sources
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a new custom source client, limited to the "custom"
+// source's rate limit
+func NewClient() *Client {
+	return &Client{httpClient: httpclient.NewForSource(Source, 30*time.Second)}
+}
+
+// FetchLatest fetches a source's endpoint and returns its current version as
+// a pseudo-app
+func (c *Client) FetchLatest(ctx context.Context, d Definition) (*models.AppInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", d.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if d.AuthHeader != "" {
+		name, value, _ := strings.Cut(d.AuthHeader, ":")
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(os.ExpandEnv(value)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", d.ID, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", d.ID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(body) > 200 {
+			body = body[:200]
+		}
+		return nil, fmt.Errorf("%s returned status %d: %s", d.ID, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", d.ID, err)
+	}
+
+	version, err := Evaluate(doc, d.VersionPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find version for %s: %w", d.ID, err)
+	}
+	if version == "" {
+		return nil, fmt.Errorf("empty version for %s at %s", d.ID, d.VersionPath)
+	}
+
+	app := &models.AppInfo{
+		BundleID:        d.BundleID(),
+		TrackName:       d.Name,
+		Version:         version,
+		Source:          Source,
+		LastChecked:     time.Now(),
+		FirstDiscovered: time.Now(),
+	}
+	if app.TrackName == "" {
+		app.TrackName = d.ID
+	}
+	if d.NotesPath != "" {
+		// Notes are optional; a release without them is still a release
+		if notes, err := Evaluate(doc, d.NotesPath); err == nil {
+			app.ReleaseNotes = notes
+		}
+	}
+	return app, nil
+}
@@ -0,0 +1,113 @@
+package customsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step is one segment of a JSONPath: an object key or an array index
+type step struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// parsePath parses the JSONPath subset custom sources support: a leading
+// "$", ".key", "['key']" and "[n]", where a negative n counts from the end,
+// e.g. "$.data.releases[-1]['version']"
+func parsePath(path string) ([]step, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	var steps []step
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in %q", path)
+			}
+			steps = append(steps, step{key: rest[:end], isKey: true})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed [ in %q", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, step{key: inner[1 : len(inner)-1], isKey: true})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in %q", inner, path)
+			}
+			steps = append(steps, step{index: index})
+		default:
+			// A bare key at the start, e.g. "version" for "$.version"
+			if len(steps) > 0 {
+				return nil, fmt.Errorf("unexpected %q in %q", rest[0], path)
+			}
+			rest = "." + rest
+		}
+	}
+	return steps, nil
+}
+
+// Evaluate selects the value at path in a decoded JSON document and returns
+// it as a string. Strings, numbers and booleans are supported; anything else
+// is an error.
+func Evaluate(doc interface{}, path string) (string, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	value := doc
+	for _, s := range steps {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			if !s.isKey {
+				return "", fmt.Errorf("%s: expected a key, found an object", path)
+			}
+			next, ok := node[s.key]
+			if !ok {
+				return "", fmt.Errorf("%s: no key %q", path, s.key)
+			}
+			value = next
+		case []interface{}:
+			if s.isKey {
+				return "", fmt.Errorf("%s: expected an index, found an array", path)
+			}
+			index := s.index
+			if index < 0 {
+				index += len(node)
+			}
+			if index < 0 || index >= len(node) {
+				return "", fmt.Errorf("%s: index %d out of range", path, s.index)
+			}
+			value = node[index]
+		default:
+			return "", fmt.Errorf("%s: cannot descend into %v", path, value)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("%s: selects an object or array, not a value", path)
+	}
+}
@@ -0,0 +1,293 @@
+// Package demo seeds sample apps with version histories and publishes fake
+// releases on an accelerated timeline, so the daemon and web interface can be
+// tried without real App Store traffic
+package demo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// CheckInterval is how often the demo daemon checks for updates
+	CheckInterval = time.Minute
+
+	// ReleaseInterval is how often a fake release is published
+	ReleaseInterval = 2 * time.Minute
+)
+
+// sample describes a fake app
+type sample struct {
+	bundleID string
+	trackID  int64
+	name     string
+	artist   string
+	genre    string
+	minOS    string
+	price    float64
+	version  string
+}
+
+var samples = []sample{
+	{"com.example.demo.notes", 900000001, "Inkwell Notes", "Example Labs", "Productivity", "16.0", 0, "4.2.0"},
+	{"com.example.demo.weather", 900000002, "Skyline Weather", "Example Labs", "Weather", "15.0", 0, "7.9.3"},
+	{"com.example.demo.chat", 900000003, "Parley Chat", "Parley Inc.", "Social Networking", "16.4", 0, "23.14.0"},
+	{"com.example.demo.vpn", 900000004, "Burrow VPN", "Example Security GmbH", "Utilities", "15.0", 4.99, "2.11.1"},
+	{"com.example.demo.crm", 900000005, "Pipeline CRM", "Pipeline Software", "Business", "17.0", 0, "9.0.2"},
+	{"com.example.demo.photo", 900000006, "Darkroom Pro", "Lumen Studio", "Photo & Video", "16.0", 9.99, "5.6.0"},
+}
+
+var releaseNotes = []string{
+	"Bug fixes and performance improvements.",
+	"Fixed a crash when opening the app from a notification.",
+	"New: dark mode now follows the system setting.\nFixed sync issues on slow connections.",
+	"Improved battery usage in the background.",
+	"Security fixes. We recommend all users update.",
+	"Redesigned settings screen.\nAccessibility improvements for VoiceOver users.",
+	"Support for the latest iOS features, including new widgets.",
+	"Fixed an issue where some users were signed out unexpectedly.",
+	"Faster startup and smaller download size.",
+	"New sharing options and minor fixes.",
+}
+
+// Demo serves the sample apps and publishes their fake releases
+type Demo struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	source *trackertest.FakeSource
+	apps   map[string]*models.AppInfo
+}
+
+// New creates a demo; seed makes its histories and releases reproducible
+func New(seed int64) *Demo {
+	return &Demo{
+		rng:    rand.New(rand.NewSource(seed)),
+		source: trackertest.NewFakeSource(),
+		apps:   make(map[string]*models.AppInfo),
+	}
+}
+
+// Config returns a copy of cfg adjusted for demo mode: a short check interval,
+// and no configured apps, upstreams, OS releases, reviews, in-app purchases,
+// raw archiving, scheduled reports or leader election, since they need real
+// data
+func Config(cfg *config.Config) *config.Config {
+	demoCfg := *cfg
+	demoCfg.CheckInterval = CheckInterval
+	demoCfg.Apps = nil
+	demoCfg.Upstreams = nil
+	demoCfg.OSPlatforms = nil
+	demoCfg.Packages = nil
+	demoCfg.CustomSources = nil
+	demoCfg.ReportRecipients = nil
+	demoCfg.TrackReviews = false
+	demoCfg.TrackInAppPurchases = false
+	demoCfg.ArchiveRawResponses = false
+	demoCfg.LeaderElection = false
+	return &demoCfg
+}
+
+// Source returns the source serving the sample apps' current versions
+func (d *Demo) Source() tracker.Source {
+	return d.source
+}
+
+// Seed stores the sample apps with a year or so of version history ending
+// at now
+func (d *Demo) Seed(store tracker.Store, now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, s := range samples {
+		size := int64(40+d.rng.Intn(200)) << 20
+		history := d.history(s, size, now)
+		first := history[0].UpdatedAt
+		latest := history[len(history)-1]
+
+		app := &models.AppInfo{
+			BundleID:        s.bundleID,
+			TrackID:         s.trackID,
+			TrackName:       s.name,
+			Version:         s.version,
+			ReleaseDate:     *latest.ReleasedAt,
+			ReleaseNotes:    latest.ReleaseNotes,
+			ArtistName:      s.artist,
+			MinOSVersion:    s.minOS,
+			FileSizeBytes:   size,
+			Price:           s.price,
+			Currency:        "USD",
+			LastChecked:     now,
+			FirstDiscovered: first,
+			Genre:           s.genre,
+			ContentRating:   "4+",
+			Rating: &models.RatingSnapshot{
+				AverageRating:               3.5 + d.rng.Float64()*1.4,
+				RatingCount:                 int64(500 + d.rng.Intn(200000)),
+				Version:                     s.version,
+				CurrentVersionAverageRating: 3 + d.rng.Float64()*2,
+				CurrentVersionRatingCount:   int64(10 + d.rng.Intn(2000)),
+				CapturedAt:                  now,
+			},
+		}
+		if err := store.SaveApp(app); err != nil {
+			return fmt.Errorf("failed to seed %s: %w", s.bundleID, err)
+		}
+		if err := store.AddVersionUpdates(s.bundleID, history); err != nil {
+			return fmt.Errorf("failed to seed history of %s: %w", s.bundleID, err)
+		}
+
+		d.apps[s.bundleID] = app
+		d.source.SetApp(app)
+	}
+	return nil
+}
+
+// history generates the versions leading up to a sample's current version and
+// size, oldest first, released every one to six weeks and growing a little
+// each time. Updates older than a week are acknowledged so the triage view
+// isn't swamped.
+func (d *Demo) history(s sample, size int64, now time.Time) []models.VersionUpdate {
+	versions := []string{s.version}
+	for n := 6 + d.rng.Intn(10); n > 0; n-- {
+		previous := previousVersion(versions[0], d.rng)
+		if previous == versions[0] {
+			break
+		}
+		versions = append([]string{previous}, versions...)
+	}
+
+	released := now.Add(-time.Duration(d.rng.Intn(72)+1) * time.Hour)
+	dates := make([]time.Time, len(versions))
+	sizes := make([]int64, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		dates[i] = released
+		released = released.Add(-time.Duration(7+d.rng.Intn(35)) * 24 * time.Hour)
+		sizes[i] = size
+		size -= size * int64(d.rng.Intn(6)) / 100
+	}
+
+	var history []models.VersionUpdate
+	for i := 1; i < len(versions); i++ {
+		releasedAt := dates[i]
+		update := models.VersionUpdate{
+			BundleID:     s.bundleID,
+			TrackID:      s.trackID,
+			TrackName:    s.name,
+			OldVersion:   versions[i-1],
+			NewVersion:   versions[i],
+			UpdatedAt:    releasedAt.Add(time.Duration(d.rng.Intn(240)) * time.Minute),
+			ReleasedAt:   &releasedAt,
+			ReleaseNotes: releaseNotes[d.rng.Intn(len(releaseNotes))],
+
+			OldFileSizeBytes: sizes[i-1],
+			FileSizeBytes:    sizes[i],
+		}
+		if now.Sub(update.UpdatedAt) > 7*24*time.Hour {
+			update.Acknowledged = &models.Acknowledgement{By: "demo", At: update.UpdatedAt.Add(24 * time.Hour)}
+		}
+		history = append(history, update)
+	}
+	return history
+}
+
+// Run publishes a fake release every interval until ctx is done
+func (d *Demo) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			app := d.Release(time.Now())
+			log.Printf("Demo: published %s %s", app.TrackName, app.Version)
+		}
+	}
+}
+
+// Release publishes a new version of a random sample app, a little larger and
+// occasionally with a price change, a higher minimum OS or a much larger
+// download, and returns it. The tracker picks
+// it up on its next check.
+func (d *Demo) Release(now time.Time) *models.AppInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := samples[d.rng.Intn(len(samples))]
+	app := *d.apps[s.bundleID]
+	app.Version = nextVersion(app.Version, d.rng)
+	app.ReleaseDate = now
+	app.ReleaseNotes = releaseNotes[d.rng.Intn(len(releaseNotes))]
+	app.FileSizeBytes += app.FileSizeBytes * int64(d.rng.Intn(4)) / 100
+
+	switch d.rng.Intn(10) {
+	case 0:
+		if app.Price > 0 {
+			app.Price = float64(d.rng.Intn(10)) + 0.99
+		}
+	case 1:
+		if major, err := strconv.Atoi(strings.Split(app.MinOSVersion, ".")[0]); err == nil && major < 18 {
+			app.MinOSVersion = strconv.Itoa(major+1) + ".0"
+		}
+	case 2:
+		app.FileSizeBytes += app.FileSizeBytes * int64(20+d.rng.Intn(30)) / 100
+	}
+
+	d.apps[s.bundleID] = &app
+	d.source.SetApp(&app)
+	return &app
+}
+
+// nextVersion bumps the patch number of a major.minor.patch version, or now
+// and then the minor or major number
+func nextVersion(version string, rng *rand.Rand) string {
+	parts := parseVersion(version)
+	switch n := rng.Intn(20); {
+	case n == 0:
+		parts = [3]int{parts[0] + 1, 0, 0}
+	case n < 6:
+		parts = [3]int{parts[0], parts[1] + 1, 0}
+	default:
+		parts[2]++
+	}
+	return formatVersion(parts)
+}
+
+// previousVersion steps a major.minor.patch version back to a plausible
+// earlier release, returning it unchanged at 1.0.0
+func previousVersion(version string, rng *rand.Rand) string {
+	parts := parseVersion(version)
+	switch {
+	case parts[2] > 0:
+		parts[2]--
+	case parts[1] > 0:
+		parts = [3]int{parts[0], parts[1] - 1, rng.Intn(4)}
+	case parts[0] > 1:
+		parts = [3]int{parts[0] - 1, 5 + rng.Intn(5), rng.Intn(4)}
+	}
+	return formatVersion(parts)
+}
+
+func parseVersion(version string) [3]int {
+	var parts [3]int
+	for i, field := range strings.SplitN(version, ".", 3) {
+		parts[i], _ = strconv.Atoi(field)
+	}
+	return parts
+}
+
+func formatVersion(parts [3]int) string {
+	return fmt.Sprintf("%d.%d.%d", parts[0], parts[1], parts[2])
+}
@@ -0,0 +1,132 @@
+package discover
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultDir is where macOS installs Mac App Store apps
+const DefaultDir = "/Applications"
+
+// InstalledApp is a Mac App Store app found on disk
+type InstalledApp struct {
+	Path     string
+	BundleID string
+	Name     string
+	Version  string
+}
+
+// Scan looks for Mac App Store apps in dir. Only apps carrying an App Store
+// receipt (Contents/_MASReceipt/receipt) are returned, since apps installed
+// any other way can't be looked up in the App Store.
+func Scan(dir string) ([]InstalledApp, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var apps []InstalledApp
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".app") {
+			continue
+		}
+
+		appPath := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(appPath, "Contents", "_MASReceipt", "receipt")); err != nil {
+			continue
+		}
+
+		info, err := readInfoPlist(filepath.Join(appPath, "Contents", "Info.plist"))
+		if err != nil || info["CFBundleIdentifier"] == "" {
+			continue
+		}
+
+		name := info["CFBundleName"]
+		if name == "" {
+			name = strings.TrimSuffix(entry.Name(), ".app")
+		}
+
+		apps = append(apps, InstalledApp{
+			Path:     appPath,
+			BundleID: info["CFBundleIdentifier"],
+			Name:     name,
+			Version:  info["CFBundleShortVersionString"],
+		})
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+
+	return apps, nil
+}
+
+// readInfoPlist returns the top-level string values of an Info.plist. Binary
+// plists are converted to XML with plutil, which ships with macOS.
+func readInfoPlist(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		data, err = exec.Command("plutil", "-convert", "xml1", "-o", "-", path).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert binary plist: %w", err)
+		}
+	}
+
+	return parseXMLPlist(data)
+}
+
+// parseXMLPlist extracts the <key>/<string> pairs of the top-level plist dict
+func parseXMLPlist(data []byte) (map[string]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	values := make(map[string]string)
+
+	depth := 0
+	var key string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			// depth 2 is the top-level <dict> inside <plist>; its children are at depth 3
+			if depth != 3 {
+				continue
+			}
+
+			var text string
+			if err := decoder.DecodeElement(&text, &t); err != nil {
+				return nil, fmt.Errorf("failed to parse plist: %w", err)
+			}
+			depth--
+
+			switch t.Name.Local {
+			case "key":
+				key = text
+			case "string":
+				if key != "" {
+					values[key] = text
+				}
+				key = ""
+			default:
+				key = ""
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return values, nil
+}
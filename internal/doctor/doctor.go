@@ -0,0 +1,235 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/storage"
+)
+
+// Finding statuses
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// maxClockSkew is the clock difference to the App Store above which a warning is raised
+const maxClockSkew = 2 * time.Minute
+
+// Finding is the result of a single diagnostic check
+type Finding struct {
+	Check   string
+	Status  string
+	Message string
+	// Hint suggests how to fix a warning or failure
+	Hint string
+}
+
+// Run performs all diagnostic checks. cfg may be nil if the configuration
+// failed to load, in which case loadErr is reported and checks that need
+// configuration are skipped.
+func Run(ctx context.Context, cfg *config.Config, loadErr error) []Finding {
+	findings := checkConfig(loadErr)
+	if cfg == nil {
+		return findings
+	}
+
+	findings = append(findings, checkDataDir(cfg.DataDir, cfg.EncryptionKeyFile)...)
+
+	client := appstore.NewClientWithCountry(cfg.Country)
+	findings = append(findings, checkAppStore(ctx, client)...)
+
+	findings = append(findings, checkNotifiers(cfg)...)
+
+	return findings
+}
+
+// Failed reports whether any finding is a failure
+func Failed(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkConfig(loadErr error) []Finding {
+	var findings []Finding
+	if loadErr != nil {
+		findings = append(findings, Finding{
+			Check:   "config",
+			Status:  StatusFail,
+			Message: loadErr.Error(),
+			Hint:    "fix the MAVT_* environment variables; see .env.example",
+		})
+	}
+
+	for _, warning := range config.Warnings() {
+		findings = append(findings, Finding{
+			Check:   "config",
+			Status:  StatusWarn,
+			Message: warning,
+			Hint:    "check the variable name and value against .env.example",
+		})
+	}
+
+	if loadErr == nil && len(findings) == 0 {
+		findings = append(findings, Finding{Check: "config", Status: StatusOK, Message: "configuration is valid"})
+	}
+	return findings
+}
+
+func checkDataDir(dataDir, keyFile string) []Finding {
+	store, err := storage.Open(dataDir, keyFile)
+	if err == nil {
+		err = store.Ping()
+	}
+	if err != nil {
+		return []Finding{{
+			Check:   "data dir",
+			Status:  StatusFail,
+			Message: fmt.Sprintf("%s: %v", dataDir, err),
+			Hint:    fmt.Sprintf("make sure %s exists and is writable by uid %d, and MAVT_ENCRYPTION_KEY_FILE (if set) is readable", dataDir, os.Getuid()),
+		}}
+	}
+
+	findings := []Finding{{Check: "data dir", Status: StatusOK, Message: fmt.Sprintf("%s is readable and writable", dataDir)}}
+
+	problems, err := store.Verify()
+	if err != nil {
+		return append(findings, Finding{Check: "storage", Status: StatusFail, Message: err.Error(),
+			Hint: "check the permissions of the directories inside " + dataDir})
+	}
+	for _, problem := range problems {
+		findings = append(findings, Finding{Check: "storage", Status: StatusFail, Message: problem,
+			Hint: "restore the file from a backup, or delete it and re-add the app"})
+	}
+	if len(problems) == 0 {
+		findings = append(findings, Finding{Check: "storage", Status: StatusOK, Message: "all stored files are valid"})
+	}
+
+	return findings
+}
+
+func checkAppStore(ctx context.Context, client *appstore.Client) []Finding {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	serverTime, err := client.Ping(ctx)
+	if err != nil {
+		return []Finding{{
+			Check:   "app store",
+			Status:  StatusFail,
+			Message: err.Error(),
+			Hint:    "check DNS, proxy and firewall access to itunes.apple.com:443",
+		}}
+	}
+
+	findings := []Finding{{Check: "app store", Status: StatusOK, Message: "iTunes lookup API is reachable"}}
+
+	if serverTime.IsZero() {
+		return findings
+	}
+	skew := time.Since(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		findings = append(findings, Finding{
+			Check:   "clock",
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("local clock differs from the App Store by %s", skew.Round(time.Second)),
+			Hint:    "enable NTP time sync; update times and 'recent' windows depend on the clock",
+		})
+	} else {
+		findings = append(findings, Finding{Check: "clock", Status: StatusOK, Message: "clock is in sync"})
+	}
+
+	return findings
+}
+
+// endpoint is a notification target to test for reachability
+type endpoint struct {
+	name string
+	addr string
+	err  error
+}
+
+// checkNotifiers checks that configured notification endpoints accept TCP
+// connections, without sending a notification
+func checkNotifiers(cfg *config.Config) []Finding {
+	var endpoints []endpoint
+	if cfg.AppriseURL != "" {
+		addr, err := urlAddress(cfg.AppriseURL)
+		endpoints = append(endpoints, endpoint{"apprise", addr, err})
+	}
+	if cfg.WebhookURL != "" {
+		addr, err := urlAddress(cfg.WebhookURL)
+		endpoints = append(endpoints, endpoint{"webhook", addr, err})
+	}
+	if cfg.SMTPHost != "" {
+		endpoints = append(endpoints, endpoint{"smtp", net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)), nil})
+	}
+
+	if len(endpoints) == 0 {
+		return []Finding{{Check: "notifier", Status: StatusOK, Message: "no notifiers configured"}}
+	}
+
+	var findings []Finding
+	for _, target := range endpoints {
+		err := target.err
+		if err == nil {
+			var conn net.Conn
+			conn, err = net.DialTimeout("tcp", target.addr, 5*time.Second)
+			if err == nil {
+				conn.Close()
+			}
+		}
+
+		if err != nil {
+			findings = append(findings, Finding{
+				Check:   target.name,
+				Status:  StatusFail,
+				Message: fmt.Sprintf("cannot connect: %v", err),
+				Hint:    "check the configured URL/host and that the service is running",
+			})
+			continue
+		}
+		findings = append(findings, Finding{Check: target.name, Status: StatusOK, Message: target.addr + " is reachable"})
+	}
+
+	return findings
+}
+
+// urlAddress returns the host:port to dial for an HTTP(S) URL
+func urlAddress(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("URL has no host")
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
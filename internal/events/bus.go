@@ -0,0 +1,66 @@
+// Package events delivers what the tracker observes to the integrations that
+// act on it, so the tracker doesn't call each of them directly
+package events
+
+import (
+	"sync"
+
+	"github.com/thomas/mavt/internal/recovery"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Handler receives published events
+type Handler func(*models.Event)
+
+// Bus delivers events synchronously to its subscribers, in the order they
+// subscribed. A panicking subscriber is recovered so the others still run.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscription
+}
+
+// subscription is a handler and the event types it receives; nil types
+// means every type
+type subscription struct {
+	types   map[string]bool
+	handler Handler
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for the given event types, or for every event
+// if none are given
+func (b *Bus) Subscribe(handler Handler, eventTypes ...string) {
+	sub := subscription{handler: handler}
+	if len(eventTypes) > 0 {
+		sub.types = make(map[string]bool, len(eventTypes))
+		for _, eventType := range eventTypes {
+			sub.types[eventType] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, sub)
+}
+
+// Publish delivers an event to every subscriber of its type
+func (b *Bus) Publish(event *models.Event) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		if sub.types != nil && !sub.types[event.Type] {
+			continue
+		}
+		handler := sub.handler
+		recovery.Run("event "+event.Type, func() error {
+			handler(event)
+			return nil
+		})
+	}
+}
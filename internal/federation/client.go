@@ -0,0 +1,91 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// SourcePrefix identifies apps pulled from an upstream instance in storage.
+// The full source is the prefix followed by the upstream's host.
+const SourcePrefix = "upstream:"
+
+// Client reads tracked apps and version updates from another MAVT instance
+// through its REST API
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewClient creates a client for the MAVT instance at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		httpClient: httpclient.New(30 * time.Second),
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Source returns the AppInfo.Source recorded for apps pulled from this upstream
+func (c *Client) Source() string {
+	if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+		return SourcePrefix + u.Host
+	}
+	return SourcePrefix + c.baseURL
+}
+
+// IsUpstreamSource reports whether an app's source is an upstream instance
+func IsUpstreamSource(source string) bool {
+	return strings.HasPrefix(source, SourcePrefix)
+}
+
+// FetchApps returns the apps tracked by the upstream instance
+func (c *Client) FetchApps(ctx context.Context) ([]*models.AppInfo, error) {
+	var apps []*models.AppInfo
+	if err := c.get(ctx, "/api/apps", &apps); err != nil {
+		return nil, fmt.Errorf("failed to fetch apps: %w", err)
+	}
+	return apps, nil
+}
+
+// FetchUpdates returns the upstream's version updates within since
+func (c *Client) FetchUpdates(ctx context.Context, since time.Duration) ([]models.VersionUpdate, error) {
+	var updates []models.VersionUpdate
+	path := "/api/updates?since=" + url.QueryEscape(since.String())
+	if err := c.get(ctx, path, &updates); err != nil {
+		return nil, fmt.Errorf("failed to fetch updates: %w", err)
+	}
+	return updates, nil
+}
+
+// get fetches a JSON document from the upstream API into out
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upstream returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Package fleet describes the managed device fleet apps must keep working on,
+// and finds tracked apps that are, or are about to become, incompatible with
+// it
+package fleet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Profile is the device fleet compatibility is checked against
+type Profile struct {
+	// MinOSVersion is the oldest OS version still running in the fleet, e.g.
+	// "15.0"
+	MinOSVersion string `json:"min_os_version,omitempty"`
+
+	// Devices are device models still in the fleet, as App Store
+	// supportedDevices names (e.g. "iPadAir2"), or families with a trailing
+	// "*" (e.g. "iPad*")
+	Devices []string `json:"devices,omitempty"`
+}
+
+// LoadProfile reads a profile from a JSON file
+func LoadProfile(file string) (Profile, error) {
+	var profile Profile
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return profile, fmt.Errorf("failed to read fleet profile: %w", err)
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("failed to parse fleet profile %s: %w", file, err)
+	}
+	return profile, nil
+}
+
+// IsZero reports whether the profile describes nothing, so no compatibility
+// checks apply
+func (p Profile) IsZero() bool {
+	return p.MinOSVersion == "" && len(p.Devices) == 0
+}
+
+// String summarizes the profile, e.g. for printing the configuration
+func (p Profile) String() string {
+	var parts []string
+	if p.MinOSVersion != "" {
+		parts = append(parts, "min OS "+p.MinOSVersion)
+	}
+	if len(p.Devices) > 0 {
+		parts = append(parts, "devices "+strings.Join(p.Devices, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ExceedsMinOS reports whether an app's minimum OS version is above the
+// fleet's oldest OS, so some devices can't install it
+func (p Profile) ExceedsMinOS(minOS string) bool {
+	return p.MinOSVersion != "" && minOS != "" && models.CompareVersions(minOS, p.MinOSVersion) > 0
+}
+
+// Unsupported returns the fleet devices an app's supportedDevices list
+// doesn't include. An empty list means the App Store didn't report devices,
+// so nothing is unsupported.
+func (p Profile) Unsupported(devices []string) []string {
+	if len(devices) == 0 {
+		return nil
+	}
+
+	supported := deviceModels(devices)
+	var unsupported []string
+	for _, entry := range p.Devices {
+		if !supports(supported, entry) {
+			unsupported = append(unsupported, entry)
+		}
+	}
+	return unsupported
+}
+
+// Dropped returns the fleet devices an app supported before but not now. A
+// family is only dropped once no model in it is supported.
+func (p Profile) Dropped(old, current []string) []string {
+	if len(old) == 0 || len(current) == 0 {
+		return nil
+	}
+
+	oldModels, currentModels := deviceModels(old), deviceModels(current)
+	var dropped []string
+	for _, entry := range p.Devices {
+		if supports(oldModels, entry) && !supports(currentModels, entry) {
+			dropped = append(dropped, entry)
+		}
+	}
+	return dropped
+}
+
+// DiffDevices returns the device models added and removed between two
+// supportedDevices lists, sorted
+func DiffDevices(old, current []string) (added, removed []string) {
+	if len(old) == 0 || len(current) == 0 {
+		return nil, nil
+	}
+
+	oldModels, currentModels := deviceModels(old), deviceModels(current)
+	for key, model := range currentModels {
+		if _, ok := oldModels[key]; !ok {
+			added = append(added, model)
+		}
+	}
+	for key, model := range oldModels {
+		if _, ok := currentModels[key]; !ok {
+			removed = append(removed, model)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// Kinds of compatibility risk
+const (
+	RiskMinOS   = "min_os"
+	RiskDevices = "devices"
+)
+
+// Risk is a tracked app that some fleet devices can't install or update
+type Risk struct {
+	BundleID string `json:"bundle_id"`
+	AppName  string `json:"app_name"`
+	Version  string `json:"version"`
+	Kind     string `json:"kind"`
+	Summary  string `json:"summary"`
+
+	// MinOSVersion is set on min_os risks, Devices on devices risks
+	MinOSVersion string   `json:"min_os_version,omitempty"`
+	Devices      []string `json:"devices,omitempty"`
+}
+
+// Risks returns the compatibility risks of apps against the profile, by app
+// name. Apps whose versions don't come from the App Store are skipped.
+func (p Profile) Risks(apps []*models.AppInfo) []Risk {
+	risks := []Risk{}
+	for _, app := range apps {
+		if app.Source != "" {
+			continue
+		}
+
+		if p.ExceedsMinOS(app.MinOSVersion) {
+			risks = append(risks, Risk{
+				BundleID:     app.BundleID,
+				AppName:      app.Name(),
+				Version:      app.Version,
+				Kind:         RiskMinOS,
+				Summary:      fmt.Sprintf("Requires OS %s; devices on OS %s can't update", app.MinOSVersion, p.MinOSVersion),
+				MinOSVersion: app.MinOSVersion,
+			})
+		}
+		if unsupported := p.Unsupported(app.SupportedDevices); len(unsupported) > 0 {
+			risks = append(risks, Risk{
+				BundleID: app.BundleID,
+				AppName:  app.Name(),
+				Version:  app.Version,
+				Kind:     RiskDevices,
+				Summary:  "Not supported on " + strings.Join(unsupported, ", "),
+				Devices:  unsupported,
+			})
+		}
+	}
+
+	sort.SliceStable(risks, func(i, j int) bool {
+		return strings.ToLower(risks[i].AppName) < strings.ToLower(risks[j].AppName)
+	})
+	return risks
+}
+
+// deviceModel returns the model name of an App Store supportedDevices entry,
+// which repeats it as e.g. "iPadAir2-iPadAir2"
+func deviceModel(device string) string {
+	model, _, _ := strings.Cut(device, "-")
+	return model
+}
+
+// deviceModels returns the distinct model names of supportedDevices entries,
+// keyed case-insensitively
+func deviceModels(devices []string) map[string]string {
+	byKey := make(map[string]string, len(devices))
+	for _, device := range devices {
+		model := deviceModel(device)
+		byKey[strings.ToLower(model)] = model
+	}
+	return byKey
+}
+
+// supports reports whether supported includes a fleet entry: the model, or
+// for a family any model starting with it
+func supports(supported map[string]string, entry string) bool {
+	entry = strings.ToLower(entry)
+	prefix, family := strings.CutSuffix(entry, "*")
+	if !family {
+		_, ok := supported[entry]
+		return ok
+	}
+	for key := range supported {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+// Package httpclient provides the HTTP transport shared by MAVT's outbound
+// clients (App Store, notifications, webhooks and other integrations), so
+// connections are pooled once and timeouts, TLS and the User-Agent are
+// configured in one place
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options tune the shared transport
+type Options struct {
+	// Timeout overrides every client's own request timeout; zero keeps them
+	Timeout time.Duration
+
+	// MaxIdleConns limits idle keep-alive connections, overall and per host
+	MaxIdleConns int
+
+	// TLSMinVersion is the oldest TLS version accepted: "1.2" or "1.3"
+	TLSMinVersion string
+
+	// CAFile is a PEM file of extra trusted CA certificates, e.g. for a
+	// TLS-intercepting proxy
+	CAFile string
+
+	// UserAgent is sent with requests that don't set their own
+	UserAgent string
+
+	// RateLimits caps requests per minute by source, for clients created
+	// with NewForSource; sources without a positive limit are unlimited
+	RateLimits map[string]int
+}
+
+// DefaultUserAgent is sent until Configure sets another
+const DefaultUserAgent = "mavt"
+
+// TLSVersions maps the accepted TLSMinVersion values to their tls constants
+var TLSVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var (
+	mu      sync.RWMutex
+	timeout time.Duration
+	shared  = &sharedTransport{next: userAgentTransport{next: newTransport(100, nil), userAgent: DefaultUserAgent}}
+)
+
+// Configure applies opts to the shared transport, including clients created
+// before it was called. Timeout only applies to clients created afterwards.
+func Configure(opts Options) error {
+	var tlsConfig *tls.Config
+	if opts.TLSMinVersion != "" || opts.CAFile != "" {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if opts.TLSMinVersion != "" {
+		version, ok := TLSVersions[opts.TLSMinVersion]
+		if !ok {
+			return fmt.Errorf("unsupported TLS version %q: must be 1.2 or 1.3", opts.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+	if opts.CAFile != "" {
+		pool, err := certPool(opts.CAFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	maxIdle := opts.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = 100
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	setRateLimits(opts.RateLimits)
+
+	mu.Lock()
+	defer mu.Unlock()
+	timeout = opts.Timeout
+	shared.set(userAgentTransport{next: newTransport(maxIdle, tlsConfig), userAgent: userAgent})
+	return nil
+}
+
+// New returns a client using the shared transport, with the configured
+// timeout or defaultTimeout if none is configured
+func New(defaultTimeout time.Duration) *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if timeout > 0 {
+		defaultTimeout = timeout
+	}
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: shared,
+	}
+}
+
+// Transport returns the shared transport
+func Transport() http.RoundTripper {
+	return shared
+}
+
+// newTransport tunes a copy of the default transport
+func newTransport(maxIdle int, tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = maxIdle
+	transport.MaxIdleConnsPerHost = maxIdle
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// certPool returns the system CA pool with the certificates in file added
+func certPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", file)
+	}
+	return pool, nil
+}
+
+// sharedTransport forwards to the currently configured transport
+type sharedTransport struct {
+	mu   sync.RWMutex
+	next http.RoundTripper
+}
+
+func (t *sharedTransport) set(next http.RoundTripper) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next = next
+}
+
+func (t *sharedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	next := t.next
+	t.mu.RUnlock()
+	return next.RoundTrip(req)
+}
+
+// userAgentTransport sets the User-Agent on requests that don't have one
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.next.RoundTrip(req)
+}
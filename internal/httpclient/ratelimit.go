@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	limitersMu sync.RWMutex
+	limiters   = map[string]*limiter{}
+)
+
+// setRateLimits replaces the per-source rate limits, in requests per minute
+func setRateLimits(limits map[string]int) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	limiters = make(map[string]*limiter, len(limits))
+	for source, perMinute := range limits {
+		if perMinute > 0 {
+			limiters[source] = &limiter{interval: time.Minute / time.Duration(perMinute)}
+		}
+	}
+}
+
+// NewForSource returns a client like New whose requests are spaced out to the
+// rate limit configured for source, if any. The limit is shared by every
+// client of the source and follows later calls to Configure.
+func NewForSource(source string, defaultTimeout time.Duration) *http.Client {
+	client := New(defaultTimeout)
+	client.Transport = limitedTransport{next: client.Transport, source: source}
+	return client
+}
+
+// limitedTransport waits for its source's limiter before each request
+type limitedTransport struct {
+	next   http.RoundTripper
+	source string
+}
+
+func (t limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limitersMu.RLock()
+	l := limiters[t.source]
+	limitersMu.RUnlock()
+
+	if l != nil {
+		if err := l.wait(req); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// limiter hands out request slots at a fixed interval
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the request's slot, or until its context is done
+func (l *limiter) wait(req *http.Request) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
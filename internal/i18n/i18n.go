@@ -0,0 +1,119 @@
+// Package i18n holds the message catalogs that translate the web interface
+// and notifications, one JSON file per language in locales/
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultLanguage is the language of the messages other catalogs fall back to
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalogs maps each language to its messages, keyed by message ID, e.g.
+// "notify.updated" for notifications or "ui.updates.title" for the web
+// interface
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to list catalogs: %v", err))
+	}
+
+	catalogs := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := locales.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", file.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return catalogs
+}
+
+// Languages returns the languages with a catalog, sorted
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Supported reports whether lang has a catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Translator looks up messages in one language
+type Translator struct {
+	lang     string
+	messages map[string]string
+}
+
+// New returns a translator for lang, or for DefaultLanguage if lang has no
+// catalog
+func New(lang string) *Translator {
+	if !Supported(lang) {
+		lang = DefaultLanguage
+	}
+	return &Translator{lang: lang, messages: catalogs[lang]}
+}
+
+// Language returns the translator's language
+func (t *Translator) Language() string {
+	return t.lang
+}
+
+// T returns the message for id, formatted with args as by fmt.Sprintf.
+// Messages missing from the language's catalog come from the default one; an
+// unknown id is returned as is.
+func (t *Translator) T(id string, args ...interface{}) string {
+	message, ok := t.messages[id]
+	if !ok {
+		message, ok = catalogs[DefaultLanguage][id]
+	}
+	if !ok {
+		return id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Date formats a date the way the language writes it, e.g. 2025-01-31 or
+// 31.01.2025
+func (t *Translator) Date(date time.Time) string {
+	return date.Format(t.T("format.date"))
+}
+
+// Messages returns the messages whose ids start with prefix, keyed by the
+// rest of their id, with the default language filling any gaps; the web
+// interface gets its messages this way
+func (t *Translator) Messages(prefix string) map[string]string {
+	messages := make(map[string]string)
+	for _, catalog := range []map[string]string{catalogs[DefaultLanguage], t.messages} {
+		for id, message := range catalog {
+			if strings.HasPrefix(id, prefix) {
+				messages[strings.TrimPrefix(id, prefix)] = message
+			}
+		}
+	}
+	return messages
+}
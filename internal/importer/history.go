@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Header names accepted in a version history CSV, alongside bundleIDHeaders
+var (
+	versionHeaders      = []string{"version", "app version", "version string"}
+	releaseDateHeaders  = []string{"release date", "release_date", "released", "released_at", "date", "updated_at"}
+	releaseNotesHeaders = []string{"release notes", "release_notes", "notes", "what's new", "whats new"}
+)
+
+// ParseHistoryCSV reads past versions of apps from a CSV with bundle ID,
+// version and release date columns and an optional release notes column.
+// Dates are YYYY-MM-DD or RFC 3339. Versions are grouped by bundle ID.
+func ParseHistoryCSV(r io.Reader) (map[string][]models.VersionUpdate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	bundleCol := findColumn(header, bundleIDHeaders)
+	versionCol := findColumn(header, versionHeaders)
+	dateCol := findColumn(header, releaseDateHeaders)
+	notesCol := findColumn(header, releaseNotesHeaders)
+	if bundleCol < 0 || versionCol < 0 || dateCol < 0 {
+		return nil, fmt.Errorf("CSV must have bundle ID, version and release date columns")
+	}
+
+	field := func(record []string, col int) string {
+		if col < 0 || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	history := make(map[string][]models.VersionUpdate)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		bundleID := field(record, bundleCol)
+		version := field(record, versionCol)
+		if bundleID == "" && version == "" {
+			continue
+		}
+		if bundleID == "" || version == "" {
+			return nil, fmt.Errorf("row %d: bundle ID and version are required", row)
+		}
+
+		released, err := parseReleaseDate(field(record, dateCol))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		history[bundleID] = append(history[bundleID], models.VersionUpdate{
+			BundleID:     bundleID,
+			NewVersion:   version,
+			UpdatedAt:    released,
+			ReleasedAt:   &released,
+			ReleaseNotes: field(record, notesCol),
+		})
+	}
+
+	return history, nil
+}
+
+// parseReleaseDate parses a release date given as YYYY-MM-DD or RFC 3339
+func parseReleaseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid release date %q (use YYYY-MM-DD or RFC 3339)", s)
+}
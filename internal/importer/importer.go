@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thomas/mavt/internal/tracker"
+)
+
+// Entry is a single app row read from an MDM/CSV export
+type Entry struct {
+	Row      int    `json:"row"`
+	BundleID string `json:"bundle_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Result describes what happened (or would happen on a dry run) to an entry
+type Result struct {
+	Entry
+	ResolvedBundleID string `json:"resolved_bundle_id,omitempty"`
+	ResolvedName     string `json:"resolved_name,omitempty"`
+	Version          string `json:"version,omitempty"`
+	Status           string `json:"status"`
+	Error            string `json:"error,omitempty"`
+}
+
+// Import result statuses
+const (
+	StatusTracked        = "tracked"
+	StatusWouldTrack     = "would_track"
+	StatusAlreadyTracked = "already_tracked"
+	StatusNotFound       = "not_found"
+	StatusFailed         = "failed"
+)
+
+// Header names used by common MDM exports (Jamf Pro, Intune, Kandji, Mosyle, ...)
+var (
+	bundleIDHeaders = []string{"bundle id", "bundle_id", "bundleid", "bundle identifier", "identifier", "app identifier", "application identifier"}
+	nameHeaders     = []string{"app name", "app_name", "name", "display name", "application", "application name", "title"}
+)
+
+// ParseCSV reads app entries from a CSV export. The header row must contain a
+// bundle ID column, an app name column, or both; other columns are ignored.
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	bundleCol := findColumn(header, bundleIDHeaders)
+	nameCol := findColumn(header, nameHeaders)
+	if bundleCol < 0 && nameCol < 0 {
+		return nil, fmt.Errorf("CSV must have a bundle ID or app name column")
+	}
+
+	var entries []Entry
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		entry := Entry{Row: row}
+		if bundleCol >= 0 && bundleCol < len(record) {
+			entry.BundleID = strings.TrimSpace(record[bundleCol])
+		}
+		if nameCol >= 0 && nameCol < len(record) {
+			entry.Name = strings.TrimSpace(record[nameCol])
+		}
+
+		if entry.BundleID == "" && entry.Name == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// findColumn returns the index of the first header matching one of the candidates
+func findColumn(header []string, candidates []string) int {
+	for _, candidate := range candidates {
+		for i, h := range header {
+			// Strip a UTF-8 BOM, which Excel adds to the first header cell
+			h = strings.TrimPrefix(h, "\ufeff")
+			if strings.EqualFold(strings.TrimSpace(h), candidate) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Run resolves each entry against the App Store and tracks it. With dryRun set,
+// entries are resolved but nothing is saved.
+func Run(tr *tracker.Tracker, entries []Entry, dryRun bool) ([]Result, error) {
+	tracked, err := tr.GetTrackedApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tracked apps: %w", err)
+	}
+
+	trackedMap := make(map[string]bool)
+	for _, app := range tracked {
+		trackedMap[app.BundleID] = true
+	}
+
+	results := make([]Result, 0, len(entries))
+	for _, entry := range entries {
+		result := Result{Entry: entry}
+
+		app, err := tr.ResolveApp(entry.BundleID, entry.Name)
+		if err != nil {
+			result.Status = StatusNotFound
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.ResolvedBundleID = app.BundleID
+		result.ResolvedName = app.TrackName
+		result.Version = app.Version
+
+		switch {
+		case trackedMap[app.BundleID]:
+			result.Status = StatusAlreadyTracked
+		case dryRun:
+			result.Status = StatusWouldTrack
+		default:
+			if err := tr.TrackApp(app.BundleID); err != nil {
+				result.Status = StatusFailed
+				result.Error = err.Error()
+			} else {
+				result.Status = StatusTracked
+			}
+		}
+
+		// Avoid tracking the same app twice when the export lists it more than once
+		trackedMap[app.BundleID] = true
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,129 @@
+package jamf
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// mobileDeviceAppsPath is the Classic API resource for mobile device apps,
+// which the Jamf Pro API doesn't cover yet
+const mobileDeviceAppsPath = "/JSSResource/mobiledeviceapplications"
+
+// mobileDeviceAppResponse is the part of a Classic API mobile device app
+// needed to update it
+type mobileDeviceAppResponse struct {
+	MobileDeviceApplication struct {
+		General struct {
+			ID      int    `json:"id"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"general"`
+		Scope struct {
+			MobileDeviceGroups []struct {
+				ID int `json:"id"`
+			} `json:"mobile_device_groups"`
+		} `json:"scope"`
+	} `json:"mobile_device_application"`
+}
+
+// mobileDeviceAppUpdate is the Classic API XML body that updates a mobile
+// device app's version and scoped groups
+type mobileDeviceAppUpdate struct {
+	XMLName xml.Name `xml:"mobile_device_application"`
+	General struct {
+		Version string `xml:"version"`
+	} `xml:"general"`
+	Scope struct {
+		Groups []mobileDeviceGroup `xml:"mobile_device_groups>mobile_device_group"`
+	} `xml:"scope"`
+}
+
+type mobileDeviceGroup struct {
+	ID int `xml:"id"`
+}
+
+// UpdateMobileDeviceApp sets the version of the mobile device app with
+// bundleID to version, so Jamf Pro sends the update to the devices in its
+// scope, and adds the mobile device groups with groupIDs to that scope.
+// Groups already in scope are kept.
+func (c *Client) UpdateMobileDeviceApp(bundleID, version string, groupIDs []string) error {
+	token, err := c.getToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", c.baseURL+mobileDeviceAppsPath+"/bundleid/"+url.PathEscape(bundleID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create app request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Jamf mobile device app: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("no Jamf mobile device app with bundle ID %s", bundleID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Jamf mobile device app returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var current mobileDeviceAppResponse
+	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+		return fmt.Errorf("failed to decode Jamf mobile device app: %w", err)
+	}
+
+	var update mobileDeviceAppUpdate
+	update.General.Version = version
+	scoped := make(map[int]bool)
+	for _, group := range current.MobileDeviceApplication.Scope.MobileDeviceGroups {
+		scoped[group.ID] = true
+		update.Scope.Groups = append(update.Scope.Groups, mobileDeviceGroup{ID: group.ID})
+	}
+	for _, groupID := range groupIDs {
+		id, err := strconv.Atoi(groupID)
+		if err != nil {
+			return fmt.Errorf("invalid Jamf mobile device group ID %q", groupID)
+		}
+		if !scoped[id] {
+			scoped[id] = true
+			update.Scope.Groups = append(update.Scope.Groups, mobileDeviceGroup{ID: id})
+		}
+	}
+
+	body, err := xml.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to encode Jamf app update: %w", err)
+	}
+
+	appURL := fmt.Sprintf("%s%s/id/%d", c.baseURL, mobileDeviceAppsPath, current.MobileDeviceApplication.General.ID)
+	req, err = http.NewRequest("PUT", appURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create app update request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/xml")
+
+	putResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update Jamf mobile device app: %w", err)
+	}
+	defer putResp.Body.Close()
+
+	if putResp.StatusCode != http.StatusOK && putResp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(putResp.Body)
+		return fmt.Errorf("Jamf mobile device app update returned status %d: %s", putResp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package jamf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+)
+
+const (
+	tokenPath         = "/api/oauth/token"
+	mobileDevicesPath = "/api/v2/mobile-devices/detail"
+	pageSize          = 100
+)
+
+// Client handles communication with the Jamf Pro API using API client credentials
+type Client struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// DeviceApp represents an app installed on a managed device
+type DeviceApp struct {
+	DeviceID   string
+	DeviceName string
+	BundleID   string
+	Version    string
+}
+
+// NewClient creates a new Jamf Pro API client
+func NewClient(baseURL, clientID, clientSecret string) *Client {
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   httpclient.New(30 * time.Second),
+	}
+}
+
+// tokenResponse represents the Jamf Pro OAuth token response
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// mobileDevicesResponse represents a page of mobile device inventory
+type mobileDevicesResponse struct {
+	TotalCount int `json:"totalCount"`
+	Results    []struct {
+		MobileDeviceID string `json:"mobileDeviceId"`
+		General        struct {
+			DisplayName string `json:"displayName"`
+		} `json:"general"`
+		Applications []struct {
+			Identifier   string `json:"identifier"`
+			Version      string `json:"version"`
+			ShortVersion string `json:"shortVersion"`
+		} `json:"applications"`
+	} `json:"results"`
+}
+
+// getToken returns a cached access token, requesting a new one when it is about to expire
+func (c *Client) getToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+
+	resp, err := c.httpClient.PostForm(c.baseURL+tokenPath, form)
+	if err != nil {
+		return "", fmt.Errorf("failed to request Jamf token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("Jamf token request returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tr tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("failed to decode Jamf token: %w", err)
+	}
+
+	// Refresh a little early so in-flight requests don't use an expired token
+	c.token = tr.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tr.ExpiresIn)*time.Second - 30*time.Second)
+
+	return c.token, nil
+}
+
+// FetchMobileDeviceApps returns every app installed on every managed mobile device
+func (c *Client) FetchMobileDeviceApps() ([]DeviceApp, error) {
+	token, err := c.getToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []DeviceApp
+	for page := 0; ; page++ {
+		params := url.Values{}
+		params.Set("section", "APPLICATIONS")
+		params.Set("page", fmt.Sprintf("%d", page))
+		params.Set("page-size", fmt.Sprintf("%d", pageSize))
+
+		req, err := http.NewRequest("GET", c.baseURL+mobileDevicesPath+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create inventory request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch Jamf inventory: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("Jamf inventory returned status %d: %s", resp.StatusCode, string(body))
+		}
+
+		var inventory mobileDevicesResponse
+		err = json.NewDecoder(resp.Body).Decode(&inventory)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Jamf inventory: %w", err)
+		}
+
+		for _, device := range inventory.Results {
+			for _, app := range device.Applications {
+				version := app.ShortVersion
+				if version == "" {
+					version = app.Version
+				}
+				apps = append(apps, DeviceApp{
+					DeviceID:   device.MobileDeviceID,
+					DeviceName: device.General.DisplayName,
+					BundleID:   app.Identifier,
+					Version:    version,
+				})
+			}
+		}
+
+		if len(inventory.Results) < pageSize || (page+1)*pageSize >= inventory.TotalCount {
+			break
+		}
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,53 @@
+package jamf
+
+import (
+	"sort"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// BuildComplianceReport compares installed app versions against the latest tracked
+// versions and returns one entry per tracked app that is installed on any device
+func BuildComplianceReport(apps []*models.AppInfo, installed []DeviceApp) []models.ComplianceEntry {
+	byBundleID := make(map[string][]DeviceApp)
+	for _, app := range installed {
+		byBundleID[app.BundleID] = append(byBundleID[app.BundleID], app)
+	}
+
+	var report []models.ComplianceEntry
+	for _, app := range apps {
+		devices := byBundleID[app.BundleID]
+		if len(devices) == 0 {
+			continue
+		}
+
+		entry := models.ComplianceEntry{
+			BundleID:      app.BundleID,
+			TrackName:     app.TrackName,
+			LatestVersion: app.Version,
+			TotalDevices:  len(devices),
+		}
+
+		for _, device := range devices {
+			if models.CompareVersions(device.Version, app.Version) < 0 {
+				entry.DevicesBehind = append(entry.DevicesBehind, models.DeviceVersion{
+					DeviceID:         device.DeviceID,
+					DeviceName:       device.DeviceName,
+					InstalledVersion: device.Version,
+				})
+			}
+		}
+
+		report = append(report, entry)
+	}
+
+	// Apps with the most out-of-date devices first
+	sort.Slice(report, func(i, j int) bool {
+		if len(report[i].DevicesBehind) != len(report[j].DevicesBehind) {
+			return len(report[i].DevicesBehind) > len(report[j].DevicesBehind)
+		}
+		return report[i].TrackName < report[j].TrackName
+	})
+
+	return report
+}
@@ -0,0 +1,208 @@
+package mdm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	intuneTokenURL = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+	graphURL       = "https://graph.microsoft.com/v1.0"
+	graphScope     = "https://graph.microsoft.com/.default"
+)
+
+// Intune deploys updates through Microsoft Intune by making the app a required
+// install for each device group (Entra ID group ID). Intune keeps required
+// App Store apps updated, so groups that already require it need nothing.
+type Intune struct {
+	tenantID     string
+	clientID     string
+	clientSecret string
+	graphURL     string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewIntune creates an Intune pusher using an app registration with the
+// DeviceManagementApps.ReadWrite.All application permission
+func NewIntune(tenantID, clientID, clientSecret string) *Intune {
+	return &Intune{
+		tenantID:     tenantID,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		graphURL:     graphURL,
+		httpClient:   httpclient.New(30 * time.Second),
+	}
+}
+
+// graphApps is a page of Intune mobile apps
+type graphApps struct {
+	Value []struct {
+		ID       string `json:"id"`
+		BundleID string `json:"bundleId"`
+	} `json:"value"`
+	NextLink string `json:"@odata.nextLink"`
+}
+
+// graphAssignments are an Intune app's assignments
+type graphAssignments struct {
+	Value []struct {
+		Intent string `json:"intent"`
+		Target struct {
+			GroupID string `json:"groupId"`
+		} `json:"target"`
+	} `json:"value"`
+}
+
+func (i *Intune) PushUpdate(update *models.VersionUpdate, groups []string) error {
+	appID, err := i.findApp(update.BundleID)
+	if err != nil {
+		return err
+	}
+
+	var assignments graphAssignments
+	if err := i.do("GET", i.graphURL+"/deviceAppManagement/mobileApps/"+appID+"/assignments", nil, &assignments); err != nil {
+		return err
+	}
+	required := make(map[string]bool)
+	for _, assignment := range assignments.Value {
+		if assignment.Intent == "required" {
+			required[strings.ToLower(assignment.Target.GroupID)] = true
+		}
+	}
+
+	for _, group := range groups {
+		if required[strings.ToLower(group)] {
+			continue
+		}
+		assignment := map[string]interface{}{
+			"@odata.type": "#microsoft.graph.mobileAppAssignment",
+			"intent":      "required",
+			"target": map[string]string{
+				"@odata.type": "#microsoft.graph.groupAssignmentTarget",
+				"groupId":     group,
+			},
+		}
+		if err := i.do("POST", i.graphURL+"/deviceAppManagement/mobileApps/"+appID+"/assignments", assignment, nil); err != nil {
+			return fmt.Errorf("failed to assign to group %s: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// findApp returns the Intune ID of the iOS app with bundleID
+func (i *Intune) findApp(bundleID string) (string, error) {
+	next := i.graphURL + "/deviceAppManagement/mobileApps?$select=id,bundleId"
+	for next != "" {
+		var page graphApps
+		if err := i.do("GET", next, nil, &page); err != nil {
+			return "", err
+		}
+		for _, app := range page.Value {
+			if strings.EqualFold(app.BundleID, bundleID) {
+				return app.ID, nil
+			}
+		}
+		next = page.NextLink
+	}
+	return "", fmt.Errorf("no Intune app with bundle ID %s", bundleID)
+}
+
+// do sends a Graph API request with an optional JSON body, decoding the JSON
+// response into out if it isn't nil
+func (i *Intune) do(method, endpoint string, body, out interface{}) error {
+	token, err := i.getToken()
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := i.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Intune request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return statusError("Intune "+method, resp.StatusCode, data)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode Intune response: %w", err)
+		}
+	}
+	return nil
+}
+
+// getToken returns a cached access token, requesting a new one when it is about to expire
+func (i *Intune) getToken() (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.token != "" && time.Now().Before(i.tokenExpiry) {
+		return i.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", i.clientID)
+	form.Set("client_secret", i.clientSecret)
+	form.Set("scope", graphScope)
+
+	resp, err := i.httpClient.PostForm(fmt.Sprintf(intuneTokenURL, url.PathEscape(i.tenantID)), form)
+	if err != nil {
+		return "", fmt.Errorf("failed to request Intune token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return "", statusError("Intune token request", resp.StatusCode, data)
+	}
+
+	var tr struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", fmt.Errorf("failed to decode Intune token: %w", err)
+	}
+
+	// Refresh a little early so in-flight requests don't use an expired token
+	i.token = tr.AccessToken
+	i.tokenExpiry = time.Now().Add(time.Duration(tr.ExpiresIn)*time.Second - 30*time.Second)
+
+	return i.token, nil
+}
@@ -0,0 +1,93 @@
+// Package mdm pushes approved app updates to managed devices through an MDM,
+// closing the loop from detecting an update to deploying it
+package mdm
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/jamf"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Pusher asks an MDM to deploy an app update to device groups. An empty group
+// list means wherever the MDM already deploys the app.
+type Pusher interface {
+	PushUpdate(update *models.VersionUpdate, groups []string) error
+}
+
+// New returns the pusher for the configured MDM, or nil if none is configured
+func New(cfg *config.Config) Pusher {
+	switch cfg.MDMProvider {
+	case config.MDMProviderJamf:
+		return &jamfPusher{client: jamf.NewClient(cfg.JamfURL, cfg.JamfClientID, cfg.JamfClientSecret)}
+	case config.MDMProviderIntune:
+		return NewIntune(cfg.IntuneTenantID, cfg.IntuneClientID, cfg.IntuneClientSecret)
+	case config.MDMProviderSimpleMDM:
+		return NewSimpleMDM(cfg.SimpleMDMAPIKey)
+	}
+	return nil
+}
+
+// jamfPusher deploys updates by bumping the app's version in Jamf Pro
+type jamfPusher struct {
+	client *jamf.Client
+}
+
+func (p *jamfPusher) PushUpdate(update *models.VersionUpdate, groups []string) error {
+	return p.client.UpdateMobileDeviceApp(update.BundleID, update.NewVersion, groups)
+}
+
+// Deployer pushes the update of every approved_update event it receives and
+// records the outcome
+type Deployer struct {
+	provider string
+	pusher   Pusher
+	groups   []string
+	record   func(bundleID, version string, deployment models.Deployment) error
+}
+
+// NewDeployer creates a deployer pushing to groups through pusher. record is
+// called with the outcome of each push, e.g. to store it with the update.
+func NewDeployer(provider string, pusher Pusher, groups []string, record func(bundleID, version string, deployment models.Deployment) error) *Deployer {
+	return &Deployer{provider: provider, pusher: pusher, groups: groups, record: record}
+}
+
+// HandleEvent is the deployer's event bus subscriber. Pushes run in the
+// background so approving an update doesn't wait for the MDM.
+func (d *Deployer) HandleEvent(event *models.Event) {
+	if event.Type != models.EventApprovedUpdate {
+		return
+	}
+	for i := range event.Updates {
+		update := event.Updates[i]
+		go d.Deploy(&update)
+	}
+}
+
+// Deploy pushes one update and records the outcome, which it returns
+func (d *Deployer) Deploy(update *models.VersionUpdate) models.Deployment {
+	deployment := models.Deployment{Provider: d.provider, Groups: d.groups, At: time.Now()}
+	if err := d.pusher.PushUpdate(update, d.groups); err != nil {
+		deployment.Error = err.Error()
+		log.Printf("Failed to push %s %s via %s: %v", update.BundleID, update.NewVersion, d.provider, err)
+	} else {
+		log.Printf("Pushed %s %s via %s", update.BundleID, update.NewVersion, d.provider)
+	}
+
+	if err := d.record(update.BundleID, update.NewVersion, deployment); err != nil {
+		log.Printf("Failed to record deployment of %s %s: %v", update.BundleID, update.NewVersion, err)
+	}
+	return deployment
+}
+
+// statusError describes an unexpected MDM API response
+func statusError(action string, status int, body []byte) error {
+	const maxBody = 200
+	if len(body) > maxBody {
+		body = append(body[:maxBody:maxBody], "..."...)
+	}
+	return fmt.Errorf("%s returned status %d: %s", action, status, string(body))
+}
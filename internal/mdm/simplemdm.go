@@ -0,0 +1,129 @@
+package mdm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const simpleMDMURL = "https://a.simplemdm.com/api/v1"
+
+// SimpleMDM deploys updates through SimpleMDM: with groups (assignment group
+// IDs) it pushes each group's apps to its devices, otherwise it asks every
+// device with the app installed to update it
+type SimpleMDM struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewSimpleMDM creates a SimpleMDM pusher authenticating with an API key
+func NewSimpleMDM(apiKey string) *SimpleMDM {
+	return &SimpleMDM{
+		apiKey:     apiKey,
+		baseURL:    simpleMDMURL,
+		httpClient: httpclient.New(30 * time.Second),
+	}
+}
+
+// simpleMDMApps is a page of SimpleMDM apps
+type simpleMDMApps struct {
+	Data []struct {
+		ID         int `json:"id"`
+		Attributes struct {
+			BundleIdentifier string `json:"bundle_identifier"`
+		} `json:"attributes"`
+	} `json:"data"`
+	HasMore bool `json:"has_more"`
+}
+
+func (s *SimpleMDM) PushUpdate(update *models.VersionUpdate, groups []string) error {
+	if len(groups) > 0 {
+		for _, group := range groups {
+			if err := s.post("/assignment_groups/" + url.PathEscape(group) + "/push_apps"); err != nil {
+				return fmt.Errorf("failed to push apps to assignment group %s: %w", group, err)
+			}
+		}
+		return nil
+	}
+
+	appID, err := s.findApp(update.BundleID)
+	if err != nil {
+		return err
+	}
+	return s.post(fmt.Sprintf("/apps/%d/update_installation", appID))
+}
+
+// findApp returns the SimpleMDM ID of the app with bundleID
+func (s *SimpleMDM) findApp(bundleID string) (int, error) {
+	startingAfter := 0
+	for {
+		params := url.Values{}
+		params.Set("limit", "100")
+		if startingAfter > 0 {
+			params.Set("starting_after", fmt.Sprintf("%d", startingAfter))
+		}
+
+		req, err := http.NewRequest("GET", s.baseURL+"/apps?"+params.Encode(), nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.SetBasicAuth(s.apiKey, "")
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return 0, fmt.Errorf("SimpleMDM request failed: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return 0, statusError("SimpleMDM apps", resp.StatusCode, data)
+		}
+
+		var page simpleMDMApps
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode SimpleMDM apps: %w", err)
+		}
+
+		for _, app := range page.Data {
+			if strings.EqualFold(app.Attributes.BundleIdentifier, bundleID) {
+				return app.ID, nil
+			}
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return 0, fmt.Errorf("no SimpleMDM app with bundle ID %s", bundleID)
+		}
+		startingAfter = page.Data[len(page.Data)-1].ID
+	}
+}
+
+// post sends a SimpleMDM API request without a body
+func (s *SimpleMDM) post(path string) error {
+	req, err := http.NewRequest("POST", s.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(s.apiKey, "")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("SimpleMDM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return statusError("SimpleMDM "+path, resp.StatusCode, data)
+	}
+	return nil
+}
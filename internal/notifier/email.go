@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer sends HTML email over SMTP. STARTTLS is used when the server offers it.
+type Mailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewMailer creates a new SMTP mailer. Authentication is skipped when username is empty.
+func NewMailer(host string, port int, username, password, from string) *Mailer {
+	return &Mailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// SendHTML sends an HTML email to the given recipients
+func (m *Mailer) SendHTML(to []string, subject, htmlBody string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	if err := smtp.SendMail(addr, auth, m.from, to, m.buildMessage(to, subject, htmlBody)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage builds a MIME message with a base64-encoded HTML body
+func (m *Mailer) buildMessage(to []string, subject, htmlBody string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	// Wrap base64 at 76 characters per RFC 2045
+	encoded := base64.StdEncoding.EncodeToString([]byte(htmlBody))
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded + "\r\n")
+
+	return msg.Bytes()
+}
@@ -3,12 +3,15 @@ package notifier
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/internal/i18n"
 	"github.com/thomas/mavt/pkg/models"
 )
 
@@ -17,6 +20,25 @@ type Notifier struct {
 	appriseURL string
 	enabled    bool
 	client     *http.Client
+	webhook    *Webhook
+	publicURL  string
+
+	// messages translates notifications into the configured language
+	messages *i18n.Translator
+
+	// notesDisplay is which release notes notifications show when a
+	// translation is stored: one of the NotesDisplay values
+	notesDisplay string
+
+	// vendorOf returns what the user recorded about a developer, added to
+	// warnings so they say who to escalate to
+	vendorOf func(developer string) *models.Vendor
+
+	// appriseMode is how updates found together are notified: AppriseBatch
+	// or AppriseIndividual. targets are further Apprise service URLs with
+	// their own mode.
+	appriseMode string
+	targets     []Target
 }
 
 // NewNotifier creates a new notifier instance
@@ -24,17 +46,201 @@ func NewNotifier(appriseURL string) *Notifier {
 	enabled := appriseURL != ""
 
 	return &Notifier{
-		appriseURL: appriseURL,
-		enabled:    enabled,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		appriseURL:   appriseURL,
+		enabled:      enabled,
+		client:       httpclient.New(10 * time.Second),
+		messages:     i18n.New(i18n.DefaultLanguage),
+		notesDisplay: NotesTranslated,
+		appriseMode:  AppriseBatch,
+	}
+}
+
+// SetLanguage sets the language notifications are written in; see
+// i18n.Languages
+func (n *Notifier) SetLanguage(lang string) {
+	n.messages = i18n.New(lang)
+}
+
+// Which release notes notifications show for an update with translated notes
+const (
+	// NotesTranslated shows the translation in place of the original
+	NotesTranslated = "translated"
+	// NotesOriginal shows the original notes, ignoring the translation
+	NotesOriginal = "original"
+	// NotesBoth shows the translation followed by the original
+	NotesBoth = "both"
+)
+
+// SetNotesDisplay sets which release notes notifications show for updates
+// with translated notes: NotesTranslated, NotesOriginal or NotesBoth
+func (n *Notifier) SetNotesDisplay(display string) {
+	n.notesDisplay = display
+}
+
+// releaseNotes returns an update's release notes as notifications show them
+func (n *Notifier) releaseNotes(update *models.VersionUpdate) string {
+	if update.TranslatedNotes == "" || n.notesDisplay == NotesOriginal {
+		return update.ReleaseNotes
+	}
+	if n.notesDisplay == NotesBoth {
+		return update.TranslatedNotes + "\n\n" + n.messages.T("notify.originalNotes") + "\n" + update.ReleaseNotes
 	}
+	return update.TranslatedNotes
+}
+
+// How Apprise is notified of several updates found together
+const (
+	// AppriseBatch sends one notification listing the updates
+	AppriseBatch = "batch"
+	// AppriseIndividual sends one notification per update
+	AppriseIndividual = "individual"
+)
+
+// TopicPlaceholder in a target's URL is replaced with the app's bundle ID,
+// dots turned into dashes, in AppriseIndividual mode: e.g.
+// ntfy://ntfy.sh/mavt-{topic} gives each app its own ntfy topic
+const TopicPlaceholder = "{topic}"
+
+// Target is an Apprise service URL, or several separated by commas, notified
+// through the Apprise API in its own mode
+type Target struct {
+	URL  string
+	Mode string
+}
+
+// SetAppriseMode sets how the Apprise URL is notified of updates found
+// together: AppriseBatch or AppriseIndividual
+func (n *Notifier) SetAppriseMode(mode string) {
+	n.appriseMode = mode
+}
+
+// SetTargets sets the Apprise service URLs notified of updates besides the
+// Apprise API's configured ones
+func (n *Notifier) SetTargets(targets []Target) {
+	n.targets = targets
+}
+
+// SetWebhook adds an outbound webhook that receives version updates alongside Apprise
+func (n *Notifier) SetWebhook(webhook *Webhook) {
+	n.webhook = webhook
+}
+
+// HasWebhook reports whether an outbound webhook is configured
+func (n *Notifier) HasWebhook() bool {
+	return n.webhook != nil
+}
+
+// RedeliverWebhook sends the payload of a logged webhook delivery again
+func (n *Notifier) RedeliverWebhook(delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	if n.webhook == nil {
+		return nil, fmt.Errorf("no outbound webhook is configured")
+	}
+	return n.webhook.Redeliver(delivery)
+}
+
+// NotifyEvent sends an event other than a version update to the outbound
+// webhook, if it is configured and subscribed to the event's type
+func (n *Notifier) NotifyEvent(event *models.Event) error {
+	if n.webhook == nil {
+		return nil
+	}
+	return n.webhook.SendEvent(event)
+}
+
+// HandleEvent is the notifier's event bus subscriber: version updates are
+// notified via Apprise and the webhook, alert events via Apprise and other
+// events go to the webhook. Failures are logged.
+func (n *Notifier) HandleEvent(event *models.Event) {
+	if !n.IsEnabled() {
+		return
+	}
+
+	if event.Type == models.EventVersionUpdate {
+		if err := n.NotifyUpdates(event.Updates); err != nil {
+			log.Printf("Failed to send notifications: %v", err)
+		}
+		return
+	}
+
+	if models.IsAlertEvent(event.Type) {
+		if err := n.notifyAlert(event); err != nil {
+			log.Printf("Failed to send %s alert for %q: %v", event.Type, event.BundleID, err)
+		}
+		return
+	}
+
+	if err := n.NotifyEvent(event); err != nil {
+		log.Printf("Failed to send %s event for %q: %v", event.Type, event.BundleID, err)
+	}
+}
+
+// notifyAlert sends an alert event as the notification for its type
+func (n *Notifier) notifyAlert(event *models.Event) error {
+	alert := event.Alert
+	if alert == nil {
+		return fmt.Errorf("%s event has no alert details", event.Type)
+	}
+
+	switch event.Type {
+	case models.EventMinOSIncrease:
+		return n.NotifyMinOSIncrease(alert.App, alert.OldMinOS, alert.FleetMinOS)
+	case models.EventDevicesDropped:
+		return n.NotifyDevicesDropped(alert.App, alert.Devices)
+	case models.EventSizeGrowth:
+		return n.NotifySizeGrowth(alert.App, alert.OldSize)
+	case models.EventReviewBurst:
+		return n.NotifyReviewBurst(alert.App, alert.OneStarCount)
+	case models.EventAssignment:
+		if len(event.Updates) == 0 {
+			return fmt.Errorf("assignment event has no update")
+		}
+		return n.NotifyAssignment(&event.Updates[0])
+	case models.EventWatchlistMatch:
+		if len(event.Updates) == 0 || alert.Watchlist == nil {
+			return fmt.Errorf("watchlist_match event has no update or watchlist")
+		}
+		return n.NotifyWatchlist(alert.Watchlist, &event.Updates[0], alert.Keywords)
+	case models.EventRelease:
+		return n.NotifyRelease(alert.CurrentVersion, alert.NewVersion, alert.URL)
+	}
+	return fmt.Errorf("unknown alert event %s", event.Type)
+}
+
+// SetPublicURL sets the web interface's base URL, so notifications link to
+// each update
+func (n *Notifier) SetPublicURL(publicURL string) {
+	n.publicURL = publicURL
+}
+
+// SetVendors sets how warnings look up the app developer's vendor details
+func (n *Notifier) SetVendors(vendorOf func(developer string) *models.Vendor) {
+	n.vendorOf = vendorOf
+}
+
+// withVendor appends the app developer's vendor details to a warning body
+func (n *Notifier) withVendor(body string, app *models.AppInfo) string {
+	if n.vendorOf == nil || app.ArtistName == "" {
+		return body
+	}
+	vendor := n.vendorOf(app.ArtistName)
+	if vendor == nil || vendor.IsEmpty() {
+		return body
+	}
+	return body + "\n" + strings.Join(vendor.Summary(), "\n")
+}
+
+// UpdateURL returns the web interface link for an update, or "" if the base
+// URL or the update ID is unknown
+func UpdateURL(publicURL string, update *models.VersionUpdate) string {
+	if publicURL == "" || update.ID == "" {
+		return ""
+	}
+	return strings.TrimRight(publicURL, "/") + "/#update/" + update.ID
 }
 
 // IsEnabled returns whether notifications are enabled
 func (n *Notifier) IsEnabled() bool {
-	return n.enabled
+	return n.enabled || n.webhook != nil
 }
 
 // NotifyUpdate sends a notification for an app update
@@ -43,58 +249,321 @@ func (n *Notifier) NotifyUpdate(update *models.VersionUpdate) error {
 		return nil
 	}
 
-	title := fmt.Sprintf("📱 %s Updated", update.TrackName)
-	body := fmt.Sprintf("Version %s → %s", update.OldVersion, update.NewVersion)
+	title, body := n.updateMessage(update)
+	return n.sendNotification(title, body, "info")
+}
+
+// updateMessage writes the notification for an app update
+func (n *Notifier) updateMessage(update *models.VersionUpdate) (title, body string) {
+	title = n.messages.T("notify.updated", update.Name())
+	body = n.messages.T("notify.version", update.Change())
+	if update.AppNotes != "" {
+		body += "\n" + n.messages.T("notify.notes", update.AppNotes)
+	}
+	if update.Assignment != nil {
+		body += "\n" + n.messages.T("notify.assignedTo", update.Assignment.To)
+	}
+	if link := UpdateURL(n.publicURL, update); link != "" {
+		body += "\n" + link
+	}
 
 	if update.ReleaseNotes != "" {
 		// Truncate long release notes for notification
-		notes := update.ReleaseNotes
+		notes := n.releaseNotes(update)
 		if len(notes) > 500 {
 			notes = notes[:500] + "..."
 		}
 		body += "\n\n" + notes
 	}
 
-	return n.sendNotification(title, body, "info")
+	return title, body
 }
 
-// NotifyUpdates sends a batch notification for multiple updates
+// NotifyUpdates sends notifications for multiple updates to Apprise, its
+// targets and the outbound webhook, if configured
 func (n *Notifier) NotifyUpdates(updates []models.VersionUpdate) error {
-	if !n.enabled || len(updates) == 0 {
+	if len(updates) == 0 {
 		return nil
 	}
 
-	if len(updates) == 1 {
-		return n.NotifyUpdate(&updates[0])
+	var errs []error
+	if n.webhook != nil {
+		if err := n.webhook.SendUpdates(updates, n.publicURL); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	title := fmt.Sprintf("📱 %d App Updates Detected", len(updates))
+	if n.enabled {
+		if err := n.notifyAppriseUpdates(updates, n.appriseMode, ""); err != nil {
+			errs = append(errs, err)
+		}
+		for _, target := range n.targets {
+			if err := n.notifyAppriseUpdates(updates, target.Mode, target.URL); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// notifyAppriseUpdates notifies Apprise of multiple updates, to the given
+// service URLs or, if empty, the API's configured ones. In AppriseIndividual
+// mode each update is notified on its own; otherwise one notification lists
+// them all, and updates of apps linked into one application are notified
+// together.
+func (n *Notifier) notifyAppriseUpdates(updates []models.VersionUpdate, mode, urls string) error {
+	if mode == AppriseIndividual {
+		var errs []error
+		for i := range updates {
+			title, body := n.updateMessage(&updates[i])
+			if err := n.send(title, body, "info", topicURLs(urls, &updates[i])); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	groups := groupByApplication(updates)
+	if len(groups) == 1 {
+		if len(groups[0]) == 1 {
+			title, body := n.updateMessage(&groups[0][0])
+			return n.send(title, body, "info", urls)
+		}
+		title, body := n.applicationUpdateMessage(groups[0])
+		return n.send(title, body, "info", urls)
+	}
+
+	title := n.messages.T("notify.updatesDetected", len(groups))
 
 	var body strings.Builder
-	for i, update := range updates {
+	for i, group := range groups {
 		if i > 0 {
 			body.WriteString("\n")
 		}
-		body.WriteString(fmt.Sprintf("• %s: %s → %s",
-			update.TrackName, update.OldVersion, update.NewVersion))
+		if len(group) == 1 {
+			body.WriteString(fmt.Sprintf("• %s: %s", group[0].Name(), group[0].Change()))
+		} else {
+			changes := make([]string, len(group))
+			for j, update := range group {
+				changes[j] = update.Platform + " " + update.Change()
+			}
+			body.WriteString(fmt.Sprintf("• %s: %s", group[0].Application, strings.Join(changes, ", ")))
+		}
+		if link := UpdateURL(n.publicURL, &group[0]); link != "" {
+			body.WriteString(" " + link)
+		}
 
 		// Limit to first 10 updates in notification
-		if i >= 9 && len(updates) > 10 {
-			body.WriteString(fmt.Sprintf("\n... and %d more", len(updates)-10))
+		if i >= 9 && len(groups) > 10 {
+			body.WriteString("\n" + n.messages.T("notify.more", len(groups)-10))
 			break
 		}
 	}
 
-	return n.sendNotification(title, body.String(), "success")
+	return n.send(title, body.String(), "success", urls)
+}
+
+// topicURLs fills TopicPlaceholder in service URLs with the update's app
+func topicURLs(urls string, update *models.VersionUpdate) string {
+	return strings.ReplaceAll(urls, TopicPlaceholder, strings.ReplaceAll(update.BundleID, ".", "-"))
+}
+
+// applicationUpdateMessage writes one notification for updates to several
+// platforms of a linked application
+func (n *Notifier) applicationUpdateMessage(updates []models.VersionUpdate) (title, body string) {
+	title = n.messages.T("notify.updated", updates[0].Application)
+
+	var b strings.Builder
+	for i := range updates {
+		update := &updates[i]
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(fmt.Sprintf("• %s: %s", update.Platform, update.Change()))
+		if link := UpdateURL(n.publicURL, update); link != "" {
+			b.WriteString(" " + link)
+		}
+	}
+	for i := range updates {
+		if updates[i].ReleaseNotes != "" {
+			notes := n.releaseNotes(&updates[i])
+			if len(notes) > 500 {
+				notes = notes[:500] + "..."
+			}
+			b.WriteString("\n\n" + updates[i].Platform + ":\n" + notes)
+		}
+	}
+
+	return title, b.String()
+}
+
+// groupByApplication groups updates of apps linked into the same application,
+// keeping the order each group first appears in. Other updates are alone in
+// their group.
+func groupByApplication(updates []models.VersionUpdate) [][]models.VersionUpdate {
+	var groups [][]models.VersionUpdate
+	index := make(map[string]int)
+	for _, update := range updates {
+		if update.Application != "" {
+			if i, ok := index[update.Application]; ok {
+				groups[i] = append(groups[i], update)
+				continue
+			}
+			index[update.Application] = len(groups)
+		}
+		groups = append(groups, []models.VersionUpdate{update})
+	}
+	return groups
+}
+
+// NotifyAssignment sends a notification when a version update is assigned to someone
+func (n *Notifier) NotifyAssignment(update *models.VersionUpdate) error {
+	if !n.enabled || update.Assignment == nil {
+		return nil
+	}
+
+	title := n.messages.T("notify.assignment.title", update.Name(), update.NewVersion, update.Assignment.To)
+	body := n.messages.T("notify.assignment.body", update.Change(), n.messages.Date(update.UpdatedAt))
+	if update.Assignment.By != "" {
+		body += "\n" + n.messages.T("notify.assignedBy", update.Assignment.By)
+	}
+	if update.AppNotes != "" {
+		body += "\n" + n.messages.T("notify.notes", update.AppNotes)
+	}
+	if link := UpdateURL(n.publicURL, update); link != "" {
+		body += "\n" + link
+	}
+
+	return n.sendNotification(title, body, "info")
+}
+
+// NotifyWatchlist tells a user that an update's release notes mention
+// keywords on their watchlist, sending it to the watchlist's own Apprise
+// URLs if it has any
+func (n *Notifier) NotifyWatchlist(watchlist *models.Watchlist, update *models.VersionUpdate, keywords []string) error {
+	if !n.enabled {
+		return nil
+	}
+	// Checked again when sending, as the URL's host may resolve elsewhere now
+	if watchlist.NotifyURL != "" {
+		if err := ValidateServiceURLs(watchlist.NotifyURL); err != nil {
+			return err
+		}
+	}
+
+	title := n.messages.T("notify.watchlist.title", update.Name(), update.NewVersion, strings.Join(keywords, ", "))
+	body := n.messages.T("notify.watchlist.body", update.Change(), watchlist.User)
+	if link := UpdateURL(n.publicURL, update); link != "" {
+		body += "\n" + link
+	}
+	if notes := n.releaseNotes(update); notes != "" {
+		if len(notes) > 500 {
+			notes = notes[:500] + "..."
+		}
+		body += "\n\n" + notes
+	}
+
+	return n.send(title, body, "info", watchlist.NotifyURL)
+}
+
+// NotifyMinOSIncrease sends a warning when an app's minimum OS version rises above
+// the oldest OS version in the fleet, meaning those devices will stop receiving updates
+func (n *Notifier) NotifyMinOSIncrease(app *models.AppInfo, oldMinOS, fleetMinOS string) error {
+	if !n.enabled {
+		return nil
+	}
+
+	title := n.messages.T("notify.minOS.title", app.Name())
+	body := n.messages.T("notify.minOS.body", app.Version, app.MinOSVersion, oldMinOS, fleetMinOS)
+
+	return n.sendNotification(title, n.withVendor(body, app), "warning")
+}
+
+// NotifyDevicesDropped sends a warning when an app drops support for device
+// models still in the fleet, meaning they will stop receiving updates
+func (n *Notifier) NotifyDevicesDropped(app *models.AppInfo, devices []string) error {
+	if !n.enabled {
+		return nil
+	}
+
+	title := n.messages.T("notify.devicesDropped.title", app.Name())
+	body := n.messages.T("notify.devicesDropped.body", app.Version, strings.Join(devices, ", "))
+
+	return n.sendNotification(title, n.withVendor(body, app), "warning")
+}
+
+// NotifySizeGrowth sends a warning when an update grows an app's download
+// size, e.g. for planning the bandwidth to push it to managed devices
+func (n *Notifier) NotifySizeGrowth(app *models.AppInfo, oldSize int64) error {
+	if !n.enabled {
+		return nil
+	}
+
+	growth := float64(app.FileSizeBytes-oldSize) * 100 / float64(oldSize)
+	title := n.messages.T("notify.sizeGrowth.title", app.Name(), app.Version, growth)
+	body := n.messages.T("notify.sizeGrowth.body", formatBytes(oldSize), formatBytes(app.FileSizeBytes))
+
+	return n.sendNotification(title, n.withVendor(body, app), "warning")
+}
+
+// formatBytes formats a size in bytes as e.g. "48.2 MB"
+func formatBytes(size int64) string {
+	const unit = 1000
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, prefix := float64(size)/unit, 0
+	for value >= unit && prefix < 3 {
+		value /= unit
+		prefix++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "kMGT"[prefix])
+}
+
+// NotifyReviewBurst sends a warning when an app receives a burst of 1-star reviews after a release
+func (n *Notifier) NotifyReviewBurst(app *models.AppInfo, oneStarCount int) error {
+	if !n.enabled {
+		return nil
+	}
+
+	title := n.messages.T("notify.reviewBurst.title", app.Name(), app.Version)
+	body := n.messages.T("notify.reviewBurst.body", oneStarCount, app.Version, n.messages.Date(app.ReleaseDate))
+
+	return n.sendNotification(title, n.withVendor(body, app), "warning")
+}
+
+// NotifyRelease sends a notification that a newer MAVT release is available
+func (n *Notifier) NotifyRelease(currentVersion, newVersion, url string) error {
+	if !n.enabled {
+		return nil
+	}
+
+	title := n.messages.T("notify.release.title", newVersion)
+	body := n.messages.T("notify.release.body", currentVersion)
+	if url != "" {
+		body += "\n" + n.messages.T("notify.release.notes", url)
+	}
+
+	return n.sendNotification(title, body, "info")
 }
 
 // sendNotification sends a notification via Apprise API
 func (n *Notifier) sendNotification(title, body, notifyType string) error {
+	return n.send(title, body, notifyType, "")
+}
+
+// send posts a notification to the Apprise API. urls, if set, are the
+// Apprise service URLs to notify instead of the API's configured ones.
+func (n *Notifier) send(title, body, notifyType, urls string) error {
 	payload := map[string]interface{}{
 		"title": title,
 		"body":  body,
 		"type":  notifyType,
 	}
+	if urls != "" {
+		payload["urls"] = urls
+	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
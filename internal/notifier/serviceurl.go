@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsafeServiceURL is returned for a user's Apprise service URL that isn't
+// one or points into MAVT's or Apprise's own network
+var ErrUnsafeServiceURL = errors.New("unsafe service URL")
+
+// resolveTimeout bounds looking up the host of a service URL
+const resolveTimeout = 3 * time.Second
+
+// schemePattern matches Apprise service URL schemes, e.g. "ntfy" or "mailtos"
+var schemePattern = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// carrierNAT is the shared address space ISPs use behind NAT, 100.64.0.0/10
+var carrierNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// ValidateServiceURLs checks Apprise service URLs given by users, such as a
+// watchlist's notify_url, before the Apprise API is asked to send to them.
+// Each URL, separated by commas or spaces, needs a scheme and a host, and the
+// host may not be, or resolve to, a loopback, private or link-local address.
+// Hosts that don't resolve, such as a Discord webhook ID, are allowed.
+func ValidateServiceURLs(urls string) error {
+	for _, raw := range strings.FieldsFunc(urls, func(r rune) bool { return r == ',' || r == ' ' }) {
+		scheme, host, ok := splitServiceURL(raw)
+		if !ok {
+			return fmt.Errorf("%w: URLs must look like scheme://host/...", ErrUnsafeServiceURL)
+		}
+		if internalHost(host) {
+			return fmt.Errorf("%w: %s://%s points to a local or private address", ErrUnsafeServiceURL, scheme, host)
+		}
+	}
+	return nil
+}
+
+// splitServiceURL returns the scheme and host of an Apprise service URL. It
+// doesn't use url.Parse, which rejects URLs whose host holds a token, such as
+// tgram://123456:ABC-DEF/chat.
+func splitServiceURL(raw string) (scheme, host string, ok bool) {
+	scheme, rest, found := strings.Cut(raw, "://")
+	scheme = strings.ToLower(scheme)
+	if !found || !schemePattern.MatchString(scheme) {
+		return "", "", false
+	}
+
+	authority := rest
+	if i := strings.IndexAny(authority, "/?#"); i >= 0 {
+		authority = authority[:i]
+	}
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+	if strings.HasPrefix(authority, "[") {
+		end := strings.Index(authority, "]")
+		if end < 0 {
+			return "", "", false
+		}
+		host = authority[1:end]
+	} else {
+		host, _, _ = strings.Cut(authority, ":")
+	}
+	if host == "" || strings.ContainsAny(host, "%\\") {
+		return "", "", false
+	}
+	return scheme, host, true
+}
+
+// internalHost reports whether a host is, or resolves to, an address on a
+// local or private network
+func internalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return internalIP(ip)
+	}
+	// Numeric hosts such as 2130706433 or 0x7f.1 are IPv4 addresses to
+	// the C resolver Apprise uses
+	if ip := parseLegacyIPv4(host); ip != nil {
+		return internalIP(ip)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if internalIP(addr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// internalIP reports whether an address is loopback, private, link-local,
+// unspecified or carrier-grade NAT
+func internalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || carrierNAT.Contains(ip)
+}
+
+// parseLegacyIPv4 parses the one to four part decimal, octal or hex IPv4
+// forms inet_aton accepts, e.g. "127.1" or "0x7f000001", returning nil for
+// anything else
+func parseLegacyIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseUint(part, 0, 32)
+		if err != nil {
+			return nil
+		}
+		values[i] = value
+	}
+
+	// Each part but the last is one byte; the last fills the remaining bytes
+	var addr uint64
+	for i, value := range values[:len(values)-1] {
+		if value > 0xff {
+			return nil
+		}
+		addr |= value << (24 - 8*uint(i))
+	}
+	last := values[len(values)-1]
+	if last >= 1<<(8*uint(5-len(values))) {
+		return nil
+	}
+	addr |= last
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
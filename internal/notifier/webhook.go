@@ -0,0 +1,284 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+	"github.com/thomas/mavt/pkg/webhook"
+)
+
+// Webhook payload formats
+const (
+	// WebhookFormatJSON posts all updates from a check cycle in one nested JSON document
+	WebhookFormatJSON = "json"
+	// WebhookFormatFlat posts one request per update with only top-level string values,
+	// for low-code tools like Zapier and IFTTT
+	WebhookFormatFlat = "flat"
+)
+
+// Webhook posts version updates to an arbitrary HTTP endpoint
+type Webhook struct {
+	url    string
+	format string
+	secret string
+	client *http.Client
+
+	// events are the event types sent; nil means version updates only
+	events map[string]bool
+
+	// onDelivery is called with every delivery attempt, e.g. to log it
+	onDelivery func(*models.WebhookDelivery)
+}
+
+// deliveryIDHeader identifies a delivery to the receiver; a redelivery has a
+// new ID
+const deliveryIDHeader = "X-MAVT-Delivery"
+
+// maxResponseSnippet is how much of the endpoint's response is kept with a
+// delivery
+const maxResponseSnippet = 1024
+
+// NewWebhook creates a new outbound webhook. An empty format defaults to JSON.
+func NewWebhook(url, format string) *Webhook {
+	if format == "" {
+		format = WebhookFormatJSON
+	}
+
+	return &Webhook{
+		url:    url,
+		format: format,
+		client: httpclient.New(10 * time.Second),
+	}
+}
+
+// SetSecret signs every delivery with an HMAC of its body keyed with secret,
+// sent in the X-MAVT-Signature header
+func (w *Webhook) SetSecret(secret string) {
+	w.secret = secret
+}
+
+// SetEvents subscribes the webhook to the given event types ("all" for every
+// type). By default only version updates are sent.
+func (w *Webhook) SetEvents(eventTypes []string) {
+	w.events = make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if eventType == "all" {
+			for _, t := range models.EventTypes {
+				w.events[t] = true
+			}
+			continue
+		}
+		w.events[eventType] = true
+	}
+}
+
+// Subscribed reports whether the webhook is sent events of the given type
+func (w *Webhook) Subscribed(eventType string) bool {
+	if w.events == nil {
+		return eventType == models.EventVersionUpdate
+	}
+	return w.events[eventType]
+}
+
+// SetDeliveryHandler sets a function called with every delivery attempt,
+// successful or not
+func (w *Webhook) SetDeliveryHandler(handler func(*models.WebhookDelivery)) {
+	w.onDelivery = handler
+}
+
+// SendUpdates posts version updates in the configured format. With publicURL
+// set, flat payloads include a link to each update in the web interface.
+func (w *Webhook) SendUpdates(updates []models.VersionUpdate, publicURL string) error {
+	if !w.Subscribed(models.EventVersionUpdate) {
+		return nil
+	}
+
+	if w.format == WebhookFormatFlat {
+		for i := range updates {
+			payload := FlattenUpdate(&updates[i])
+			payload["url"] = UpdateURL(publicURL, &updates[i])
+			if err := w.post(payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return w.post(map[string]interface{}{
+		"event":   models.EventVersionUpdate,
+		"count":   len(updates),
+		"updates": updates,
+	})
+}
+
+// SendEvent posts an event other than a version update if the webhook is
+// subscribed to its type
+func (w *Webhook) SendEvent(event *models.Event) error {
+	if !w.Subscribed(event.Type) {
+		return nil
+	}
+
+	if w.format == WebhookFormatFlat {
+		return w.post(FlattenEvent(event))
+	}
+	return w.post(event)
+}
+
+// FlattenEvent converts an event into flat string key/value pairs, with its
+// details as top-level keys
+func FlattenEvent(event *models.Event) map[string]string {
+	flat := map[string]string{
+		"event":         event.Type,
+		"app_name":      event.AppName,
+		"bundle_id":     event.BundleID,
+		"occurred_at":   event.OccurredAt.UTC().Format(time.RFC3339),
+		"occurred_unix": strconv.FormatInt(event.OccurredAt.Unix(), 10),
+		"summary":       event.Summary,
+	}
+	for key, value := range event.Details {
+		if _, taken := flat[key]; !taken {
+			flat[key] = value
+		}
+	}
+	return flat
+}
+
+// FlattenUpdate converts an update into flat string key/value pairs. Timestamps
+// are provided both as RFC 3339 and as a plain date/time that automation tools
+// can display without parsing.
+func FlattenUpdate(update *models.VersionUpdate) map[string]string {
+	return map[string]string{
+		"event":            models.EventVersionUpdate,
+		"id":               update.ID,
+		"app_name":         update.Name(),
+		"app_notes":        update.AppNotes,
+		"assigned_to":      assignee(update),
+		"bundle_id":        update.BundleID,
+		"track_id":         strconv.FormatInt(update.TrackID, 10),
+		"kind":             update.Kind,
+		"severity":         update.ClassifySeverity(),
+		"old_version":      update.OldVersion,
+		"new_version":      update.NewVersion,
+		"release_notes":    update.ReleaseNotes,
+		"translated_notes": update.TranslatedNotes,
+		"released_at":      releasedAt(update),
+		"updated_at":       update.UpdatedAt.UTC().Format(time.RFC3339),
+		"updated_date":     update.UpdatedAt.UTC().Format("2006-01-02"),
+		"updated_time":     update.UpdatedAt.UTC().Format("15:04 UTC"),
+		"updated_unix":     strconv.FormatInt(update.UpdatedAt.Unix(), 10),
+		"summary":          summarize(update),
+	}
+}
+
+// summarize describes an update in one sentence
+func summarize(update *models.VersionUpdate) string {
+	switch update.Kind {
+	case models.UpdateKindReRelease:
+		return fmt.Sprintf("%s %s was re-released", update.Name(), update.NewVersion)
+	case models.UpdateKindRollback:
+		return fmt.Sprintf("%s rolled back from %s to %s", update.Name(), update.OldVersion, update.NewVersion)
+	}
+	return fmt.Sprintf("%s updated from %s to %s", update.Name(), update.OldVersion, update.NewVersion)
+}
+
+// releasedAt returns when the update's version was released as RFC 3339, or
+// "" if the release date is unknown
+func releasedAt(update *models.VersionUpdate) string {
+	if update.ReleasedAt == nil {
+		return ""
+	}
+	return update.ReleasedAt.UTC().Format(time.RFC3339)
+}
+
+// assignee returns who an update is assigned to, or "" if it is unassigned
+func assignee(update *models.VersionUpdate) string {
+	if update.Assignment == nil {
+		return ""
+	}
+	return update.Assignment.To
+}
+
+// post sends a JSON payload to the webhook URL
+func (w *Webhook) post(payload interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	_, err = w.deliver(jsonData, "")
+	return err
+}
+
+// Redeliver sends the payload of a logged delivery again, recording it as a
+// new delivery, and returns that delivery
+func (w *Webhook) Redeliver(delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	return w.deliver(delivery.Payload, delivery.ID)
+}
+
+// deliver posts a JSON body to the webhook URL and passes the attempt to the
+// delivery handler, if set
+func (w *Webhook) deliver(body []byte, redeliveryOf string) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{
+		ID:           newDeliveryID(),
+		DeliveredAt:  time.Now(),
+		Payload:      json.RawMessage(body),
+		RedeliveryOf: redeliveryOf,
+	}
+
+	err := w.send(body, delivery)
+	delivery.DurationMs = time.Since(delivery.DeliveredAt).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if w.onDelivery != nil {
+		w.onDelivery(delivery)
+	}
+	return delivery, err
+}
+
+// send makes the HTTP request for a delivery and records the response on it
+func (w *Webhook) send(body []byte, delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequest("POST", w.url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(deliveryIDHeader, delivery.ID)
+	if w.secret != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	delivery.Response = string(snippet)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// newDeliveryID returns a random ID for a webhook delivery
+func newDeliveryID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,123 @@
+package osreleases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// gdmfURL is Apple's public software update catalog used by MDM servers
+	gdmfURL = "https://gdmf.apple.com/v2/pmv"
+
+	// Source identifies OS release pseudo-apps in storage
+	Source = "apple-os"
+
+	// bundleIDPrefix is prepended to the lowercase platform name to build pseudo bundle IDs
+	bundleIDPrefix = "com.apple.os."
+)
+
+// Client fetches Apple OS release information
+type Client struct {
+	httpClient *http.Client
+	url        string
+}
+
+// NewClient creates a new OS release client, limited to the "os" source's
+// rate limit
+func NewClient() *Client {
+	return &Client{
+		httpClient: httpclient.NewForSource("os", 30*time.Second),
+		url:        gdmfURL,
+	}
+}
+
+// gdmfResponse represents the response from the GDMF catalog
+type gdmfResponse struct {
+	PublicAssetSets map[string][]gdmfAsset `json:"PublicAssetSets"`
+}
+
+// gdmfAsset represents a single OS release in the GDMF catalog
+type gdmfAsset struct {
+	ProductVersion   string   `json:"ProductVersion"`
+	Build            string   `json:"Build"`
+	PostingDate      string   `json:"PostingDate"`
+	SupportedDevices []string `json:"SupportedDevices"`
+}
+
+// BundleID returns the pseudo bundle ID used to store a platform's releases
+func BundleID(platform string) string {
+	return bundleIDPrefix + strings.ToLower(platform)
+}
+
+// FetchLatest returns the latest public release for each requested platform
+// (e.g. "iOS", "macOS", "visionOS") as a pseudo-app
+func (c *Client) FetchLatest(platforms []string) ([]*models.AppInfo, error) {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OS releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var catalog gdmfResponse
+	if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("failed to decode OS releases: %w", err)
+	}
+
+	var releases []*models.AppInfo
+	for _, platform := range platforms {
+		assets := findPlatform(catalog.PublicAssetSets, platform)
+		if len(assets) == 0 {
+			continue
+		}
+
+		// Older branches keep receiving security releases, so pick the highest
+		// version rather than the most recently posted one
+		latest := assets[0]
+		for _, asset := range assets[1:] {
+			if models.CompareVersions(asset.ProductVersion, latest.ProductVersion) > 0 {
+				latest = asset
+			}
+		}
+
+		releaseDate, err := time.Parse("2006-01-02", latest.PostingDate)
+		if err != nil {
+			releaseDate = time.Now()
+		}
+
+		releases = append(releases, &models.AppInfo{
+			BundleID:        BundleID(platform),
+			TrackName:       platform,
+			Version:         latest.ProductVersion,
+			ReleaseDate:     releaseDate,
+			ReleaseNotes:    fmt.Sprintf("Build %s, supported on %d device models", latest.Build, len(latest.SupportedDevices)),
+			ArtistName:      "Apple",
+			Source:          Source,
+			LastChecked:     time.Now(),
+			FirstDiscovered: time.Now(),
+		})
+	}
+
+	return releases, nil
+}
+
+// findPlatform looks up a platform's assets case-insensitively
+func findPlatform(sets map[string][]gdmfAsset, platform string) []gdmfAsset {
+	for name, assets := range sets {
+		if strings.EqualFold(name, platform) {
+			return assets
+		}
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+// Package packages watches npm and PyPI packages for new releases, recorded as
+// pseudo-apps so MAVT can follow libraries and tools as well as mobile apps
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Registries, which are also the AppInfo.Source of their packages
+const (
+	SourceNPM  = "npm"
+	SourcePyPI = "pypi"
+)
+
+const (
+	npmURL  = "https://registry.npmjs.org"
+	pypiURL = "https://pypi.org/pypi"
+)
+
+var (
+	// npmNamePattern and pypiNamePattern match valid package names; npm
+	// names may have an @scope/ prefix
+	npmNamePattern  = regexp.MustCompile(`^(@[a-z0-9][a-z0-9._~-]*/)?[a-z0-9][a-z0-9._~-]*$`)
+	pypiNamePattern = regexp.MustCompile(`(?i)^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+	// changelogPattern matches PyPI project URL labels that point at release notes
+	changelogPattern = regexp.MustCompile(`(?i)change|release|history|news`)
+)
+
+// ErrNotFound is returned when a registry has no such package
+var ErrNotFound = errors.New("package not found")
+
+// Package is a package in a registry
+type Package struct {
+	Registry string
+	Name     string
+}
+
+// ParsePackage parses a "registry:name" spec, e.g. "npm:react",
+// "npm:@babel/core" or "pypi:requests"
+func ParsePackage(spec string) (Package, error) {
+	registry, name, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok {
+		return Package{}, fmt.Errorf("invalid package %q: must be npm:<name> or pypi:<name>", spec)
+	}
+
+	p := Package{Registry: strings.ToLower(registry), Name: name}
+	switch p.Registry {
+	case SourceNPM:
+		if !npmNamePattern.MatchString(name) {
+			return Package{}, fmt.Errorf("invalid npm package name %q", name)
+		}
+	case SourcePyPI:
+		if !pypiNamePattern.MatchString(name) {
+			return Package{}, fmt.Errorf("invalid PyPI package name %q", name)
+		}
+	default:
+		return Package{}, fmt.Errorf("invalid package %q: registry must be npm or pypi", spec)
+	}
+	return p, nil
+}
+
+// String returns the package's spec
+func (p Package) String() string {
+	return p.Registry + ":" + p.Name
+}
+
+// BundleID returns the pseudo bundle ID a package's releases are stored
+// under, e.g. "npm.babel.core" for @babel/core
+func (p Package) BundleID() string {
+	name := strings.NewReplacer("@", "", "/", ".").Replace(p.Name)
+	return p.Registry + "." + strings.ToLower(name)
+}
+
+// IsSource reports whether an AppInfo.Source is a package registry
+func IsSource(source string) bool {
+	return source == SourceNPM || source == SourcePyPI
+}
+
+// Client fetches the latest releases of packages
+type Client struct {
+	npmClient  *http.Client
+	pypiClient *http.Client
+	npmToken   string
+	npmURL     string
+	pypiURL    string
+}
+
+// NewClient creates a new package registry client. Each registry is limited
+// to its source's rate limit, and npmToken, if set, authenticates npm
+// requests, e.g. for private packages.
+func NewClient(npmToken string) *Client {
+	return &Client{
+		npmClient:  httpclient.NewForSource(SourceNPM, 30*time.Second),
+		pypiClient: httpclient.NewForSource(SourcePyPI, 30*time.Second),
+		npmToken:   npmToken,
+		npmURL:     npmURL,
+		pypiURL:    pypiURL,
+	}
+}
+
+// FetchLatest returns a package's latest release as a pseudo-app. Its release
+// notes link to the package's changelog when one can be found.
+func (c *Client) FetchLatest(ctx context.Context, p Package) (*models.AppInfo, error) {
+	var app *models.AppInfo
+	var err error
+	switch p.Registry {
+	case SourceNPM:
+		app, err = c.fetchNPM(ctx, p)
+	case SourcePyPI:
+		app, err = c.fetchPyPI(ctx, p)
+	default:
+		return nil, fmt.Errorf("unsupported registry: %s", p.Registry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	app.BundleID = p.BundleID()
+	app.TrackName = p.Name
+	app.Source = p.Registry
+	app.LastChecked = time.Now()
+	app.FirstDiscovered = time.Now()
+	return app, nil
+}
+
+// npmVersion is the part of an npm registry version document MAVT uses
+type npmVersion struct {
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Homepage    string          `json:"homepage"`
+	Author      json.RawMessage `json:"author"`
+	Repository  json.RawMessage `json:"repository"`
+}
+
+// fetchNPM reads the version tagged latest. The full package document has the
+// release dates but can run to megabytes, so the release date is left unset.
+func (c *Client) fetchNPM(ctx context.Context, p Package) (*models.AppInfo, error) {
+	name := strings.Replace(p.Name, "/", "%2F", 1)
+	var latest npmVersion
+	if err := c.getJSON(ctx, SourceNPM, c.npmURL+"/"+name+"/latest", &latest); err != nil {
+		return nil, err
+	}
+
+	repository := npmRepositoryURL(latest.Repository)
+	changelog := ""
+	if strings.HasPrefix(repository, "https://github.com/") {
+		changelog = repository + "/releases"
+	} else if repository != "" {
+		changelog = repository
+	} else {
+		changelog = latest.Homepage
+	}
+
+	return &models.AppInfo{
+		Version:      latest.Version,
+		ReleaseNotes: releaseNotes(latest.Description, changelog),
+		ArtistName:   npmPerson(latest.Author),
+		Genre:        "npm package",
+	}, nil
+}
+
+// npmRepositoryURL returns a browsable URL for an npm repository field, which
+// is either a string or an object with a url
+func npmRepositoryURL(raw json.RawMessage) string {
+	var repo string
+	if json.Unmarshal(raw, &repo) != nil {
+		var obj struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(raw, &obj) != nil {
+			return ""
+		}
+		repo = obj.URL
+	}
+
+	// "github:owner/repo" and "owner/repo" shorthands
+	if rest, ok := strings.CutPrefix(repo, "github:"); ok {
+		repo = "https://github.com/" + rest
+	} else if !strings.Contains(repo, ":") && strings.Count(repo, "/") == 1 {
+		repo = "https://github.com/" + repo
+	}
+
+	repo = strings.TrimPrefix(repo, "git+")
+	repo = strings.Replace(repo, "git://", "https://", 1)
+	repo = strings.Replace(repo, "ssh://git@", "https://", 1)
+	repo = strings.TrimSuffix(repo, ".git")
+	if u, err := url.Parse(repo); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return ""
+	}
+	return repo
+}
+
+// npmPerson returns the name in an npm author field, which is either a string
+// like "Jane Doe <jane@example.com>" or an object with a name
+func npmPerson(raw json.RawMessage) string {
+	var person string
+	if json.Unmarshal(raw, &person) != nil {
+		var obj struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(raw, &obj) != nil {
+			return ""
+		}
+		return obj.Name
+	}
+	name, _, _ := strings.Cut(person, "<")
+	return strings.TrimSpace(name)
+}
+
+// pypiProject is the part of a PyPI project document MAVT uses
+type pypiProject struct {
+	Info struct {
+		Version     string            `json:"version"`
+		Summary     string            `json:"summary"`
+		Author      string            `json:"author"`
+		HomePage    string            `json:"home_page"`
+		ProjectURL  string            `json:"project_url"`
+		ProjectURLs map[string]string `json:"project_urls"`
+	} `json:"info"`
+	URLs []struct {
+		UploadTime string `json:"upload_time_iso_8601"`
+	} `json:"urls"`
+}
+
+func (c *Client) fetchPyPI(ctx context.Context, p Package) (*models.AppInfo, error) {
+	var project pypiProject
+	if err := c.getJSON(ctx, SourcePyPI, c.pypiURL+"/"+url.PathEscape(p.Name)+"/json", &project); err != nil {
+		return nil, err
+	}
+
+	app := &models.AppInfo{
+		Version:    project.Info.Version,
+		ArtistName: project.Info.Author,
+		Genre:      "PyPI package",
+	}
+
+	// The release date is the earliest upload of the latest release's files
+	for _, file := range project.URLs {
+		if uploaded, err := time.Parse(time.RFC3339, file.UploadTime); err == nil && (app.ReleaseDate.IsZero() || uploaded.Before(app.ReleaseDate)) {
+			app.ReleaseDate = uploaded
+		}
+	}
+
+	changelog := ""
+	for label, link := range project.Info.ProjectURLs {
+		if changelogPattern.MatchString(label) && (changelog == "" || link < changelog) {
+			changelog = link
+		}
+	}
+	if changelog == "" {
+		changelog = project.Info.HomePage
+	}
+	if changelog == "" {
+		changelog = project.Info.ProjectURL
+	}
+	app.ReleaseNotes = releaseNotes(project.Info.Summary, changelog)
+	return app, nil
+}
+
+// releaseNotes combines a package's description and changelog link
+func releaseNotes(description, changelog string) string {
+	notes := strings.TrimSpace(description)
+	if changelog != "" {
+		if notes != "" {
+			notes += "\n\n"
+		}
+		notes += "Changelog: " + changelog
+	}
+	return notes
+}
+
+// getJSON fetches and decodes a registry document
+func (c *Client) getJSON(ctx context.Context, registry, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.pypiClient
+	if registry == SourceNPM {
+		client = c.npmClient
+		if c.npmToken != "" {
+			req.Header.Set("Authorization", "Bearer "+c.npmToken)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("registry returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode registry response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package packages
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// npmSearch is the part of an npm registry search response MAVT uses
+type npmSearch struct {
+	Objects []struct {
+		Package struct {
+			Name        string `json:"name"`
+			Version     string `json:"version"`
+			Description string `json:"description"`
+			Date        string `json:"date"`
+			Publisher   struct {
+				Username string `json:"username"`
+			} `json:"publisher"`
+		} `json:"package"`
+	} `json:"objects"`
+}
+
+// Search finds packages in a registry matching term, returned as pseudo-apps
+// ready to track. PyPI has no search API, so a PyPI search looks up the
+// package named term and returns it if it exists.
+func (c *Client) Search(ctx context.Context, registry, term string, limit int) ([]*models.AppInfo, error) {
+	term = strings.TrimSpace(term)
+	if registry == SourcePyPI {
+		p, err := ParsePackage(SourcePyPI + ":" + strings.ReplaceAll(term, " ", "-"))
+		if err != nil {
+			return nil, nil
+		}
+		app, err := c.FetchLatest(ctx, p)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []*models.AppInfo{app}, nil
+	}
+
+	params := url.Values{}
+	params.Set("text", term)
+	params.Set("size", strconv.Itoa(limit))
+	var results npmSearch
+	if err := c.getJSON(ctx, SourceNPM, c.npmURL+"/-/v1/search?"+params.Encode(), &results); err != nil {
+		return nil, err
+	}
+
+	apps := make([]*models.AppInfo, 0, len(results.Objects))
+	for _, object := range results.Objects {
+		p := Package{Registry: SourceNPM, Name: object.Package.Name}
+		app := &models.AppInfo{
+			BundleID:     p.BundleID(),
+			TrackName:    p.Name,
+			Version:      object.Package.Version,
+			ReleaseNotes: object.Package.Description,
+			ArtistName:   object.Package.Publisher.Username,
+			Genre:        "npm package",
+			Source:       SourceNPM,
+		}
+		if published, err := time.Parse(time.RFC3339, object.Package.Date); err == nil {
+			app.ReleaseDate = published
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
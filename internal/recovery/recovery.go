@@ -0,0 +1,98 @@
+package recovery
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/thomas/mavt/internal/version"
+)
+
+// sentryEnabled is set once Init has configured a Sentry client
+var sentryEnabled bool
+
+// Init configures optional panic reporting to Sentry. An empty DSN disables reporting.
+func Init(dsn string) error {
+	if dsn == "" {
+		return nil
+	}
+
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:     dsn,
+		Release: "mavt@" + version.Version,
+	}); err != nil {
+		return fmt.Errorf("failed to initialize Sentry: %w", err)
+	}
+
+	sentryEnabled = true
+	return nil
+}
+
+// Flush waits for queued Sentry events to be sent
+func Flush() {
+	if sentryEnabled {
+		sentry.Flush(2 * time.Second)
+	}
+}
+
+// Report logs a recovered panic with its stack trace and reports it to Sentry
+func Report(where string, recovered interface{}) {
+	log.Printf("PANIC in %s: %v\n%s", where, recovered, debug.Stack())
+
+	if sentryEnabled {
+		hub := sentry.CurrentHub().Clone()
+		hub.ConfigureScope(func(scope *sentry.Scope) {
+			scope.SetTag("where", where)
+		})
+		hub.Recover(recovered)
+	}
+}
+
+// Recover recovers from a panic, reports it and lets the caller continue.
+// It must be called directly via defer:
+//
+//	defer recovery.Recover("slack track")
+func Recover(where string) {
+	if r := recover(); r != nil {
+		Report(where, r)
+	}
+}
+
+// Run calls fn, converting a panic into an error so a single failure can't
+// take down the process
+func Run(where string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			Report(where, r)
+			err = fmt.Errorf("panic in %s: %v", where, r)
+		}
+	}()
+	return fn()
+}
+
+// internalErrorBody is the API's JSON error for a panicking handler
+const internalErrorBody = `{"error":{"status":500,"code":"internal_error","message":"Internal server error"}}`
+
+// Middleware recovers from panics in HTTP handlers, reports them and responds with a 500
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				// Let net/http handle deliberate connection aborts as usual
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				Report(r.Method+" "+r.URL.Path, rec)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Content-Type-Options", "nosniff")
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprintln(w, internalErrorBody)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,104 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Cycle is the outcome of one replayed check cycle
+type Cycle struct {
+	Number int
+
+	// Tracked lists the apps tracked in the first cycle; Errors the apps
+	// that couldn't be tracked
+	Tracked []string
+	Errors  []string
+
+	// Updates are the updates detected; Events and Alerts are what would
+	// have been notified
+	Updates []models.VersionUpdate
+	Events  []models.Event
+	Alerts  []string
+}
+
+// Run replays source's recorded responses through a tracker configured by
+// cfg. The first cycle tracks every app, and each later cycle checks them
+// against the next recorded responses. History is kept in a temporary data
+// directory that is removed afterwards, and nothing is sent: notifications
+// are recorded in the returned cycles instead. OS release tracking, reviews,
+// in-app purchases, raw archiving and leader election are turned off since
+// they aren't recorded.
+func Run(ctx context.Context, cfg *config.Config, source *Source) ([]Cycle, error) {
+	dataDir, err := os.MkdirTemp("", "mavt-replay-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay data directory: %w", err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	store, err := storage.NewStorage(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize replay storage: %w", err)
+	}
+
+	replayCfg := *cfg
+	replayCfg.OSPlatforms = nil
+	replayCfg.Packages = nil
+	replayCfg.CustomSources = nil
+	replayCfg.TrackReviews = false
+	replayCfg.TrackInAppPurchases = false
+	replayCfg.ArchiveRawResponses = false
+	replayCfg.LeaderElection = false
+
+	notify := trackertest.NewFakeNotifier()
+	tr := tracker.NewTracker(&replayCfg, store, notify)
+	tr.SetSource(source)
+
+	recorded := recorder{notify: notify}
+
+	first := Cycle{Number: 0}
+	source.SetCycle(0)
+	for _, bundleID := range source.BundleIDs() {
+		if err := tr.TrackApp(bundleID); err != nil {
+			first.Errors = append(first.Errors, fmt.Sprintf("%s: %v", bundleID, err))
+			continue
+		}
+		first.Tracked = append(first.Tracked, bundleID)
+	}
+	first.Events, first.Alerts = recorded.next()
+	cycles := []Cycle{first}
+
+	for number := 1; number < source.Cycles(); number++ {
+		source.SetCycle(number)
+		updates, err := tr.CheckForUpdates(ctx)
+		if err != nil {
+			return cycles, fmt.Errorf("cycle %d: %w", number, err)
+		}
+		cycle := Cycle{Number: number, Updates: updates}
+		cycle.Events, cycle.Alerts = recorded.next()
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles, nil
+}
+
+// recorder splits what the fake notifier recorded into cycles
+type recorder struct {
+	notify         *trackertest.FakeNotifier
+	events, alerts int
+}
+
+// next returns what was notified since the last call
+func (r *recorder) next() ([]models.Event, []string) {
+	events := r.notify.Events[r.events:]
+	alerts := r.notify.Alerts[r.alerts:]
+	r.events += len(events)
+	r.alerts += len(alerts)
+	return events, alerts
+}
@@ -0,0 +1,190 @@
+// Package replay runs check cycles against recorded App Store lookup responses
+// instead of the live API, so detection, notification rules and storage can
+// be exercised deterministically
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Source serves recorded lookup responses in place of the App Store. Fixtures
+// use the raw response archive's layout, {dir}/{bundleID}/{name}.json or
+// .json.gz, so archived responses (MAVT_ARCHIVE_RAW) can be replayed as is. An
+// app's responses are served in file name order, one per cycle, and the last
+// one is repeated once they run out.
+type Source struct {
+	mu        sync.Mutex
+	responses map[string][][]byte
+	cycle     int
+}
+
+var _ tracker.Source = (*Source)(nil)
+
+// LoadFixtures reads every recorded response under dir
+func LoadFixtures(dir string) (*Source, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures directory: %w", err)
+	}
+
+	s := &Source{responses: make(map[string][][]byte)}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		bundleID := entry.Name()
+		files, err := os.ReadDir(filepath.Join(dir, bundleID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures for %s: %w", bundleID, err)
+		}
+
+		// os.ReadDir sorts by name, and archived names are timestamps
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			body, err := readFixture(filepath.Join(dir, bundleID, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if body != nil {
+				s.responses[bundleID] = append(s.responses[bundleID], body)
+			}
+		}
+	}
+
+	if len(s.responses) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	return s, nil
+}
+
+// readFixture reads a .json or .json.gz response, returning nil for other files
+func readFixture(path string) ([]byte, error) {
+	switch {
+	case strings.HasSuffix(path, ".json.gz"):
+		return storage.ReadRawResponse(path)
+	case strings.HasSuffix(path, ".json"):
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		return body, nil
+	default:
+		return nil, nil
+	}
+}
+
+// BundleIDs returns the apps with recorded responses, sorted
+func (s *Source) BundleIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bundleIDs := make([]string, 0, len(s.responses))
+	for bundleID := range s.responses {
+		bundleIDs = append(bundleIDs, bundleID)
+	}
+	sort.Strings(bundleIDs)
+	return bundleIDs
+}
+
+// Cycles returns the number of cycles needed to serve every recorded response
+func (s *Source) Cycles() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cycles := 0
+	for _, responses := range s.responses {
+		cycles = max(cycles, len(responses))
+	}
+	return cycles
+}
+
+// SetCycle selects which recorded response lookups are served from
+func (s *Source) SetCycle(cycle int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cycle = cycle
+}
+
+// LookupByBundleID parses the app's response for the current cycle
+func (s *Source) LookupByBundleID(bundleID string) (*models.AppInfo, error) {
+	return s.LookupByBundleIDWithLocale(context.Background(), bundleID, "", "")
+}
+
+// LookupByBundleIDWithLocale parses the app's response for the current cycle;
+// the locale is ignored since responses were recorded for one storefront
+func (s *Source) LookupByBundleIDWithLocale(_ context.Context, bundleID, _, _ string) (*models.AppInfo, error) {
+	s.mu.Lock()
+	responses := s.responses[bundleID]
+	cycle := s.cycle
+	s.mu.Unlock()
+
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%w: %s", appstore.ErrNotFound, bundleID)
+	}
+
+	app, err := appstore.ParseLookupResponse(responses[min(cycle, len(responses)-1)])
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, fmt.Errorf("%w: %s", appstore.ErrNotFound, bundleID)
+	}
+	return app, nil
+}
+
+// LookupByTrackID finds the app with trackID among the current cycle's responses
+func (s *Source) LookupByTrackID(trackID int64) (*models.AppInfo, error) {
+	for _, bundleID := range s.BundleIDs() {
+		app, err := s.LookupByBundleID(bundleID)
+		if err == nil && app.TrackID == trackID {
+			return app, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: track ID %d", appstore.ErrNotFound, trackID)
+}
+
+// SearchApps returns up to limit apps from the current cycle's responses
+// whose name contains term, ignoring case
+func (s *Source) SearchApps(term string, limit int) ([]*models.AppInfo, error) {
+	var results []*models.AppInfo
+	for _, bundleID := range s.BundleIDs() {
+		if len(results) == limit {
+			break
+		}
+		app, err := s.LookupByBundleID(bundleID)
+		if err == nil && strings.Contains(strings.ToLower(app.TrackName), strings.ToLower(term)) {
+			results = append(results, app)
+		}
+	}
+	return results, nil
+}
+
+// FetchReviews returns no reviews; reviews aren't recorded
+func (s *Source) FetchReviews(int64, string) ([]models.Review, error) {
+	return nil, nil
+}
+
+// FetchTestFlight returns nothing; TestFlight pages aren't recorded, so the
+// stored beta status is kept
+func (s *Source) FetchTestFlight(context.Context, string) (*models.BetaStatus, error) {
+	return nil, nil
+}
+
+// FetchInAppPurchases returns none; store pages aren't recorded
+func (s *Source) FetchInAppPurchases(context.Context, int64, string) ([]models.InAppPurchase, error) {
+	return []models.InAppPurchase{}, nil
+}
@@ -0,0 +1,342 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Supported report formats
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// AppChanges groups the version updates of a single app in date order. Name
+// is the app's display name if it has one, and Notes the user's notes on it.
+type AppChanges struct {
+	BundleID string
+	Name     string
+	Notes    string
+	Updates  []models.VersionUpdate
+}
+
+// Group is a titled set of apps in a report, e.g. all apps from one vendor.
+// Reports that aren't grouped have a single group with an empty name.
+type Group struct {
+	Name string
+	Apps []AppChanges
+
+	// Vendor is what the user recorded about the group's vendor, if anything
+	Vendor *models.Vendor
+
+	// Application is the group's application when grouped by product
+	Application *models.Application
+}
+
+// Document is a report that can be written in any supported format
+type Document interface {
+	Write(w io.Writer, format string) error
+
+	// AppCount returns the number of apps with updates in the report
+	AppCount() int
+}
+
+// Report is a changelog of version updates between two points in time
+type Report struct {
+	From   time.Time
+	To     time.Time
+	Groups []Group
+}
+
+// New builds a report from a set of updates, grouped by app and sorted by app
+// name, with each app's updates oldest first
+func New(updates []models.VersionUpdate, from, to time.Time) *Report {
+	report := &Report{From: from, To: to}
+	if apps := groupByApp(updates); len(apps) > 0 {
+		report.Groups = []Group{{Apps: apps}}
+	}
+	return report
+}
+
+// NewByVendor builds a report like New, with apps further grouped under the
+// vendor returned by vendorOf. Groups are sorted by name, and apps with no
+// known vendor are listed last under "Other".
+func NewByVendor(updates []models.VersionUpdate, from, to time.Time, vendorOf func(bundleID string) string) *Report {
+	return newGrouped(updates, from, to, vendorOf)
+}
+
+// NewByApplication builds a report like New, with apps further grouped under
+// the application they are linked into and named with their platform, so it
+// reads by product rather than by store entry. Apps not in any application
+// are listed last under "Other".
+func NewByApplication(updates []models.VersionUpdate, from, to time.Time, applications []models.Application) *Report {
+	byBundleID := make(map[string]*models.Application)
+	byName := make(map[string]*models.Application, len(applications))
+	for i := range applications {
+		byName[applications[i].Name] = &applications[i]
+		for _, member := range applications[i].Members {
+			byBundleID[member.BundleID] = &applications[i]
+		}
+	}
+
+	report := newGrouped(updates, from, to, func(bundleID string) string {
+		if application := byBundleID[bundleID]; application != nil {
+			return application.Name
+		}
+		return ""
+	})
+
+	for i := range report.Groups {
+		group := &report.Groups[i]
+		application := byName[group.Name]
+		if application == nil {
+			continue
+		}
+		if len(application.Summary()) > 0 {
+			group.Application = application
+		}
+		for j := range group.Apps {
+			if member := application.Member(group.Apps[j].BundleID); member != nil && member.Platform != "" {
+				group.Apps[j].Name += " (" + member.Platform + ")"
+			}
+		}
+	}
+
+	return report
+}
+
+// newGrouped builds a report with apps grouped under the name returned by
+// groupOf, sorted by name with apps groupOf has no name for last under "Other"
+func newGrouped(updates []models.VersionUpdate, from, to time.Time, groupOf func(bundleID string) string) *Report {
+	byGroup := make(map[string][]models.VersionUpdate)
+	for _, update := range updates {
+		name := groupOf(update.BundleID)
+		if name == "" {
+			name = otherGroup
+		}
+		byGroup[name] = append(byGroup[name], update)
+	}
+
+	report := &Report{From: from, To: to}
+	for name, groupUpdates := range byGroup {
+		report.Groups = append(report.Groups, Group{Name: name, Apps: groupByApp(groupUpdates)})
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i].Name, report.Groups[j].Name
+		if (a == otherGroup) != (b == otherGroup) {
+			return b == otherGroup
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+
+	return report
+}
+
+// WithVendors attaches the vendor details returned by lookup to the report's
+// vendor groups, so the report says who to contact about each vendor's apps
+func (r *Report) WithVendors(lookup func(name string) *models.Vendor) *Report {
+	for i := range r.Groups {
+		if name := r.Groups[i].Name; name != "" && name != otherGroup {
+			if vendor := lookup(name); vendor != nil && !vendor.IsEmpty() {
+				r.Groups[i].Vendor = vendor
+			}
+		}
+	}
+	return r
+}
+
+// AppCount returns the number of apps with updates in the report
+func (r *Report) AppCount() int {
+	count := 0
+	for _, group := range r.Groups {
+		count += len(group.Apps)
+	}
+	return count
+}
+
+const otherGroup = "Other"
+
+// groupByApp groups updates by bundle ID and sorts the apps by name
+func groupByApp(updates []models.VersionUpdate) []AppChanges {
+	byApp := make(map[string]*AppChanges)
+	for _, update := range updates {
+		changes, ok := byApp[update.BundleID]
+		if !ok {
+			changes = &AppChanges{BundleID: update.BundleID}
+			byApp[update.BundleID] = changes
+		}
+		changes.Updates = append(changes.Updates, update)
+	}
+
+	var apps []AppChanges
+	for _, changes := range byApp {
+		sort.Slice(changes.Updates, func(i, j int) bool {
+			return changes.Updates[i].UpdatedAt.Before(changes.Updates[j].UpdatedAt)
+		})
+		// Use the most recent name and notes in case the app was renamed
+		latest := changes.Updates[len(changes.Updates)-1]
+		changes.Name = latest.Name()
+		changes.Notes = latest.AppNotes
+		apps = append(apps, *changes)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+
+	return apps
+}
+
+// Write renders the report in the given format
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	case FormatHTML:
+		return htmlTemplate.Execute(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s (must be md or html)", format)
+	}
+}
+
+// ContentType returns the MIME type for a report format
+func ContentType(format string) string {
+	if format == FormatHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+func (r *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# App Changelog: %s to %s\n\n", r.From.Format(dateFormat), r.To.Format(dateFormat))
+
+	if len(r.Groups) == 0 {
+		b.WriteString("No app updates in this period.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "%d apps updated.\n", r.AppCount())
+
+	for _, group := range r.Groups {
+		if group.Name != "" {
+			fmt.Fprintf(&b, "\n## %s\n", group.Name)
+		}
+		if group.Vendor != nil {
+			b.WriteString("\n")
+			for _, line := range group.Vendor.Summary() {
+				fmt.Fprintf(&b, "- %s\n", line)
+			}
+		}
+		if group.Application != nil {
+			b.WriteString("\n")
+			for _, line := range group.Application.Summary() {
+				fmt.Fprintf(&b, "- %s\n", line)
+			}
+		}
+		for _, app := range group.Apps {
+			writeMarkdownApp(&b, app)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownApp(b *strings.Builder, app AppChanges) {
+	fmt.Fprintf(b, "\n### %s\n\n`%s`\n", app.Name, app.BundleID)
+	if app.Notes != "" {
+		fmt.Fprintf(b, "\n_%s_\n", strings.Join(strings.Fields(app.Notes), " "))
+	}
+
+	for _, update := range app.Updates {
+		fmt.Fprintf(b, "\n#### %s (%s)\n", update.Change(), update.UpdatedAt.Format(dateFormat))
+
+		notes := strings.TrimSpace(update.ReleaseNotes)
+		if notes == "" {
+			b.WriteString("\n_No release notes._\n")
+			continue
+		}
+
+		// Quote release notes so their own formatting doesn't break the document
+		b.WriteString("\n")
+		for _, line := range strings.Split(notes, "\n") {
+			b.WriteString(strings.TrimRight("> "+strings.TrimRight(line, "\r"), " "))
+			b.WriteString("\n")
+		}
+	}
+}
+
+const dateFormat = "2006-01-02"
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format(dateFormat) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>App Changelog: {{date .From}} to {{date .To}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 800px; margin: 2em auto; color: #333; }
+h2 { border-bottom: 2px solid #667eea; padding-bottom: 0.2em; margin-top: 1.5em; }
+h3 { border-bottom: 1px solid #ddd; padding-bottom: 0.2em; margin-top: 1.5em; }
+code { color: #666; }
+.notes { white-space: pre-wrap; background: #f6f8fa; border-left: 3px solid #667eea; padding: 0.5em 1em; }
+.none { color: #999; font-style: italic; }
+.app-notes { color: #666; font-style: italic; }
+.vendor, .application { color: #666; padding-left: 1.2em; }
+</style>
+</head>
+<body>
+<h1>App Changelog: {{date .From}} to {{date .To}}</h1>
+{{- if not .Groups}}
+<p>No app updates in this period.</p>
+{{- else}}
+<p>{{.AppCount}} apps updated.</p>
+{{- range .Groups}}
+{{- if .Name}}
+<h2>{{.Name}}</h2>
+{{- end}}
+{{- with .Vendor}}
+<ul class="vendor">
+{{- range .Summary}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Application}}
+<ul class="application">
+{{- range .Summary}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Apps}}
+<h3>{{.Name}}</h3>
+<p><code>{{.BundleID}}</code></p>
+{{- if .Notes}}
+<p class="app-notes">{{.Notes}}</p>
+{{- end}}
+{{- range .Updates}}
+<h4>{{.Change}} ({{date .UpdatedAt}})</h4>
+{{- if .ReleaseNotes}}
+<div class="notes"{{if .Language}} lang="{{.Language}}"{{end}}>{{.ReleaseNotes}}</div>
+{{- else}}
+<p class="none">No release notes.</p>
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,373 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// maxKeywords is how many notable keywords a tag summary lists
+	maxKeywords = 10
+
+	// minKeywordLength and minKeywordNotes filter out short words and words
+	// only one set of release notes used
+	minKeywordLength = 4
+	minKeywordNotes  = 2
+)
+
+// TagApp is one app's activity in a tag summary
+type TagApp struct {
+	BundleID string
+	Name     string
+	Vendor   string
+	Version  string
+
+	// Versions is how many new versions the app shipped in the period, and
+	// Cadence the average time between them; zero with fewer than two
+	Versions int
+	Cadence  time.Duration
+
+	// Latest is the newest update in the period, if any
+	Latest *models.VersionUpdate
+}
+
+// Keyword is a word used in release notes and how many notes used it
+type Keyword struct {
+	Word  string
+	Count int
+}
+
+// TagSummary summarizes the apps of the applications with one tag, e.g. a
+// set of competitors
+type TagSummary struct {
+	Tag  string
+	Apps []TagApp
+
+	// Versions is how many new versions the tag's apps shipped, and Cadence
+	// the average of their cadences
+	Versions int
+	Cadence  time.Duration
+
+	// Keywords are the words most used across the tag's release notes
+	Keywords []Keyword
+}
+
+// TagReport summarizes releases per application tag between two points in
+// time, for watching competitors rather than reading every changelog
+type TagReport struct {
+	From time.Time
+	To   time.Time
+	Tags []TagSummary
+}
+
+// NewByTag builds a tag report from the updates in a period. Each tag lists
+// the apps linked into applications with it, including apps that shipped
+// nothing; an app is under every tag of its application. With tags given,
+// only those tags are summarized. Re-releases and rollbacks aren't new
+// versions and aren't counted.
+func NewByTag(updates []models.VersionUpdate, from, to time.Time, apps []*models.AppInfo, applications []models.Application, tags ...string) *TagReport {
+	appsByBundleID := make(map[string]*models.AppInfo, len(apps))
+	for _, app := range apps {
+		appsByBundleID[app.BundleID] = app
+	}
+	updatesByBundleID := make(map[string][]models.VersionUpdate)
+	for _, update := range updates {
+		if update.Kind == "" {
+			updatesByBundleID[update.BundleID] = append(updatesByBundleID[update.BundleID], update)
+		}
+	}
+
+	// Collect each tag's apps, once each even if several of its
+	// applications share them
+	members := make(map[string]map[string]bool)
+	for _, application := range applications {
+		for _, tag := range application.Tags {
+			if len(tags) > 0 && !containsFold(tags, tag) {
+				continue
+			}
+			if members[tag] == nil {
+				members[tag] = make(map[string]bool)
+			}
+			for _, member := range application.Members {
+				if appsByBundleID[member.BundleID] != nil {
+					members[tag][member.BundleID] = true
+				}
+			}
+		}
+	}
+
+	report := &TagReport{From: from, To: to}
+	for tag, bundleIDs := range members {
+		summary := TagSummary{Tag: tag}
+		var notes []string
+		var cadences []time.Duration
+		for bundleID := range bundleIDs {
+			app := appsByBundleID[bundleID]
+			appUpdates := updatesByBundleID[bundleID]
+			sort.Slice(appUpdates, func(i, j int) bool {
+				return appUpdates[i].ReleaseTime().Before(appUpdates[j].ReleaseTime())
+			})
+
+			tagApp := TagApp{
+				BundleID: bundleID,
+				Name:     app.Name(),
+				Vendor:   app.ArtistName,
+				Version:  app.Version,
+				Versions: len(appUpdates),
+				Cadence:  models.ReleaseCadence(appUpdates),
+			}
+			if len(appUpdates) > 0 {
+				tagApp.Latest = &appUpdates[len(appUpdates)-1]
+			}
+			if tagApp.Cadence > 0 {
+				cadences = append(cadences, tagApp.Cadence)
+			}
+			for _, update := range appUpdates {
+				notes = append(notes, update.ReleaseNotes)
+			}
+
+			summary.Apps = append(summary.Apps, tagApp)
+			summary.Versions += tagApp.Versions
+		}
+
+		sort.Slice(summary.Apps, func(i, j int) bool {
+			return strings.ToLower(summary.Apps[i].Name) < strings.ToLower(summary.Apps[j].Name)
+		})
+		if len(cadences) > 0 {
+			var total time.Duration
+			for _, cadence := range cadences {
+				total += cadence
+			}
+			summary.Cadence = total / time.Duration(len(cadences))
+		}
+		summary.Keywords = notableKeywords(notes)
+		report.Tags = append(report.Tags, summary)
+	}
+
+	sort.Slice(report.Tags, func(i, j int) bool {
+		return report.Tags[i].Tag < report.Tags[j].Tag
+	})
+	return report
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// notableKeywords returns the words used by the most release notes, most
+// used first, leaving out common words and words only one note used
+func notableKeywords(notes []string) []Keyword {
+	counts := make(map[string]int)
+	for _, note := range notes {
+		seen := make(map[string]bool)
+		for _, word := range strings.FieldsFunc(strings.ToLower(note), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+		}) {
+			word = strings.Trim(word, "-")
+			if len([]rune(word)) < minKeywordLength || stopWords[word] || seen[word] || !strings.ContainsFunc(word, unicode.IsLetter) {
+				continue
+			}
+			seen[word] = true
+			counts[word]++
+		}
+	}
+
+	var keywords []Keyword
+	for word, count := range counts {
+		if count >= minKeywordNotes {
+			keywords = append(keywords, Keyword{Word: word, Count: count})
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Count != keywords[j].Count {
+			return keywords[i].Count > keywords[j].Count
+		}
+		return keywords[i].Word < keywords[j].Word
+	})
+	if len(keywords) > maxKeywords {
+		keywords = keywords[:maxKeywords]
+	}
+	return keywords
+}
+
+// stopWords are common English words and release note boilerplate that say
+// nothing about what changed
+var stopWords = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(`
+		about above after again also been before being below between both
+		could does doing down during each from further have having here
+		into just more most much only other over same should some such
+		than that their them then there these they this those through
+		under until very were what when where which while will with would
+		your yours within without make makes made using used across every
+		many like able need release releases released version versions
+		update updates updated updating latest apps application thanks
+		thank please bugs fixes fixed fixing issue issues improvement improvements
+		improved improve performance stability minor various general
+		enhancements enhancement changes change tweaks small experience
+		love feedback review reviews rate support team time keep always`) {
+		words[word] = true
+	}
+	return words
+}()
+
+// AppCount returns the number of apps that shipped a new version in the
+// report, counting apps under several tags once
+func (r *TagReport) AppCount() int {
+	counted := make(map[string]bool)
+	for _, summary := range r.Tags {
+		for _, app := range summary.Apps {
+			if app.Versions > 0 {
+				counted[app.BundleID] = true
+			}
+		}
+	}
+	return len(counted)
+}
+
+// Write renders the report in the given format
+func (r *TagReport) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	case FormatHTML:
+		return tagHTMLTemplate.Execute(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s (must be md or html)", format)
+	}
+}
+
+func (r *TagReport) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Competitor Report: %s to %s\n\n", r.From.Format(dateFormat), r.To.Format(dateFormat))
+
+	if len(r.Tags) == 0 {
+		b.WriteString("No tagged applications.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	for _, tag := range r.Tags {
+		fmt.Fprintf(&b, "## %s\n\n%s\n", tag.Tag, tag.Overview())
+		if len(tag.Keywords) > 0 {
+			fmt.Fprintf(&b, "\nNotable keywords: %s\n", tag.KeywordList())
+		}
+
+		b.WriteString("\n| App | Vendor | Versions | Cadence | Latest |\n|---|---|---|---|---|\n")
+		for _, app := range tag.Apps {
+			fmt.Fprintf(&b, "| %s | %s | %d | %s | %s |\n",
+				markdownCell(app.Name), markdownCell(app.Vendor), app.Versions, formatCadence(app.Cadence), markdownCell(app.LatestRelease()))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Overview describes the tag's activity in a sentence
+func (t TagSummary) Overview() string {
+	overview := fmt.Sprintf("%d new versions from %d apps", t.Versions, len(t.Apps))
+	if t.Cadence > 0 {
+		overview += fmt.Sprintf(", one every %s per app on average", formatCadence(t.Cadence))
+	}
+	return overview + "."
+}
+
+// KeywordList lists the tag's keywords with how many release notes used them
+func (t TagSummary) KeywordList() string {
+	words := make([]string, 0, len(t.Keywords))
+	for _, keyword := range t.Keywords {
+		words = append(words, fmt.Sprintf("%s (%d)", keyword.Word, keyword.Count))
+	}
+	return strings.Join(words, ", ")
+}
+
+// LatestRelease describes the app's newest version in the period, e.g.
+// "4.2 (2025-01-31)", or a dash if it shipped none
+func (a TagApp) LatestRelease() string {
+	if a.Latest == nil {
+		return "–"
+	}
+	return fmt.Sprintf("%s (%s)", a.Latest.NewVersion, a.Latest.ReleaseTime().Format(dateFormat))
+}
+
+// formatCadence formats a cadence in days, or a dash if there is none
+func formatCadence(cadence time.Duration) string {
+	if cadence <= 0 {
+		return "–"
+	}
+	return fmt.Sprintf("%.1f days", cadence.Hours()/24)
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(value string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(value), " "), "|", `\|`)
+}
+
+// tagHTMLTemplate renders a tag report for browsers and printing to PDF
+var tagHTMLTemplate = template.Must(template.New("tags").Funcs(template.FuncMap{
+	"date":    func(t time.Time) string { return t.Format(dateFormat) },
+	"cadence": formatCadence,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>Competitor Report: {{date .From}} to {{date .To}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; max-width: 900px; margin: 2em auto; color: #333; }
+h2 { border-bottom: 2px solid #667eea; padding-bottom: 0.2em; margin-top: 1.5em; }
+table { width: 100%; border-collapse: collapse; margin: 1em 0; font-size: 0.9em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #ddd; }
+th { background: #f6f8fa; }
+td.number { text-align: right; }
+.keywords { color: #555; }
+.none { color: #666; font-style: italic; }
+@page { margin: 2cm; }
+@media print {
+  body { margin: 0; max-width: none; }
+  h2 { break-after: avoid; }
+  tr { break-inside: avoid; }
+}
+</style>
+</head>
+<body>
+<h1>Competitor Report: {{date .From}} to {{date .To}}</h1>
+{{- if not .Tags}}
+<p class="none">No tagged applications.</p>
+{{- end}}
+{{- range .Tags}}
+<section>
+<h2>{{.Tag}}</h2>
+<p>{{.Overview}}</p>
+{{- if .Keywords}}
+<p class="keywords">Notable keywords: {{.KeywordList}}</p>
+{{- end}}
+<table>
+<thead><tr><th scope="col">App</th><th scope="col">Vendor</th><th scope="col">Versions</th><th scope="col">Cadence</th><th scope="col">Latest</th></tr></thead>
+<tbody>
+{{- range .Apps}}
+<tr><th scope="row">{{.Name}}</th><td>{{.Vendor}}</td><td class="number">{{.Versions}}</td><td>{{cadence .Cadence}}</td><td>{{.LatestRelease}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</section>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,37 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestTagReportAppCount(t *testing.T) {
+	now := time.Now()
+	apps := []*models.AppInfo{
+		{BundleID: "com.a", TrackName: "A"},
+		{BundleID: "com.b", TrackName: "B"},
+	}
+	applications := []models.Application{
+		{Name: "A", Tags: []string{"finance", "tier-1"}, Members: []models.ApplicationMember{{BundleID: "com.a"}}},
+		{Name: "B", Tags: []string{"finance"}, Members: []models.ApplicationMember{{BundleID: "com.b"}}},
+	}
+	updates := []models.VersionUpdate{
+		{BundleID: "com.a", OldVersion: "1.0", NewVersion: "1.1", UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	rpt := NewByTag(updates, now.Add(-24*time.Hour), now, apps, applications)
+	if got := rpt.AppCount(); got != 1 {
+		t.Errorf("AppCount() = %d, want 1 for an app updated under two tags", got)
+	}
+
+	var body strings.Builder
+	if err := rpt.Write(&body, FormatHTML); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.String(), "finance") {
+		t.Error("HTML report doesn't list the finance tag")
+	}
+}
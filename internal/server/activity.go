@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultActivityDays is how many days /api/activity covers by default,
+	// a year like a contribution calendar
+	defaultActivityDays = 365
+
+	// maxActivityDays bounds ?days= on /api/activity
+	maxActivityDays = 3 * 366
+)
+
+// activity is the /api/activity response: release counts per UTC day, oldest
+// first, including days without releases
+type activity struct {
+	BundleID string       `json:"bundle_id,omitempty"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Total    int          `json:"total"`
+	Max      int          `json:"max"`
+	Days     []dailyCount `json:"days"`
+}
+
+// handleActivity counts releases per day for a calendar heatmap, across all
+// tracked apps or, with ?bundle_id=, one app. Updates count on the day the
+// version was released, or was detected if its release date is unknown, so
+// past versions imported when an app was tracked show up on their own days.
+// ?days= sets how far back it goes (default 365).
+func (s *Server) handleActivity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := defaultActivityDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed <= 0 || parsed > maxActivityDays {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'days' parameter: %s (must be 1 to %d)", daysStr, maxActivityDays), http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	bundleIDs := []string{}
+	bundleID := r.URL.Query().Get("bundle_id")
+	if bundleID != "" {
+		if err := validateBundleID(bundleID); err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+		bundleIDs = append(bundleIDs, bundleID)
+	} else {
+		apps, err := s.tracker.GetTrackedApps()
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+			return
+		}
+		for _, app := range apps {
+			bundleIDs = append(bundleIDs, app.BundleID)
+		}
+	}
+
+	var released []time.Time
+	for _, id := range bundleIDs {
+		history, err := s.tracker.GetVersionHistory(id)
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get version history: %v", err), http.StatusInternalServerError)
+			return
+		}
+		for i := range history {
+			// Re-releases and rollbacks aren't new versions
+			if history[i].Kind != "" {
+				continue
+			}
+			released = append(released, history[i].ReleaseTime())
+		}
+	}
+
+	to := time.Now().UTC()
+	from := to.Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	result := activity{
+		BundleID: bundleID,
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Days:     dailyCounts(released, from, to),
+	}
+	for _, day := range result.Days {
+		result.Total += day.Count
+		if day.Count > result.Max {
+			result.Max = day.Count
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(result)
+}
+
+// dailyCounts counts times per UTC day between from and to, including days
+// with none
+func dailyCounts(times []time.Time, from, to time.Time) []dailyCount {
+	byDay := make(map[string]int)
+	for _, t := range times {
+		if t.Before(from) || t.After(to) {
+			continue
+		}
+		byDay[t.UTC().Format("2006-01-02")]++
+	}
+
+	counts := []dailyCount{}
+	start := from.UTC().Truncate(24 * time.Hour)
+	for day := start; !day.After(to.UTC()); day = day.Add(24 * time.Hour) {
+		key := day.Format("2006-01-02")
+		counts = append(counts, dailyCount{Date: key, Count: byDay[key]})
+	}
+	return counts
+}
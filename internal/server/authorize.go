@@ -0,0 +1,72 @@
+package server
+
+import (
+	"crypto/subtle"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// authorizeUpdateAction checks a request that approves or deploys an update,
+// replying with an error if it may not. Browsers may only send it from the
+// web interface's own origin and as JSON, which can't be posted cross-site
+// without a preflight, and it must carry MAVT_APPROVAL_TOKEN as a bearer
+// token.
+func (s *Server) authorizeUpdateAction(w http.ResponseWriter, r *http.Request) bool {
+	if s.approvalToken == "" {
+		writeError(w, "Approvals are disabled (set MAVT_APPROVAL_TOKEN)", http.StatusForbidden)
+		return false
+	}
+	if !s.sameOrigin(r) {
+		writeError(w, "Cross-origin requests are not allowed", http.StatusForbidden)
+		return false
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader)); mediaType != contentTypeJSON {
+		writeError(w, "Content-Type must be "+contentTypeJSON, http.StatusUnsupportedMediaType)
+		return false
+	}
+
+	token, _ := bearerToken(r)
+	if !tokenMatches(token, s.approvalToken) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mavt"`)
+		writeError(w, "Invalid or missing token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// sameOrigin reports whether a request comes from the web interface: its
+// Origin is the server's own host or MAVT_PUBLIC_URL's, or it has none, as
+// from curl
+func (s *Server) sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host) || (s.publicOrigin != "" && strings.EqualFold(origin, s.publicOrigin))
+}
+
+// publicOrigin returns the origin, scheme and host, of MAVT_PUBLIC_URL
+func publicOrigin(publicURL string) string {
+	u, err := url.Parse(publicURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// bearerToken returns the request's bearer token, if it has one
+func bearerToken(r *http.Request) (string, bool) {
+	return strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// tokenMatches reports whether a token given with a request is the expected
+// one, comparing in constant time
+func tokenMatches(token, want string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
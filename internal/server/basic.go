@@ -0,0 +1,282 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBasicSince is how far back the basic view lists updates by default,
+// matching the dashboard's default period
+const defaultBasicSince = "7d"
+
+// basicPeriods are the periods offered by the basic view, by ?since= value
+var basicPeriods = []struct {
+	Since   string
+	Message string
+}{
+	{"24h", "updates.last24h"},
+	{"7d", "updates.last7d"},
+	{"30d", "updates.last30d"},
+	{"90d", "updates.last90d"},
+}
+
+// basicTemplate renders /basic. It is plain HTML with no scripts, and uses the
+// dashboard's colour tokens so both meet WCAG AA contrast.
+var basicTemplate = template.Must(template.New("basic").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{call .T "basic.title"}}</title>
+    <style>
+        :root { --bg: #f8f9fa; --bg-card: #ffffff; --text: #1a1a1a; --text-secondary: #5c636a; --border: #dee2e6; --accent: #0052cc; }
+        @media (prefers-color-scheme: dark) {
+            :root { --bg: #0d1117; --bg-card: #161b22; --text: #e6edf3; --text-secondary: #8b949e; --border: #30363d; --accent: #58a6ff; }
+        }
+        body { margin: 0; padding: 16px; background: var(--bg); color: var(--text); font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; line-height: 1.4; }
+        main { max-width: 1100px; margin: 0 auto; }
+        a { color: var(--accent); }
+        :focus-visible { outline: 2px solid var(--accent); outline-offset: 2px; }
+        .skip-link { position: absolute; left: -9999px; }
+        .skip-link:focus { left: 16px; top: 8px; padding: 4px 8px; background: var(--bg-card); }
+        section { background: var(--bg-card); border: 1px solid var(--border); border-radius: 8px; padding: 12px 16px; margin: 16px 0; overflow-x: auto; }
+        table { width: 100%; border-collapse: collapse; font-size: 0.9em; }
+        caption { text-align: left; color: var(--text-secondary); padding-bottom: 6px; }
+        th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid var(--border); vertical-align: top; }
+        th { color: var(--text-secondary); font-weight: 600; }
+        details summary { cursor: pointer; color: var(--accent); }
+        details p { white-space: pre-wrap; margin: 6px 0 0; }
+        form { margin-bottom: 10px; }
+        select, button { font: inherit; padding: 4px 8px; }
+        .muted { color: var(--text-secondary); }
+    </style>
+</head>
+<body>
+    <a class="skip-link" href="#updates">{{call .T "basic.skip"}}</a>
+    <main>
+        <h1>{{call .T "basic.title"}}</h1>
+        <p><a href="/">{{call .T "basic.dashboard"}}</a></p>
+
+        <section aria-labelledby="updatesTitle">
+            <h2 id="updatesTitle">{{call .T "updates.title"}}</h2>
+            <form method="get" action="/basic">
+                <label for="since">{{call .T "updates.period"}}</label>
+                <select id="since" name="since">
+                    {{range .Periods}}<option value="{{.Since}}"{{if .Selected}} selected{{end}}>{{.Label}}</option>
+                    {{end}}
+                </select>
+                <button type="submit">{{call .T "basic.show"}}</button>
+            </form>
+            <div id="updates">
+            {{if .Updates}}
+            <table>
+                <caption>{{.PeriodLabel}}</caption>
+                <thead>
+                    <tr>
+                        <th scope="col">{{call .T "basic.app"}}</th>
+                        <th scope="col">{{call .T "basic.change"}}</th>
+                        <th scope="col">{{call .T "basic.severity"}}</th>
+                        <th scope="col">{{call .T "basic.detected"}}</th>
+                        <th scope="col">{{call .T "basic.notes"}}</th>
+                    </tr>
+                </thead>
+                <tbody>
+                {{range .Updates}}
+                    <tr>
+                        <th scope="row">{{.Name}}</th>
+                        <td>{{.Change}}</td>
+                        <td>{{.Severity}}</td>
+                        <td><time datetime="{{.DetectedISO}}">{{.Detected}}</time></td>
+                        <td>{{if .ReleaseNotes}}<details><summary>{{call $.T "basic.notes"}}</summary><p>{{.ReleaseNotes}}</p></details>{{else}}<span class="muted">–</span>{{end}}</td>
+                    </tr>
+                {{end}}
+                </tbody>
+            </table>
+            {{else}}
+            <p class="muted">{{call .T "updates.none" .PeriodLabel}}</p>
+            {{end}}
+            </div>
+        </section>
+
+        <section aria-labelledby="appsTitle">
+            <h2 id="appsTitle">{{call .T "apps.title"}}</h2>
+            {{if .Apps}}
+            <table>
+                <thead>
+                    <tr>
+                        <th scope="col">{{call .T "basic.app"}}</th>
+                        <th scope="col">{{call .T "basic.developer"}}</th>
+                        <th scope="col">{{call .T "basic.version"}}</th>
+                        <th scope="col">{{call .T "apps.column.store"}}</th>
+                        <th scope="col">{{call .T "basic.released"}}</th>
+                        <th scope="col">{{call .T "basic.checked"}}</th>
+                    </tr>
+                </thead>
+                <tbody>
+                {{range .Apps}}
+                    <tr>
+                        <th scope="row">{{.Name}}<br><span class="muted">{{.BundleID}}</span></th>
+                        <td>{{.Developer}}</td>
+                        <td>{{.Version}}</td>
+                        <td>{{.Store}}</td>
+                        <td>{{.Released}}</td>
+                        <td>{{.Checked}}</td>
+                    </tr>
+                {{end}}
+                </tbody>
+            </table>
+            {{else}}
+            <p class="muted">{{call .T "apps.none"}}</p>
+            {{end}}
+        </section>
+    </main>
+</body>
+</html>
+`))
+
+// basicPeriod is a period option in the basic view's form
+type basicPeriod struct {
+	Since    string
+	Label    string
+	Selected bool
+}
+
+// basicApp is a tracked app row in the basic view
+type basicApp struct {
+	Name      string
+	BundleID  string
+	Developer string
+	Version   string
+	Store     string
+	Released  string
+	Checked   string
+}
+
+// basicUpdate is a version update row in the basic view
+type basicUpdate struct {
+	Name         string
+	Change       string
+	Severity     string
+	Detected     string
+	DetectedISO  string
+	ReleaseNotes string
+}
+
+// basicPage is the data /basic renders
+type basicPage struct {
+	Lang        string
+	T           func(string, ...string) string
+	Periods     []basicPeriod
+	PeriodLabel string
+	Apps        []basicApp
+	Updates     []basicUpdate
+}
+
+// handleBasic serves a server-rendered view of the tracked apps and recent
+// updates that works without JavaScript, e.g. for screen readers or locked-down
+// browsers. ?since= picks the updates period (default 7d).
+func (s *Server) handleBasic(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		sinceStr = defaultBasicSince
+	}
+	since, err := parseSince(sinceStr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+	updates, err := s.tracker.GetRecentUpdates(since)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].UpdatedAt.After(updates[j].UpdatedAt)
+	})
+
+	page := basicPage{
+		Lang:        s.messages.Language(),
+		T:           s.uiText,
+		PeriodLabel: sinceStr,
+	}
+	for _, period := range basicPeriods {
+		label := s.uiText(period.Message)
+		if period.Since == sinceStr {
+			page.PeriodLabel = label
+		}
+		page.Periods = append(page.Periods, basicPeriod{Since: period.Since, Label: label, Selected: period.Since == sinceStr})
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name()) < strings.ToLower(apps[j].Name())
+	})
+	for _, app := range apps {
+		page.Apps = append(page.Apps, basicApp{
+			Name:      app.Name(),
+			BundleID:  app.BundleID,
+			Developer: app.ArtistName,
+			Version:   app.Version,
+			Store:     app.Storefront,
+			Released:  s.basicDate(app.ReleaseDate),
+			Checked:   s.basicDate(app.LastChecked),
+		})
+	}
+	for i := range updates {
+		update := &updates[i]
+		page.Updates = append(page.Updates, basicUpdate{
+			Name:         update.Name(),
+			Change:       update.Change(),
+			Severity:     update.Severity,
+			Detected:     s.basicDate(update.UpdatedAt),
+			DetectedISO:  update.UpdatedAt.UTC().Format(time.RFC3339),
+			ReleaseNotes: update.ReleaseNotes,
+		})
+	}
+
+	w.Header().Set(contentTypeHeader, "text/html; charset=utf-8")
+	if err := basicTemplate.Execute(w, page); err != nil {
+		log.Printf("Failed to render basic view: %v", err)
+	}
+}
+
+// uiText returns the web interface message id in the configured language,
+// filling its {0}, {1}, ... placeholders with args like the dashboard's t()
+func (s *Server) uiText(id string, args ...string) string {
+	text := s.messages.T("ui." + id)
+	for i, arg := range args {
+		text = strings.ReplaceAll(text, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return text
+}
+
+// noscriptNotice returns the dashboard's <noscript> notice, which points to
+// the basic view, as HTML
+func (s *Server) noscriptNotice() string {
+	return template.HTMLEscapeString(s.uiText("basic.noscript")) +
+		` <a href="/basic">` + template.HTMLEscapeString(s.uiText("basic.link")) + `</a>`
+}
+
+// basicDate formats a date for the basic view, or a dash if it is unset
+func (s *Server) basicDate(date time.Time) string {
+	if date.IsZero() {
+		return "–"
+	}
+	return s.messages.Date(date)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// cadenceRelease is one new version in an app's comparison timeline
+type cadenceRelease struct {
+	Version    string    `json:"version"`
+	ReleasedAt time.Time `json:"released_at"`
+	Severity   string    `json:"severity"`
+}
+
+// appCadence is one side of /api/compare: an app's release timeline, newest
+// first, and statistics about it. Re-releases and rollbacks aren't new
+// versions and are left out of both.
+type appCadence struct {
+	BundleID string `json:"bundle_id"`
+	Name     string `json:"name"`
+	Vendor   string `json:"vendor"`
+	Version  string `json:"version"`
+
+	Releases         int `json:"releases"`
+	ReleasesLastYear int `json:"releases_last_year"`
+
+	// AverageDays and MedianDays are the mean and median days between
+	// releases; unset with fewer than two releases
+	AverageDays float64 `json:"average_days,omitempty"`
+	MedianDays  float64 `json:"median_days,omitempty"`
+
+	LastRelease          *time.Time `json:"last_release,omitempty"`
+	DaysSinceLastRelease float64    `json:"days_since_last_release,omitempty"`
+
+	// Severities counts releases by severity
+	Severities map[string]int `json:"severities"`
+
+	Timeline []cadenceRelease `json:"timeline"`
+}
+
+// handleCompare returns two tracked apps' release timelines side by side
+// with cadence statistics, e.g. to weigh up competing vendors. ?a= and ?b=
+// are the apps' bundle IDs.
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	a, b := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if a == "" || b == "" {
+		writeErrorCode(w, codeInvalidParameter, "Query parameters 'a' and 'b' are required", http.StatusBadRequest)
+		return
+	}
+	for param, bundleID := range map[string]string{"a": a, "b": b} {
+		if err := validateBundleID(bundleID); err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid '%s' parameter: %v", param, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+	byBundleID := make(map[string]*models.AppInfo, len(apps))
+	for _, app := range apps {
+		byBundleID[app.BundleID] = app
+	}
+
+	now := time.Now()
+	result := make(map[string]*appCadence, 2)
+	for key, bundleID := range map[string]string{"a": a, "b": b} {
+		app, ok := byBundleID[bundleID]
+		if !ok {
+			writeErrorCode(w, codeAppNotFound, fmt.Sprintf("No tracked app found: %s", bundleID), http.StatusNotFound)
+			return
+		}
+		history, err := s.tracker.GetVersionHistory(bundleID)
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get version history: %v", err), http.StatusInternalServerError)
+			return
+		}
+		result[key] = releaseCadence(app, history, now)
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(result)
+}
+
+// releaseCadence builds an app's side of a comparison from its version
+// history as of now
+func releaseCadence(app *models.AppInfo, history []models.VersionUpdate, now time.Time) *appCadence {
+	cadence := &appCadence{
+		BundleID:   app.BundleID,
+		Name:       app.Name(),
+		Vendor:     app.ArtistName,
+		Version:    app.Version,
+		Severities: map[string]int{},
+		Timeline:   []cadenceRelease{},
+	}
+
+	for i := range history {
+		update := &history[i]
+		if update.Kind != "" {
+			continue
+		}
+		severity := update.Severity
+		if severity == "" {
+			severity = update.ClassifySeverity()
+		}
+		cadence.Timeline = append(cadence.Timeline, cadenceRelease{
+			Version:    update.NewVersion,
+			ReleasedAt: update.ReleaseTime(),
+			Severity:   severity,
+		})
+		cadence.Severities[severity]++
+	}
+	sort.Slice(cadence.Timeline, func(i, j int) bool {
+		return cadence.Timeline[i].ReleasedAt.After(cadence.Timeline[j].ReleasedAt)
+	})
+
+	cadence.Releases = len(cadence.Timeline)
+	if cadence.Releases == 0 {
+		return cadence
+	}
+
+	last := cadence.Timeline[0].ReleasedAt
+	cadence.LastRelease = &last
+	cadence.DaysSinceLastRelease = roundDays(now.Sub(last))
+
+	yearAgo := now.AddDate(-1, 0, 0)
+	var gaps []time.Duration
+	for i, release := range cadence.Timeline {
+		if release.ReleasedAt.After(yearAgo) {
+			cadence.ReleasesLastYear++
+		}
+		if i > 0 {
+			gaps = append(gaps, cadence.Timeline[i-1].ReleasedAt.Sub(release.ReleasedAt))
+		}
+	}
+	if len(gaps) > 0 {
+		cadence.AverageDays = roundDays(cadence.Timeline[0].ReleasedAt.Sub(cadence.Timeline[len(cadence.Timeline)-1].ReleasedAt) / time.Duration(len(gaps)))
+		sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+		median := gaps[len(gaps)/2]
+		if len(gaps)%2 == 0 {
+			median = (gaps[len(gaps)/2-1] + gaps[len(gaps)/2]) / 2
+		}
+		cadence.MedianDays = roundDays(median)
+	}
+	return cadence
+}
+
+// roundDays converts a duration to days, to one decimal place
+func roundDays(d time.Duration) float64 {
+	return math.Round(d.Hours()/24*10) / 10
+}
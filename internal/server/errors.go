@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/tracker"
+)
+
+// Error codes tell clients why a request failed without parsing the message
+const (
+	codeInvalidRequest   = "invalid_request"
+	codeInvalidParameter = "invalid_parameter"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeConflict         = "conflict"
+	codeUnsupportedMedia = "unsupported_media_type"
+	codeInternalError    = "internal_error"
+	codeUnavailable      = "unavailable"
+
+	codeAppNotFound         = "app_not_found"
+	codeUpdateNotFound      = "update_not_found"
+	codeApplicationNotFound = "application_not_found"
+	codeAlreadyTracked      = "already_tracked"
+
+	// codeStorageError is a failure reading or writing MAVT's data,
+	// codeRateLimited an App Store throttling MAVT and codeUpstreamError any
+	// other failure of the App Store or another service MAVT calls
+	codeStorageError  = "storage_error"
+	codeRateLimited   = "rate_limited"
+	codeUpstreamError = "upstream_error"
+)
+
+// statusCodes are the error codes of responses that don't give a more
+// specific one, by HTTP status
+var statusCodes = map[int]string{
+	http.StatusBadRequest:           codeInvalidRequest,
+	http.StatusUnauthorized:         codeUnauthorized,
+	http.StatusForbidden:            codeForbidden,
+	http.StatusNotFound:             codeNotFound,
+	http.StatusMethodNotAllowed:     codeMethodNotAllowed,
+	http.StatusConflict:             codeConflict,
+	http.StatusUnsupportedMediaType: codeUnsupportedMedia,
+	http.StatusTooManyRequests:      codeRateLimited,
+	http.StatusInternalServerError:  codeInternalError,
+	http.StatusBadGateway:           codeUpstreamError,
+	http.StatusServiceUnavailable:   codeUnavailable,
+}
+
+const (
+	// defaultSearchLimit and maxSearchLimit are the default and the most
+	// results /api/search returns, the most the App Store returns for one search
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+
+	// maxSince bounds ?since= durations, which reach back ten years at most
+	maxSince    = 3650 * 24 * time.Hour
+	maxSinceStr = "3650d"
+
+	// maxBundleIDLength bounds bundle IDs given to the API
+	maxBundleIDLength = 255
+)
+
+// bundleIDPattern matches well-formed bundle IDs, including the pseudo bundle
+// IDs of packages, custom sources and OS releases
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// errorResponse is the body of every API error: {"error": {...}}
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes an API error. Code is stable for clients to act on;
+// Message is for people and may change.
+type errorDetail struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newErrorDetail describes an error with the given code, or the status's
+// code if it is empty
+func newErrorDetail(code, message string, status int) errorDetail {
+	if code == "" {
+		code = statusCodes[status]
+	}
+	if code == "" {
+		code = codeInternalError
+	}
+	return errorDetail{Status: status, Code: code, Message: strings.TrimSpace(message)}
+}
+
+// writeError replies to the request with the message as a JSON error and the
+// HTTP status code, like http.Error does with plain text. The error's code is
+// the status's; see writeErrorCode for a more specific one.
+func writeError(w http.ResponseWriter, message string, status int) {
+	writeErrorCode(w, "", message, status)
+}
+
+// writeErrorCode replies to the request with a JSON error with the given code
+func writeErrorCode(w http.ResponseWriter, code, message string, status int) {
+	h := w.Header()
+	// Drop headers set for a successful response, as http.Error does
+	h.Del("Content-Length")
+	h.Del("Content-Disposition")
+	h.Set(contentTypeHeader, contentTypeJSON)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: newErrorDetail(code, message, status)})
+}
+
+// writeLookupError replies to a request that failed looking an app up on the
+// App Store, with a status and code telling why: 429 rate_limited, with a
+// Retry-After if Apple gave one, when the App Store throttled MAVT, 404
+// app_not_found for an app it doesn't have, and 502 upstream_error when it
+// failed otherwise
+func writeLookupError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, appstore.ErrThrottled):
+		if retryAfter := appstore.RetryAfter(err); retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+		writeErrorCode(w, codeRateLimited, message, http.StatusTooManyRequests)
+	case errors.Is(err, appstore.ErrNotFound), errors.Is(err, tracker.ErrNotTracked):
+		writeErrorCode(w, codeAppNotFound, message, http.StatusNotFound)
+	case errors.Is(err, appstore.ErrInvalid), errors.Is(err, appstore.ErrTransient):
+		writeErrorCode(w, codeUpstreamError, message, http.StatusBadGateway)
+	default:
+		writeError(w, message, http.StatusInternalServerError)
+	}
+}
+
+// parseSince parses a ?since= duration, e.g. "24h" or "7d", which must be
+// positive and at most maxSince
+func parseSince(value string) (time.Duration, error) {
+	since, err := config.ParseDuration(value)
+	if err != nil || since <= 0 || since > maxSince {
+		return 0, fmt.Errorf("%s (must be a positive duration up to %s)", value, maxSinceStr)
+	}
+	return since, nil
+}
+
+// validateBundleID checks a bundle ID given to the API is well formed
+func validateBundleID(bundleID string) error {
+	if len(bundleID) > maxBundleIDLength || !bundleIDPattern.MatchString(bundleID) {
+		return fmt.Errorf("bundle ID %q must be letters, digits, '.', '-' or '_', up to %d characters", bundleID, maxBundleIDLength)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Export types for /api/export.csv
+const (
+	exportTypeApps    = "apps"
+	exportTypeUpdates = "updates"
+)
+
+const (
+	// defaultExportSince is how far back an updates export goes by default
+	defaultExportSince = "30d"
+
+	// exportFlushRows is how many rows are written between flushes, so large
+	// exports stream rather than building up in the response buffer
+	exportFlushRows = 100
+)
+
+var (
+	exportAppsHeader = []string{
+		"bundle_id", "name", "vendor", "version", "release_date", "price", "currency",
+		"source", "country", "genre", "min_os_version", "file_size_bytes",
+		"first_discovered", "last_checked", "notes",
+	}
+	exportUpdatesHeader = []string{
+		"id", "bundle_id", "name", "vendor", "old_version", "new_version", "severity",
+		"kind", "detected_at", "released_at", "application", "platform", "release_notes",
+	}
+)
+
+// handleExportCSV streams tracked apps (?type=apps, the default) or version
+// updates (?type=updates) as CSV for spreadsheets. ?since= limits updates to
+// those detected within it (default 30d) and apps to those tracked within it.
+func (s *Server) handleExportCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	exportType := r.URL.Query().Get("type")
+	if exportType == "" {
+		exportType = exportTypeApps
+	}
+	if exportType != exportTypeApps && exportType != exportTypeUpdates {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'type' parameter: %s (must be %s or %s)", exportType, exportTypeApps, exportTypeUpdates), http.StatusBadRequest)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" && exportType == exportTypeUpdates {
+		sinceStr = defaultExportSince
+	}
+	var since time.Duration
+	if sinceStr != "" {
+		parsed, err := parseSince(sinceStr)
+		if err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var updates []models.VersionUpdate
+	if exportType == exportTypeUpdates {
+		updates, err = s.tracker.GetRecentUpdates(since)
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("mavt-%s-%s.csv", exportType, time.Now().Format("2006-01-02"))
+	w.Header().Set(contentTypeHeader, "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// write reports whether the row was written, so the export stops once
+	// the client has gone away; the status has been sent by then, so
+	// failures can only be logged
+	writer := csv.NewWriter(w)
+	rows := 0
+	write := func(row []string) bool {
+		if err := writer.Write(row); err != nil {
+			log.Printf("Failed to write %s export: %v", exportType, err)
+			return false
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				log.Printf("Failed to write %s export: %v", exportType, err)
+				return false
+			}
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		return true
+	}
+
+	if exportType == exportTypeApps {
+		if !write(exportAppsHeader) {
+			return
+		}
+		for _, app := range apps {
+			if since > 0 && app.FirstDiscovered.Before(time.Now().Add(-since)) {
+				continue
+			}
+			if !write(exportAppRow(app)) {
+				return
+			}
+		}
+	} else {
+		// Updates don't record the vendor, so take it from the app
+		vendors := make(map[string]string, len(apps))
+		for _, app := range apps {
+			vendors[app.BundleID] = app.ArtistName
+		}
+		if !write(exportUpdatesHeader) {
+			return
+		}
+		for i := range updates {
+			if !write(exportUpdateRow(&updates[i], vendors[updates[i].BundleID])) {
+				return
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Failed to write %s export: %v", exportType, err)
+	}
+}
+
+// exportAppRow returns an app's CSV row, in exportAppsHeader order
+func exportAppRow(app *models.AppInfo) []string {
+	return []string{
+		csvText(app.BundleID),
+		csvText(app.Name()),
+		csvText(app.ArtistName),
+		csvText(app.Version),
+		csvTime(app.ReleaseDate),
+		strconv.FormatFloat(app.Price, 'f', -1, 64),
+		csvText(app.Currency),
+		csvText(app.Source),
+		csvText(app.Storefront),
+		csvText(app.Genre),
+		csvText(app.MinOSVersion),
+		strconv.FormatInt(app.FileSizeBytes, 10),
+		csvTime(app.FirstDiscovered),
+		csvTime(app.LastChecked),
+		csvText(app.Notes),
+	}
+}
+
+// exportUpdateRow returns an update's CSV row, in exportUpdatesHeader order
+func exportUpdateRow(update *models.VersionUpdate, vendor string) []string {
+	releasedAt := ""
+	if update.ReleasedAt != nil {
+		releasedAt = csvTime(*update.ReleasedAt)
+	}
+	return []string{
+		update.ID,
+		csvText(update.BundleID),
+		csvText(update.Name()),
+		csvText(vendor),
+		csvText(update.OldVersion),
+		csvText(update.NewVersion),
+		update.ClassifySeverity(),
+		csvText(update.Kind),
+		csvTime(update.UpdatedAt),
+		releasedAt,
+		csvText(update.Application),
+		csvText(update.Platform),
+		csvText(update.ReleaseNotes),
+	}
+}
+
+// csvText guards a text cell against spreadsheet formula injection: cells
+// starting with a formula character are prefixed with a quote so Excel and
+// Sheets show them as text instead of evaluating them. Quoting and escaping
+// are left to encoding/csv.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvTime formats a time as RFC 3339 in UTC, or nothing if it is unset
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Grafana metric names exposed by the JSON datasource endpoints
+const (
+	grafanaMetricUpdatesPerDay = "updates_per_day"
+	grafanaMetricTrackedApps   = "tracked_apps"
+)
+
+// grafanaQueryRequest is the body Grafana's JSON datasource sends to /query
+type grafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	Targets []struct {
+		Target string `json:"target"`
+	} `json:"targets"`
+}
+
+// grafanaTimeSeries is a time series response; datapoints are [value, unix ms] pairs
+type grafanaTimeSeries struct {
+	Target     string     `json:"target"`
+	Datapoints [][2]int64 `json:"datapoints"`
+}
+
+// grafanaTable is a table response
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// grafanaColumn describes a table column
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// dailyCount is a single day's update count for the Infinity datasource
+type dailyCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// handleGrafanaRoot answers the JSON datasource connection test
+func (s *Server) handleGrafanaRoot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "ok",
+	})
+}
+
+// handleGrafanaSearch lists the metrics available to the JSON datasource
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode([]string{grafanaMetricUpdatesPerDay, grafanaMetricTrackedApps})
+}
+
+// handleGrafanaQuery answers JSON datasource queries for the requested time range
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req grafanaQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.Range.To.IsZero() {
+		req.Range.To = time.Now()
+	}
+	if req.Range.From.IsZero() {
+		req.Range.From = req.Range.To.Add(-30 * 24 * time.Hour)
+	}
+
+	var results []interface{}
+	for _, target := range req.Targets {
+		switch target.Target {
+		case grafanaMetricUpdatesPerDay:
+			counts, err := s.updatesPerDay(req.Range.From, req.Range.To)
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+				return
+			}
+
+			series := grafanaTimeSeries{Target: grafanaMetricUpdatesPerDay, Datapoints: [][2]int64{}}
+			for _, c := range counts {
+				day, _ := time.Parse("2006-01-02", c.Date)
+				series.Datapoints = append(series.Datapoints, [2]int64{int64(c.Count), day.UnixMilli()})
+			}
+			results = append(results, series)
+
+		case grafanaMetricTrackedApps:
+			apps, err := s.tracker.GetTrackedApps()
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+				return
+			}
+			results = append(results, trackedAppsTable(apps))
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(results)
+}
+
+// handleGrafanaUpdatesPerDay returns daily update counts as a flat JSON array for
+// the Infinity datasource. Accepts the same 'since' parameter as /api/updates.
+func (s *Server) handleGrafanaUpdatesPerDay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		sinceStr = "720h"
+	}
+
+	since, err := parseSince(sinceStr)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	counts, err := s.updatesPerDay(now.Add(-since), now)
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(counts)
+}
+
+// handleGrafanaApps returns tracked apps with their latest versions as a table
+func (s *Server) handleGrafanaApps(w http.ResponseWriter, r *http.Request) {
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(trackedAppsTable(apps))
+}
+
+// updatesPerDay counts version updates per UTC day between from and to, including
+// days without updates so graphs don't interpolate across gaps
+func (s *Server) updatesPerDay(from, to time.Time) ([]dailyCount, error) {
+	updates, err := s.tracker.GetRecentUpdates(time.Since(from))
+	if err != nil {
+		return nil, err
+	}
+
+	detected := make([]time.Time, 0, len(updates))
+	for _, update := range updates {
+		detected = append(detected, update.UpdatedAt)
+	}
+	return dailyCounts(detected, from, to), nil
+}
+
+// trackedAppsTable builds a Grafana table of tracked apps, sorted by name
+func trackedAppsTable(apps []*models.AppInfo) grafanaTable {
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name() < apps[j].Name()
+	})
+
+	table := grafanaTable{
+		Type: "table",
+		Columns: []grafanaColumn{
+			{Text: "App", Type: "string"},
+			{Text: "Bundle ID", Type: "string"},
+			{Text: "Version", Type: "string"},
+			{Text: "Developer", Type: "string"},
+			{Text: "Released", Type: "time"},
+			{Text: "Last Checked", Type: "time"},
+			{Text: "Rating", Type: "number"},
+			{Text: "Ratings", Type: "number"},
+		},
+		Rows: [][]interface{}{},
+	}
+
+	for _, app := range apps {
+		// Apps without ratings leave the cells empty rather than showing zero
+		var rating, ratings interface{}
+		if app.Rating != nil {
+			rating, ratings = app.Rating.AverageRating, app.Rating.RatingCount
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			app.Name(),
+			app.BundleID,
+			app.Version,
+			app.ArtistName,
+			app.ReleaseDate.UnixMilli(),
+			app.LastChecked.UnixMilli(),
+			rating,
+			ratings,
+		})
+	}
+
+	return table
+}
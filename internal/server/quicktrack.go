@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/tracker"
+)
+
+// quickTrackRequest is the body of POST /api/quicktrack
+type quickTrackRequest struct {
+	URL string `json:"url"`
+}
+
+// handleQuickTrack tracks the app of an App Store link, for a browser
+// extension or bookmarklet tracking the app page being viewed. It needs
+// MAVT_QUICKTRACK_TOKEN as a bearer token (or ?token= where headers can't be
+// set) and takes the link as JSON {"url": "..."} or a url form value. Links
+// from another storefront than the default track the app there.
+func (s *Server) handleQuickTrack(w http.ResponseWriter, r *http.Request) {
+	if s.quickTrackToken == "" {
+		writeError(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	s.allowQuickTrackOrigin(w, r)
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.quickTrackAuthorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mavt"`)
+		writeError(w, "Invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+
+	var req quickTrackRequest
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get(contentTypeHeader)); mediaType == contentTypeJSON {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		req.URL = r.FormValue("url")
+	}
+	if req.URL == "" {
+		writeError(w, "url is required", http.StatusBadRequest)
+		return
+	}
+
+	trackID, country, err := appstore.ParseStoreURL(req.URL)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if strings.EqualFold(country, s.country) {
+		country = ""
+	}
+
+	// Tracking an app twice isn't an error here: the extension only needs to
+	// know the app is tracked
+	app, added, err := s.tracker.TrackSearchResult("", trackID, country, "")
+	if err != nil && !errors.Is(err, tracker.ErrAlreadyTracked) {
+		writeLookupError(w, fmt.Sprintf("Failed to track app: %v", err), err)
+		return
+	}
+
+	message := "App successfully added to tracking"
+	status := http.StatusCreated
+	if !added {
+		message = "App is already tracked"
+		status = http.StatusOK
+	} else {
+		log.Printf("Added app to tracking via quick track: %s", sanitizeForLog(app.BundleID))
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":         true,
+		"already_tracked": !added,
+		bundleIDField:     app.BundleID,
+		"name":            app.Name(),
+		"app":             app,
+		"message":         message,
+	})
+}
+
+// allowQuickTrackOrigin lets browsers call /api/quicktrack from the
+// configured origins, such as the App Store's site or an extension
+func (s *Server) allowQuickTrackOrigin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !(slices.Contains(s.quickTrackOrigins, origin) || slices.Contains(s.quickTrackOrigins, "*")) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Max-Age", "600")
+}
+
+// quickTrackAuthorized reports whether the request carries the quick track
+// token, as a bearer token or ?token=
+func (s *Server) quickTrackAuthorized(r *http.Request) bool {
+	token, ok := bearerToken(r)
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	return tokenMatches(token, s.quickTrackToken)
+}
@@ -2,26 +2,39 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/thomas/mavt/internal/appstore"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/i18n"
+	"github.com/thomas/mavt/internal/importer"
+	"github.com/thomas/mavt/internal/jamf"
+	"github.com/thomas/mavt/internal/mdm"
+	"github.com/thomas/mavt/internal/packages"
+	"github.com/thomas/mavt/internal/recovery"
+	"github.com/thomas/mavt/internal/report"
+	"github.com/thomas/mavt/internal/storage"
 	"github.com/thomas/mavt/internal/tracker"
 	"github.com/thomas/mavt/internal/version"
 	"github.com/thomas/mavt/pkg/models"
 )
 
 const (
-	contentTypeHeader     = "Content-Type"
-	contentTypeJSON       = "application/json"
-	contentTypeHTML       = "text/html; charset=utf-8"
-	methodNotAllowedMsg   = "Method not allowed"
-	bundleIDField         = "bundle_id"
+	contentTypeHeader   = "Content-Type"
+	contentTypeJSON     = "application/json"
+	contentTypeHTML     = "text/html; charset=utf-8"
+	methodNotAllowedMsg = "Method not allowed"
+	bundleIDField       = "bundle_id"
 )
 
 // sanitizeForLog removes newlines and control characters to prevent log injection attacks
@@ -41,52 +54,140 @@ func sanitizeForLog(s string) string {
 
 // Server handles HTTP requests
 type Server struct {
-	tracker       *tracker.Tracker
-	appstoreClient *appstore.Client
-	mux           *http.ServeMux
-	checkInterval time.Duration
+	tracker            *tracker.Tracker
+	jamfClient         *jamf.Client
+	slackSigningSecret string
+	quickTrackToken    string
+	quickTrackOrigins  []string
+	approvalToken      string
+	publicOrigin       string
+	country            string
+	mux                *http.ServeMux
+	checkInterval      time.Duration
+	messages           *i18n.Translator
+	releases           *version.ReleaseChecker
+	deployer           *mdm.Deployer
+	checkNow           func() bool
 }
 
 // NewServer creates a new HTTP server
-func NewServer(tracker *tracker.Tracker, checkInterval time.Duration) *Server {
+func NewServer(tracker *tracker.Tracker, cfg *config.Config) *Server {
 	s := &Server{
-		tracker:       tracker,
-		appstoreClient: appstore.NewClient(),
-		mux:           http.NewServeMux(),
-		checkInterval: checkInterval,
+		tracker:            tracker,
+		mux:                http.NewServeMux(),
+		checkInterval:      cfg.CheckInterval,
+		messages:           i18n.New(cfg.UILanguage),
+		slackSigningSecret: cfg.SlackSigningSecret,
+		quickTrackToken:    cfg.QuickTrackToken,
+		quickTrackOrigins:  cfg.QuickTrackOrigins,
+		approvalToken:      cfg.ApprovalToken,
+		publicOrigin:       publicOrigin(cfg.PublicURL),
+		country:            cfg.Country,
+	}
+	if cfg.JamfURL != "" {
+		s.jamfClient = jamf.NewClient(cfg.JamfURL, cfg.JamfClientID, cfg.JamfClientSecret)
 	}
 	s.setupRoutes()
 	return s
 }
 
+// SetReleaseChecker reports newer MAVT releases found by checker in the health
+// endpoint and the web interface
+func (s *Server) SetReleaseChecker(checker *version.ReleaseChecker) {
+	s.releases = checker
+}
+
+// SetDeployer lets approved updates be pushed to the configured MDM again
+// from the web interface and API, e.g. after a failed push
+func (s *Server) SetDeployer(deployer *mdm.Deployer) {
+	s.deployer = deployer
+}
+
+// SetCheckTrigger lets the web interface and API start a check cycle without
+// waiting for the next interval. trigger returns false if a check is already
+// queued.
+func (s *Server) SetCheckTrigger(trigger func() bool) {
+	s.checkNow = trigger
+}
+
 // setupRoutes configures all HTTP routes
 func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/basic", s.handleBasic)
 	s.mux.HandleFunc("/api/apps", s.handleApps)
 	s.mux.HandleFunc("/api/updates", s.handleUpdates)
 	s.mux.HandleFunc("/api/health", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReady)
+	s.mux.HandleFunc("/api/status", s.handleStatus)
+	s.mux.HandleFunc("/api/check", s.handleCheck)
 	s.mux.HandleFunc("/api/search", s.handleSearch)
 	s.mux.HandleFunc("/api/track", s.handleTrack)
+	s.mux.HandleFunc("/api/track/bulk", s.handleTrackBulk)
+	s.mux.HandleFunc("/api/unarchive", s.handleUnarchive)
+	s.mux.HandleFunc("/api/label", s.handleLabel)
+	s.mux.HandleFunc("/api/vendors", s.handleVendors)
+	s.mux.HandleFunc("/api/watchlists", s.handleWatchlists)
+	s.mux.HandleFunc("/api/views", s.handleViews)
+	s.mux.HandleFunc("/api/applications", s.handleApplications)
+	s.mux.HandleFunc("/api/applications/link", s.handleApplicationLink)
+	s.mux.HandleFunc("/api/applications/history", s.handleApplicationHistory)
+	s.mux.HandleFunc("/api/testflight", s.handleTestFlight)
+	s.mux.HandleFunc("/api/acknowledge", s.handleAcknowledge)
+	s.mux.HandleFunc("/api/approval", s.handleApproval)
+	s.mux.HandleFunc("/api/deploy", s.handleDeploy)
+	s.mux.HandleFunc("/api/assign", s.handleAssign)
+	s.mux.HandleFunc("/api/updates/", s.handleUpdate)
+	s.mux.HandleFunc("/api/updates/wait", s.handleUpdatesWait)
+	s.mux.HandleFunc("/api/sync", s.handleSync)
+	s.mux.HandleFunc("/api/export.csv", s.handleExportCSV)
 	s.mux.HandleFunc("/api/history", s.handleHistory)
 	s.mux.HandleFunc("/api/last-update", s.handleLastUpdate)
+	s.mux.HandleFunc("/api/report", s.handleReport)
+	s.mux.HandleFunc("/api/reviews", s.handleReviews)
+	s.mux.HandleFunc("/api/sizes", s.handleSizes)
+	s.mux.HandleFunc("/api/activity", s.handleActivity)
+	s.mux.HandleFunc("/api/compare", s.handleCompare)
+	s.mux.HandleFunc("/api/app/", s.handleApp)
+	s.mux.HandleFunc("/api/compliance", s.handleCompliance)
+	s.mux.HandleFunc("/api/compatibility", s.handleCompatibility)
+	s.mux.HandleFunc("/api/import", s.handleImport)
+	s.mux.HandleFunc("/api/webhooks/", s.handleWebhooks)
+	s.mux.HandleFunc("/api/slack/command", s.handleSlackCommand)
+	s.mux.HandleFunc("/api/quicktrack", s.handleQuickTrack)
+	s.mux.HandleFunc("/api/grafana/", s.handleGrafanaRoot)
+	s.mux.HandleFunc("/api/grafana/search", s.handleGrafanaSearch)
+	s.mux.HandleFunc("/api/grafana/query", s.handleGrafanaQuery)
+	s.mux.HandleFunc("/api/grafana/updates-per-day", s.handleGrafanaUpdatesPerDay)
+	s.mux.HandleFunc("/api/grafana/apps", s.handleGrafanaApps)
 }
 
 // Start starts the HTTP server
 func (s *Server) Start(host string, port int) error {
 	addr := fmt.Sprintf("%s:%d", host, port)
 	log.Printf("Starting HTTP server on http://%s", addr)
-	return http.ListenAndServe(addr, s.mux)
+
+	// Name server spans after the route rather than the generic operation name
+	handler := otelhttp.NewHandler(recovery.Middleware(s.mux), "mavt", otelhttp.WithSpanNameFormatter(
+		func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		},
+	))
+	return http.ListenAndServe(addr, handler)
 }
 
 // handleIndex serves the main page
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeError(w, "Not found", http.StatusNotFound)
+			return
+		}
 		http.NotFound(w, r)
 		return
 	}
 
 	html := `<!DOCTYPE html>
-<html>
+<html lang="%[2]s">
 <head>
     <title>MAVT - App Version Tracker</title>
     <meta charset="utf-8">
@@ -98,8 +199,8 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             --bg-secondary: #ffffff;
             --bg-card: #ffffff;
             --text-primary: #1a1a1a;
-            --text-secondary: #6c757d;
-            --text-muted: #adb5bd;
+            --text-secondary: #5c636a;
+            --text-muted: #6c757d;
             --border-color: #e9ecef;
             --accent-primary: #0066ff;
             --accent-secondary: #0052cc;
@@ -113,6 +214,11 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             --update-border: #0d7a3f;
             --release-notes-bg: #f8f9fa;
             --search-result-bg: #f8f9fa;
+            --heat-0: #ebedf0;
+            --heat-1: #9be9a8;
+            --heat-2: #40c463;
+            --heat-3: #30a14e;
+            --heat-4: #216e39;
         }
 
         [data-theme="dark"] {
@@ -121,7 +227,7 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             --bg-card: #161b22;
             --text-primary: #e6edf3;
             --text-secondary: #8b949e;
-            --text-muted: #6e7681;
+            --text-muted: #848d97;
             --border-color: #30363d;
             --accent-primary: #58a6ff;
             --accent-secondary: #1f6feb;
@@ -135,9 +241,18 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             --update-border: #3fb950;
             --release-notes-bg: #0d1117;
             --search-result-bg: #161b22;
+            --heat-0: #21262d;
+            --heat-1: #0e4429;
+            --heat-2: #006d32;
+            --heat-3: #26a641;
+            --heat-4: #39d353;
         }
 
         * { margin: 0; padding: 0; box-sizing: border-box; }
+        :focus-visible {
+            outline: 2px solid var(--accent-primary);
+            outline-offset: 2px;
+        }
         body {
             font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
             line-height: 1.4;
@@ -354,6 +469,12 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             transition: background-color 0.3s;
             font-size: 0.9em;
         }
+        .delivery-ok {
+            color: var(--success-text);
+        }
+        .delivery-failed {
+            color: var(--error-text);
+        }
         .empty-state {
             text-align: center;
             padding: 24px;
@@ -362,8 +483,19 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
         }
         .search-box {
             margin-bottom: 12px;
+            display: flex;
+            gap: 8px;
+        }
+        .search-source {
+            padding: 10px;
+            font-size: 14px;
+            border: 2px solid var(--border-color);
+            border-radius: 6px;
+            background: var(--bg-secondary);
+            color: var(--text-primary);
         }
         .search-input {
+            flex: 1;
             width: 100%%;
             padding: 10px;
             font-size: 14px;
@@ -392,6 +524,13 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             border-left: 3px solid var(--accent-primary);
             transition: background-color 0.3s;
         }
+        .search-result-icon {
+            width: 40px;
+            height: 40px;
+            border-radius: 9px;
+            margin-right: 10px;
+            flex-shrink: 0;
+        }
         .search-result-info {
             flex: 1;
         }
@@ -487,6 +626,50 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
         .toggle-notes:hover {
             color: var(--accent-secondary);
         }
+        .visually-hidden {
+            position: absolute;
+            width: 1px;
+            height: 1px;
+            overflow: hidden;
+            clip: rect(0 0 0 0);
+            white-space: nowrap;
+        }
+        .palette-content {
+            max-width: 560px;
+            margin-top: 12vh;
+            padding: 12px;
+        }
+        .palette-results {
+            list-style: none;
+            margin-top: 8px;
+            max-height: 50vh;
+            overflow-y: auto;
+        }
+        .palette-results li {
+            display: flex;
+            justify-content: space-between;
+            gap: 12px;
+            padding: 8px 10px;
+            border-radius: 6px;
+            cursor: pointer;
+        }
+        .palette-results li[aria-selected="true"] {
+            background: var(--search-result-bg);
+            outline: 2px solid var(--accent-primary);
+        }
+        .palette-results kbd, .palette-hint {
+            color: var(--text-secondary);
+            font-size: 0.85em;
+        }
+        .palette-hint {
+            margin-top: 8px;
+        }
+        .noscript {
+            padding: 12px 16px;
+            text-align: center;
+            background: var(--bg-secondary);
+            border-bottom: 1px solid var(--border-color);
+        }
         footer a {
             color: var(--accent-primary);
             text-decoration: none;
@@ -554,6 +737,131 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             background: #c82333;
             box-shadow: 0 2px 6px rgba(220, 53, 69, 0.3);
         }
+        .bulk-actions {
+            display: flex;
+            gap: 8px;
+            margin-bottom: 8px;
+        }
+        .bulk-actions label {
+            display: flex;
+            align-items: center;
+            gap: 6px;
+            font-size: 0.85em;
+            color: var(--text-secondary);
+        }
+        .ack-btn {
+            padding: 3px 10px;
+            font-size: 0.8em;
+        }
+        .app-card.selected {
+            outline: 2px solid var(--accent-primary);
+        }
+        .column-picker {
+            position: relative;
+            font-size: 0.85em;
+            color: var(--text-secondary);
+            align-self: center;
+        }
+        .column-picker summary {
+            cursor: pointer;
+        }
+        .column-picker #appColumnOptions {
+            position: absolute;
+            z-index: 10;
+            padding: 8px 12px;
+            border: 1px solid var(--border-color);
+            border-radius: 6px;
+            background: var(--bg-secondary);
+            display: flex;
+            flex-direction: column;
+            gap: 4px;
+            white-space: nowrap;
+        }
+        .compact .app-card {
+            padding: 4px 10px;
+            margin: 2px 0;
+            gap: 10px;
+            font-size: 0.9em;
+        }
+        .compact .app-details {
+            gap: 10px;
+        }
+        .compact .app-notes {
+            display: none;
+        }
+        .app-select {
+            margin-right: 8px;
+            pointer-events: none;
+        }
+        .app-notes {
+            font-size: 0.8em;
+            font-weight: normal;
+            font-style: italic;
+            color: var(--text-secondary);
+            margin-top: 2px;
+        }
+        .label-editor {
+            padding: 12px 24px;
+            border-top: 1px solid var(--border-color);
+            display: grid;
+            grid-template-columns: 1fr 2fr auto;
+            gap: 8px;
+            align-items: start;
+        }
+        .label-editor .search-input {
+            padding: 6px 8px;
+            font-family: inherit;
+        }
+        .testflight-editor {
+            grid-template-columns: 1fr auto;
+            border-top: none;
+            padding-top: 0;
+        }
+        .vendor-editor {
+            grid-template-columns: 1fr 1fr 1fr auto;
+            border-top: none;
+            padding-top: 0;
+        }
+        .vendor-editor .vendor-title {
+            grid-column: 1 / -1;
+            font-size: 0.85em;
+            color: var(--text-secondary);
+        }
+        .application-editor {
+            grid-template-columns: 1fr 1fr auto auto;
+            border-top: none;
+            padding-top: 0;
+        }
+        .application-editor .vendor-title {
+            grid-column: 1 / -1;
+            font-size: 0.85em;
+            color: var(--text-secondary);
+        }
+        .application-form .search-input {
+            flex: 1;
+        }
+        .application-tag {
+            display: inline-block;
+            margin-right: 4px;
+            padding: 1px 8px;
+            border-radius: 10px;
+            border: 1px solid var(--border-color);
+            background: var(--bg-primary);
+            color: var(--text-secondary);
+            font-size: 0.85em;
+            cursor: pointer;
+        }
+        .application-platform {
+            cursor: pointer;
+            white-space: nowrap;
+        }
+        .application-platforms {
+            grid-column: 1 / -1;
+            font-size: 0.85em;
+        }
+        .application-platforms:empty {
+            display: none;
+        }
         .modal-actions {
             padding: 16px 24px;
             border-top: 1px solid var(--border-color);
@@ -582,6 +890,15 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             flex-direction: column;
             gap: 4px;
         }
+        .import-box {
+            display: flex;
+            align-items: center;
+            gap: 10px;
+            flex-wrap: wrap;
+            margin-top: 12px;
+            font-size: 0.9em;
+            color: var(--text-secondary);
+        }
         .modal-subtitle {
             display: flex;
             align-items: center;
@@ -611,7 +928,9 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             align-items: center;
             justify-content: center;
             font-size: 20px;
+            font-family: inherit;
             font-weight: bold;
+            line-height: 1;
             cursor: pointer;
             transition: all 0.3s;
         }
@@ -671,6 +990,15 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             color: var(--text-secondary);
             line-height: 1.3;
         }
+        .notes-translation {
+            margin-top: 4px;
+            font-size: 0.9em;
+        }
+        .history-devices {
+            margin-top: 4px;
+            font-size: 0.75em;
+            color: var(--text-muted);
+        }
         .empty-history {
             text-align: center;
             padding: 24px;
@@ -678,6 +1006,110 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             font-style: italic;
             font-size: 0.9em;
         }
+        .linked-update {
+            margin: 0 0 12px;
+            padding: 10px 12px;
+            border: 2px solid var(--accent-primary);
+            border-radius: 6px;
+        }
+        .permalink {
+            text-decoration: none;
+            margin-left: 6px;
+        }
+        .history-more {
+            text-align: center;
+            padding: 12px 0 0;
+        }
+        .activity-heatmap svg {
+            display: block;
+            width: 100%%;
+            max-width: 760px;
+            height: auto;
+        }
+        .activity-heatmap text {
+            fill: var(--text-secondary);
+            font-size: 9px;
+        }
+        .activity-heatmap .heat-0 { fill: var(--heat-0); }
+        .activity-heatmap .heat-1 { fill: var(--heat-1); }
+        .activity-heatmap .heat-2 { fill: var(--heat-2); }
+        .activity-heatmap .heat-3 { fill: var(--heat-3); }
+        .activity-heatmap .heat-4 { fill: var(--heat-4); }
+        .size-chart {
+            margin: 0 0 12px;
+            color: var(--text-secondary);
+            font-size: 13px;
+        }
+        .size-chart svg {
+            display: block;
+            width: 100%%;
+            height: auto;
+        }
+        .size-chart polyline {
+            fill: none;
+            stroke: var(--accent-primary);
+            stroke-width: 2;
+        }
+        .size-chart circle {
+            fill: var(--accent-primary);
+        }
+        .compare-grid {
+            display: grid;
+            grid-template-columns: 1fr 1fr;
+            gap: 16px;
+            margin-top: 12px;
+        }
+        .compare-grid h3 {
+            font-size: 1em;
+            margin-bottom: 4px;
+        }
+        .compare-grid dl {
+            display: grid;
+            grid-template-columns: auto 1fr;
+            gap: 2px 12px;
+            font-size: 13px;
+            margin: 8px 0;
+        }
+        .compare-grid dt {
+            color: var(--text-secondary);
+        }
+        .compare-grid ol {
+            list-style: none;
+            font-size: 13px;
+            max-height: 320px;
+            overflow-y: auto;
+            border-top: 1px solid var(--border-color);
+        }
+        .compare-grid li {
+            display: flex;
+            justify-content: space-between;
+            padding: 3px 0;
+            border-bottom: 1px solid var(--border-color);
+        }
+        @media (max-width: 700px) {
+            .compare-grid {
+                grid-template-columns: 1fr;
+            }
+        }
+        .history-cadence {
+            color: var(--text-secondary);
+            font-size: 13px;
+            margin-bottom: 12px;
+        }
+        .snapshot-changes {
+            margin: 12px 0;
+            color: var(--text-secondary);
+            font-size: 13px;
+        }
+        .snapshot-changes summary {
+            cursor: pointer;
+        }
+        .snapshot-value {
+            max-height: 120px;
+            overflow-y: auto;
+            white-space: pre-wrap;
+            word-break: break-word;
+        }
         .loading-history {
             text-align: center;
             padding: 24px;
@@ -701,16 +1133,22 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
     </style>
 </head>
 <body>
+    <noscript><p class="noscript">%[4]s</p></noscript>
     <div class="container">
         <header>
             <div class="header-left">
                 <h1>📱 MAVT</h1>
             </div>
             <div class="header-right">
+                <div class="last-synced-box" id="checkStatus" style="display:none;">
+                    <span id="checkStatusLabel" data-i18n="header.checking">Checking:</span>
+                    <span id="checkStatusText"></span>
+                </div>
                 <div class="last-synced-box" id="lastSynced">
-                    <span>Synced:</span>
-                    <span id="lastSyncedTime">Loading...</span>
+                    <span data-i18n="header.synced">Synced:</span>
+                    <span id="lastSyncedTime" data-i18n="header.loading">Loading...</span>
                 </div>
+                <button class="theme-toggle" id="paletteToggle" aria-label="Command palette (/)" aria-keyshortcuts="/" data-i18n-label="palette.open" onclick="openPalette()">/</button>
                 <button class="theme-toggle" id="themeToggle" aria-label="Toggle dark mode">🌙</button>
                 <a href="https://github.com/hoiber/mavt" target="_blank" rel="noopener noreferrer" class="github-link" aria-label="View on GitHub">
                     <svg viewBox="0 0 16 16" xmlns="http://www.w3.org/2000/svg">
@@ -721,135 +1159,624 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
         </header>
 
         <div class="section">
-            <h2>Search & Add Apps</h2>
+            <h2 data-i18n="search.title">Search & Add Apps</h2>
             <div class="search-box">
-                <input type="text" id="searchInput" class="search-input" placeholder="Search App Store (e.g., 'Instagram', 'WhatsApp')..." />
+                <select id="searchSource" class="search-source" aria-label="Search source">
+                    <option value="appstore">App Store</option>
+                    <option value="npm">npm</option>
+                    <option value="pypi">PyPI</option>
+                    <option value="all" data-i18n="search.allSources">All sources</option>
+                </select>
+                <input type="text" id="searchInput" class="search-input" placeholder="Search App Store (e.g., 'Instagram', 'WhatsApp')..." data-i18n-placeholder="search.placeholder" />
             </div>
             <div id="searchResults" class="search-results"></div>
+            <div class="import-box">
+                <label for="importFile" data-i18n="search.import">Import from MDM/CSV export:</label>
+                <input type="file" id="importFile" accept=".csv,text/csv" />
+                <button class="btn" onclick="importCSV(true)" data-i18n="search.preview">Preview</button>
+                <button class="btn" id="importBtn" onclick="importCSV(false)" disabled data-i18n="search.importButton">Import</button>
+            </div>
+            <div id="importResults"></div>
+        </div>
+
+        <div class="section">
+            <h2 id="updatesTitle" tabindex="-1" data-i18n="updates.title">Recent Updates</h2>
+            <div class="bulk-actions">
+                <label for="savedViews" data-i18n="updates.view">View:</label>
+                <select id="savedViews" onchange="selectSavedView(this.value)">
+                    <option value="" data-i18n="updates.customView">Custom</option>
+                </select>
+                <button class="btn" onclick="saveView()" data-i18n="updates.saveView">Save view</button>
+                <button class="btn btn-danger" id="deleteViewBtn" onclick="deleteView()" style="display:none;" data-i18n="updates.deleteView">Delete view</button>
+            </div>
+            <div class="bulk-actions">
+                <label for="updatePeriod" data-i18n="updates.period">Period:</label>
+                <select id="updatePeriod" onchange="loadUpdates()">
+                    <option value="24h" data-i18n="updates.last24h">Last 24 hours</option>
+                    <option value="7d" selected data-i18n="updates.last7d">Last 7 days</option>
+                    <option value="30d" data-i18n="updates.last30d">Last 30 days</option>
+                    <option value="90d" data-i18n="updates.last90d">Last 90 days</option>
+                </select>
+                <label><input type="checkbox" id="unacknowledgedOnly" onchange="loadUpdates()"> <span data-i18n="updates.unacknowledgedOnly">Unacknowledged only</span></label>
+                <label><input type="checkbox" id="myUpdatesOnly" onchange="loadUpdates()"> <span data-i18n="updates.mine">My updates</span></label>
+                <label><input type="checkbox" id="pendingApprovalOnly" onchange="loadUpdates()"> <span data-i18n="updates.pendingApproval">Pending approval</span></label>
+                <label for="updateSeverity" data-i18n="updates.severity">Severity:</label>
+                <select id="updateSeverity" onchange="loadUpdates()">
+                    <option value="" data-i18n="common.all">All</option>
+                    <option value="critical" data-i18n="updates.critical">Critical</option>
+                    <option value="critical,major" data-i18n="updates.majorOrWorse">Major or worse</option>
+                    <option value="critical,major,minor" data-i18n="updates.minorOrWorse">Minor or worse</option>
+                </select>
+                <label for="updateTag" data-i18n="common.tag">Tag:</label>
+                <select id="updateTag" onchange="loadUpdates()">
+                    <option value="" data-i18n="common.all">All</option>
+                </select>
+                <label for="updateSort" data-i18n="updates.sort">Sort:</label>
+                <select id="updateSort" onchange="loadUpdates()">
+                    <option value="newest" data-i18n="updates.newest">Newest first</option>
+                    <option value="oldest" data-i18n="updates.oldest">Oldest first</option>
+                    <option value="app" data-i18n="updates.appName">App name</option>
+                    <option value="severity" data-i18n="updates.bySeverity">Severity</option>
+                </select>
+            </div>
+            <div id="updates" class="loading" data-i18n="updates.loading">Loading updates...</div>
+        </div>
+
+        <div class="section">
+            <h2 data-i18n="activity.title">Release Activity</h2>
+            <div class="activity-heatmap" id="activity"></div>
+        </div>
+
+        <div class="section">
+            <h2 id="compareTitle" tabindex="-1" data-i18n="compare.title">Compare Apps</h2>
+            <div class="bulk-actions">
+                <label for="compareA" class="visually-hidden" data-i18n="compare.first">First app</label>
+                <select id="compareA"></select>
+                <label for="compareB" class="visually-hidden" data-i18n="compare.second">Second app</label>
+                <select id="compareB"></select>
+                <button class="btn" onclick="loadComparison()" data-i18n="compare.button">Compare</button>
+            </div>
+            <div id="comparison" class="history-cadence" data-i18n="compare.pick">Pick two tracked apps to compare their release cadence.</div>
         </div>
 
         <div class="section">
-            <h2>Recent Updates (Last 7 Days)</h2>
-            <div id="updates" class="loading">Loading updates...</div>
+            <h2 id="appsTitle" tabindex="-1" data-i18n="apps.title">Tracked Apps</h2>
+            <div class="bulk-actions">
+                <button class="btn" id="selectModeBtn" onclick="toggleSelectMode()" data-i18n="apps.select">Select</button>
+                <button class="btn btn-danger" id="removeSelectedBtn" onclick="removeSelectedApps()" style="display:none;" disabled>Remove selected (0)</button>
+                <details class="column-picker">
+                    <summary data-i18n="apps.columns">Columns</summary>
+                    <div id="appColumnOptions"></div>
+                </details>
+                <label for="appDensity" data-i18n="apps.density">Density:</label>
+                <select id="appDensity" onchange="setAppDensity(this.value)">
+                    <option value="comfortable" data-i18n="apps.comfortable">Comfortable</option>
+                    <option value="compact" data-i18n="apps.compact">Compact</option>
+                </select>
+            </div>
+            <div id="apps" class="loading" data-i18n="apps.loading">Loading apps...</div>
+        </div>
+
+        <div class="section">
+            <h2 data-i18n="applications.title">Applications</h2>
+            <div class="bulk-actions application-form">
+                <input type="text" id="newApplicationName" class="search-input" placeholder="Application, e.g. Slack" data-i18n-placeholder="applications.namePlaceholder">
+                <input type="text" id="newApplicationOwner" class="search-input" placeholder="Owner (optional)" data-i18n-placeholder="applications.ownerPlaceholder">
+                <input type="text" id="newApplicationTags" class="search-input" placeholder="Tags, comma-separated (optional)" data-i18n-placeholder="applications.tagsPlaceholder">
+                <button class="btn" id="saveApplicationBtn" onclick="saveApplication()" data-i18n="applications.create">Create</button>
+                <button class="btn" id="cancelApplicationBtn" onclick="resetApplicationForm()" style="display:none;" data-i18n="common.cancel">Cancel</button>
+            </div>
+            <div class="bulk-actions">
+                <label for="applicationTagFilter" data-i18n="common.tag">Tag:</label>
+                <select id="applicationTagFilter" onchange="loadApplications()">
+                    <option value="" data-i18n="common.all">All</option>
+                </select>
+                <a href="/api/report?group_by=application&format=html" target="_blank" rel="noopener noreferrer" data-i18n="applications.report">Report by application</a>
+                <a href="/api/report?group_by=tag&since=30d&format=html" target="_blank" rel="noopener noreferrer" data-i18n="applications.tagReport">Competitor report by tag</a>
+            </div>
+            <div id="applications" class="loading" data-i18n="applications.loading">Loading applications...</div>
         </div>
 
         <div class="section">
-            <h2>Tracked Apps</h2>
-            <div id="apps" class="loading">Loading apps...</div>
+            <h2 data-i18n="watchlist.title">My Watchlist</h2>
+            <div class="history-cadence" id="watchlistDescription"></div>
+            <div class="bulk-actions application-form">
+                <input type="text" id="watchlistKeywords" class="search-input" placeholder="Keywords, comma-separated, e.g. GDPR, login, crash fix" data-i18n-placeholder="watchlist.keywordsPlaceholder">
+                <input type="text" id="watchlistNotifyURL" class="search-input" placeholder="Apprise URL to notify, e.g. mailto://you@example.com (optional)" data-i18n-placeholder="watchlist.notifyPlaceholder">
+                <button class="btn" id="saveWatchlistBtn" onclick="saveWatchlist()" data-i18n="common.save">Save</button>
+            </div>
+        </div>
+
+        <div class="section" id="complianceSection" style="display:none;">
+            <h2 data-i18n="compliance.title">Fleet Compliance</h2>
+            <div id="compliance"></div>
+        </div>
+
+        <div class="section" id="compatibilitySection" style="display:none;">
+            <h2 data-i18n="compatibility.title">Compatibility Risks</h2>
+            <div class="history-cadence" id="fleetProfile"></div>
+            <div id="compatibility"></div>
+        </div>
+
+        <div class="section" id="webhooksSection" style="display:none;">
+            <h2 data-i18n="webhooks.title">Webhook Deliveries</h2>
+            <div id="webhookDeliveries"></div>
         </div>
     </div>
 
     <!-- Version History Modal -->
-    <div id="historyModal" class="modal">
+    <div id="historyModal" class="modal" role="dialog" aria-modal="true" aria-labelledby="modalAppName">
         <div class="modal-content">
             <div class="modal-header">
-                <span class="close" onclick="closeHistoryModal()">&times;</span>
-                <h2 id="modalAppName">Version History</h2>
+                <button type="button" class="close" id="closeHistoryBtn" onclick="closeHistoryModal()" aria-label="Close" data-i18n-label="history.close">&times;</button>
+                <h2 id="modalAppName" data-i18n="history.title">Version History</h2>
                 <p id="modalAppDetails"></p>
             </div>
+            <div class="linked-update" id="linkedUpdate" style="display:none;"></div>
+            <div class="size-chart" id="sizeChart" style="display:none;"></div>
+            <div class="size-chart activity-heatmap" id="appActivity" style="display:none;"></div>
             <div class="modal-body" id="historyTableContainer">
-                <div class="loading-history">Loading version history...</div>
+                <div class="loading-history" data-i18n="history.loading">Loading version history...</div>
+            </div>
+            <details class="snapshot-changes" id="snapshotChanges" style="display:none;">
+                <summary id="snapshotSummary" data-i18n="history.listingChanges">Listing changes</summary>
+                <div id="snapshotList"></div>
+            </details>
+            <div class="label-editor">
+                <input type="text" id="labelDisplayName" class="search-input" placeholder="Display name (optional)" data-i18n-placeholder="history.displayNamePlaceholder">
+                <textarea id="labelNotes" class="search-input" rows="2" placeholder="Notes, e.g. used by Sales, contact J. Doe" data-i18n-placeholder="history.notesPlaceholder"></textarea>
+                <button class="btn" id="saveLabelBtn" onclick="saveAppLabel()" data-i18n="common.save">Save</button>
+            </div>
+            <div class="label-editor testflight-editor">
+                <input type="text" id="testFlightURL" class="search-input" placeholder="Public TestFlight link, e.g. https://testflight.apple.com/join/AbCd1234" data-i18n-placeholder="history.testFlightPlaceholder">
+                <button class="btn" id="saveTestFlightBtn" onclick="saveTestFlightURL()" data-i18n="common.save">Save</button>
+            </div>
+            <div class="label-editor vendor-editor">
+                <div class="vendor-title" id="vendorTitle" data-i18n="history.vendor">Vendor</div>
+                <input type="text" id="vendorOwner" class="search-input" placeholder="Internal owner" data-i18n-placeholder="history.vendorOwner">
+                <input type="text" id="vendorSupport" class="search-input" placeholder="Vendor support contact" data-i18n-placeholder="history.vendorSupport">
+                <input type="text" id="vendorSLA" class="search-input" placeholder="Contract / SLA notes" data-i18n-placeholder="history.vendorSLA">
+                <button class="btn" id="saveVendorBtn" onclick="saveVendor()" data-i18n="common.save">Save</button>
+            </div>
+            <div class="label-editor application-editor">
+                <div class="vendor-title" id="applicationTitle" data-i18n="history.application">Application: link this app with the same product on other platforms</div>
+                <div class="application-platforms" id="applicationPlatforms"></div>
+                <input type="text" id="applicationName" class="search-input" placeholder="Application, e.g. Slack" data-i18n-placeholder="applications.namePlaceholder">
+                <input type="text" id="applicationPlatform" class="search-input" placeholder="Platform, e.g. iOS (optional)" data-i18n-placeholder="history.platformPlaceholder">
+                <button class="btn" id="linkApplicationBtn" onclick="linkApplication()" data-i18n="history.link">Link</button>
+                <button class="btn btn-danger" id="unlinkApplicationBtn" onclick="unlinkApplication()" style="display:none;" data-i18n="history.unlink">Unlink</button>
             </div>
             <div class="modal-actions">
-                <button class="btn btn-danger" id="removeAppBtn" onclick="removeAppFromHistory()">Remove App</button>
+                <button class="btn btn-danger" id="removeAppBtn" onclick="removeAppFromHistory()" data-i18n="history.archive">Archive App</button>
             </div>
         </div>
     </div>
 
+    <!-- Command Palette -->
+    <div id="commandPalette" class="modal" role="dialog" aria-modal="true" aria-labelledby="paletteTitle">
+        <div class="modal-content palette-content">
+            <h2 id="paletteTitle" class="visually-hidden" data-i18n="palette.title">Command palette</h2>
+            <input type="text" id="paletteInput" class="search-input" role="combobox" aria-expanded="true" aria-controls="paletteResults" aria-autocomplete="list" autocomplete="off" placeholder="Search apps or commands..." data-i18n-placeholder="palette.placeholder">
+            <ul id="paletteResults" class="palette-results" role="listbox" aria-labelledby="paletteTitle"></ul>
+            <div class="palette-hint" data-i18n="palette.hint">↑↓ to move, Enter to run, Esc to close. Outside the palette, j and k move through the tracked apps.</div>
+        </div>
+    </div>
+
     <footer style="text-align: center; padding: 12px; color: var(--text-muted); font-size: 0.8em;">
-        MAVT v` + version.Version + ` &bull; <a href="https://github.com/thomas/mavt">GitHub</a>
+        MAVT v` + version.Version + ` &bull; <a href="https://github.com/thomas/mavt">GitHub</a> &bull; <a href="/basic" data-i18n="basic.link">Basic view</a>
+        <span id="releaseNotice" style="display:none;"> &bull; <a id="releaseLink" href="https://github.com/thomas/mavt/releases" target="_blank" rel="noopener noreferrer"></a></span>
     </footer>
 
     <script>
+        // The language set by MAVT_LANG and its messages, by message ID
+        const LANG = '%[2]s';
+        const MESSAGES = %[3]s;
+
+        // Look up a message, filling its {0}, {1}, ... placeholders with args
+        function t(id, ...args) {
+            const message = MESSAGES[id] || id;
+            return message.replace(/\{(\d+)\}/g, (placeholder, i) => i < args.length ? args[i] : placeholder);
+        }
+
+        // Translate the page's static text: elements marked data-i18n get
+        // their message as text, and data-i18n-placeholder as placeholder
+        function translatePage() {
+            document.querySelectorAll('[data-i18n]').forEach(element => {
+                element.textContent = t(element.dataset.i18n);
+            });
+            // Inputs without a visible label are named by their placeholder
+            document.querySelectorAll('[data-i18n-placeholder]').forEach(element => {
+                element.placeholder = t(element.dataset.i18nPlaceholder);
+                element.setAttribute('aria-label', element.placeholder);
+            });
+            document.querySelectorAll('[data-i18n-label]').forEach(element => {
+                element.setAttribute('aria-label', t(element.dataset.i18nLabel));
+            });
+            document.getElementById('themeToggle').setAttribute('aria-label', t('header.toggleTheme'));
+        }
+
+        // Tracked apps keyed by bundle ID, used by the detail modal
+        let appsByBundleId = {};
+        let trackedApps = [];
+
+        // Multi-select mode for removing several apps at once
+        let selectMode = false;
+        const selectedApps = new Set();
+
         async function loadApps() {
             try {
                 const response = await fetch('/api/apps');
                 const apps = await response.json();
-                const container = document.getElementById('apps');
 
-                if (!apps || apps.length === 0) {
-                    container.innerHTML = '<div class="empty-state">No apps are currently being tracked</div>';
-                    return;
-                }
+                trackedApps = apps || [];
+                appsByBundleId = {};
+                trackedApps.forEach(app => { appsByBundleId[app.bundle_id] = app; });
 
-                container.innerHTML = apps.map((app, index) => {
-                    let releaseNotesToggle = '';
-                    let releaseNotesContent = '';
-                    let isCritical = false;
-                    if (app.release_notes && app.release_notes.trim()) {
-                        const notesId = 'app-notes-' + index;
-                        // Check if release notes contain CVE
-                        isCritical = app.release_notes.toUpperCase().includes('CVE');
-                        releaseNotesToggle = '<span class="toggle-notes" onclick="event.stopPropagation(); toggleNotes(\'' + notesId + '\', this)">Latest Release Notes (v' + app.version + ') ▼</span>';
-                        releaseNotesContent = '<div class="release-notes" id="' + notesId + '" style="display:none;">' +
-                            app.release_notes +
-                        '</div>';
-                    }
+                // Drop selections for apps that are no longer tracked
+                selectedApps.forEach(bundleId => {
+                    if (!appsByBundleId[bundleId]) selectedApps.delete(bundleId);
+                });
+                updateRemoveSelectedButton();
 
-                    const versionClass = isCritical ? 'version critical' : 'version';
-                    return '<div class="app-card" onclick="showVersionHistory(\'' + app.bundle_id + '\', \'' + app.track_name.replace(/'/g, "\\'") + '\', \'' + app.artist_name.replace(/'/g, "\\'") + '\')">' +
-                        '<div class="app-name">' + app.track_name + '</div>' +
-                        '<span class="' + versionClass + '">' + app.version + '</span>' +
-                        '<div class="app-details">' +
-                            '<div class="detail">' +
-                                '<span class="detail-label">Bundle:</span>' +
-                                '<span class="detail-value">' + app.bundle_id + '</span>' +
-                            '</div>' +
-                            '<div class="detail">' +
-                                '<span class="detail-label">Dev:</span>' +
-                                '<span class="detail-value">' + app.artist_name + '</span>' +
-                            '</div>' +
-                            '<div class="detail">' +
-                                '<span class="detail-label">Checked:</span>' +
-                                '<span class="detail-value">' + new Date(app.last_checked).toLocaleString() + '</span>' +
-                            '</div>' +
-                        '</div>' +
-                        (releaseNotesToggle ? '<div class="notes-toggle-container">' + releaseNotesToggle + '</div>' : '<div></div>') +
-                        releaseNotesContent +
-                    '</div>';
-                }).join('');
+                renderApps();
+                compareOptions();
             } catch (error) {
                 document.getElementById('apps').innerHTML =
                     '<div class="error">Failed to load apps: ' + error.message + '</div>';
             }
         }
 
-        async function loadUpdates() {
+        // Optional columns of the tracked apps list, in display order, labeled
+        // by their apps.column.* message; those marked shown are on until the
+        // user picks their own
+        const appColumns = {
+            bundle: { shown: true, value: app => escapeHtml(app.bundle_id) },
+            developer: { shown: true, value: app => escapeHtml(app.artist_name) },
+            store: { shown: true, value: app => escapeHtml([app.country, app.language].filter(Boolean).join(' / ')) },
+            price: { value: app => escapeHtml(formatPrice(app)) },
+            min_os: { value: app => escapeHtml(app.min_os_version) },
+            size: { value: app => app.file_size_bytes ? formatBytes(app.file_size_bytes) : '' },
+            released: { value: app => app.release_date && new Date(app.release_date).getFullYear() > 1 ?
+                new Date(app.release_date).toLocaleDateString(LANG) : '' },
+            tags: { value: app => appTags(app.bundle_id).map(t =>
+                '<span class="application-tag" onclick="event.stopPropagation(); filterApplications(\'' + jsString(t) + '\')">' + escapeHtml(t) + '</span>').join('') },
+            checked: { shown: true, value: app => new Date(app.last_checked).toLocaleString(LANG) },
+        };
+        const APP_COLUMNS_KEY = 'mavt-app-columns';
+        const APP_DENSITY_KEY = 'mavt-app-density';
+
+        // The columns this browser shows in the tracked apps list
+        function shownAppColumns() {
             try {
-                const response = await fetch('/api/updates?since=168h'); // 7 days
+                const saved = JSON.parse(localStorage.getItem(APP_COLUMNS_KEY));
+                if (Array.isArray(saved)) {
+                    return Object.keys(appColumns).filter(key => saved.includes(key));
+                }
+            } catch (error) {
+                // Fall back to the default columns
+            }
+            return Object.keys(appColumns).filter(key => appColumns[key].shown);
+        }
+
+        // Show the column pickers and density for this browser's settings
+        function initializeAppDisplay() {
+            const shown = shownAppColumns();
+            document.getElementById('appColumnOptions').innerHTML = Object.keys(appColumns).map(key =>
+                '<label><input type="checkbox" value="' + key + '"' + (shown.includes(key) ? ' checked' : '') +
+                    ' onchange="saveAppColumns()"> ' + t('apps.column.' + key) + '</label>').join('');
+            const density = localStorage.getItem(APP_DENSITY_KEY) || 'comfortable';
+            document.getElementById('appDensity').value = density;
+            document.getElementById('apps').classList.toggle('compact', density === 'compact');
+        }
+
+        function saveAppColumns() {
+            const checked = [...document.querySelectorAll('#appColumnOptions input:checked')].map(input => input.value);
+            localStorage.setItem(APP_COLUMNS_KEY, JSON.stringify(checked));
+            renderApps();
+        }
+
+        function setAppDensity(density) {
+            localStorage.setItem(APP_DENSITY_KEY, density);
+            document.getElementById('apps').classList.toggle('compact', density === 'compact');
+        }
+
+        // Format an app's price in its currency, or "Free"
+        function formatPrice(app) {
+            if (!app.price) {
+                return app.currency ? t('apps.free') : '';
+            }
+            try {
+                return new Intl.NumberFormat(undefined, { style: 'currency', currency: app.currency }).format(app.price);
+            } catch (error) {
+                return app.price + ' ' + (app.currency || '');
+            }
+        }
+
+        // The tags of the application an app is linked into, if any
+        function appTags(bundleId) {
+            const application = Object.values(applicationsById).find(application =>
+                (application.platforms || []).some(platform => platform.bundle_id === bundleId));
+            return application ? application.tags || [] : [];
+        }
+
+        // Render the tracked apps with this browser's columns
+        function renderApps() {
+            const container = document.getElementById('apps');
+            if (trackedApps.length === 0) {
+                container.innerHTML = '<div class="empty-state">' + t('apps.none') + '</div>';
+                return;
+            }
+
+            const columns = shownAppColumns();
+            container.innerHTML = trackedApps.map((app, index) => {
+                let releaseNotesToggle = '';
+                let releaseNotesContent = '';
+                let isCritical = false;
+                if (app.release_notes && app.release_notes.trim()) {
+                    const notesId = 'app-notes-' + index;
+                    // Check if release notes contain CVE
+                    isCritical = app.release_notes.toUpperCase().includes('CVE');
+                    releaseNotesToggle = '<span class="toggle-notes" onclick="event.stopPropagation(); toggleNotes(\'' + notesId + '\', this)">Latest Release Notes (v' + app.version + ') ▼</span>';
+                    releaseNotesContent = '<div class="release-notes" id="' + notesId + '" style="display:none;"' + (app.language ? ' lang="' + app.language.replace('_', '-') + '"' : '') + '>' +
+                        app.release_notes +
+                    '</div>';
+                }
+
+                const versionClass = isCritical ? 'version critical' : 'version';
+                const selected = selectedApps.has(app.bundle_id);
+                const clickHandler = selectMode ?
+                    'toggleAppSelection(\'' + app.bundle_id + '\')' :
+                    'showVersionHistory(\'' + app.bundle_id + '\', \'' + jsString(app.display_name || app.track_name) + '\', \'' + jsString(app.artist_name) + '\')';
+                const checkbox = selectMode ?
+                    '<input type="checkbox" class="app-select"' + (selected ? ' checked' : '') + '>' : '';
+                const details = columns.map(key => {
+                    const value = appColumns[key].value(app);
+                    return value ?
+                        '<div class="detail">' +
+                            '<span class="detail-label">' + t('apps.column.' + key) + ':</span>' +
+                            '<span class="detail-value">' + value + '</span>' +
+                        '</div>' : '';
+                });
+                if (app.display_name) {
+                    details.splice(columns.includes('developer') ? columns.indexOf('developer') + 1 : 0, 0,
+                        '<div class="detail">' +
+                            '<span class="detail-label">' + t('apps.appStoreName') + ':</span>' +
+                            '<span class="detail-value">' + app.track_name + '</span>' +
+                        '</div>');
+                }
+                return '<div class="app-card' + (selectMode && selected ? ' selected' : '') + '" tabindex="0" aria-haspopup="dialog" onclick="' + clickHandler + '" onkeydown="activateOnKey(event)">' +
+                    '<div class="app-name">' + checkbox + (app.display_name || app.track_name) +
+                        (app.notes ? '<div class="app-notes">' + app.notes + '</div>' : '') +
+                    '</div>' +
+                    '<span class="' + versionClass + '">' + app.version + '</span>' +
+                    '<div class="app-details">' + details.join('') + '</div>' +
+                    (releaseNotesToggle ? '<div class="notes-toggle-container">' + releaseNotesToggle + '</div>' : '<div></div>') +
+                    releaseNotesContent +
+                '</div>';
+            }).join('');
+        }
+
+        // Escape a value for a single-quoted JS string inside an HTML attribute
+        function jsString(value) {
+            return (value || '').replace(/\\/g, '\\\\').replace(/'/g, "\\'").replace(/"/g, '&quot;');
+        }
+
+        // Escape text from outside MAVT, such as a webhook response, for HTML
+        // Error codes explained in the user's language rather than by the
+        // server's message alone
+        const errorCodeMessages = {
+            rate_limited: 'errors.rate_limited',
+            upstream_error: 'errors.upstream_error',
+            storage_error: 'errors.storage_error',
+        };
+
+        // The message of a failed API request, whose body is a JSON error
+        // like {"error": {"status": 400, "code": "invalid_parameter", "message": "..."}}
+        async function errorMessage(response) {
+            const text = await response.text();
+            let error;
+            try {
+                error = JSON.parse(text).error;
+            } catch (parseError) {
+                return text;
+            }
+            if (!error) {
+                return text;
+            }
+            return errorCodeMessages[error.code] ? t(errorCodeMessages[error.code], error.message) : error.message;
+        }
+
+        function escapeHtml(value) {
+            return (value || '').replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+        }
+
+        function toggleSelectMode() {
+            selectMode = !selectMode;
+            if (!selectMode) {
+                selectedApps.clear();
+            }
+            document.getElementById('selectModeBtn').textContent = selectMode ? t('common.cancel') : t('apps.select');
+            document.getElementById('removeSelectedBtn').style.display = selectMode ? '' : 'none';
+            updateRemoveSelectedButton();
+            loadApps();
+        }
+
+        function toggleAppSelection(bundleId) {
+            if (selectedApps.has(bundleId)) {
+                selectedApps.delete(bundleId);
+            } else {
+                selectedApps.add(bundleId);
+            }
+            updateRemoveSelectedButton();
+            loadApps();
+        }
+
+        function updateRemoveSelectedButton() {
+            const button = document.getElementById('removeSelectedBtn');
+            button.textContent = t('apps.removeSelected', selectedApps.size);
+            button.disabled = selectedApps.size === 0;
+        }
+
+        async function removeSelectedApps() {
+            const bundleIds = Array.from(selectedApps);
+            if (bundleIds.length === 0) {
+                return;
+            }
+
+            if (!confirm('Archive ' + bundleIds.length + ' app(s)? They will no longer be checked, but their version history is kept and they can be restored later.')) {
+                return;
+            }
+
+            const button = document.getElementById('removeSelectedBtn');
+            button.disabled = true;
+            button.textContent = 'Removing...';
+
+            try {
+                const response = await fetch('/api/track/bulk', {
+                    method: 'DELETE',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({ bundle_ids: bundleIds })
+                });
+
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+
+                const result = await response.json();
+                const failed = Object.keys(result.failed || {});
+                if (failed.length > 0) {
+                    alert('Failed to remove: ' + failed.map(id => id + ' (' + result.failed[id] + ')').join(', '));
+                }
+
+                (result.removed || []).forEach(bundleId => selectedApps.delete(bundleId));
+                if (selectedApps.size === 0) {
+                    toggleSelectMode();
+                } else {
+                    updateRemoveSelectedButton();
+                    await loadApps();
+                }
+            } catch (error) {
+                alert('Failed to remove apps: ' + error.message);
+                updateRemoveSelectedButton();
+            }
+        }
+
+        async function loadUpdates() {
+            try {
+                const unacknowledgedOnly = document.getElementById('unacknowledgedOnly').checked;
+                const myUpdatesCheckbox = document.getElementById('myUpdatesOnly');
+                const period = document.getElementById('updatePeriod');
+                let url = '/api/updates?since=' + period.value;
+                if (unacknowledgedOnly) {
+                    url += '&unacknowledged=true';
+                }
+                if (document.getElementById('pendingApprovalOnly').checked) {
+                    url += '&approval=pending';
+                }
+                const severity = document.getElementById('updateSeverity').value;
+                if (severity) {
+                    url += '&severity=' + severity;
+                }
+                const tag = document.getElementById('updateTag').value;
+                if (tag) {
+                    url += '&tag=' + encodeURIComponent(tag);
+                }
+                if (myUpdatesCheckbox.checked) {
+                    const me = currentUser();
+                    if (!me) {
+                        myUpdatesCheckbox.checked = false;
+                    } else {
+                        url += '&assignee=' + encodeURIComponent(me);
+                    }
+                }
+
+                const response = await fetch(url);
                 const updates = await response.json();
                 const container = document.getElementById('updates');
 
                 if (!updates || updates.length === 0) {
-                    container.innerHTML = '<div class="empty-state">No updates in the last 7 days</div>';
+                    container.innerHTML = '<div class="empty-state">' +
+                        t('updates.none', period.options[period.selectedIndex].text) + '</div>';
                     return;
                 }
+                sortUpdates(updates, document.getElementById('updateSort').value);
 
                 container.innerHTML = updates.map((update, index) => {
                     let releaseNotesToggle = '';
                     let releaseNotesContent = '';
-                    let isCritical = false;
+                    const isCritical = update.severity === 'critical';
                     if (update.release_notes && update.release_notes.trim()) {
                         const notesId = 'update-notes-' + index;
-                        // Check if release notes contain CVE
-                        isCritical = update.release_notes.toUpperCase().includes('CVE');
                         releaseNotesToggle = '<span class="toggle-notes" onclick="toggleNotes(\'' + notesId + '\', this)">Release Notes (v' + update.new_version + ') ▼</span>';
                         releaseNotesContent = '<div class="release-notes" id="' + notesId + '" style="display:none;">' +
-                            update.release_notes +
+                            releaseNotesHtml(update, '') +
                         '</div>';
                     }
 
                     const versionClass = isCritical ? 'version critical' : 'version version-update';
-                    const versionChange = '<span class="' + versionClass + '">' + update.old_version + ' → ' + update.new_version + '</span>';
+                    const versionChange = '<span class="' + versionClass + '">' + versionChangeText(update) + '</span>';
+                    const ackArgs = '\'' + update.bundle_id + '\', \'' + jsString(update.new_version) + '\'';
+                    const acknowledgement = update.acknowledged ?
+                        '<div class="detail">' +
+                            '<span class="detail-label">' + t('updates.acknowledged') + '</span>' +
+                            '<span class="detail-value">' + update.acknowledged.by + ', ' + new Date(update.acknowledged.at).toLocaleDateString(LANG) + '</span>' +
+                            '<button class="btn ack-btn" onclick="acknowledgeUpdate(' + ackArgs + ', false)">' + t('updates.undo') + '</button>' +
+                        '</div>' :
+                        '<div class="detail">' +
+                            '<button class="btn ack-btn" onclick="acknowledgeUpdate(' + ackArgs + ', true)">' + t('updates.acknowledge') + '</button>' +
+                        '</div>';
+                    const assignment = '<div class="detail">' +
+                        (update.assignment ?
+                            '<span class="detail-label">' + t('updates.assigned') + '</span>' +
+                            '<span class="detail-value">' + update.assignment.to + '</span>' : '') +
+                        '<button class="btn ack-btn" onclick="assignUpdate(' + ackArgs + ', \'' + jsString(update.assignment ? update.assignment.to : '') + '\')">' +
+                            (update.assignment ? t('updates.reassign') : t('updates.assign')) + '</button>' +
+                    '</div>';
+
+                    let approval = '';
+                    if (update.approval && update.approval.state === 'pending') {
+                        approval = '<div class="detail">' +
+                            '<span class="detail-label">' + t('updates.approval') + '</span>' +
+                            '<span class="detail-value">' + t('updates.pending') + '</span>' +
+                            '<button class="btn ack-btn" onclick="decideUpdate(' + ackArgs + ', \'approved\')">' + t('updates.approve') + '</button>' +
+                            '<button class="btn ack-btn btn-danger" onclick="decideUpdate(' + ackArgs + ', \'rejected\')">' + t('updates.reject') + '</button>' +
+                        '</div>';
+                    } else if (update.approval) {
+                        approval = '<div class="detail">' +
+                            '<span class="detail-label">' + (update.approval.state === 'approved' ? t('updates.approved') : t('updates.rejected')) + '</span>' +
+                            '<span class="detail-value">' + escapeHtml(update.approval.by) + ', ' + new Date(update.approval.at).toLocaleDateString(LANG) +
+                                (update.approval.comment ? ' (' + escapeHtml(update.approval.comment) + ')' : '') + '</span>' +
+                        '</div>';
+                    }
+                    if (update.deployment) {
+                        approval += '<div class="detail">' +
+                            '<span class="detail-label">' + t('updates.deployed') + '</span>' +
+                            '<span class="detail-value">' + (update.deployment.error ?
+                                'Failed via ' + escapeHtml(update.deployment.provider) + ': ' + escapeHtml(update.deployment.error) :
+                                'via ' + escapeHtml(update.deployment.provider) + ', ' + new Date(update.deployment.at).toLocaleString(LANG)) + '</span>' +
+                            (update.deployment.error ? '<button class="btn ack-btn" onclick="deployUpdate(' + ackArgs + ')">' + t('updates.retry') + '</button>' : '') +
+                        '</div>';
+                    }
 
                     return '<div class="app-card">' +
-                        '<div class="app-name">' + update.track_name + '</div>' +
+                        '<div class="app-name">' + (update.display_name || update.track_name) + '</div>' +
                         versionChange +
                         '<div class="app-details">' +
+                            (update.released_at ?
+                                '<div class="detail">' +
+                                    '<span class="detail-label">' + t('updates.released') + '</span>' +
+                                    '<span class="detail-value">' + new Date(update.released_at).toLocaleString(LANG) + '</span>' +
+                                '</div>' : '') +
                             '<div class="detail">' +
-                                '<span class="detail-label">Updated:</span>' +
-                                '<span class="detail-value">' + new Date(update.updated_at).toLocaleString() + '</span>' +
+                                '<span class="detail-label">Detected:</span>' +
+                                '<span class="detail-value">' + new Date(update.updated_at).toLocaleString(LANG) + '</span>' +
+                                (update.id ? '<a class="permalink" href="#update/' + update.id + '" title="' + t('updates.link') + '">🔗</a>' : '') +
                             '</div>' +
+                            assignment +
+                            acknowledgement +
+                            approval +
                         '</div>' +
                         (releaseNotesToggle ? '<div class="notes-toggle-container">' + releaseNotesToggle + '</div>' : '<div></div>') +
                         releaseNotesContent +
@@ -861,446 +1788,4071 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             }
         }
 
-        // Search functionality
-        let searchTimeout;
-        const searchInput = document.getElementById('searchInput');
-        const searchResults = document.getElementById('searchResults');
+        // Sort updates in place in one of the saved view sort orders
+        function sortUpdates(updates, order) {
+            const detected = (a, b) => new Date(b.updated_at) - new Date(a.updated_at);
+            const severities = ['critical', 'major', 'minor', 'patch'];
+            const severityRank = update => {
+                const rank = severities.indexOf(update.severity);
+                return rank < 0 ? severities.length : rank;
+            };
+            const comparators = {
+                newest: detected,
+                oldest: (a, b) => detected(b, a),
+                app: (a, b) => (a.display_name || a.track_name || '').localeCompare(b.display_name || b.track_name || '') || detected(a, b),
+                severity: (a, b) => severityRank(a) - severityRank(b) || detected(a, b),
+            };
+            updates.sort(comparators[order] || detected);
+        }
 
-        searchInput.addEventListener('input', (e) => {
-            clearTimeout(searchTimeout);
-            const query = e.target.value.trim();
+        // The Recent Updates filters that differ from their defaults, as a
+        // saved view query string
+        function viewQuery() {
+            const params = new URLSearchParams();
+            const period = document.getElementById('updatePeriod').value;
+            if (period !== '7d') params.set('since', period);
+            const severity = document.getElementById('updateSeverity').value;
+            if (severity) params.set('severity', severity);
+            const tag = document.getElementById('updateTag').value;
+            if (tag) params.set('tag', tag);
+            if (document.getElementById('unacknowledgedOnly').checked) params.set('unacknowledged', 'true');
+            if (document.getElementById('myUpdatesOnly').checked) params.set('mine', 'true');
+            if (document.getElementById('pendingApprovalOnly').checked) params.set('approval', 'pending');
+            const order = document.getElementById('updateSort').value;
+            if (order !== 'newest') params.set('sort', order);
+            return params.toString();
+        }
 
-            if (query.length < 2) {
-                searchResults.innerHTML = '';
-                return;
+        // Set the Recent Updates filters from a saved view query string
+        function applyViewQuery(query) {
+            const params = new URLSearchParams(query);
+            document.getElementById('updatePeriod').value = params.get('since') || '7d';
+            if (!document.getElementById('updatePeriod').value) {
+                const period = document.getElementById('updatePeriod');
+                period.add(new Option('Last ' + params.get('since'), params.get('since')));
+                period.value = params.get('since');
+            }
+            document.getElementById('updateSeverity').value = params.get('severity') || '';
+            const tag = params.get('tag') || '';
+            const updateTag = document.getElementById('updateTag');
+            if (tag && ![...updateTag.options].some(option => option.value === tag)) {
+                updateTag.add(new Option(tag, tag));
             }
+            updateTag.value = tag;
+            document.getElementById('unacknowledgedOnly').checked = params.get('unacknowledged') === 'true';
+            document.getElementById('myUpdatesOnly').checked = params.get('mine') === 'true';
+            document.getElementById('pendingApprovalOnly').checked = params.get('approval') === 'pending';
+            document.getElementById('updateSort').value = params.get('sort') || 'newest';
+            loadUpdates();
+        }
 
-            searchTimeout = setTimeout(() => searchApps(query), 500);
-        });
+        // Fill the saved views dropdown with the current user's views, plus
+        // the open one if it is someone else's
+        async function loadSavedViews(openView) {
+            const select = document.getElementById('savedViews');
+            const selected = openView ? openView.id : select.value;
+            const user = localStorage.getItem('mavt-user');
+            let views = [];
+            if (user) {
+                try {
+                    const response = await fetch('/api/views?user=' + encodeURIComponent(user));
+                    if (response.ok) {
+                        views = await response.json() || [];
+                    }
+                } catch (error) {
+                    console.error('Failed to load saved views:', error);
+                }
+            }
+            if (openView && !views.some(view => view.id === openView.id)) {
+                views.push(openView);
+            }
 
-        async function searchApps(query) {
-            try {
-                searchResults.innerHTML = '<div class="loading">Searching...</div>';
-                const response = await fetch('/api/search?q=' + encodeURIComponent(query) + '&limit=10');
-                const apps = await response.json();
+            select.innerHTML = '<option value="">Custom</option>' + views.map(view =>
+                '<option value="' + escapeHtml(view.id) + '"' + (view.id === selected ? ' selected' : '') + '>' +
+                    escapeHtml(view.name) + (user && view.user.toLowerCase() === user.toLowerCase() ? '' : ' (' + escapeHtml(view.user) + ')') +
+                '</option>').join('');
+            document.getElementById('deleteViewBtn').style.display = select.value ? '' : 'none';
+        }
 
-                if (!apps || apps.length === 0) {
-                    searchResults.innerHTML = '<div class="empty-state">No apps found</div>';
-                    return;
-                }
+        // Open a saved view from the dropdown through its #view/{id} link, so
+        // the address can be shared or bookmarked
+        function selectSavedView(id) {
+            if (id) {
+                location.hash = '#view/' + encodeURIComponent(id);
+                return;
+            }
+            if (location.hash.startsWith('#view/')) {
+                history.replaceState(null, '', location.pathname + location.search);
+            }
+            document.getElementById('deleteViewBtn').style.display = 'none';
+        }
 
-                searchResults.innerHTML = apps.map(app => {
-                    let buttonHtml;
-                    if (app.is_tracked) {
-                        buttonHtml = '<button class="btn btn-success" disabled>✓ Tracked</button>';
-                    } else {
-                        buttonHtml = '<button class="btn" onclick="trackApp(\'' + app.bundle_id + '\', this)">Track</button>';
-                    }
+        // Apply the saved view named by a #view/{id} link
+        async function openLinkedView() {
+            if (!location.hash.startsWith('#view/')) {
+                return;
+            }
+            const id = decodeURIComponent(location.hash.slice('#view/'.length));
 
-                    return '<div class="search-result-card">' +
-                        '<div class="search-result-info">' +
-                            '<div class="search-result-name">' + app.track_name + '</div>' +
-                            '<div class="search-result-details">' +
-                                app.artist_name + ' • v' + app.version + ' • ' + app.bundle_id +
-                            '</div>' +
-                        '</div>' +
-                        buttonHtml +
-                    '</div>';
-                }).join('');
+            try {
+                const response = await fetch('/api/views?id=' + encodeURIComponent(id));
+                if (!response.ok) {
+                    throw new Error(response.status === 404 ? 'This view no longer exists' : await errorMessage(response));
+                }
+                const view = await response.json();
+                applyViewQuery(view.query);
+                await loadSavedViews(view);
             } catch (error) {
-                searchResults.innerHTML = '<div class="error">Search failed: ' + error.message + '</div>';
+                alert('Failed to open saved view: ' + error.message);
             }
         }
 
-        async function trackApp(bundleId, button) {
-            const originalText = button.textContent;
-            button.disabled = true;
-            button.textContent = 'Adding...';
+        window.addEventListener('hashchange', openLinkedView);
+
+        // Save the current filters as a named view of the current user
+        async function saveView() {
+            const user = currentUser();
+            if (!user) {
+                return;
+            }
+            const select = document.getElementById('savedViews');
+            const current = select.value ? select.options[select.selectedIndex].text : '';
+            const name = prompt('Name this view, e.g. "Security apps this month":', current);
+            if (!name || !name.trim()) {
+                return;
+            }
 
             try {
-                const response = await fetch('/api/track', {
+                const response = await fetch('/api/views', {
                     method: 'POST',
                     headers: {
                         'Content-Type': 'application/json',
                     },
-                    body: JSON.stringify({ bundle_id: bundleId })
+                    body: JSON.stringify({ user: user, name: name.trim(), query: viewQuery() })
                 });
 
                 if (!response.ok) {
-                    const error = await response.text();
+                    const error = await errorMessage(response);
                     throw new Error(error);
                 }
 
-                button.textContent = '✓ Tracked';
-                button.className = 'btn btn-success';
+                const data = await response.json();
+                await loadSavedViews(data.view);
+                history.replaceState(null, '', location.pathname + location.search + '#view/' + encodeURIComponent(data.view.id));
+            } catch (error) {
+                alert('Failed to save view: ' + error.message);
+            }
+        }
 
-                // Refresh tracked apps list
-                setTimeout(() => {
-                    loadApps();
-                }, 1000);
+        // Delete the selected saved view, keeping its filters applied
+        async function deleteView() {
+            const select = document.getElementById('savedViews');
+            const id = select.value;
+            if (!id || !confirm('Delete the view "' + select.options[select.selectedIndex].text + '"?')) {
+                return;
+            }
 
-                // Re-run search to update the search results with tracking status
-                const currentQuery = searchInput.value.trim();
-                if (currentQuery.length >= 2) {
-                    setTimeout(() => {
-                        searchApps(currentQuery);
-                    }, 1000);
+            try {
+                const response = await fetch('/api/views?id=' + encodeURIComponent(id), { method: 'DELETE' });
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
                 }
+
+                select.value = '';
+                selectSavedView('');
+                await loadSavedViews();
             } catch (error) {
-                alert('Failed to add app: ' + error.message);
-                button.disabled = false;
-                button.textContent = originalText;
+                alert('Failed to delete view: ' + error.message);
             }
         }
 
-        function toggleNotes(notesId, toggleElement) {
-            const notesDiv = document.getElementById(notesId);
-            const currentText = toggleElement.textContent;
+        // Describe an update's version change; re-releases keep their version
+        function versionChangeText(update) {
+            if (update.kind === 're-release') {
+                return update.new_version + ' re-released';
+            }
+            return update.old_version + ' → ' + update.new_version + (update.kind === 'rollback' ? ' (rollback)' : '');
+        }
 
-            if (notesDiv.style.display === 'none') {
-                notesDiv.style.display = 'block';
-                // Replace ▼ with ▲ and update show/hide text
-                if (currentText.includes('Latest Release Notes')) {
-                    toggleElement.textContent = currentText.replace('▼', '▲');
-                } else {
-                    toggleElement.textContent = 'Hide release notes ▲';
-                }
-            } else {
-                notesDiv.style.display = 'none';
-                // Replace ▲ with ▼ and update show/hide text
-                if (currentText.includes('Latest Release Notes')) {
-                    toggleElement.textContent = currentText.replace('▲', '▼');
-                } else {
-                    toggleElement.textContent = 'Show release notes ▼';
+        // Version badges for the history table and linked update
+        function versionBadges(update) {
+            if (update.kind === 're-release') {
+                return '<span class="version-badge">' + update.new_version + '</span>' +
+                    '<span class="version-arrow">re-released</span>';
+            }
+            return '<span class="version-badge">' + update.old_version + '</span>' +
+                '<span class="version-arrow">' + (update.kind === 'rollback' ? '↩ rollback' : '→') + '</span>' +
+                '<span class="version-badge">' + update.new_version + '</span>';
+        }
+
+        // Headers for approving or deploying an update, which needs
+        // MAVT_APPROVAL_TOKEN; the token is asked for once and kept in this
+        // browser. Returns null if none is given.
+        function approvalHeaders() {
+            let token = localStorage.getItem('mavt-approval-token');
+            if (!token) {
+                token = (prompt(t('approval.tokenPrompt')) || '').trim();
+                if (!token) {
+                    return null;
                 }
+                localStorage.setItem('mavt-approval-token', token);
             }
+            return {
+                'Content-Type': 'application/json',
+                'Authorization': 'Bearer ' + token,
+            };
         }
 
-        // Store current bundle ID for removal
-        let currentBundleId = null;
+        // Forgets a rejected approval token, so the next attempt asks again
+        function checkApprovalToken(response) {
+            if (response.status === 401) {
+                localStorage.removeItem('mavt-approval-token');
+            }
+        }
 
-        // Version History Modal Functions
-        async function showVersionHistory(bundleId, appName, developer) {
-            const modal = document.getElementById('historyModal');
-            const modalAppName = document.getElementById('modalAppName');
-            const modalAppDetails = document.getElementById('modalAppDetails');
-            const historyContainer = document.getElementById('historyTableContainer');
+        // The current user's name for acknowledgements and assignments, asked for once
+        function currentUser() {
+            let name = localStorage.getItem('mavt-user');
+            if (!name) {
+                name = (prompt('Your name, recorded with acknowledgements and assignments:') || '').trim();
+                if (name) {
+                    localStorage.setItem('mavt-user', name);
+                }
+            }
+            return name;
+        }
 
-            // Store bundle ID for removal
-            currentBundleId = bundleId;
+        // Acknowledge an update as the current user
+        async function acknowledgeUpdate(bundleId, version, acknowledge) {
+            const by = acknowledge ? currentUser() : localStorage.getItem('mavt-user');
+            if (acknowledge && !by) {
+                return;
+            }
 
-            // Set modal header info
-            modalAppName.textContent = appName;
-            modalAppDetails.innerHTML = '<div class="modal-subtitle">' +
-                '<div class="modal-subtitle-item">' +
-                    '<span class="modal-subtitle-label">Developer:</span>' +
-                    '<span>' + developer + '</span>' +
-                '</div>' +
-                '<div class="modal-subtitle-item">' +
-                    '<span class="modal-subtitle-label">Bundle ID:</span>' +
-                    '<span>' + bundleId + '</span>' +
-                '</div>' +
-            '</div>';
+            try {
+                const response = await fetch('/api/acknowledge', {
+                    method: acknowledge ? 'POST' : 'DELETE',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({ bundle_id: bundleId, version: version, by: by })
+                });
 
-            // Show modal
-            modal.style.display = 'block';
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
 
-            // Load version history
-            historyContainer.innerHTML = '<div class="loading-history">Loading version history...</div>';
+                await loadUpdates();
+            } catch (error) {
+                alert('Failed to update acknowledgement: ' + error.message);
+            }
+        }
+
+        // Assign an update to someone; an empty name unassigns it
+        async function assignUpdate(bundleId, version, current) {
+            const assignee = prompt('Assign this update to (leave empty to unassign):', current || localStorage.getItem('mavt-user') || '');
+            if (assignee === null) {
+                return;
+            }
 
             try {
-                const response = await fetch('/api/history?bundle_id=' + encodeURIComponent(bundleId));
+                const response = await fetch('/api/assign', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({ bundle_id: bundleId, version: version, assignee: assignee.trim(), by: localStorage.getItem('mavt-user') || '' })
+                });
+
                 if (!response.ok) {
-                    throw new Error('Failed to load version history');
+                    const error = await errorMessage(response);
+                    throw new Error(error);
                 }
 
-                const history = await response.json();
+                await loadUpdates();
+            } catch (error) {
+                alert('Failed to assign update: ' + error.message);
+            }
+        }
 
-                if (!history || history.length === 0) {
-                    historyContainer.innerHTML = '<div class="empty-history">No version history available yet. Updates will appear here when the app version changes.</div>';
-                    return;
-                }
-
-                // Build table
-                let tableHtml = '<table class="history-table">' +
-                    '<thead>' +
-                        '<tr>' +
-                            '<th>Date</th>' +
-                            '<th>Version Change</th>' +
-                            '<th>Release Notes</th>' +
-                        '</tr>' +
-                    '</thead>' +
-                    '<tbody>';
-
-                history.forEach(update => {
-                    const dateStr = new Date(update.updated_at).toLocaleString();
-                    const notesText = update.release_notes && update.release_notes.trim()
-                        ? update.release_notes
-                        : 'No release notes available';
+        // Push an approved update to the MDM again, e.g. after a failed push
+        async function deployUpdate(bundleId, version) {
+            const headers = approvalHeaders();
+            if (!headers) {
+                return;
+            }
 
-                    tableHtml += '<tr>' +
-                        '<td>' + dateStr + '</td>' +
-                        '<td>' +
-                            '<span class="version-badge">' + update.old_version + '</span>' +
-                            '<span class="version-arrow">→</span>' +
-                            '<span class="version-badge">' + update.new_version + '</span>' +
-                        '</td>' +
-                        '<td><div class="history-notes">' + notesText + '</div></td>' +
-                    '</tr>';
+            try {
+                const response = await fetch('/api/deploy', {
+                    method: 'POST',
+                    headers: headers,
+                    body: JSON.stringify({ bundle_id: bundleId, version: version })
                 });
 
-                tableHtml += '</tbody></table>';
-                historyContainer.innerHTML = tableHtml;
+                if (!response.ok) {
+                    checkApprovalToken(response);
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
 
+                const deployment = await response.json();
+                if (deployment.error) {
+                    alert('Deployment failed again: ' + deployment.error);
+                }
+                await loadUpdates();
             } catch (error) {
-                historyContainer.innerHTML = '<div class="error">Failed to load version history: ' + error.message + '</div>';
+                alert('Failed to deploy update: ' + error.message);
             }
         }
 
-        function closeHistoryModal() {
-            document.getElementById('historyModal').style.display = 'none';
-            currentBundleId = null;
-        }
-
-        async function removeAppFromHistory() {
-            if (!currentBundleId) {
-                alert('No app selected for removal');
+        // Approve or reject an update pending approval as the current user
+        async function decideUpdate(bundleId, version, state) {
+            const by = currentUser();
+            if (!by) {
                 return;
             }
-
-            const confirmed = confirm('Are you sure you want to remove this app from tracking? This will delete all version history.');
-            if (!confirmed) {
+            const comment = prompt(state === 'approved' ? 'Approve this update? Optional comment:' : 'Reject this update? Reason:', '');
+            if (comment === null) {
                 return;
             }
 
-            const removeBtn = document.getElementById('removeAppBtn');
-            const originalText = removeBtn.textContent;
-            removeBtn.disabled = true;
-            removeBtn.textContent = 'Removing...';
+            const headers = approvalHeaders();
+            if (!headers) {
+                return;
+            }
 
             try {
-                const response = await fetch('/api/track', {
-                    method: 'DELETE',
-                    headers: {
-                        'Content-Type': 'application/json',
-                    },
-                    body: JSON.stringify({ bundle_id: currentBundleId })
+                const response = await fetch('/api/approval', {
+                    method: 'POST',
+                    headers: headers,
+                    body: JSON.stringify({ bundle_id: bundleId, version: version, state: state, by: by, comment: comment.trim() })
                 });
 
                 if (!response.ok) {
-                    const error = await response.text();
+                    checkApprovalToken(response);
+                    const error = await errorMessage(response);
                     throw new Error(error);
                 }
 
-                // Close modal
-                closeHistoryModal();
+                await loadUpdates();
+            } catch (error) {
+                alert('Failed to update approval: ' + error.message);
+            }
+        }
 
-                // Refresh the apps list
-                await loadApps();
+        async function loadCompliance() {
+            const section = document.getElementById('complianceSection');
+            const container = document.getElementById('compliance');
 
-                // Show success message (could be improved with a toast notification)
-                alert('App successfully removed from tracking');
+            try {
+                const response = await fetch('/api/compliance');
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+
+                const data = await response.json();
+                if (!data.enabled) {
+                    section.style.display = 'none';
+                    return;
+                }
+
+                section.style.display = 'block';
+
+                if (!data.apps || data.apps.length === 0) {
+                    container.innerHTML = '<div class="empty-state">No tracked apps are installed on managed devices</div>';
+                    return;
+                }
 
+                container.innerHTML = '<table class="history-table">' +
+                    '<thead>' +
+                        '<tr>' +
+                            '<th>App</th>' +
+                            '<th>Latest</th>' +
+                            '<th>Devices Behind</th>' +
+                            '<th>Out-of-date Devices</th>' +
+                        '</tr>' +
+                    '</thead>' +
+                    '<tbody>' +
+                    data.apps.map(entry => {
+                        const behind = entry.devices_behind || [];
+                        return '<tr>' +
+                            '<td>' + entry.track_name + '</td>' +
+                            '<td><span class="version-badge">' + entry.latest_version + '</span></td>' +
+                            '<td>' + behind.length + ' / ' + entry.total_devices + '</td>' +
+                            '<td>' + behind.map(d => d.device_name + ' (' + d.installed_version + ')').join(', ') + '</td>' +
+                        '</tr>';
+                    }).join('') +
+                    '</tbody></table>';
             } catch (error) {
-                alert('Failed to remove app: ' + error.message);
-                removeBtn.disabled = false;
-                removeBtn.textContent = originalText;
+                section.style.display = 'block';
+                container.innerHTML = '<div class="error">Failed to load compliance report: ' + error.message + '</div>';
             }
         }
 
-        // Close modal when clicking outside of it
-        window.onclick = function(event) {
-            const modal = document.getElementById('historyModal');
-            if (event.target === modal) {
-                closeHistoryModal();
-            }
-        }
+        async function loadCompatibility() {
+            const section = document.getElementById('compatibilitySection');
+            const container = document.getElementById('compatibility');
 
-        // Store the last sync timestamp
-        let lastSyncTime = null;
+            try {
+                const response = await fetch('/api/compatibility');
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
 
-        // Update last synced timestamp display
-        function updateLastSyncedDisplay() {
-            if (!lastSyncTime) {
-                document.getElementById('lastSyncedTime').textContent = 'Loading...';
-                return;
-            }
+                const data = await response.json();
+                if (!data.enabled) {
+                    section.style.display = 'none';
+                    return;
+                }
 
-            const now = Date.now();
-            const diffMs = now - lastSyncTime;
-            const diffSecs = Math.floor(diffMs / 1000);
-            const diffMins = Math.floor(diffSecs / 60);
+                section.style.display = 'block';
+                const profile = [];
+                if (data.profile.min_os_version) {
+                    profile.push('oldest OS ' + escapeHtml(data.profile.min_os_version));
+                }
+                if (data.profile.devices && data.profile.devices.length) {
+                    profile.push('devices ' + escapeHtml(data.profile.devices.join(', ')));
+                }
+                document.getElementById('fleetProfile').innerHTML = 'Fleet: ' + profile.join('; ');
 
-            let timeStr;
-            if (diffSecs < 10) {
-                timeStr = 'Just now';
-            } else if (diffSecs < 60) {
-                timeStr = diffSecs + 's ago';
-            } else if (diffMins < 60) {
-                timeStr = diffMins + (diffMins === 1 ? ' min ago' : ' mins ago');
-            } else {
-                const diffHours = Math.floor(diffMins / 60);
-                timeStr = diffHours + (diffHours === 1 ? ' hour ago' : ' hours ago');
-            }
+                if (data.risks.length === 0) {
+                    container.innerHTML = '<div class="empty-state">All tracked apps run on every device in the fleet</div>';
+                    return;
+                }
 
-            document.getElementById('lastSyncedTime').textContent = timeStr;
+                container.innerHTML = '<table class="history-table">' +
+                    '<thead>' +
+                        '<tr>' +
+                            '<th>App</th>' +
+                            '<th>Version</th>' +
+                            '<th>Risk</th>' +
+                        '</tr>' +
+                    '</thead>' +
+                    '<tbody>' +
+                    data.risks.map(risk => '<tr>' +
+                        '<td>' + escapeHtml(risk.app_name) + '</td>' +
+                        '<td><span class="version-badge">' + escapeHtml(risk.version) + '</span></td>' +
+                        '<td>' + escapeHtml(risk.summary) + '</td>' +
+                    '</tr>').join('') +
+                    '</tbody></table>';
+            } catch (error) {
+                section.style.display = 'block';
+                container.innerHTML = '<div class="error">Failed to load compatibility risks: ' + error.message + '</div>';
+            }
         }
 
-        // Store the last known update timestamp
-        let lastKnownUpdate = null;
-
-        // Load all data and update sync time
-        async function refreshData() {
-            await Promise.all([loadApps(), loadUpdates()]);
-            lastSyncTime = Date.now();
-            updateLastSyncedDisplay();
-        }
+        async function loadWebhookDeliveries() {
+            const section = document.getElementById('webhooksSection');
+            const container = document.getElementById('webhookDeliveries');
 
-        // Check for new updates and refresh if found
-        async function checkForNewUpdates() {
             try {
-                const response = await fetch('/api/last-update');
+                const response = await fetch('/api/webhooks/deliveries');
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+
                 const data = await response.json();
+                if (!data.enabled) {
+                    section.style.display = 'none';
+                    return;
+                }
 
-                if (data.has_updates) {
-                    const currentUpdate = new Date(data.last_update).getTime();
+                section.style.display = 'block';
 
-                    // If we have a previous timestamp and it's different, refresh
-                    if (lastKnownUpdate !== null && currentUpdate > lastKnownUpdate) {
-                        console.log('New update detected, refreshing data...');
-                        await refreshData();
-                    }
+                if (!data.deliveries || data.deliveries.length === 0) {
+                    container.innerHTML = '<div class="empty-state">No webhook deliveries yet</div>';
+                    return;
+                }
 
-                    // Update the last known timestamp
-                    lastKnownUpdate = currentUpdate;
+                container.innerHTML = '<table class="history-table">' +
+                    '<thead>' +
+                        '<tr>' +
+                            '<th>Delivered</th>' +
+                            '<th>Status</th>' +
+                            '<th>Duration</th>' +
+                            '<th>Response</th>' +
+                            '<th></th>' +
+                        '</tr>' +
+                    '</thead>' +
+                    '<tbody>' +
+                    data.deliveries.map(delivery => {
+                        const ok = !delivery.error && delivery.status_code >= 200 && delivery.status_code < 300;
+                        const status = delivery.status_code ? String(delivery.status_code) : 'No response';
+                        return '<tr>' +
+                            '<td>' + new Date(delivery.delivered_at).toLocaleString(LANG) +
+                                (delivery.redelivery_of ? ' <span class="version-badge">redelivery</span>' : '') + '</td>' +
+                            '<td class="' + (ok ? 'delivery-ok' : 'delivery-failed') + '">' + (ok ? '✓ ' : '✗ ') + status + '</td>' +
+                            '<td>' + delivery.duration_ms + ' ms</td>' +
+                            '<td><div class="history-notes">' + escapeHtml(delivery.error || delivery.response || '') + '</div></td>' +
+                            '<td><button class="btn ack-btn" onclick="redeliverWebhook(\'' + jsString(delivery.id) + '\', this)">Redeliver</button></td>' +
+                        '</tr>';
+                    }).join('') +
+                    '</tbody></table>';
+            } catch (error) {
+                section.style.display = 'block';
+                container.innerHTML = '<div class="error">Failed to load webhook deliveries: ' + error.message + '</div>';
+            }
+        }
+
+        async function redeliverWebhook(id, button) {
+            button.disabled = true;
+            button.textContent = 'Sending...';
+            try {
+                const response = await fetch('/api/webhooks/' + encodeURIComponent(id) + '/redeliver', { method: 'POST' });
+                if (!response.ok && response.status !== 502) {
+                    throw new Error(await errorMessage(response));
                 }
             } catch (error) {
-                console.error('Failed to check for updates:', error);
+                alert('Failed to redeliver webhook: ' + error.message);
             }
+            await loadWebhookDeliveries();
         }
 
-        // Initialize last known update on page load
-        async function initializeUpdateTracking() {
+        // Search functionality
+        let searchTimeout;
+        const searchInput = document.getElementById('searchInput');
+        const searchResults = document.getElementById('searchResults');
+        const searchSource = document.getElementById('searchSource');
+        const searchSourceNames = { appstore: 'App Store', npm: 'npm', pypi: 'PyPI' };
+        const searchPlaceholders = {
+            appstore: "Search App Store (e.g., 'Instagram', 'WhatsApp')...",
+            npm: "Search npm (e.g., 'react', '@babel/core')...",
+            pypi: "Look up a PyPI package by name (e.g., 'requests')...",
+            all: 'Search App Store, npm and PyPI...'
+        };
+
+        searchSource.addEventListener('change', () => {
+            searchInput.placeholder = searchPlaceholders[searchSource.value];
+            const query = searchInput.value.trim();
+            if (query.length >= 2) {
+                searchApps(query);
+            }
+        });
+
+        searchInput.addEventListener('input', (e) => {
+            clearTimeout(searchTimeout);
+            const query = e.target.value.trim();
+
+            if (query.length < 2) {
+                searchResults.innerHTML = '';
+                return;
+            }
+
+            searchTimeout = setTimeout(() => searchApps(query), 500);
+        });
+
+        async function searchApps(query) {
             try {
-                const response = await fetch('/api/last-update');
-                const data = await response.json();
-                if (data.has_updates) {
-                    lastKnownUpdate = new Date(data.last_update).getTime();
+                searchResults.innerHTML = '<div class="loading">Searching...</div>';
+                const response = await fetch('/api/search?q=' + encodeURIComponent(query) + '&limit=10&source=' + searchSource.value);
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+                const apps = await response.json();
+
+                if (!apps || apps.length === 0) {
+                    searchResults.innerHTML = '<div class="empty-state">No apps found</div>';
+                    return;
                 }
+
+                searchResults.innerHTML = apps.map(app => {
+                    let buttonHtml;
+                    if (app.is_tracked) {
+                        buttonHtml = '<button class="btn btn-success" disabled>✓ Tracked</button>';
+                    } else if (app.source !== 'appstore') {
+                        buttonHtml = '<button class="btn" onclick="trackPackage(\'' + app.source + '\', \'' + app.track_name + '\', this)">Track</button>';
+                    } else {
+                        buttonHtml = '<button class="btn" onclick="trackApp(\'' + app.bundle_id + '\', ' + Number(app.track_id) + ', this)">Track</button>';
+                    }
+
+                    const details = [app.artist_name, app.version ? 'v' + app.version : '', app.source === 'appstore' ? app.bundle_id : app.release_notes]
+                        .filter(part => part).map(escapeHtml).join(' • ');
+                    // Price, rating and genre tell look-alike apps apart
+                    let storeDetails = '';
+                    if (app.source === 'appstore') {
+                        const rating = app.rating_count ?
+                            t('search.rating', app.average_rating.toFixed(1), app.rating_count.toLocaleString(LANG)) : t('search.noRatings');
+                        storeDetails = '<div class="search-result-details">' +
+                            [formatPrice(app), rating, app.genre].filter(part => part).map(escapeHtml).join(' • ') + '</div>';
+                    }
+                    const icon = app.icon_url ?
+                        '<img class="search-result-icon" src="' + escapeHtml(app.icon_url) + '" alt="" loading="lazy">' : '';
+                    return '<div class="search-result-card">' + icon +
+                        '<div class="search-result-info">' +
+                            '<div class="search-result-name">' + escapeHtml(app.track_name) +
+                                ' <span class="version-badge">' + (searchSourceNames[app.source] || escapeHtml(app.source)) + '</span></div>' +
+                            '<div class="search-result-details">' + details + '</div>' +
+                            storeDetails +
+                        '</div>' +
+                        buttonHtml +
+                    '</div>';
+                }).join('');
             } catch (error) {
-                console.error('Failed to initialize update tracking:', error);
+                searchResults.innerHTML = '<div class="error">Search failed: ' + error.message + '</div>';
             }
         }
 
-        // Load data on page load
-        async function initialize() {
-            await refreshData();
-            await initializeUpdateTracking();
+        async function trackApp(bundleId, trackId, button) {
+            await addTracking({ bundle_id: bundleId, track_id: trackId }, button);
         }
 
-        initialize();
+        async function trackPackage(source, name, button) {
+            await addTracking({ source: source, name: name }, button);
+        }
 
-        // Get check interval from server (in milliseconds)
-        const checkIntervalMs = %d;
+        async function addTracking(request, button) {
+            const originalText = button.textContent;
+            button.disabled = true;
+            button.textContent = 'Adding...';
 
-        // Poll for new updates every 30 seconds
-        setInterval(() => {
-            checkForNewUpdates();
-        }, 30000);
+            try {
+                const response = await fetch('/api/track', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify(request)
+                });
 
-        // Refresh at the same interval as the backend checks
-        setInterval(() => {
-            refreshData();
-        }, checkIntervalMs);
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
 
-        // Update the relative time display every second
-        setInterval(() => {
-            updateLastSyncedDisplay();
-        }, 1000);
+                button.textContent = '✓ Tracked';
+                button.className = 'btn btn-success';
 
-        // Dark mode functionality
-        const themeToggle = document.getElementById('themeToggle');
-        const htmlElement = document.documentElement;
-        const THEME_KEY = 'mavt-theme';
+                // Refresh tracked apps list
+                setTimeout(() => {
+                    loadApps();
+                }, 1000);
 
-        // Check for saved theme preference or default to light mode
-        function getSavedTheme() {
-            return localStorage.getItem(THEME_KEY) || 'light';
+                // Re-run search to update the search results with tracking status
+                const currentQuery = searchInput.value.trim();
+                if (currentQuery.length >= 2) {
+                    setTimeout(() => {
+                        searchApps(currentQuery);
+                    }, 1000);
+                }
+            } catch (error) {
+                alert('Failed to add app: ' + error.message);
+                button.disabled = false;
+                button.textContent = originalText;
+            }
         }
 
-        // Apply theme to the document
-        function applyTheme(theme) {
-            if (theme === 'dark') {
-                htmlElement.setAttribute('data-theme', 'dark');
-                themeToggle.textContent = '☀️';
+        async function importCSV(dryRun) {
+            const fileInput = document.getElementById('importFile');
+            const importBtn = document.getElementById('importBtn');
+            const container = document.getElementById('importResults');
+
+            if (!fileInput.files.length) {
+                alert('Choose a CSV file first');
+                return;
+            }
+
+            const formData = new FormData();
+            formData.append('file', fileInput.files[0]);
+            container.innerHTML = '<div class="loading">' + (dryRun ? 'Resolving apps...' : 'Importing apps...') + '</div>';
+
+            try {
+                const response = await fetch('/api/import?dry_run=' + dryRun, {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+
+                const data = await response.json();
+                const results = data.results || [];
+
+                container.innerHTML = '<table class="history-table">' +
+                    '<thead><tr><th>Row</th><th>Input</th><th>App Store Match</th><th>Status</th></tr></thead>' +
+                    '<tbody>' +
+                    results.map(result => '<tr>' +
+                        '<td>' + result.row + '</td>' +
+                        '<td>' + (result.bundle_id || result.name) + '</td>' +
+                        '<td>' + (result.resolved_name ? result.resolved_name + ' (' + result.resolved_bundle_id + ') v' + result.version : (result.error || '')) + '</td>' +
+                        '<td>' + result.status.replace('_', ' ') + '</td>' +
+                    '</tr>').join('') +
+                    '</tbody></table>';
+
+                // Only allow importing after a successful preview
+                importBtn.disabled = !dryRun;
+                if (!dryRun) {
+                    loadApps();
+                }
+            } catch (error) {
+                container.innerHTML = '<div class="error">Import failed: ' + error.message + '</div>';
+            }
+        }
+
+        // Require a fresh preview whenever a different file is chosen
+        document.getElementById('importFile').addEventListener('change', () => {
+            document.getElementById('importBtn').disabled = true;
+            document.getElementById('importResults').innerHTML = '';
+        });
+
+        function toggleNotes(notesId, toggleElement) {
+            const notesDiv = document.getElementById(notesId);
+            const currentText = toggleElement.textContent;
+
+            if (notesDiv.style.display === 'none') {
+                notesDiv.style.display = 'block';
+                // Replace ▼ with ▲ and update show/hide text
+                if (currentText.includes('Latest Release Notes')) {
+                    toggleElement.textContent = currentText.replace('▼', '▲');
+                } else {
+                    toggleElement.textContent = 'Hide release notes ▲';
+                }
             } else {
-                htmlElement.removeAttribute('data-theme');
-                themeToggle.textContent = '🌙';
+                notesDiv.style.display = 'none';
+                // Replace ▲ with ▼ and update show/hide text
+                if (currentText.includes('Latest Release Notes')) {
+                    toggleElement.textContent = currentText.replace('▲', '▼');
+                } else {
+                    toggleElement.textContent = 'Show release notes ▼';
+                }
+            }
+        }
+
+        // Store current bundle ID for removal
+        let currentBundleId = null;
+
+        // Version History Modal Functions
+        async function showVersionHistory(bundleId, appName, developer) {
+            const modal = document.getElementById('historyModal');
+            const modalAppName = document.getElementById('modalAppName');
+            const modalAppDetails = document.getElementById('modalAppDetails');
+            const historyContainer = document.getElementById('historyTableContainer');
+
+            // Store bundle ID for removal
+            currentBundleId = bundleId;
+            document.getElementById('linkedUpdate').style.display = 'none';
+
+            // Set modal header info
+            modalAppName.textContent = appName;
+            modalAppDetails.innerHTML = '<div class="modal-subtitle">' +
+                '<div class="modal-subtitle-item">' +
+                    '<span class="modal-subtitle-label">Developer:</span>' +
+                    '<span>' + developer + '</span>' +
+                '</div>' +
+                '<div class="modal-subtitle-item">' +
+                    '<span class="modal-subtitle-label">Bundle ID:</span>' +
+                    '<span>' + bundleId + '</span>' +
+                '</div>' +
+                appMetadataHtml(appsByBundleId[bundleId]) +
+            '</div>';
+
+            const app = appsByBundleId[bundleId] || {};
+            document.getElementById('labelDisplayName').value = app.display_name || '';
+            document.getElementById('labelNotes').value = app.notes || '';
+            document.getElementById('testFlightURL').value = app.testflight_url || '';
+            loadVendor(app.artist_name || developer);
+            loadApplication(bundleId);
+
+            // Show modal, moving focus into it and remembering where it was
+            if (modal.style.display !== 'block') {
+                modalReturnFocus = document.activeElement;
+            }
+            modal.style.display = 'block';
+            document.getElementById('closeHistoryBtn').focus();
+
+            loadReviewSummary(bundleId);
+            loadSizeChart(bundleId);
+            loadAppActivity(bundleId);
+            loadSnapshotChanges(bundleId);
+
+            // Load version history, newest first
+            historyContainer.innerHTML = '<div class="loading-history">Loading version history...</div>';
+            await loadHistoryPage(bundleId, 0);
+        }
+
+        // Number of history entries loaded into the modal at a time
+        const historyPageSize = 25;
+
+        // Load one page of an app's version history into the modal, starting at
+        // offset, and offer a button to load the next page
+        async function loadHistoryPage(bundleId, offset) {
+            const historyContainer = document.getElementById('historyTableContainer');
+            const moreBtn = document.getElementById('historyMoreBtn');
+            if (moreBtn) {
+                moreBtn.disabled = true;
+                moreBtn.textContent = 'Loading...';
+            }
+
+            try {
+                const response = await fetch('/api/history?bundle_id=' + encodeURIComponent(bundleId) +
+                    '&limit=' + historyPageSize + '&offset=' + offset);
+                if (!response.ok) {
+                    throw new Error('Failed to load version history');
+                }
+
+                const history = await response.json();
+                const total = parseInt(response.headers.get('X-Total-Count'), 10) || 0;
+                if (bundleId !== currentBundleId) {
+                    return;
+                }
+
+                if (offset === 0 && (!history || history.length === 0)) {
+                    historyContainer.innerHTML = '<div class="empty-history">No version history available yet. Updates will appear here when the app version changes.</div>';
+                    return;
+                }
+
+                if (offset === 0) {
+                    const cadence = response.headers.get('X-Release-Cadence-Days');
+                    historyContainer.innerHTML = (cadence
+                        ? '<div class="history-cadence">Releases a new version every ' + cadence + ' days on average</div>'
+                        : '') +
+                        '<table class="history-table">' +
+                        '<thead>' +
+                            '<tr>' +
+                                '<th>Released</th>' +
+                                '<th>Detected</th>' +
+                                '<th>Version Change</th>' +
+                                '<th>Release Notes</th>' +
+                            '</tr>' +
+                        '</thead>' +
+                        '<tbody id="historyTableBody"></tbody></table>' +
+                        '<div class="history-more"></div>';
+                }
+
+                let rowsHtml = '';
+                history.forEach(update => {
+                    const dateStr = new Date(update.updated_at).toLocaleString(LANG);
+                    const releasedStr = update.released_at ? new Date(update.released_at).toLocaleDateString(LANG) : '—';
+
+                    rowsHtml += '<tr>' +
+                        '<td>' + releasedStr + '</td>' +
+                        '<td>' + dateStr + '</td>' +
+                        '<td>' +
+                            versionBadges(update) +
+                        '</td>' +
+                        '<td><div class="history-notes">' + releaseNotesHtml(update, 'No release notes available') + '</div>' + deviceChangesHtml(update) + '</td>' +
+                    '</tr>';
+                });
+                document.getElementById('historyTableBody').insertAdjacentHTML('beforeend', rowsHtml);
+
+                const loaded = offset + history.length;
+                const more = historyContainer.querySelector('.history-more');
+                more.innerHTML = loaded < total
+                    ? '<button class="btn ack-btn" id="historyMoreBtn" onclick="loadHistoryPage(\'' + jsString(bundleId) + '\', ' + loaded + ')">' +
+                        'Load older versions (' + (total - loaded) + ' more)</button>'
+                    : '';
+
+            } catch (error) {
+                if (offset === 0) {
+                    historyContainer.innerHTML = '<div class="error">Failed to load version history: ' + error.message + '</div>';
+                } else if (moreBtn) {
+                    moreBtn.disabled = false;
+                    moreBtn.textContent = 'Retry loading older versions';
+                }
+            }
+        }
+
+        // List the changes between the app's metadata snapshots, newest first,
+        // field by field
+        async function loadSnapshotChanges(bundleId) {
+            const section = document.getElementById('snapshotChanges');
+            section.style.display = 'none';
+            section.open = false;
+
+            try {
+                const response = await fetch('/api/app/' + encodeURIComponent(bundleId) + '/snapshots');
+                if (!response.ok) {
+                    return;
+                }
+
+                const data = await response.json();
+                const changed = (data.snapshots || []).filter(snapshot => snapshot.changes && snapshot.changes.length);
+                if (!changed.length || bundleId !== currentBundleId) {
+                    return;
+                }
+
+                document.getElementById('snapshotSummary').textContent =
+                    'Listing changes (' + changed.length + ' snapshot' + (changed.length === 1 ? '' : 's') + ')';
+                let rowsHtml = '';
+                changed.forEach(snapshot => {
+                    snapshot.changes.forEach((change, i) => {
+                        rowsHtml += '<tr>' +
+                            '<td>' + (i === 0 ? new Date(snapshot.taken_at).toLocaleString(LANG) : '') + '</td>' +
+                            '<td>' + escapeHtml(change.field) + '</td>' +
+                            '<td><div class="snapshot-value">' + snapshotValueHtml(change.old) + '</div></td>' +
+                            '<td><div class="snapshot-value">' + snapshotValueHtml(change.new) + '</div></td>' +
+                        '</tr>';
+                    });
+                });
+                document.getElementById('snapshotList').innerHTML = '<table class="history-table">' +
+                    '<thead><tr><th>Seen</th><th>Field</th><th>Before</th><th>After</th></tr></thead>' +
+                    '<tbody>' + rowsHtml + '</tbody></table>';
+                section.style.display = 'block';
+            } catch (error) {
+                console.error('Failed to load snapshots:', error);
+            }
+        }
+
+        // Format a snapshot field value for the changes table
+        function snapshotValueHtml(value) {
+            if (value === null || value === undefined || value === '') {
+                return '—';
+            }
+            return escapeHtml(typeof value === 'string' ? value : JSON.stringify(value, null, 1));
+        }
+
+        // Release notes HTML for an update. With a stored translation, the
+        // translation is shown with a link to switch to the original.
+        function releaseNotesHtml(update, fallback) {
+            const notes = update.release_notes && update.release_notes.trim() ? update.release_notes : fallback;
+            if (!update.translated_notes) {
+                return escapeHtml(notes);
+            }
+            return '<span class="notes-translated">' + escapeHtml(update.translated_notes) + '</span>' +
+                '<span class="notes-original" style="display:none;">' + escapeHtml(notes) + '</span>' +
+                '<div class="notes-translation"><a href="#" onclick="return toggleTranslation(event, this)">' +
+                    'Show original</a></div>';
+        }
+
+        // Switch release notes between their translation and the original
+        function toggleTranslation(event, link) {
+            event.stopPropagation();
+            const notes = link.parentElement.parentElement;
+            const original = notes.querySelector('.notes-original');
+            const showOriginal = original.style.display === 'none';
+            original.style.display = showOriginal ? '' : 'none';
+            notes.querySelector('.notes-translated').style.display = showOriginal ? 'none' : '';
+            link.textContent = showOriginal ? 'Show translation' : 'Show original';
+            return false;
+        }
+
+        // Describe the device models a version update added or dropped
+        function deviceChangesHtml(update) {
+            const parts = [];
+            if (update.devices_removed && update.devices_removed.length) {
+                parts.push('Dropped support: ' + escapeHtml(update.devices_removed.join(', ')));
+            }
+            if (update.devices_added && update.devices_added.length) {
+                parts.push('Added support: ' + escapeHtml(update.devices_added.join(', ')));
+            }
+            return parts.length ? '<div class="history-devices">' + parts.join('<br>') + '</div>' : '';
+        }
+
+        // Build the extended metadata rows shown in the detail modal
+        function appMetadataHtml(app) {
+            if (!app) {
+                return '';
+            }
+
+            const item = (label, value) => value ?
+                '<div class="modal-subtitle-item">' +
+                    '<span class="modal-subtitle-label">' + label + ':</span>' +
+                    '<span>' + value + '</span>' +
+                '</div>' : '';
+
+            const rating = app.rating ?
+                app.rating.average_rating.toFixed(1) + '★ from ' + app.rating.rating_count.toLocaleString(LANG) + ' ratings' : '';
+            const purchases = (app.in_app_purchases || [])
+                .map(p => escapeHtml(p.name) + ' (' + escapeHtml(p.price) + ')').join(', ');
+
+            return item('Genre', app.genre) +
+                item('App Store Rating', rating) +
+                item('Storefront', app.storefront) +
+                item('Content Rating', app.content_rating) +
+                item('Minimum OS', app.min_os_version) +
+                item('Download Size', app.file_size_bytes ? formatBytes(app.file_size_bytes) : '') +
+                item('Languages', (app.language_codes || []).join(', ')) +
+                item('Supported Devices', (app.supported_devices || []).join(', ')) +
+                item('In-App Purchases', purchases) +
+                item('TestFlight Beta', betaText(app));
+        }
+
+        // Describe an app's TestFlight beta, e.g. "3.2 (45), open: ahead of
+        // store 3.1 by 5 days"
+        function betaText(app) {
+            const beta = app.beta;
+            if (!beta) {
+                return '';
+            }
+
+            const states = { open: 'open', full: 'full', closed: 'not accepting testers' };
+            let text = (beta.version ? escapeHtml(beta.version) + ' (' + escapeHtml(beta.build) + '), ' : '') + (states[beta.state] || escapeHtml(beta.state));
+            if (beta.version && compareVersions(beta.version, app.version) > 0) {
+                const days = Math.floor((Date.now() - new Date(beta.first_seen)) / 86400000);
+                text += ': ahead of store ' + escapeHtml(app.version) + ' by ' +
+                    (days >= 1 ? days + ' day' + (days === 1 ? '' : 's') : 'less than a day');
+            }
+            return text;
+        }
+
+        // Compare dotted version strings numerically
+        function compareVersions(a, b) {
+            const pa = String(a).split('.').map(n => parseInt(n, 10) || 0);
+            const pb = String(b).split('.').map(n => parseInt(n, 10) || 0);
+            for (let i = 0; i < Math.max(pa.length, pb.length); i++) {
+                const diff = (pa[i] || 0) - (pb[i] || 0);
+                if (diff !== 0) {
+                    return diff;
+                }
+            }
+            return 0;
+        }
+
+        async function saveTestFlightURL() {
+            if (!currentBundleId) {
+                return;
+            }
+
+            const saveBtn = document.getElementById('saveTestFlightBtn');
+            saveBtn.disabled = true;
+
+            try {
+                const response = await fetch('/api/testflight', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({
+                        bundle_id: currentBundleId,
+                        url: document.getElementById('testFlightURL').value.trim()
+                    })
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+
+                await loadApps();
+            } catch (error) {
+                alert('Failed to save TestFlight link: ' + error.message);
+            } finally {
+                saveBtn.disabled = false;
+            }
+        }
+
+        async function loadReviewSummary(bundleId) {
+            try {
+                const response = await fetch('/api/reviews?bundle_id=' + encodeURIComponent(bundleId));
+                if (!response.ok) {
+                    return;
+                }
+
+                const data = await response.json();
+                if (!data.summary || data.summary.total_reviews === 0 || bundleId !== currentBundleId) {
+                    return;
+                }
+
+                document.querySelector('#modalAppDetails .modal-subtitle').insertAdjacentHTML('beforeend',
+                    '<div class="modal-subtitle-item">' +
+                        '<span class="modal-subtitle-label">Rating:</span>' +
+                        '<span>' + data.summary.recent_average.toFixed(1) + '★ recent, ' +
+                            data.summary.average_rating.toFixed(1) + '★ overall (' + data.summary.trend + ')</span>' +
+                    '</div>');
+            } catch (error) {
+                console.error('Failed to load reviews:', error);
+            }
+        }
+
+        // Format a size in bytes as e.g. "48.2 MB"
+        function formatBytes(size) {
+            const units = ['B', 'kB', 'MB', 'GB', 'TB'];
+            let unit = 0;
+            while (size >= 1000 && unit < units.length - 1) {
+                size /= 1000;
+                unit++;
             }
+            return (unit === 0 ? size : size.toFixed(1)) + ' ' + units[unit];
         }
 
-        // Toggle between light and dark themes
-        function toggleTheme() {
-            const currentTheme = getSavedTheme();
-            const newTheme = currentTheme === 'dark' ? 'light' : 'dark';
-            localStorage.setItem(THEME_KEY, newTheme);
-            applyTheme(newTheme);
-        }
+        // Plot the app's download size per version above its history
+        async function loadSizeChart(bundleId) {
+            const chart = document.getElementById('sizeChart');
+            chart.style.display = 'none';
+            chart.innerHTML = '';
+
+            try {
+                const response = await fetch('/api/sizes?bundle_id=' + encodeURIComponent(bundleId));
+                if (!response.ok) {
+                    return;
+                }
+
+                const sizes = await response.json();
+                if (sizes.length < 2 || bundleId !== currentBundleId) {
+                    return;
+                }
+
+                const width = 600, height = 120, pad = 8;
+                const values = sizes.map(s => s.file_size_bytes);
+                const min = Math.min(...values), max = Math.max(...values);
+                const range = max - min || 1;
+                const points = sizes.map((s, i) => [
+                    pad + i * (width - 2 * pad) / (sizes.length - 1),
+                    height - pad - (s.file_size_bytes - min) * (height - 2 * pad) / range
+                ]);
+
+                const first = values[0], last = values[values.length - 1];
+                const change = ((last - first) * 100 / first).toFixed(0);
+                chart.innerHTML = '<div>Download size: ' + formatBytes(first) + ' (' + escapeHtml(sizes[0].version) + ') → ' +
+                        formatBytes(last) + ' (' + escapeHtml(sizes[sizes.length - 1].version) + '), ' +
+                        (change >= 0 ? '+' : '') + change + '%%</div>' +
+                    '<svg viewBox="0 0 ' + width + ' ' + height + '">' +
+                        '<polyline points="' + points.map(p => p.join(',')).join(' ') + '"></polyline>' +
+                        points.map((p, i) => '<circle cx="' + p[0] + '" cy="' + p[1] + '" r="3">' +
+                            '<title>' + escapeHtml(sizes[i].version) + ': ' + formatBytes(sizes[i].file_size_bytes) + '</title></circle>').join('') +
+                    '</svg>';
+                chart.style.display = 'block';
+            } catch (error) {
+                console.error('Failed to load size history:', error);
+            }
+        }
+
+        // Draw releases per day from /api/activity as a calendar heatmap, one
+        // column per week and one row per weekday, shaded relative to the
+        // busiest day
+        function activityHeatmap(activity) {
+            const cell = 11, gap = 2, top = 14;
+            const offset = new Date(activity.days[0].date + 'T00:00:00Z').getUTCDay();
+            const weeks = Math.ceil((activity.days.length + offset) / 7);
+            const width = weeks * (cell + gap), height = top + 7 * (cell + gap);
+            let cells = '', months = '';
+
+            activity.days.forEach((day, i) => {
+                const date = new Date(day.date + 'T00:00:00Z');
+                const x = Math.floor((i + offset) / 7) * (cell + gap);
+                const y = top + date.getUTCDay() * (cell + gap);
+                const level = day.count === 0 ? 0 : Math.min(4, Math.ceil(day.count * 4 / activity.max));
+                cells += '<rect x="' + x + '" y="' + y + '" width="' + cell + '" height="' + cell + '" rx="2" class="heat-' + level + '">' +
+                    '<title>' + t('activity.day', day.count, date.toLocaleDateString(LANG, { timeZone: 'UTC' })) + '</title></rect>';
+                if (date.getUTCDate() === 1 && x < width - 3 * (cell + gap)) {
+                    months += '<text x="' + x + '" y="10">' + date.toLocaleDateString(LANG, { month: 'short', timeZone: 'UTC' }) + '</text>';
+                }
+            });
+
+            const summary = t('activity.summary', activity.total);
+            const legend = [0, 1, 2, 3, 4].map(level =>
+                '<svg width="' + cell + '" height="' + cell + '" style="display:inline-block;vertical-align:middle;" aria-hidden="true">' +
+                    '<rect width="' + cell + '" height="' + cell + '" rx="2" class="heat-' + level + '"></rect></svg>').join(' ');
+            return '<div>' + summary + '</div>' +
+                '<svg viewBox="0 0 ' + width + ' ' + height + '" role="img" aria-label="' + summary + '">' + months + cells + '</svg>' +
+                '<div>' + t('activity.less') + ' ' + legend + ' ' + t('activity.more') + '</div>';
+        }
+
+        // Show release activity across all tracked apps on the dashboard
+        async function loadActivity() {
+            const container = document.getElementById('activity');
+            try {
+                const response = await fetch('/api/activity');
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+                container.innerHTML = activityHeatmap(await response.json());
+            } catch (error) {
+                container.innerHTML = '<div class="error">Failed to load release activity: ' + escapeHtml(error.message) + '</div>';
+            }
+        }
+
+        // Fill the comparison pickers with the tracked apps, keeping the picks
+        function compareOptions() {
+            const apps = trackedApps.slice().sort((a, b) =>
+                (a.display_name || a.track_name).localeCompare(b.display_name || b.track_name));
+            ['compareA', 'compareB'].forEach((id, i) => {
+                const select = document.getElementById(id);
+                const selected = select.value || (apps[i] ? apps[i].bundle_id : '');
+                select.innerHTML = apps.map(app =>
+                    '<option value="' + escapeHtml(app.bundle_id) + '"' + (app.bundle_id === selected ? ' selected' : '') + '>' +
+                        escapeHtml(app.display_name || app.track_name) + '</option>').join('');
+            });
+        }
+
+        // Number of releases listed in each comparison timeline
+        const compareTimelineLength = 25;
+
+        // Show the picked apps' release timelines side by side with their cadence
+        async function loadComparison() {
+            const a = document.getElementById('compareA').value;
+            const b = document.getElementById('compareB').value;
+            const container = document.getElementById('comparison');
+            if (!a || !b) {
+                return;
+            }
+
+            try {
+                const response = await fetch('/api/compare?a=' + encodeURIComponent(a) + '&b=' + encodeURIComponent(b));
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+                const comparison = await response.json();
+                container.innerHTML = '<div class="compare-grid">' + [comparison.a, comparison.b].map(compareColumn).join('') + '</div>';
+            } catch (error) {
+                container.innerHTML = '<div class="error">Failed to compare apps: ' + escapeHtml(error.message) + '</div>';
+            }
+        }
+
+        // One app's side of the comparison
+        function compareColumn(app) {
+            const stat = (label, value) => value ? '<dt>' + label + '</dt><dd>' + value + '</dd>' : '';
+            const lastRelease = app.last_release ?
+                t('compare.daysAgo', new Date(app.last_release).toLocaleDateString(LANG), Math.floor(app.days_since_last_release)) : '';
+            const severities = Object.entries(app.severities)
+                .map(([severity, count]) => escapeHtml(severity) + ' ' + count).join(', ');
+            const releases = app.timeline.slice(0, compareTimelineLength).map(release =>
+                '<li><span>' + escapeHtml(release.version) + ' <span class="detail-label">' + escapeHtml(release.severity) + '</span></span>' +
+                    '<span>' + new Date(release.released_at).toLocaleDateString(LANG) + '</span></li>').join('');
+            const more = app.timeline.length > compareTimelineLength ?
+                '<li>' + t('compare.more', app.timeline.length - compareTimelineLength) + '</li>' : '';
+
+            return '<div>' +
+                '<h3>' + escapeHtml(app.name) + ' ' + escapeHtml(app.version) + '</h3>' +
+                '<div class="history-cadence">' + escapeHtml(app.vendor) + '</div>' +
+                '<dl>' +
+                    stat(t('compare.releases'), String(app.releases)) +
+                    stat(t('compare.lastYear'), String(app.releases_last_year)) +
+                    stat(t('compare.average'), app.average_days) +
+                    stat(t('compare.median'), app.median_days) +
+                    stat(t('compare.lastRelease'), lastRelease) +
+                    stat(t('compare.severities'), severities) +
+                '</dl>' +
+                '<h3>' + t('compare.timeline') + '</h3>' +
+                '<ol>' + releases + more + '</ol>' +
+            '</div>';
+        }
+
+        // Show one app's release activity above its history
+        async function loadAppActivity(bundleId) {
+            const container = document.getElementById('appActivity');
+            container.style.display = 'none';
+            container.innerHTML = '';
+
+            try {
+                const response = await fetch('/api/activity?bundle_id=' + encodeURIComponent(bundleId));
+                if (!response.ok) {
+                    return;
+                }
+
+                const activity = await response.json();
+                if (activity.total === 0 || bundleId !== currentBundleId) {
+                    return;
+                }
+                container.innerHTML = activityHeatmap(activity);
+                container.style.display = 'block';
+            } catch (error) {
+                console.error('Failed to load release activity:', error);
+            }
+        }
+
+        async function saveAppLabel() {
+            if (!currentBundleId) {
+                return;
+            }
+
+            const saveBtn = document.getElementById('saveLabelBtn');
+            const displayName = document.getElementById('labelDisplayName').value.trim();
+            saveBtn.disabled = true;
+
+            try {
+                const response = await fetch('/api/label', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({
+                        bundle_id: currentBundleId,
+                        display_name: displayName,
+                        notes: document.getElementById('labelNotes').value.trim()
+                    })
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+
+                const app = appsByBundleId[currentBundleId];
+                document.getElementById('modalAppName').textContent = displayName || (app ? app.track_name : currentBundleId);
+                await loadApps();
+            } catch (error) {
+                alert('Failed to save label: ' + error.message);
+            } finally {
+                saveBtn.disabled = false;
+            }
+        }
+
+        // Developer of the app shown in the modal, whose vendor details are edited
+        let currentVendor = '';
+
+        async function loadVendor(name) {
+            currentVendor = name || '';
+            document.getElementById('vendorTitle').textContent = currentVendor ? t('history.vendor') + ': ' + currentVendor : t('history.vendor');
+            document.getElementById('vendorOwner').value = '';
+            document.getElementById('vendorSupport').value = '';
+            document.getElementById('vendorSLA').value = '';
+            document.getElementById('saveVendorBtn').disabled = !currentVendor;
+            if (!currentVendor) {
+                return;
+            }
+
+            try {
+                const response = await fetch('/api/vendors?name=' + encodeURIComponent(currentVendor));
+                const vendor = await response.json();
+                if (vendor.name !== currentVendor) {
+                    return;
+                }
+                document.getElementById('vendorOwner').value = vendor.owner || '';
+                document.getElementById('vendorSupport').value = vendor.support_contact || '';
+                document.getElementById('vendorSLA').value = vendor.sla || '';
+            } catch (error) {
+                console.error('Failed to load vendor:', error);
+            }
+        }
+
+        async function saveVendor() {
+            if (!currentVendor) {
+                return;
+            }
+
+            const saveBtn = document.getElementById('saveVendorBtn');
+            saveBtn.disabled = true;
+
+            try {
+                const response = await fetch('/api/vendors', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({
+                        name: currentVendor,
+                        owner: document.getElementById('vendorOwner').value.trim(),
+                        support_contact: document.getElementById('vendorSupport').value.trim(),
+                        sla: document.getElementById('vendorSLA').value.trim()
+                    })
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+            } catch (error) {
+                alert('Failed to save vendor: ' + error.message);
+            } finally {
+                saveBtn.disabled = false;
+            }
+        }
+
+        let currentApplication = null;
+
+        // Show the application the open app is linked into, with each
+        // platform's current version
+        async function loadApplication(bundleId) {
+            currentApplication = null;
+            document.getElementById('applicationTitle').textContent = t('history.application');
+            document.getElementById('applicationPlatforms').innerHTML = '';
+            document.getElementById('applicationName').value = '';
+            document.getElementById('applicationPlatform').value = '';
+            document.getElementById('unlinkApplicationBtn').style.display = 'none';
+
+            try {
+                const response = await fetch('/api/applications?bundle_id=' + encodeURIComponent(bundleId));
+                if (!response.ok || bundleId !== currentBundleId) {
+                    return;
+                }
+                const application = await response.json();
+                currentApplication = application;
+
+                const member = application.members.find(m => m.bundle_id === bundleId);
+                document.getElementById('applicationTitle').textContent = 'Application: ' + application.name;
+                document.getElementById('applicationName').value = application.name;
+                document.getElementById('applicationPlatform').value = member ? member.platform : '';
+                document.getElementById('unlinkApplicationBtn').style.display = '';
+                document.getElementById('applicationPlatforms').innerHTML =
+                    application.platforms.map(p => escapeHtml(p.platform) + ' <span class="version-badge">' + escapeHtml(p.version) + '</span>').join(' &bull; ') +
+                    ' <button class="btn ack-btn" onclick="loadApplicationHistory()">Combined timeline</button>';
+            } catch (error) {
+                console.error('Failed to load application:', error);
+            }
+        }
+
+        async function linkApplication() {
+            const name = document.getElementById('applicationName').value.trim();
+            if (!currentBundleId || !name) {
+                alert('Enter the application to link this app into');
+                return;
+            }
+            await changeApplicationLink('POST', {
+                bundle_id: currentBundleId,
+                application: name,
+                platform: document.getElementById('applicationPlatform').value.trim()
+            });
+        }
+
+        async function unlinkApplication() {
+            if (currentBundleId) {
+                await changeApplicationLink('DELETE', { bundle_id: currentBundleId });
+            }
+        }
+
+        async function changeApplicationLink(method, request) {
+            const bundleId = currentBundleId;
+            try {
+                const response = await fetch('/api/applications/link', {
+                    method: method,
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify(request)
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+                await Promise.all([loadApplication(bundleId), loadApplications()]);
+            } catch (error) {
+                alert('Failed to update application: ' + error.message);
+            }
+        }
+
+        let applicationsById = {};
+        let editingApplicationId = null;
+
+        // List applications with each platform's current version, optionally
+        // only those with the selected tag
+        async function loadApplications() {
+            const container = document.getElementById('applications');
+            const filter = document.getElementById('applicationTagFilter');
+            const tag = filter.value;
+
+            try {
+                const response = await fetch('/api/applications');
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+                const applications = await response.json() || [];
+
+                applicationsById = {};
+                applications.forEach(application => { applicationsById[application.id] = application; });
+                if (shownAppColumns().includes('tags')) {
+                    renderApps();
+                }
+
+                const tags = [...new Set(applications.flatMap(application => application.tags || []))].sort();
+                const tagOptions = selected => '<option value="">All</option>' +
+                    (selected && !tags.includes(selected) ? tags.concat(selected) : tags).map(t => '<option value="' + escapeHtml(t) + '"' + (t === selected ? ' selected' : '') + '>' + escapeHtml(t) + '</option>').join('');
+                filter.innerHTML = tagOptions(tag);
+                const updateTag = document.getElementById('updateTag');
+                updateTag.innerHTML = tagOptions(updateTag.value);
+
+                const shown = tag ? applications.filter(application => (application.tags || []).includes(tag)) : applications;
+                if (shown.length === 0) {
+                    container.innerHTML = '<div class="empty-state">' +
+                        (applications.length === 0 ? 'No applications yet. Create one, then link apps into it from their detail view.' : 'No applications with this tag') +
+                    '</div>';
+                    return;
+                }
+
+                container.innerHTML = '<table class="history-table">' +
+                    '<thead>' +
+                        '<tr>' +
+                            '<th>Application</th>' +
+                            '<th>Owner</th>' +
+                            '<th>Tags</th>' +
+                            '<th>Platforms</th>' +
+                            '<th></th>' +
+                        '</tr>' +
+                    '</thead>' +
+                    '<tbody>' +
+                    shown.map(application => '<tr>' +
+                        '<td>' + escapeHtml(application.name) + '</td>' +
+                        '<td>' + escapeHtml(application.owner || '') + '</td>' +
+                        '<td>' + (application.tags || []).map(t =>
+                            '<span class="application-tag" onclick="filterApplications(\'' + jsString(t) + '\')">' + escapeHtml(t) + '</span>').join('') + '</td>' +
+                        '<td>' + (application.platforms.length === 0 ? '<span class="empty-state">No apps linked</span>' :
+                            application.platforms.map(p =>
+                                '<span class="application-platform" onclick="showApplicationApp(\'' + jsString(p.bundle_id) + '\', \'' + jsString(p.name) + '\')">' +
+                                    escapeHtml(p.platform) + (p.country ? ' (' + escapeHtml(p.country) + ')' : '') +
+                                    ' <span class="version-badge">' + escapeHtml(p.version) + '</span></span>').join(' &bull; ')) + '</td>' +
+                        '<td>' +
+                            '<button class="btn ack-btn" onclick="editApplication(\'' + jsString(application.id) + '\')">Edit</button> ' +
+                            '<button class="btn btn-danger ack-btn" onclick="deleteApplication(\'' + jsString(application.id) + '\')">Delete</button>' +
+                        '</td>' +
+                    '</tr>').join('') +
+                    '</tbody></table>';
+            } catch (error) {
+                container.innerHTML = '<div class="error">Failed to load applications: ' + escapeHtml(error.message) + '</div>';
+            }
+        }
+
+        function showApplicationApp(bundleId, name) {
+            const app = appsByBundleId[bundleId];
+            showVersionHistory(bundleId, name, app ? app.artist_name : '');
+        }
+
+        function filterApplications(tag) {
+            document.getElementById('applicationTagFilter').value = tag;
+            loadApplications();
+        }
+
+        function editApplication(id) {
+            const application = applicationsById[id];
+            if (!application) {
+                return;
+            }
+            editingApplicationId = id;
+            document.getElementById('newApplicationName').value = application.name;
+            document.getElementById('newApplicationOwner').value = application.owner || '';
+            document.getElementById('newApplicationTags').value = (application.tags || []).join(', ');
+            document.getElementById('saveApplicationBtn').textContent = t('common.save');
+            document.getElementById('cancelApplicationBtn').style.display = '';
+            document.getElementById('newApplicationName').focus();
+        }
+
+        function resetApplicationForm() {
+            editingApplicationId = null;
+            document.getElementById('newApplicationName').value = '';
+            document.getElementById('newApplicationOwner').value = '';
+            document.getElementById('newApplicationTags').value = '';
+            document.getElementById('saveApplicationBtn').textContent = t('applications.create');
+            document.getElementById('cancelApplicationBtn').style.display = 'none';
+        }
+
+        // Create an application, or save the one being edited
+        async function saveApplication() {
+            const name = document.getElementById('newApplicationName').value.trim();
+            if (!name) {
+                alert('Enter the application name');
+                return;
+            }
+            const request = {
+                name: name,
+                owner: document.getElementById('newApplicationOwner').value.trim(),
+                tags: document.getElementById('newApplicationTags').value.split(',').map(t => t.trim()).filter(Boolean)
+            };
+
+            try {
+                const url = editingApplicationId ? '/api/applications?id=' + encodeURIComponent(editingApplicationId) : '/api/applications';
+                const response = await fetch(url, {
+                    method: editingApplicationId ? 'PUT' : 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify(request)
+                });
+
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+                resetApplicationForm();
+                await loadApplications();
+            } catch (error) {
+                alert('Failed to save application: ' + error.message);
+            }
+        }
+
+        async function deleteApplication(id) {
+            const application = applicationsById[id];
+            if (!application || !confirm('Delete ' + application.name + '? Its apps stay tracked.')) {
+                return;
+            }
+
+            try {
+                const response = await fetch('/api/applications?id=' + encodeURIComponent(id), { method: 'DELETE' });
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+                if (editingApplicationId === id) {
+                    resetApplicationForm();
+                }
+                await loadApplications();
+            } catch (error) {
+                alert('Failed to delete application: ' + error.message);
+            }
+        }
+
+        // Replace the open app's history with the combined timeline of every
+        // platform of its application
+        async function loadApplicationHistory() {
+            if (!currentApplication) {
+                return;
+            }
+            const historyContainer = document.getElementById('historyTableContainer');
+            historyContainer.innerHTML = '<div class="loading-history">Loading combined timeline...</div>';
+
+            try {
+                const response = await fetch('/api/applications/history?id=' + encodeURIComponent(currentApplication.id));
+                if (!response.ok) {
+                    throw new Error(await errorMessage(response));
+                }
+                const history = await response.json();
+                if (!history || history.length === 0) {
+                    historyContainer.innerHTML = '<div class="empty-history">No version history available yet on any platform.</div>';
+                    return;
+                }
+
+                historyContainer.innerHTML = '<div class="history-cadence">Combined timeline of ' + escapeHtml(currentApplication.name) + '</div>' +
+                    '<table class="history-table">' +
+                    '<thead>' +
+                        '<tr>' +
+                            '<th>Detected</th>' +
+                            '<th>Platform</th>' +
+                            '<th>Version Change</th>' +
+                            '<th>Release Notes</th>' +
+                        '</tr>' +
+                    '</thead>' +
+                    '<tbody>' + history.map(update =>
+                        '<tr>' +
+                            '<td>' + new Date(update.updated_at).toLocaleString(LANG) + '</td>' +
+                            '<td>' + escapeHtml(update.platform || update.bundle_id) + '</td>' +
+                            '<td>' + versionBadges(update) + '</td>' +
+                            '<td><div class="history-notes">' + releaseNotesHtml(update, 'No release notes available') + '</div></td>' +
+                        '</tr>').join('') +
+                    '</tbody></table>';
+            } catch (error) {
+                historyContainer.innerHTML = '<div class="error">Failed to load combined timeline: ' + escapeHtml(error.message) + '</div>';
+            }
+        }
+
+        function closeHistoryModal() {
+            document.getElementById('historyModal').style.display = 'none';
+            currentBundleId = null;
+
+            // Return focus to whatever opened the modal
+            if (modalReturnFocus && document.contains(modalReturnFocus)) {
+                modalReturnFocus.focus();
+            }
+            modalReturnFocus = null;
+
+            // Drop an update deep link so reloading doesn't reopen it
+            if (location.hash.startsWith('#update/')) {
+                history.replaceState(null, '', location.pathname + location.search);
+            }
+        }
+
+        // Open the update named by a #update/{id} deep link: its app's history
+        // modal, with the update and its release notes highlighted at the top
+        async function openLinkedUpdate() {
+            if (!location.hash.startsWith('#update/')) {
+                return;
+            }
+            const id = decodeURIComponent(location.hash.slice('#update/'.length));
+
+            try {
+                const response = await fetch('/api/updates/' + encodeURIComponent(id));
+                if (!response.ok) {
+                    throw new Error(response.status === 404 ? 'This update no longer exists' : await errorMessage(response));
+                }
+                const update = await response.json();
+
+                const app = appsByBundleId[update.bundle_id] || {};
+                showVersionHistory(update.bundle_id, update.display_name || update.track_name, app.artist_name || '');
+
+                const linked = document.getElementById('linkedUpdate');
+                linked.innerHTML =
+                    '<div>' +
+                        versionBadges(update) + ' ' +
+                        (update.released_at ? 'released ' + new Date(update.released_at).toLocaleString(LANG) + ', ' : '') +
+                        'detected ' + new Date(update.updated_at).toLocaleString(LANG) +
+                    '</div>' +
+                    (update.assignment ? '<div>Assigned to ' + update.assignment.to + '</div>' : '') +
+                    (update.acknowledged ? '<div>Acknowledged by ' + update.acknowledged.by + '</div>' : '') +
+                    (update.approval ? '<div>' + (update.approval.state === 'pending' ? 'Pending approval' :
+                        (update.approval.state === 'approved' ? 'Approved by ' : 'Rejected by ') + escapeHtml(update.approval.by)) + '</div>' : '') +
+                    '<div class="release-notes">' + releaseNotesHtml(update, 'No release notes available') + '</div>';
+                linked.style.display = '';
+            } catch (error) {
+                alert('Failed to open linked update: ' + error.message);
+            }
+        }
+
+        window.addEventListener('hashchange', openLinkedUpdate);
+
+        async function removeAppFromHistory() {
+            if (!currentBundleId) {
+                alert('No app selected for removal');
+                return;
+            }
+
+            const confirmed = confirm('Archive this app? It will no longer be checked, but its version history is kept and it can be restored later.');
+            if (!confirmed) {
+                return;
+            }
+
+            const removeBtn = document.getElementById('removeAppBtn');
+            const originalText = removeBtn.textContent;
+            removeBtn.disabled = true;
+            removeBtn.textContent = 'Archiving...';
+
+            try {
+                const response = await fetch('/api/track', {
+                    method: 'DELETE',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({ bundle_id: currentBundleId })
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+
+                // Close modal
+                closeHistoryModal();
+
+                // Refresh the apps list
+                await loadApps();
+
+                // Show success message (could be improved with a toast notification)
+                alert('App archived');
+
+            } catch (error) {
+                alert('Failed to archive app: ' + error.message);
+                removeBtn.disabled = false;
+                removeBtn.textContent = originalText;
+            }
+        }
+
+        // Close modal when clicking outside of it
+        window.onclick = function(event) {
+            const modal = document.getElementById('historyModal');
+            if (event.target === modal) {
+                closeHistoryModal();
+            }
+            if (event.target === document.getElementById('commandPalette')) {
+                closePalette();
+            }
+        }
+
+        // The element focused before the modal opened, focused again on close
+        let modalReturnFocus = null;
+
+        // Close the modal on Escape and keep Tab focus inside it while open
+        document.addEventListener('keydown', function(event) {
+            const modal = document.getElementById('historyModal');
+            if (modal.style.display !== 'block') {
+                return;
+            }
+            if (event.key === 'Escape') {
+                closeHistoryModal();
+                return;
+            }
+            if (event.key !== 'Tab') {
+                return;
+            }
+            const focusable = Array.from(modal.querySelectorAll('button, a[href], input, select, textarea, summary, [tabindex]:not([tabindex="-1"])'))
+                .filter(element => !element.disabled && element.offsetParent !== null);
+            if (focusable.length === 0) {
+                return;
+            }
+            const first = focusable[0];
+            const last = focusable[focusable.length - 1];
+            if (event.shiftKey && (document.activeElement === first || !modal.contains(document.activeElement))) {
+                event.preventDefault();
+                last.focus();
+            } else if (!event.shiftKey && (document.activeElement === last || !modal.contains(document.activeElement))) {
+                event.preventDefault();
+                first.focus();
+            }
+        });
+
+        // Activate a focusable card with Enter or Space like a button, unless
+        // a control inside it has focus
+        function activateOnKey(event) {
+            if (event.target === event.currentTarget && (event.key === 'Enter' || event.key === ' ')) {
+                event.preventDefault();
+                event.currentTarget.click();
+            }
+        }
+
+        // Commands offered by the command palette, with their keyboard shortcuts
+        const paletteCommands = [
+            { id: 'updates', keys: 'g u', run: () => goToSection('updatesTitle') },
+            { id: 'apps', keys: 'g a', run: () => goToSection('appsTitle') },
+            { id: 'compare', keys: 'g c', run: () => goToSection('compareTitle') },
+            { id: 'search', keys: 's', run: () => goToSection('searchInput') },
+            { id: 'check', keys: 'c', run: checkNow },
+            { id: 'theme', keys: 't', run: toggleTheme },
+        ];
+
+        // The palette's current entries and the highlighted one
+        let paletteItems = [];
+        let paletteIndex = 0;
+
+        function openPalette() {
+            const palette = document.getElementById('commandPalette');
+            if (palette.style.display !== 'block') {
+                paletteReturnFocus = document.activeElement;
+            }
+            palette.style.display = 'block';
+            const input = document.getElementById('paletteInput');
+            input.value = '';
+            renderPalette();
+            input.focus();
+        }
+
+        let paletteReturnFocus = null;
+
+        function closePalette(keepFocus) {
+            document.getElementById('commandPalette').style.display = 'none';
+            if (!keepFocus && paletteReturnFocus && document.contains(paletteReturnFocus)) {
+                paletteReturnFocus.focus();
+            }
+            paletteReturnFocus = null;
+        }
+
+        // List the commands and tracked apps matching the palette's input
+        function renderPalette() {
+            const query = document.getElementById('paletteInput').value.trim().toLowerCase();
+            const commands = paletteCommands
+                .filter(command => t('palette.' + command.id).toLowerCase().includes(query))
+                .map(command => ({ label: t('palette.' + command.id), hint: command.keys, run: command.run }));
+            const apps = query ? trackedApps
+                .filter(app => [app.display_name, app.track_name, app.bundle_id, app.artist_name]
+                    .some(value => value && value.toLowerCase().includes(query)))
+                .slice(0, 8)
+                .map(app => ({
+                    label: app.display_name || app.track_name,
+                    hint: t('palette.openApp'),
+                    run: () => showVersionHistory(app.bundle_id, app.display_name || app.track_name, app.artist_name),
+                })) : [];
+
+            paletteItems = commands.concat(apps);
+            paletteIndex = 0;
+            const list = document.getElementById('paletteResults');
+            if (paletteItems.length === 0) {
+                list.innerHTML = '<li role="option" aria-disabled="true">' + t('palette.none') + '</li>';
+                document.getElementById('paletteInput').removeAttribute('aria-activedescendant');
+                return;
+            }
+            list.innerHTML = paletteItems.map((item, i) =>
+                '<li role="option" id="paletteOption' + i + '" onclick="runPaletteItem(' + i + ')">' +
+                    '<span>' + escapeHtml(item.label) + '</span><kbd>' + escapeHtml(item.hint) + '</kbd>' +
+                '</li>').join('');
+            highlightPaletteItem(0);
+        }
+
+        function highlightPaletteItem(index) {
+            if (paletteItems.length === 0) {
+                return;
+            }
+            paletteIndex = (index + paletteItems.length) %% paletteItems.length;
+            document.querySelectorAll('#paletteResults li').forEach((item, i) => {
+                item.setAttribute('aria-selected', i === paletteIndex ? 'true' : 'false');
+            });
+            const selected = document.getElementById('paletteOption' + paletteIndex);
+            selected.scrollIntoView({ block: 'nearest' });
+            document.getElementById('paletteInput').setAttribute('aria-activedescendant', selected.id);
+        }
+
+        function runPaletteItem(index) {
+            const item = paletteItems[index];
+            if (!item) {
+                return;
+            }
+            // The command decides where focus goes next
+            closePalette(true);
+            item.run();
+        }
+
+        function paletteKeydown(event) {
+            switch (event.key) {
+            case 'ArrowDown':
+                event.preventDefault();
+                highlightPaletteItem(paletteIndex + 1);
+                break;
+            case 'ArrowUp':
+                event.preventDefault();
+                highlightPaletteItem(paletteIndex - 1);
+                break;
+            case 'Enter':
+                event.preventDefault();
+                runPaletteItem(paletteIndex);
+                break;
+            case 'Escape':
+                event.preventDefault();
+                closePalette();
+                break;
+            case 'Tab':
+                // The input is the only control in the palette
+                event.preventDefault();
+                break;
+            }
+        }
+
+        // Scroll to a section and move focus to it, or to a control in it
+        function goToSection(id) {
+            const element = document.getElementById(id);
+            element.scrollIntoView({ behavior: 'smooth', block: 'start' });
+            element.focus({ preventScroll: true });
+        }
+
+        // Start a check cycle now; the status box shows its progress
+        async function checkNow() {
+            try {
+                const response = await fetch('/api/check', { method: 'POST' });
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+                setTimeout(loadStatus, 1000);
+            } catch (error) {
+                alert('Failed to start check: ' + error.message);
+            }
+        }
+
+        // Move focus to the next (step 1) or previous (step -1) app card
+        function focusAppCard(step) {
+            const cards = Array.from(document.querySelectorAll('#apps .app-card'));
+            if (cards.length === 0) {
+                return;
+            }
+            const current = cards.indexOf(document.activeElement);
+            const next = current === -1 ? (step > 0 ? 0 : cards.length - 1) :
+                Math.min(cards.length - 1, Math.max(0, current + step));
+            cards[next].focus();
+            cards[next].scrollIntoView({ block: 'nearest' });
+        }
+
+        // Whether the last key was the g of a two-key shortcut like g u
+        let pendingGoKey = false;
+        let pendingGoTimer = null;
+
+        // Dashboard keyboard shortcuts. They're off while typing in a field or
+        // while a dialog is open, and never take keys with modifiers.
+        document.addEventListener('keydown', function(event) {
+            if (event.ctrlKey || event.metaKey || event.altKey || event.defaultPrevented) {
+                return;
+            }
+            const target = event.target;
+            if (target.isContentEditable || ['INPUT', 'TEXTAREA', 'SELECT'].includes(target.tagName)) {
+                return;
+            }
+            if (document.querySelector('.modal[style*="display: block"]')) {
+                return;
+            }
+
+            if (pendingGoKey) {
+                pendingGoKey = false;
+                clearTimeout(pendingGoTimer);
+                if (event.key === 'u') {
+                    event.preventDefault();
+                    goToSection('updatesTitle');
+                } else if (event.key === 'a') {
+                    event.preventDefault();
+                    goToSection('appsTitle');
+                } else if (event.key === 'c') {
+                    event.preventDefault();
+                    goToSection('compareTitle');
+                }
+                return;
+            }
+
+            switch (event.key) {
+            case '/':
+            case '?':
+                event.preventDefault();
+                openPalette();
+                break;
+            case 'g':
+                pendingGoKey = true;
+                pendingGoTimer = setTimeout(() => { pendingGoKey = false; }, 1500);
+                break;
+            case 's':
+                event.preventDefault();
+                goToSection('searchInput');
+                break;
+            case 'c':
+                checkNow();
+                break;
+            case 't':
+                toggleTheme();
+                break;
+            case 'j':
+                focusAppCard(1);
+                break;
+            case 'k':
+                focusAppCard(-1);
+                break;
+            }
+        });
+
+        // Store the last sync timestamp
+        let lastSyncTime = null;
+
+        // Update last synced timestamp display
+        function updateLastSyncedDisplay() {
+            if (!lastSyncTime) {
+                document.getElementById('lastSyncedTime').textContent = t('header.loading');
+                return;
+            }
+
+            const now = Date.now();
+            const diffMs = now - lastSyncTime;
+            const diffSecs = Math.floor(diffMs / 1000);
+            const diffMins = Math.floor(diffSecs / 60);
+
+            let timeStr;
+            if (diffSecs < 10) {
+                timeStr = t('header.justNow');
+            } else if (diffSecs < 60) {
+                timeStr = t('header.secondsAgo', diffSecs);
+            } else if (diffMins < 60) {
+                timeStr = t(diffMins === 1 ? 'header.minuteAgo' : 'header.minutesAgo', diffMins);
+            } else {
+                const diffHours = Math.floor(diffMins / 60);
+                timeStr = t(diffHours === 1 ? 'header.hourAgo' : 'header.hoursAgo', diffHours);
+            }
+
+            document.getElementById('lastSyncedTime').textContent = timeStr;
+        }
+
+        // Store the last known update timestamp
+        let lastKnownUpdate = null;
+
+        // Load all data and update sync time
+        async function refreshData() {
+            await Promise.all([loadApps(), loadUpdates(), loadActivity(), loadApplications(), loadCompliance(), loadCompatibility(), loadWebhookDeliveries()]);
+            lastSyncTime = Date.now();
+            updateLastSyncedDisplay();
+        }
+
+        // Check for new updates and refresh if found
+        async function checkForNewUpdates() {
+            try {
+                const response = await fetch('/api/last-update');
+                const data = await response.json();
+
+                if (data.has_updates) {
+                    const currentUpdate = new Date(data.last_update).getTime();
+
+                    // If we have a previous timestamp and it's different, refresh
+                    if (lastKnownUpdate !== null && currentUpdate > lastKnownUpdate) {
+                        console.log('New update detected, refreshing data...');
+                        await refreshData();
+                    }
+
+                    // Update the last known timestamp
+                    lastKnownUpdate = currentUpdate;
+                }
+            } catch (error) {
+                console.error('Failed to check for updates:', error);
+            }
+        }
+
+        // Initialize last known update on page load
+        async function initializeUpdateTracking() {
+            try {
+                const response = await fetch('/api/last-update');
+                const data = await response.json();
+                if (data.has_updates) {
+                    lastKnownUpdate = new Date(data.last_update).getTime();
+                }
+            } catch (error) {
+                console.error('Failed to initialize update tracking:', error);
+            }
+        }
+
+        // Show check cycle progress, polling quickly while a cycle runs and
+        // reloading the data once it finishes. Between cycles, show when the
+        // next check is due and how many apps are failing.
+        let checkRunning = false;
+        let statusTimer = null;
+        let daemonStatus = null;
+        async function loadStatus() {
+            clearTimeout(statusTimer);
+            try {
+                const response = await fetch('/api/status');
+                const status = await response.json();
+                const check = status.check || {};
+
+                daemonStatus = status;
+                updateCheckStatusDisplay();
+                if (check.running) {
+                    statusTimer = setTimeout(loadStatus, 3000);
+                } else if (checkRunning) {
+                    await refreshData();
+                }
+                checkRunning = !!check.running;
+            } catch (error) {
+                console.error('Failed to load status:', error);
+            }
+        }
+
+        // Format a duration in seconds for the status box
+        function formatSeconds(secs) {
+            secs = Math.max(0, Math.round(secs));
+            if (secs < 60) {
+                return secs + 's';
+            }
+            const mins = Math.round(secs / 60);
+            if (mins < 60) {
+                return mins + (mins === 1 ? ' min' : ' mins');
+            }
+            const hours = Math.round(mins / 60);
+            return hours + (hours === 1 ? ' hour' : ' hours');
+        }
+
+        // Render the daemon status box from the last /api/status response
+        function updateCheckStatusDisplay() {
+            const box = document.getElementById('checkStatus');
+            if (!daemonStatus) {
+                box.style.display = 'none';
+                return;
+            }
+
+            const check = daemonStatus.check || {};
+            const failing = Object.entries(daemonStatus.failing_apps || {});
+            const label = document.getElementById('checkStatusLabel');
+            const text = document.getElementById('checkStatusText');
+            const details = [];
+
+            if (check.running) {
+                label.textContent = t('header.checking');
+                let progress = check.checked + ' / ' + check.total + ' apps';
+                if (check.failed > 0) {
+                    progress += ', ' + check.failed + ' failed';
+                }
+                text.textContent = progress;
+                details.push('Started ' + new Date(check.started_at).toLocaleTimeString(LANG));
+            } else if (daemonStatus.next_check) {
+                label.textContent = t('header.nextCheck');
+                const untilNext = (new Date(daemonStatus.next_check).getTime() - Date.now()) / 1000;
+                let next = untilNext > 0 ? 'in ' + formatSeconds(untilNext) : 'due';
+                if (failing.length > 0) {
+                    next += ' · ' + failing.length + ' failing';
+                }
+                text.textContent = next;
+            } else {
+                box.style.display = 'none';
+                return;
+            }
+
+            if (daemonStatus.last_check) {
+                let last = 'Last check finished ' + new Date(daemonStatus.last_check).toLocaleString(LANG);
+                if (daemonStatus.last_cycle_duration_seconds !== undefined) {
+                    last += ' in ' + formatSeconds(daemonStatus.last_cycle_duration_seconds);
+                }
+                details.push(last);
+            }
+            if (failing.length > 0) {
+                details.push('Failing: ' + failing.map(([bundleID, count]) => bundleID + ' (' + count + ')').join(', '));
+            }
+            box.title = details.join('\n');
+            box.style.display = '';
+        }
+
+        // Show a link in the footer when a newer MAVT release is available
+        async function loadReleaseNotice() {
+            try {
+                const response = await fetch('/api/health');
+                const health = await response.json();
+                const release = health.update_available;
+                if (!release) {
+                    return;
+                }
+
+                const link = document.getElementById('releaseLink');
+                link.textContent = 'MAVT v' + release.version + ' available';
+                if (release.url) {
+                    link.href = release.url;
+                }
+                document.getElementById('releaseNotice').style.display = '';
+            } catch (error) {
+                console.error('Failed to check for a new MAVT release:', error);
+            }
+        }
+
+        // Load the current user's keyword watchlist into its form
+        async function loadWatchlist() {
+            const user = localStorage.getItem('mavt-user');
+            document.getElementById('watchlistDescription').textContent =
+                user ? t('watchlist.descriptionOf', user) : t('watchlist.description');
+            if (!user) {
+                return;
+            }
+
+            try {
+                const response = await fetch('/api/watchlists?user=' + encodeURIComponent(user));
+                if (!response.ok) {
+                    return;
+                }
+                const watchlist = await response.json();
+                document.getElementById('watchlistKeywords').value = (watchlist.keywords || []).join(', ');
+                document.getElementById('watchlistNotifyURL').value = watchlist.notify_url || '';
+            } catch (error) {
+                console.error('Failed to load watchlist:', error);
+            }
+        }
+
+        // Save the current user's watchlist; clearing the keywords removes it
+        async function saveWatchlist() {
+            const user = currentUser();
+            if (!user) {
+                return;
+            }
+
+            const saveBtn = document.getElementById('saveWatchlistBtn');
+            saveBtn.disabled = true;
+            try {
+                const keywords = document.getElementById('watchlistKeywords').value
+                    .split(',').map(keyword => keyword.trim()).filter(Boolean);
+                const response = await fetch('/api/watchlists', {
+                    method: 'POST',
+                    headers: {
+                        'Content-Type': 'application/json',
+                    },
+                    body: JSON.stringify({
+                        user: user,
+                        keywords: keywords,
+                        notify_url: document.getElementById('watchlistNotifyURL').value.trim(),
+                    })
+                });
+
+                if (!response.ok) {
+                    const error = await errorMessage(response);
+                    throw new Error(error);
+                }
+
+                await loadWatchlist();
+            } catch (error) {
+                alert('Failed to save watchlist: ' + error.message);
+            } finally {
+                saveBtn.disabled = false;
+            }
+        }
+
+        // Load data on page load; each section renders as soon as its own data arrives
+        async function initialize() {
+            translatePage();
+            loadStatus();
+            loadReleaseNotice();
+            initializeAppDisplay();
+            loadWatchlist();
+            loadSavedViews();
+            if (location.hash.startsWith('#view/')) {
+                await openLinkedView();
+            }
+            await refreshData();
+            openLinkedUpdate();
+            await initializeUpdateTracking();
+        }
+
+        initialize();
+
+        // Get check interval from server (in milliseconds)
+        const checkIntervalMs = %[1]d;
+
+        // Poll for new updates and check progress every 30 seconds
+        setInterval(() => {
+            checkForNewUpdates();
+            if (!checkRunning) {
+                loadStatus();
+            }
+        }, 30000);
+
+        // Refresh at the same interval as the backend checks
+        setInterval(() => {
+            refreshData();
+        }, checkIntervalMs);
+
+        // Update the relative time displays every second
+        setInterval(() => {
+            updateLastSyncedDisplay();
+            updateCheckStatusDisplay();
+        }, 1000);
+
+        // Dark mode functionality
+        const themeToggle = document.getElementById('themeToggle');
+        const htmlElement = document.documentElement;
+        const THEME_KEY = 'mavt-theme';
+
+        // Check for saved theme preference or default to light mode
+        function getSavedTheme() {
+            return localStorage.getItem(THEME_KEY) || 'light';
+        }
+
+        // Apply theme to the document
+        function applyTheme(theme) {
+            if (theme === 'dark') {
+                htmlElement.setAttribute('data-theme', 'dark');
+                themeToggle.textContent = '☀️';
+            } else {
+                htmlElement.removeAttribute('data-theme');
+                themeToggle.textContent = '🌙';
+            }
+        }
+
+        // Toggle between light and dark themes
+        function toggleTheme() {
+            const currentTheme = getSavedTheme();
+            const newTheme = currentTheme === 'dark' ? 'light' : 'dark';
+            localStorage.setItem(THEME_KEY, newTheme);
+            applyTheme(newTheme);
+        }
+
+        // Apply saved theme on page load
+        applyTheme(getSavedTheme());
+
+        // Add click event listener to toggle button
+        themeToggle.addEventListener('click', toggleTheme);
+
+        const paletteInput = document.getElementById('paletteInput');
+        paletteInput.addEventListener('input', renderPalette);
+        paletteInput.addEventListener('keydown', paletteKeydown);
+    </script>
+</body>
+</html>`
+
+	// Inject the check interval into the HTML (convert to milliseconds)
+	checkIntervalMs := int64(s.checkInterval / time.Millisecond)
+	messages, _ := json.Marshal(s.messages.Messages("ui."))
+	htmlWithConfig := fmt.Sprintf(html, checkIntervalMs, s.messages.Language(), messages, s.noscriptNotice())
+
+	w.Header().Set(contentTypeHeader, contentTypeHTML)
+	w.Write([]byte(htmlWithConfig))
+}
+
+// handleApps returns all tracked apps, or archived apps with ?archived=true
+func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
+	shortcuts, err := shortcutsFormat(r)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'format' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	getApps := s.tracker.GetTrackedApps
+	if r.URL.Query().Get("archived") == "true" {
+		getApps = s.tracker.GetArchivedApps
+	}
+
+	apps, err := getApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if shortcuts {
+		s.writeShortcutsApps(w, apps)
+		return
+	}
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(apps)
+}
+
+// handleUpdates returns recent version updates. Besides the triage filters,
+// ?severity=, ?tag= (an application tag), ?vendor= and ?bundle_id= narrow the
+// updates to those matching any of the given values; each may be repeated,
+// and all but vendor, whose names can contain commas, take comma-separated
+// lists.
+func (s *Server) handleUpdates(w http.ResponseWriter, r *http.Request) {
+	// Parse 'since' parameter (default to 24 hours)
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		sinceStr = "24h"
+	}
+
+	since, err := parseSince(sinceStr)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	shortcuts, err := shortcutsFormat(r)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'format' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	severities := queryValues(r, "severity")
+	for _, severity := range severities {
+		if !slices.Contains(models.Severities, severity) {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'severity' parameter: %s (must be %s)", severity, strings.Join(models.Severities, ", ")), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Get all apps to check their updates
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Optionally only return updates of some apps, vendors or tagged
+	// applications, those nobody has acknowledged yet, those assigned to one
+	// person, or those in one approval state
+	bundleIDs := queryValues(r, "bundle_id")
+	for _, bundleID := range bundleIDs {
+		if err := validateBundleID(bundleID); err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	var vendors []string
+	for _, vendor := range r.URL.Query()["vendor"] {
+		if vendor = strings.TrimSpace(vendor); vendor != "" {
+			vendors = append(vendors, vendor)
+		}
+	}
+	matcher, err := s.tracker.NewUpdateMatcher(tracker.UpdateFilter{
+		BundleIDs:      bundleIDs,
+		Vendors:        vendors,
+		Tags:           queryValues(r, "tag"),
+		Severities:     severities,
+		Unacknowledged: r.URL.Query().Get("unacknowledged") == "true",
+		Assignee:       strings.TrimSpace(r.URL.Query().Get("assignee")),
+		Approval:       r.URL.Query().Get("approval"),
+	})
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Collect all updates within the timeframe
+	cutoff := time.Now().Add(-since)
+	var allUpdates []models.VersionUpdate
+
+	for _, app := range apps {
+		if !matcher.MatchesApp(app) {
+			continue
+		}
+
+		history, err := s.tracker.GetVersionHistory(app.BundleID)
+		if err != nil {
+			continue
+		}
+
+		for _, update := range history {
+			if update.UpdatedAt.After(cutoff) && matcher.Matches(update) {
+				allUpdates = append(allUpdates, update)
+			}
+		}
+	}
+
+	// Sort updates by timestamp in descending order (most recent first)
+	sort.Slice(allUpdates, func(i, j int) bool {
+		return allUpdates[i].UpdatedAt.After(allUpdates[j].UpdatedAt)
+	})
+
+	if shortcuts {
+		s.writeShortcutsUpdates(w, allUpdates, sinceStr)
+		return
+	}
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(allUpdates)
+}
+
+// handleUpdate returns a single version update by its permalink ID
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/updates/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, "Update ID is required", http.StatusBadRequest)
+		return
+	}
+
+	update, err := s.tracker.GetUpdate(id)
+	if errors.Is(err, storage.ErrUpdateNotFound) {
+		writeErrorCode(w, codeUpdateNotFound, "Update not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get update: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(update)
+}
+
+const (
+	// defaultWaitTimeout and maxWaitTimeout bound how long /api/updates/wait
+	// holds a request open
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 2 * time.Minute
+
+	// waitPollInterval is how often a waiting request rereads storage, for
+	// updates recorded by another instance sharing the data directory
+	waitPollInterval = 15 * time.Second
+)
+
+// handleUpdatesWait long-polls for updates recorded after ?since=, a cursor
+// from a previous call (now if omitted). It answers as soon as there are
+// any, oldest first, or with none once ?timeout= (default 30s, at most 2m)
+// elapses, along with the cursor for the next call. Cursors are positions in
+// the sync journal, so updates synced from an upstream with an earlier
+// detection time aren't missed.
+func (s *Server) handleUpdatesWait(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	timeout := defaultWaitTimeout
+	if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+		parsed, err := config.ParseDuration(timeoutStr)
+		if err != nil || parsed <= 0 || parsed > maxWaitTimeout {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'timeout' parameter: %s (must be a positive duration up to %s)", timeoutStr, maxWaitTimeout), http.StatusBadRequest)
+			return
+		}
+		timeout = parsed
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	poll := time.NewTicker(waitPollInterval)
+	defer poll.Stop()
+
+	cursor := r.URL.Query().Get("since")
+	for {
+		// Take the signal before reading so an update recorded in between
+		// still wakes us
+		changed := s.tracker.UpdatesChanged()
+
+		updates, next, err := s.tracker.UpdatesAfter(cursor, maxSyncLimit)
+		if errors.Is(err, tracker.ErrInvalidCursor) {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		timedOut := false
+		if len(updates) == 0 {
+			// Other changes still move the cursor on
+			cursor = next
+			select {
+			case <-changed:
+				continue
+			case <-poll.C:
+				continue
+			case <-r.Context().Done():
+				return
+			case <-deadline.C:
+				timedOut = true
+			}
+		}
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"updates":   append([]models.VersionUpdate{}, updates...),
+			"cursor":    next,
+			"timed_out": timedOut,
+		})
+		return
+	}
+}
+
+const (
+	// defaultSyncLimit and maxSyncLimit bound how many changes /api/sync
+	// returns per page
+	defaultSyncLimit = 500
+	maxSyncLimit     = 5000
+)
+
+// handleSync returns the changes after ?cursor= for incremental replication,
+// up to ?limit= (default 500, at most 5000) per page, or a snapshot of every
+// tracked app without a cursor or when the changes after it are no longer kept
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := defaultSyncLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 || parsed > maxSyncLimit {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'limit' parameter: %s (must be 1 to %d)", limitStr, maxSyncLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := s.tracker.GetChanges(r.URL.Query().Get("cursor"), limit)
+	if errors.Is(err, tracker.ErrInvalidCursor) {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get changes: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(page)
+}
+
+// handleHealth returns health status
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "unhealthy",
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	health := map[string]interface{}{
+		"status":       "healthy",
+		"version":      version.Version,
+		"tracked_apps": len(apps),
+		"timestamp":    time.Now(),
+	}
+	if s.releases != nil {
+		if release := s.releases.Available(); release != nil {
+			health["update_available"] = release
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(health)
+}
+
+// handleReady reports whether storage is usable, for container and orchestrator probes
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+
+	if err := s.tracker.Ready(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "not ready",
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "ready",
+	})
+}
+
+// handleStatus reports whether a check cycle is running and how far it has
+// got, how long the last one took, when the next is due, and which apps
+// have been failing
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	shortcuts, err := shortcutsFormat(r)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'format' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	state, err := s.tracker.SchedulerState()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get scheduler state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	progress := s.tracker.Progress()
+	if shortcuts {
+		s.writeShortcutsStatus(w, progress, state)
+		return
+	}
+	failures := state.AppFailures
+	if failures == nil {
+		failures = map[string]int{}
+	}
+	status := map[string]interface{}{
+		"check":          progress,
+		"check_interval": s.checkInterval.String(),
+		"failing_apps":   failures,
+	}
+	if !state.LastCycleEnd.IsZero() {
+		status["last_check"] = state.LastCycleEnd
+		if state.LastCycleEnd.After(state.LastCycleStart) {
+			status["last_cycle_duration_seconds"] = state.LastCycleEnd.Sub(state.LastCycleStart).Seconds()
+		}
+		if !progress.Running {
+			status["next_check"] = state.LastCycleEnd.Add(s.checkInterval)
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleCheck starts a check cycle now rather than at the next interval. The
+// cycle runs in the background; /api/status reports its progress.
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+	if s.checkNow == nil {
+		writeError(w, "Checks can only be started while running as a daemon", http.StatusServiceUnavailable)
+		return
+	}
+	if s.tracker.Progress().Running || !s.checkNow() {
+		writeError(w, "A check is already running or queued", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Check started",
+	})
+}
+
+// handleSearch searches for apps in the App Store, or with ?source= in npm,
+// PyPI or every source at once
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeErrorCode(w, codeInvalidParameter, "Query parameter 'q' is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 || parsed > maxSearchLimit {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'limit' parameter: %s (must be 1 to %d)", limitStr, maxSearchLimit), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		source = tracker.SearchSourceAppStore
+	}
+	if source != tracker.SearchSourceAll && !slices.Contains(tracker.SearchSources, source) {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid source: must be %s or %s", strings.Join(tracker.SearchSources, ", "), tracker.SearchSourceAll), http.StatusBadRequest)
+		return
+	}
+	if source != tracker.SearchSourceAll && !s.tracker.SourceEnabled(source) {
+		writeError(w, fmt.Sprintf("Source %s is disabled", source), http.StatusBadRequest)
+		return
+	}
+
+	apps, err := s.tracker.SearchSource(r.Context(), source, query, limit)
+	if err != nil {
+		writeLookupError(w, fmt.Sprintf("Search failed: %v", err), err)
+		return
+	}
+
+	// Get list of tracked apps to check which ones are already being tracked
+	trackedApps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get tracked apps: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Create a map of tracked bundle IDs for quick lookup
+	trackedMap := make(map[string]bool)
+	for _, app := range trackedApps {
+		trackedMap[app.BundleID] = true
+	}
+
+	// Add tracking status and source to search results, and the rating at
+	// the top level so look-alike apps can be told apart at a glance
+	type SearchResult struct {
+		*models.AppInfo
+		Source        string  `json:"source"`
+		IsTracked     bool    `json:"is_tracked"`
+		AverageRating float64 `json:"average_rating,omitempty"`
+		RatingCount   int64   `json:"rating_count,omitempty"`
+	}
+
+	results := make([]SearchResult, len(apps))
+	for i, app := range apps {
+		results[i] = SearchResult{
+			AppInfo:   app,
+			Source:    app.Source,
+			IsTracked: trackedMap[app.BundleID],
+		}
+		if results[i].Source == "" {
+			results[i].Source = tracker.SearchSourceAppStore
+		}
+		if app.Rating != nil {
+			results[i].AverageRating = app.Rating.AverageRating
+			results[i].RatingCount = app.Rating.RatingCount
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(results)
+}
+
+// handleTrack adds an app to tracking, or archives it on DELETE. DELETE with
+// ?purge=true deletes the app and its history instead. POST with source
+// "npm" or "pypi" and a package name tracks a package found by search.
+func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		Country  string `json:"country"`
+		Lang     string `json:"lang"`
+		Source   string `json:"source"`
+		Name     string `json:"name"`
+
+		// TrackID, or App as returned by /api/search, identify a search
+		// result to track without looking it up again
+		TrackID int64           `json:"track_id"`
+		App     *models.AppInfo `json:"app"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodPost && packages.IsSource(req.Source) {
+		s.trackPackage(w, r, req.Source, req.Name)
+		return
+	}
+
+	if r.Method == http.MethodPost && req.App != nil {
+		if req.App.BundleID == "" {
+			writeError(w, "app.bundle_id is required", http.StatusBadRequest)
+			return
+		}
+		if err := validateBundleID(req.App.BundleID); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.BundleID != "" && req.BundleID != req.App.BundleID {
+			writeError(w, "bundle_id doesn't match app.bundle_id", http.StatusBadRequest)
+			return
+		}
+		if req.TrackID != 0 && req.App.TrackID != 0 && req.TrackID != req.App.TrackID {
+			writeError(w, "track_id doesn't match app.track_id", http.StatusBadRequest)
+			return
+		}
+		req.BundleID = req.App.BundleID
+		if req.TrackID == 0 {
+			req.TrackID = req.App.TrackID
+		}
+	}
+
+	if req.TrackID < 0 {
+		writeError(w, "track_id must be positive", http.StatusBadRequest)
+		return
+	}
+	if req.BundleID == "" && r.Method == http.MethodPost && req.TrackID == 0 {
+		writeError(w, "bundle_id or track_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.BundleID == "" && r.Method != http.MethodPost {
+		writeError(w, "bundle_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.BundleID != "" {
+		if err := validateBundleID(req.BundleID); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Handle DELETE request
+	if r.Method == http.MethodDelete {
+		if r.URL.Query().Get("purge") == "true" {
+			if err := s.tracker.PurgeApp(req.BundleID); err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to purge app: %v", err), http.StatusInternalServerError)
+				return
+			}
+
+			log.Printf("Purged app via API: %s", sanitizeForLog(req.BundleID))
+
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success":     true,
+				bundleIDField: req.BundleID,
+				"message":     "App and its history permanently deleted",
+			})
+			return
+		}
+
+		if err := s.tracker.RemoveApp(req.BundleID); err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to archive app: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Archived app via API: %s", sanitizeForLog(req.BundleID))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     true,
+			bundleIDField: req.BundleID,
+			"message":     "App archived; its history is kept and can be restored",
+		})
+		return
+	}
+
+	// Check an already tracked app now instead of at the next cycle
+	if r.URL.Query().Get("refresh") == "true" {
+		s.refreshApp(w, r, req.BundleID)
+		return
+	}
+
+	// Handle POST request (add app). A recent search result is tracked from
+	// the search cache; the app in the request only identifies it, so its
+	// details can't be forged
+	app, added, err := s.tracker.TrackSearchResult(req.BundleID, req.TrackID, req.Country, req.Lang)
+	if errors.Is(err, tracker.ErrAlreadyTracked) {
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		w.WriteHeader(http.StatusConflict)
+		message := "App is already tracked; use ?refresh=true to check it now"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":           newErrorDetail(codeAlreadyTracked, message, http.StatusConflict),
+			"success":         false,
+			"already_tracked": true,
+			bundleIDField:     app.BundleID,
+			"app":             app,
+			"message":         message,
+		})
+		return
+	}
+	if err != nil {
+		writeLookupError(w, fmt.Sprintf("Failed to track app: %v", err), err)
+		return
+	}
+
+	if !added {
+		log.Printf("Changed storefront/language via API: %s", sanitizeForLog(app.BundleID))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":         true,
+			"already_tracked": true,
+			bundleIDField:     app.BundleID,
+			"app":             app,
+			"message":         "App is already tracked; its storefront and language were updated",
+		})
+		return
+	}
+
+	log.Printf("Added app to tracking via API: %s", sanitizeForLog(app.BundleID))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":         true,
+		"already_tracked": false,
+		bundleIDField:     app.BundleID,
+		"app":             app,
+		"message":         "App successfully added to tracking",
+	})
+}
+
+// refreshApp checks a tracked app now, for POST /api/track?refresh=true
+func (s *Server) refreshApp(w http.ResponseWriter, r *http.Request, bundleID string) {
+	if bundleID == "" {
+		writeError(w, "bundle_id is required to refresh an app", http.StatusBadRequest)
+		return
+	}
+
+	app, update, err := s.tracker.RefreshApp(r.Context(), bundleID)
+	if err != nil {
+		writeLookupError(w, fmt.Sprintf("Failed to refresh app: %v", err), err)
+		return
+	}
+
+	log.Printf("Refreshed app via API: %s", sanitizeForLog(bundleID))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: app.BundleID,
+		"app":         app,
+		"update":      update,
+		"message":     "App refreshed",
+	})
+}
+
+// trackPackage adds an npm or PyPI package to tracking
+func (s *Server) trackPackage(w http.ResponseWriter, r *http.Request, source, name string) {
+	pkg, err := packages.ParsePackage(source + ":" + name)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	app, err := s.tracker.TrackPackage(r.Context(), pkg)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Failed to track package: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Added package to tracking via API: %s", sanitizeForLog(pkg.String()))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: app.BundleID,
+		"message":     "Package successfully added to tracking",
+	})
+}
+
+// handleReport returns a changelog report of updates grouped by app as
+// Markdown or HTML, and with ?group_by=application by product. With
+// ?group_by=tag it instead summarizes each application tag's releases,
+// optionally only the tags given by ?tag=.
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		sinceStr = "7d"
+	}
+
+	since, err := parseSince(sinceStr)
+	if err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.FormatMarkdown
+	}
+	if format != report.FormatMarkdown && format != report.FormatHTML {
+		writeErrorCode(w, codeInvalidParameter, "Invalid 'format' parameter (must be md or html)", http.StatusBadRequest)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && groupBy != config.ReportGroupByApplication && groupBy != config.ReportGroupByTag {
+		writeErrorCode(w, codeInvalidParameter, "Invalid 'group_by' parameter (must be application or tag)", http.StatusBadRequest)
+		return
+	}
+
+	updates, err := s.tracker.GetRecentUpdates(since)
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get updates: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	var rpt report.Document = report.New(updates, now.Add(-since), now)
+	if groupBy != "" {
+		statuses, err := s.tracker.GetApplications()
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get applications: %v", err), http.StatusInternalServerError)
+			return
+		}
+		applications := make([]models.Application, 0, len(statuses))
+		for _, status := range statuses {
+			applications = append(applications, status.Application)
+		}
+		if groupBy == config.ReportGroupByTag {
+			apps, err := s.tracker.GetTrackedApps()
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+				return
+			}
+			rpt = report.NewByTag(updates, now.Add(-since), now, apps, applications, queryValues(r, "tag")...)
+		} else {
+			rpt = report.NewByApplication(updates, now.Add(-since), now, applications)
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, report.ContentType(format))
+	if err := rpt.Write(w, format); err != nil {
+		log.Printf("Failed to write report: %v", err)
+	}
+}
+
+// handleTrackBulk archives several apps at once, or deletes them and their
+// history with ?purge=true. Each app is handled independently and failures are
+// reported per bundle ID.
+func (s *Server) handleTrackBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleIDs []string `json:"bundle_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.BundleIDs) == 0 {
+		writeError(w, "bundle_ids is required", http.StatusBadRequest)
+		return
+	}
+	for _, bundleID := range req.BundleIDs {
+		if err := validateBundleID(bundleID); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	remove := s.tracker.RemoveApp
+	if r.URL.Query().Get("purge") == "true" {
+		remove = s.tracker.PurgeApp
+	}
+
+	removed := []string{}
+	failed := map[string]string{}
+	for _, bundleID := range req.BundleIDs {
+		if err := remove(bundleID); err != nil {
+			failed[bundleID] = err.Error()
+			continue
+		}
+		removed = append(removed, bundleID)
+	}
+
+	log.Printf("Removed %d app(s) from tracking via bulk API (%d failed)", len(removed), len(failed))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": len(failed) == 0,
+		"removed": removed,
+		"failed":  failed,
+	})
+}
+
+// handleUnarchive restores an archived app
+func (s *Server) handleUnarchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" {
+		writeError(w, "bundle_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.tracker.UnarchiveApp(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Failed to unarchive app: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Unarchived app via API: %s", sanitizeForLog(req.BundleID))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: req.BundleID,
+		"message":     "App restored to tracking",
+	})
+}
+
+// handleLabel sets an app's custom display name and notes. Empty values clear them.
+func (s *Server) handleLabel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID    string `json:"bundle_id"`
+		DisplayName string `json:"display_name"`
+		Notes       string `json:"notes"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" {
+		writeError(w, "bundle_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.tracker.SetAppLabel(req.BundleID, req.DisplayName, req.Notes); err != nil {
+		writeError(w, fmt.Sprintf("Failed to label app: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Updated label via API: %s", sanitizeForLog(req.BundleID))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: req.BundleID,
+		"message":     "App label updated",
+	})
+}
+
+// handleTestFlight sets an app's public TestFlight link and returns the beta
+// state read from it. An empty url stops tracking the beta.
+func (s *Server) handleTestFlight(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		URL      string `json:"url"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" {
+		writeError(w, "bundle_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	app, err := s.tracker.SetTestFlightURL(r.Context(), req.BundleID, req.URL)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Failed to set TestFlight link: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Updated TestFlight link via API: %s", sanitizeForLog(req.BundleID))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":        true,
+		bundleIDField:    req.BundleID,
+		"testflight_url": app.TestFlightURL,
+		"beta":           app.Beta,
+	})
+}
+
+// handleVendors lists vendor metadata (GET), or one vendor's with ?name=, and
+// sets a vendor's support contact, SLA and owner (POST). Empty values clear them.
+func (s *Server) handleVendors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if name := r.URL.Query().Get("name"); name != "" {
+			vendor, err := s.tracker.GetVendor(name)
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get vendor: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if vendor == nil {
+				vendor = &models.Vendor{Name: name}
+			}
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(vendor)
+			return
+		}
+
+		vendors, err := s.tracker.GetVendors()
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get vendors: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(vendors)
+
+	case http.MethodPost:
+		var req models.Vendor
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(req.Name) == "" {
+			writeError(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.tracker.SetVendor(req); err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to save vendor: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Updated vendor via API: %s", sanitizeForLog(req.Name))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"name":    strings.TrimSpace(req.Name),
+			"message": "Vendor updated",
+		})
+
+	default:
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleWatchlists lists users' keyword watchlists, or with ?user= returns
+// one user's. POST sets a user's keywords and where their matches are sent;
+// an empty keyword list removes the watchlist.
+func (s *Server) handleWatchlists(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if user := r.URL.Query().Get("user"); user != "" {
+			watchlist, err := s.tracker.GetWatchlist(user)
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get watchlist: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if watchlist == nil {
+				watchlist = &models.Watchlist{User: user, Keywords: []string{}}
+			}
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(watchlist)
+			return
+		}
+
+		watchlists, err := s.tracker.GetWatchlists()
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get watchlists: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(watchlists)
+
+	case http.MethodPost:
+		var req models.Watchlist
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(req.User) == "" {
+			writeError(w, "user is required", http.StatusBadRequest)
+			return
+		}
+
+		err := s.tracker.SetWatchlist(req)
+		if errors.Is(err, tracker.ErrInvalidWatchlist) {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to save watchlist: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Updated watchlist via API: %s", sanitizeForLog(req.User))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"user":    strings.TrimSpace(req.User),
+			"message": "Watchlist updated",
+		})
+
+	default:
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleViews lists saved dashboard views, those of one user with ?user=, or
+// with ?id= returns one. POST saves a view, replacing the user's view of the
+// same name, and DELETE ?id= removes one.
+func (s *Server) handleViews(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		if id != "" {
+			view, err := s.tracker.GetSavedView(id)
+			if errors.Is(err, tracker.ErrViewNotFound) {
+				writeError(w, fmt.Sprintf("No saved view found: %s", id), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get saved view: %v", err), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(view)
+			return
+		}
+
+		views, err := s.tracker.GetSavedViews(r.URL.Query().Get("user"))
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get saved views: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(views)
+
+	case http.MethodPost:
+		var req struct {
+			User  string `json:"user"`
+			Name  string `json:"name"`
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		view, err := s.tracker.SaveView(req.User, req.Name, req.Query)
+		if errors.Is(err, tracker.ErrInvalidView) {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to save view: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Saved view via API: %s", sanitizeForLog(view.ID))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"view":    view,
+			"message": "View saved",
+		})
+
+	case http.MethodDelete:
+		if id == "" {
+			writeError(w, "id is required", http.StatusBadRequest)
+			return
+		}
+
+		err := s.tracker.DeleteView(id)
+		if errors.Is(err, tracker.ErrViewNotFound) {
+			writeError(w, fmt.Sprintf("No saved view found: %s", id), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to delete view: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Deleted view via API: %s", sanitizeForLog(id))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"id":      id,
+			"message": "View deleted",
+		})
+
+	default:
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleApplications lists applications with their platforms' current
+// versions, optionally only those tagged ?tag=, or with ?id= or ?bundle_id=
+// returns one application or the one an app is linked into. POST creates an
+// application, PUT ?id= updates its name, owner and tags, and DELETE ?id=
+// removes it, leaving its apps tracked.
+func (s *Server) handleApplications(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		if id != "" {
+			application, err := s.tracker.GetApplication(id)
+			if errors.Is(err, tracker.ErrApplicationNotFound) {
+				writeErrorCode(w, codeApplicationNotFound, fmt.Sprintf("No application found: %s", id), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get application: %v", err), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(application)
+			return
+		}
+
+		if bundleID := r.URL.Query().Get("bundle_id"); bundleID != "" {
+			if err := validateBundleID(bundleID); err != nil {
+				writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+				return
+			}
+			application, err := s.tracker.GetApplicationOf(bundleID)
+			if err != nil {
+				writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get application: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if application == nil {
+				writeErrorCode(w, codeApplicationNotFound, fmt.Sprintf("No application linked to %s", bundleID), http.StatusNotFound)
+				return
+			}
+			w.Header().Set(contentTypeHeader, contentTypeJSON)
+			json.NewEncoder(w).Encode(application)
+			return
+		}
+
+		applications, err := s.tracker.GetApplications()
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get applications: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if tag := strings.TrimSpace(r.URL.Query().Get("tag")); tag != "" {
+			tagged := applications[:0]
+			for _, application := range applications {
+				if application.HasTag(tag) {
+					tagged = append(tagged, application)
+				}
+			}
+			applications = tagged
+		}
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(applications)
+
+	case http.MethodPost, http.MethodPut:
+		var req struct {
+			Name  string   `json:"name"`
+			Owner string   `json:"owner"`
+			Tags  []string `json:"tags"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(req.Name) == "" {
+			writeError(w, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		var application *models.Application
+		var err error
+		if r.Method == http.MethodPost {
+			application, err = s.tracker.CreateApplication(req.Name, req.Owner, req.Tags)
+		} else if id == "" {
+			writeError(w, "id is required", http.StatusBadRequest)
+			return
+		} else {
+			application, err = s.tracker.UpdateApplication(id, req.Name, req.Owner, req.Tags)
+		}
+		switch {
+		case errors.Is(err, tracker.ErrApplicationNotFound):
+			writeErrorCode(w, codeApplicationNotFound, fmt.Sprintf("No application found: %s", id), http.StatusNotFound)
+			return
+		case errors.Is(err, tracker.ErrApplicationExists):
+			writeError(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to save application: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Saved application via API: %s", sanitizeForLog(application.Name))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     true,
+			"application": application,
+			"message":     "Application saved",
+		})
+
+	case http.MethodDelete:
+		if id == "" {
+			writeError(w, "id is required", http.StatusBadRequest)
+			return
+		}
+
+		err := s.tracker.DeleteApplication(id)
+		if errors.Is(err, tracker.ErrApplicationNotFound) {
+			writeErrorCode(w, codeApplicationNotFound, fmt.Sprintf("No application found: %s", id), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to delete application: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Deleted application via API: %s", sanitizeForLog(id))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"id":      id,
+			"message": "Application deleted",
+		})
+
+	default:
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+	}
+}
+
+// handleApplicationLink links an app into an application by name on POST,
+// creating the application if needed, or unlinks it on DELETE
+func (s *Server) handleApplicationLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID    string `json:"bundle_id"`
+		Application string `json:"application"`
+		Platform    string `json:"platform"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" {
+		writeError(w, "bundle_id is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		if err := s.tracker.UnlinkApp(req.BundleID); err != nil {
+			writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to unlink app: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		log.Printf("Unlinked app from its application via API: %s", sanitizeForLog(req.BundleID))
+
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":     true,
+			bundleIDField: req.BundleID,
+			"message":     "App unlinked",
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Application) == "" {
+		writeError(w, "application is required", http.StatusBadRequest)
+		return
+	}
+
+	application, err := s.tracker.LinkApp(req.BundleID, req.Application, req.Platform)
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to link app: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Linked app %s into application %s via API", sanitizeForLog(req.BundleID), sanitizeForLog(application.Name))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: req.BundleID,
+		"application": application,
+	})
+}
+
+// handleApplicationHistory returns the combined version history of an
+// application's platforms, newest first
+func (s *Server) handleApplicationHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeError(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	history, err := s.tracker.GetApplicationHistory(id)
+	if errors.Is(err, tracker.ErrApplicationNotFound) {
+		writeErrorCode(w, codeApplicationNotFound, fmt.Sprintf("No application %s found", id), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get application history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(history)
+}
+
+// handleApproval approves or rejects a version update that is pending
+// approval. The update is identified by bundle ID and new version.
+func (s *Server) handleApproval(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.authorizeUpdateAction(w, r) {
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		Version  string `json:"version"`
+		State    string `json:"state"`
+		By       string `json:"by"`
+		Comment  string `json:"comment"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" || req.Version == "" || req.State == "" {
+		writeError(w, "bundle_id, version and state are required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err := s.tracker.DecideUpdate(req.BundleID, req.Version, req.State, req.By, req.Comment)
+	if errors.Is(err, storage.ErrUpdateNotFound) {
+		writeErrorCode(w, codeUpdateNotFound, fmt.Sprintf("No update to %s found for %s", req.Version, req.BundleID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, fmt.Sprintf("Failed to update approval: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Updated approval via API: %s %s", sanitizeForLog(req.BundleID), sanitizeForLog(req.Version))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: req.BundleID,
+		"version":     req.Version,
+		"state":       req.State,
+	})
+}
+
+// handleDeploy pushes an approved version update to the configured MDM again,
+// returning the outcome. The update is identified by bundle ID and new version.
+func (s *Server) handleDeploy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.deployer == nil {
+		writeError(w, "No MDM is configured (set MAVT_MDM_PROVIDER)", http.StatusNotFound)
+		return
+	}
+	if !s.authorizeUpdateAction(w, r) {
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		Version  string `json:"version"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" || req.Version == "" {
+		writeError(w, "bundle_id and version are required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	history, err := s.tracker.GetVersionHistory(req.BundleID)
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get version history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// The latest update to the version, which approvals and deployments are
+	// recorded on
+	i := storage.LastUpdateTo(history, req.Version)
+	if i < 0 {
+		writeErrorCode(w, codeUpdateNotFound, fmt.Sprintf("No update to %s found for %s", req.Version, req.BundleID), http.StatusNotFound)
+		return
+	}
+	update := &history[i]
+	if update.Approval == nil || update.Approval.State != models.ApprovalApproved {
+		writeError(w, "Only approved updates can be deployed", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Deploying via API: %s %s", sanitizeForLog(req.BundleID), sanitizeForLog(req.Version))
+	deployment := s.deployer.Deploy(update)
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(deployment)
+}
+
+// handleAcknowledge marks a version update as reviewed (POST) or clears the
+// acknowledgement (DELETE). The update is identified by bundle ID and new version.
+func (s *Server) handleAcknowledge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		Version  string `json:"version"`
+		By       string `json:"by"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.BundleID == "" || req.Version == "" {
+		writeError(w, "bundle_id and version are required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if r.Method == http.MethodDelete {
+		err = s.tracker.UnacknowledgeUpdate(req.BundleID, req.Version)
+	} else {
+		err = s.tracker.AcknowledgeUpdate(req.BundleID, req.Version, req.By)
+	}
+	if errors.Is(err, storage.ErrUpdateNotFound) {
+		writeErrorCode(w, codeUpdateNotFound, fmt.Sprintf("No update to %s found for %s", req.Version, req.BundleID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, fmt.Sprintf("Failed to update acknowledgement: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Updated acknowledgement via API: %s %s", sanitizeForLog(req.BundleID), sanitizeForLog(req.Version))
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":      true,
+		bundleIDField:  req.BundleID,
+		"version":      req.Version,
+		"acknowledged": r.Method == http.MethodPost,
+	})
+}
+
+// handleAssign assigns a version update to someone, or unassigns it when
+// assignee is empty. The update is identified by bundle ID and new version.
+func (s *Server) handleAssign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		BundleID string `json:"bundle_id"`
+		Version  string `json:"version"`
+		Assignee string `json:"assignee"`
+		By       string `json:"by"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
 
-        // Apply saved theme on page load
-        applyTheme(getSavedTheme());
+	if req.BundleID == "" || req.Version == "" {
+		writeError(w, "bundle_id and version are required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(req.BundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
 
-        // Add click event listener to toggle button
-        themeToggle.addEventListener('click', toggleTheme);
-    </script>
-</body>
-</html>`
+	err := s.tracker.AssignUpdate(req.BundleID, req.Version, req.Assignee, req.By)
+	if errors.Is(err, storage.ErrUpdateNotFound) {
+		writeErrorCode(w, codeUpdateNotFound, fmt.Sprintf("No update to %s found for %s", req.Version, req.BundleID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to assign update: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-	// Inject the check interval into the HTML (convert to milliseconds)
-	checkIntervalMs := int64(s.checkInterval / time.Millisecond)
-	htmlWithConfig := fmt.Sprintf(html, checkIntervalMs)
+	log.Printf("Updated assignment via API: %s %s", sanitizeForLog(req.BundleID), sanitizeForLog(req.Version))
 
-	w.Header().Set(contentTypeHeader, contentTypeHTML)
-	w.Write([]byte(htmlWithConfig))
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		bundleIDField: req.BundleID,
+		"version":     req.Version,
+		"assignee":    strings.TrimSpace(req.Assignee),
+	})
 }
 
-// handleApps returns all tracked apps
-func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
-	apps, err := s.tracker.GetTrackedApps()
+// handleHistory returns version history for a specific app
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	bundleID := r.URL.Query().Get("bundle_id")
+	if bundleID == "" {
+		writeErrorCode(w, codeInvalidParameter, "Query parameter 'bundle_id' is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(bundleID); err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	var from, to time.Time
+	var err error
+	if v := query.Get("from"); v != "" {
+		if from, err = parseHistoryTime(v); err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'from' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("to"); v != "" {
+		if to, err = parseHistoryTime(v); err != nil {
+			writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'to' parameter: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit, offset := 0, 0
+	if v := query.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			writeErrorCode(w, codeInvalidParameter, "Invalid 'limit' parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			writeErrorCode(w, codeInvalidParameter, "Invalid 'offset' parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	history, err := s.tracker.GetVersionHistory(bundleID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get version history: %v", err), http.StatusInternalServerError)
 		return
 	}
 
+	// Newest first, within [from, to)
+	matched := make([]models.VersionUpdate, 0, len(history))
+	for _, update := range history {
+		if (!from.IsZero() && update.UpdatedAt.Before(from)) || (!to.IsZero() && !update.UpdatedAt.Before(to)) {
+			continue
+		}
+		matched = append(matched, update)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].UpdatedAt.After(matched[j].UpdatedAt)
+	})
+
+	// The total before paging lets clients tell when they have loaded everything
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(matched)))
+	if cadence := models.ReleaseCadence(matched); cadence > 0 {
+		w.Header().Set("X-Release-Cadence-Days", strconv.FormatFloat(cadence.Hours()/24, 'f', 1, 64))
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	page := matched[offset:]
+	if limit > 0 && limit < len(page) {
+		page = page[:limit]
+	}
+
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	json.NewEncoder(w).Encode(apps)
+	json.NewEncoder(w).Encode(page)
 }
 
-// handleUpdates returns recent version updates
-func (s *Server) handleUpdates(w http.ResponseWriter, r *http.Request) {
-	// Parse 'since' parameter (default to 24 hours)
-	sinceStr := r.URL.Query().Get("since")
-	if sinceStr == "" {
-		sinceStr = "24h"
+// queryValues returns the values of a query parameter, which may be repeated
+// and hold comma-separated lists, trimmed and without empty ones
+func queryValues(r *http.Request, name string) []string {
+	var values []string
+	for _, param := range r.URL.Query()[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
 	}
+	return values
+}
 
-	since, err := time.ParseDuration(sinceStr)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid 'since' parameter: %v", err), http.StatusBadRequest)
+// parseHistoryTime parses a history range bound given as RFC 3339 or as a
+// date (YYYY-MM-DD, midnight UTC)
+func parseHistoryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// handleLastUpdate returns the timestamp of the most recent update
+func (s *Server) handleLastUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
 	// Get all apps to check their updates
 	apps, err := s.tracker.GetTrackedApps()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	// Collect all updates within the timeframe
-	cutoff := time.Now().Add(-since)
-	var allUpdates []models.VersionUpdate
+	var latestUpdate time.Time
 
+	// Find the most recent update across all apps
 	for _, app := range apps {
 		history, err := s.tracker.GetVersionHistory(app.BundleID)
 		if err != nil {
@@ -1308,213 +5860,290 @@ func (s *Server) handleUpdates(w http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, update := range history {
-			if update.UpdatedAt.After(cutoff) {
-				allUpdates = append(allUpdates, update)
+			if update.UpdatedAt.After(latestUpdate) {
+				latestUpdate = update.UpdatedAt
 			}
 		}
 	}
 
-	// Sort updates by timestamp in descending order (most recent first)
-	sort.Slice(allUpdates, func(i, j int) bool {
-		return allUpdates[i].UpdatedAt.After(allUpdates[j].UpdatedAt)
-	})
-
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	json.NewEncoder(w).Encode(allUpdates)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"last_update":  latestUpdate,
+		"tracked_apps": len(apps),
+		"has_updates":  !latestUpdate.IsZero(),
+	})
 }
 
-// handleHealth returns health status
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	apps, err := s.tracker.GetTrackedApps()
+// handleReviews returns stored customer reviews and the rating trend for a specific app
+func (s *Server) handleReviews(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	bundleID := r.URL.Query().Get("bundle_id")
+	if bundleID == "" {
+		writeErrorCode(w, codeInvalidParameter, "Query parameter 'bundle_id' is required", http.StatusBadRequest)
+		return
+	}
+	if err := validateBundleID(bundleID); err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	reviews, summary, err := s.tracker.GetReviews(bundleID)
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"status": "unhealthy",
-			"error":  err.Error(),
-		})
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get reviews: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":       "healthy",
-		"version":      version.Version,
-		"tracked_apps": len(apps),
-		"timestamp":    time.Now(),
+		"summary": summary,
+		"reviews": reviews,
 	})
 }
 
-// handleSearch searches for apps in the App Store
-func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+// handleSizes returns an app's download size at each version
+func (s *Server) handleSizes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+	bundleID := r.URL.Query().Get("bundle_id")
+	if bundleID == "" {
+		writeErrorCode(w, codeInvalidParameter, "Query parameter 'bundle_id' is required", http.StatusBadRequest)
 		return
 	}
-
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
+	if err := validateBundleID(bundleID); err != nil {
+		writeErrorCode(w, codeInvalidParameter, fmt.Sprintf("Invalid 'bundle_id' parameter: %v", err), http.StatusBadRequest)
+		return
 	}
 
-	apps, err := s.appstoreClient.SearchApps(query, limit)
+	sizes, err := s.tracker.GetSizeHistory(bundleID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Search failed: %v", err), http.StatusInternalServerError)
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get size history: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	// Get list of tracked apps to check which ones are already being tracked
-	trackedApps, err := s.tracker.GetTrackedApps()
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get tracked apps: %v", err), http.StatusInternalServerError)
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(sizes)
+}
+
+// handleApp serves per-app resources under /api/app/{bundle_id}/; the only
+// one is snapshots, the app's metadata over time, newest first, each with the
+// fields changed since the one before
+func (s *Server) handleApp(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Create a map of tracked bundle IDs for quick lookup
-	trackedMap := make(map[string]bool)
-	for _, app := range trackedApps {
-		trackedMap[app.BundleID] = true
+	bundleID, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/app/"), "/")
+	if bundleID == "" {
+		writeError(w, "Bundle ID is required", http.StatusBadRequest)
+		return
 	}
-
-	// Add tracking status to search results
-	type SearchResult struct {
-		*models.AppInfo
-		IsTracked bool `json:"is_tracked"`
+	if err := validateBundleID(bundleID); err != nil {
+		writeError(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if resource != "snapshots" {
+		writeError(w, "Not found", http.StatusNotFound)
+		return
 	}
 
-	results := make([]SearchResult, len(apps))
-	for i, app := range apps {
-		results[i] = SearchResult{
-			AppInfo:   app,
-			IsTracked: trackedMap[app.BundleID],
-		}
+	snapshots, err := s.tracker.GetAppSnapshots(bundleID)
+	if errors.Is(err, tracker.ErrNotTracked) {
+		writeErrorCode(w, codeAppNotFound, fmt.Sprintf("App not tracked: %s", bundleID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get snapshots: %v", err), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	json.NewEncoder(w).Encode(results)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		bundleIDField: bundleID,
+		"snapshots":   snapshots,
+	})
 }
 
-// handleTrack adds or removes an app from tracking
-func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
-		http.Error(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+// handleCompliance compares installed app versions from Jamf Pro against the latest tracked versions
+func (s *Server) handleCompliance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
-	var req struct {
-		BundleID string `json:"bundle_id"`
+	if s.jamfClient == nil {
+		w.Header().Set(contentTypeHeader, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"enabled": false,
+		})
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if req.BundleID == "" {
-		http.Error(w, "bundle_id is required", http.StatusBadRequest)
+	installed, err := s.jamfClient.FetchMobileDeviceApps()
+	if err != nil {
+		writeError(w, fmt.Sprintf("Failed to get Jamf inventory: %v", err), http.StatusBadGateway)
 		return
 	}
 
-	// Handle DELETE request
-	if r.Method == http.MethodDelete {
-		if err := s.tracker.RemoveApp(req.BundleID); err != nil {
-			http.Error(w, fmt.Sprintf("Failed to remove app: %v", err), http.StatusInternalServerError)
-			return
-		}
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":      true,
+		"generated_at": time.Now(),
+		"apps":         jamf.BuildComplianceReport(apps, installed),
+	})
+}
 
-		log.Printf("Removed app from tracking via API: %s", sanitizeForLog(req.BundleID))
+// handleCompatibility lists tracked apps that some devices in the configured
+// fleet profile can't install or update
+func (s *Server) handleCompatibility(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
 
+	profile := s.tracker.FleetProfile()
+	if profile.IsZero() {
 		w.Header().Set(contentTypeHeader, contentTypeJSON)
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":     true,
-			bundleIDField: req.BundleID,
-			"message":     "App successfully removed from tracking",
+			"enabled": false,
 		})
 		return
 	}
 
-	// Handle POST request (add app)
-	if err := s.tracker.TrackApp(req.BundleID); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to track app: %v", err), http.StatusInternalServerError)
+	risks, err := s.tracker.GetCompatibilityRisks()
+	if err != nil {
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get compatibility risks: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Added app to tracking via API: %s", sanitizeForLog(req.BundleID))
-
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success":     true,
-		bundleIDField: req.BundleID,
-		"message":     "App successfully added to tracking",
+		"enabled": true,
+		"profile": profile,
+		"risks":   risks,
 	})
 }
 
-// handleHistory returns version history for a specific app
-func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+// handleWebhooks serves the outbound webhook delivery log at
+// /api/webhooks/deliveries and redelivers a logged delivery on
+// POST /api/webhooks/{id}/redeliver
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/webhooks/")
+	if path == "deliveries" {
+		s.handleWebhookDeliveries(w, r)
 		return
 	}
 
-	bundleID := r.URL.Query().Get("bundle_id")
-	if bundleID == "" {
-		http.Error(w, "Query parameter 'bundle_id' is required", http.StatusBadRequest)
+	id, ok := strings.CutSuffix(path, "/redeliver")
+	if !ok || id == "" || strings.Contains(id, "/") {
+		writeError(w, "Not found", http.StatusNotFound)
 		return
 	}
+	s.handleRedeliverWebhook(w, r, id)
+}
 
-	history, err := s.tracker.GetVersionHistory(bundleID)
+// handleWebhookDeliveries returns the logged webhook deliveries, newest first
+func (s *Server) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	deliveries, err := s.tracker.GetWebhookDeliveries()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get version history: %v", err), http.StatusInternalServerError)
+		writeErrorCode(w, codeStorageError, fmt.Sprintf("Failed to get webhook deliveries: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
-	json.NewEncoder(w).Encode(history)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":    s.tracker.WebhookEnabled(),
+		"deliveries": deliveries,
+	})
 }
 
-// handleLastUpdate returns the timestamp of the most recent update
-func (s *Server) handleLastUpdate(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+// handleRedeliverWebhook sends a logged delivery's payload again and returns
+// the new delivery. A failed redelivery is returned with 502 Bad Gateway.
+func (s *Server) handleRedeliverWebhook(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Get all apps to check their updates
-	apps, err := s.tracker.GetTrackedApps()
+	if !s.tracker.WebhookEnabled() {
+		writeError(w, "No outbound webhook is configured", http.StatusConflict)
+		return
+	}
+
+	delivery, err := s.tracker.RedeliverWebhook(id)
+	if errors.Is(err, storage.ErrDeliveryNotFound) {
+		writeError(w, "Webhook delivery not found", http.StatusNotFound)
+		return
+	}
+	if delivery == nil {
+		writeError(w, fmt.Sprintf("Failed to redeliver webhook: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get apps: %v", err), http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// handleImport bulk-tracks apps from an uploaded MDM/CSV export. The CSV is read
+// from a multipart "file" field or the raw request body; dry_run=true previews only.
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
 		return
 	}
 
-	var latestUpdate time.Time
+	r.Body = http.MaxBytesReader(w, r.Body, 5<<20)
 
-	// Find the most recent update across all apps
-	for _, app := range apps {
-		history, err := s.tracker.GetVersionHistory(app.BundleID)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get(contentTypeHeader), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
 		if err != nil {
-			continue
+			writeError(w, fmt.Sprintf("Invalid upload: %v", err), http.StatusBadRequest)
+			return
 		}
+		defer file.Close()
+		body = file
+	}
 
-		for _, update := range history {
-			if update.UpdatedAt.After(latestUpdate) {
-				latestUpdate = update.UpdatedAt
-			}
-		}
+	entries, err := importer.ParseCSV(body)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Invalid CSV: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	results, err := importer.Run(s.tracker, entries, dryRun)
+	if err != nil {
+		writeError(w, fmt.Sprintf("Import failed: %v", err), http.StatusInternalServerError)
+		return
 	}
 
+	log.Printf("Imported %d CSV rows via API (dry run: %t)", len(entries), dryRun)
+
 	w.Header().Set(contentTypeHeader, contentTypeJSON)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"last_update":   latestUpdate,
-		"tracked_apps":  len(apps),
-		"has_updates":   !latestUpdate.IsZero(),
+		"dry_run": dryRun,
+		"results": results,
 	})
 }
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// formatShortcuts asks /api/updates, /api/apps and /api/status for responses
+// suited to the iOS Shortcuts "Get Contents of URL" action
+const formatShortcuts = "shortcuts"
+
+// maxShortcutsNotes bounds the release notes in a Shortcuts update, which
+// is shown on a phone
+const maxShortcutsNotes = 280
+
+// shortcutsList is a Shortcuts list response. Summary reads well as is, e.g.
+// in a "Show Result" action, and Items are flat dictionaries of text for
+// "Repeat with Each" and "Get Dictionary Value".
+type shortcutsList struct {
+	Summary string      `json:"summary"`
+	Count   int         `json:"count"`
+	Items   interface{} `json:"items"`
+}
+
+// shortcutsUpdate is a version update for Shortcuts
+type shortcutsUpdate struct {
+	Text            string `json:"text"`
+	App             string `json:"app"`
+	BundleID        string `json:"bundle_id"`
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version"`
+	Severity        string `json:"severity"`
+	Date            string `json:"date"`
+	Notes           string `json:"notes"`
+}
+
+// shortcutsApp is a tracked app for Shortcuts
+type shortcutsApp struct {
+	Text      string `json:"text"`
+	Name      string `json:"name"`
+	BundleID  string `json:"bundle_id"`
+	Version   string `json:"version"`
+	Developer string `json:"developer"`
+	Released  string `json:"released"`
+}
+
+// shortcutsStatus is the check status for Shortcuts
+type shortcutsStatus struct {
+	Summary   string `json:"summary"`
+	Running   bool   `json:"running"`
+	LastCheck string `json:"last_check"`
+	NextCheck string `json:"next_check"`
+	Failing   int    `json:"failing_apps"`
+}
+
+// shortcutsFormat reports whether the request asks for the Shortcuts format
+// with ?format=, the only other format being the default
+func shortcutsFormat(r *http.Request) (bool, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+		return false, nil
+	case formatShortcuts:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%s (must be %s)", format, formatShortcuts)
+	}
+}
+
+// writeShortcutsUpdates writes updates, newest first, for Shortcuts
+func (s *Server) writeShortcutsUpdates(w http.ResponseWriter, updates []models.VersionUpdate, period string) {
+	list := shortcutsList{Count: len(updates)}
+	if len(updates) == 0 {
+		list.Summary = s.uiText("shortcuts.noUpdates", period)
+	} else {
+		list.Summary = s.uiText("shortcuts.updates", strconv.Itoa(len(updates)), period)
+	}
+	items := []shortcutsUpdate{}
+	for i := range updates {
+		update := &updates[i]
+		items = append(items, shortcutsUpdate{
+			Text:            update.Name() + " " + update.Change(),
+			App:             update.Name(),
+			BundleID:        update.BundleID,
+			Version:         update.NewVersion,
+			PreviousVersion: update.OldVersion,
+			Severity:        update.Severity,
+			Date:            s.basicDate(update.UpdatedAt),
+			Notes:           truncateNotes(update.ReleaseNotes, maxShortcutsNotes),
+		})
+	}
+	list.Items = items
+	writeShortcuts(w, list)
+}
+
+// writeShortcutsApps writes tracked apps for Shortcuts
+func (s *Server) writeShortcutsApps(w http.ResponseWriter, apps []*models.AppInfo) {
+	items := []shortcutsApp{}
+	for _, app := range apps {
+		items = append(items, shortcutsApp{
+			Text:      app.Name() + " " + app.Version,
+			Name:      app.Name(),
+			BundleID:  app.BundleID,
+			Version:   app.Version,
+			Developer: app.ArtistName,
+			Released:  s.basicDate(app.ReleaseDate),
+		})
+	}
+	writeShortcuts(w, shortcutsList{
+		Summary: s.uiText("shortcuts.apps", strconv.Itoa(len(apps))),
+		Count:   len(apps),
+		Items:   items,
+	})
+}
+
+// writeShortcutsStatus writes the check status for Shortcuts
+func (s *Server) writeShortcutsStatus(w http.ResponseWriter, progress tracker.CheckProgress, state *storage.SchedulerState) {
+	status := shortcutsStatus{
+		Running:   progress.Running,
+		LastCheck: "–",
+		NextCheck: "–",
+		Failing:   len(state.AppFailures),
+	}
+	if !state.LastCycleEnd.IsZero() {
+		status.LastCheck = s.shortcutsTime(state.LastCycleEnd)
+		if !progress.Running {
+			status.NextCheck = s.shortcutsTime(state.LastCycleEnd.Add(s.checkInterval))
+		}
+	}
+	if progress.Running {
+		status.Summary = s.uiText("shortcuts.checking", strconv.Itoa(progress.Checked), strconv.Itoa(progress.Total))
+	} else {
+		status.Summary = s.uiText("shortcuts.idle", status.LastCheck, status.NextCheck)
+	}
+	writeShortcuts(w, status)
+}
+
+// shortcutsTime formats a time for Shortcuts in the server's time zone
+func (s *Server) shortcutsTime(t time.Time) string {
+	return s.messages.Date(t) + " " + t.Local().Format("15:04")
+}
+
+// truncateNotes shortens release notes to at most max characters, ending
+// them with an ellipsis if cut
+func truncateNotes(notes string, max int) string {
+	runes := []rune(strings.TrimSpace(notes))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
+
+// writeShortcuts writes a Shortcuts response
+func writeShortcuts(w http.ResponseWriter, v interface{}) {
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(v)
+}
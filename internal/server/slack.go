@@ -0,0 +1,209 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/internal/recovery"
+	"github.com/thomas/mavt/internal/tracker"
+)
+
+// slackMaxRequestAge rejects replayed Slack requests older than this
+const slackMaxRequestAge = 5 * time.Minute
+
+// slackResponse is a slash command reply; "ephemeral" is only shown to the caller
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// handleSlackCommand handles the /mavt slash command:
+//
+//	/mavt track <bundle-id>
+//	/mavt recent [duration]
+//	/mavt list
+func (s *Server) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.slackSigningSecret == "" {
+		writeError(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := verifySlackSignature(s.slackSigningSecret, r.Header, body); err != nil {
+		log.Printf("Rejected Slack request: %v", err)
+		writeError(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	fields := strings.Fields(form.Get("text"))
+	subcommand := ""
+	if len(fields) > 0 {
+		subcommand = strings.ToLower(fields[0])
+	}
+
+	var reply slackResponse
+	switch subcommand {
+	case "track":
+		if len(fields) < 2 {
+			reply = slackResponse{ResponseType: "ephemeral", Text: "Usage: /mavt track <bundle-id>"}
+			break
+		}
+		// Slack expects a reply within 3 seconds, so track in the background
+		// and post the outcome to the response URL
+		go s.slackTrack(fields[1], form.Get("response_url"))
+		reply = slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Looking up %s...", fields[1])}
+	case "recent":
+		sinceStr := "7d"
+		if len(fields) > 1 {
+			sinceStr = fields[1]
+		}
+		reply = s.slackRecent(sinceStr)
+	case "list":
+		reply = s.slackList()
+	default:
+		reply = slackResponse{
+			ResponseType: "ephemeral",
+			Text:         "Usage:\n• `/mavt track <bundle-id>` - start tracking an app\n• `/mavt recent [7d]` - show recent updates\n• `/mavt list` - list tracked apps",
+		}
+	}
+
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+	json.NewEncoder(w).Encode(reply)
+}
+
+// verifySlackSignature checks the X-Slack-Signature header against the signing secret
+func verifySlackSignature(secret string, header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid timestamp")
+	}
+
+	if age := time.Since(time.Unix(ts, 0)); age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return fmt.Errorf("request timestamp too old")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// slackTrack tracks an app and posts the result to the slash command's response URL
+func (s *Server) slackTrack(bundleID, responseURL string) {
+	defer recovery.Recover("slack track")
+
+	reply := slackResponse{ResponseType: "in_channel"}
+	if err := s.tracker.TrackApp(bundleID); errors.Is(err, tracker.ErrAlreadyTracked) {
+		reply.ResponseType = "ephemeral"
+		reply.Text = fmt.Sprintf("%s is already tracked", bundleID)
+	} else if err != nil {
+		reply.ResponseType = "ephemeral"
+		reply.Text = fmt.Sprintf("Failed to track %s: %v", bundleID, err)
+	} else {
+		log.Printf("Added app to tracking via Slack: %s", sanitizeForLog(bundleID))
+		reply.Text = fmt.Sprintf("Now tracking %s", bundleID)
+	}
+
+	if responseURL == "" {
+		return
+	}
+
+	payload, _ := json.Marshal(reply)
+	resp, err := httpclient.New(10*time.Second).Post(responseURL, contentTypeJSON, bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("Failed to post Slack response: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// slackRecent lists updates within the given duration
+func (s *Server) slackRecent(sinceStr string) slackResponse {
+	since, err := parseSince(sinceStr)
+	if err != nil {
+		return slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Invalid duration %q (e.g. 24h, 7d, up to %s)", sinceStr, maxSinceStr)}
+	}
+
+	updates, err := s.tracker.GetRecentUpdates(since)
+	if err != nil {
+		return slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Failed to get updates: %v", err)}
+	}
+
+	if len(updates) == 0 {
+		return slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("No updates in the last %s", sinceStr)}
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].UpdatedAt.After(updates[j].UpdatedAt)
+	})
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Updates in the last %s:\n", sinceStr)
+	for _, update := range updates {
+		fmt.Fprintf(&text, "• *%s*: %s (%s)\n",
+			update.Name(), update.Change(), update.UpdatedAt.Format("2006-01-02"))
+	}
+
+	return slackResponse{ResponseType: "ephemeral", Text: text.String()}
+}
+
+// slackList lists tracked apps and their current versions
+func (s *Server) slackList() slackResponse {
+	apps, err := s.tracker.GetTrackedApps()
+	if err != nil {
+		return slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("Failed to get apps: %v", err)}
+	}
+
+	if len(apps) == 0 {
+		return slackResponse{ResponseType: "ephemeral", Text: "No apps are currently being tracked"}
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name() < apps[j].Name()
+	})
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Tracking %d apps:\n", len(apps))
+	for _, app := range apps {
+		fmt.Fprintf(&text, "• *%s* %s (`%s`)\n", app.Name(), app.Version, app.BundleID)
+	}
+
+	return slackResponse{ResponseType: "ephemeral", Text: text.String()}
+}
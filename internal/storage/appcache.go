@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// cachedApp is a parsed app file, valid while the file's modification time
+// and size are unchanged
+type cachedApp struct {
+	modTime time.Time
+	size    int64
+	app     models.AppInfo
+}
+
+// readApp returns the app stored at path, parsing the file only if it changed
+// since it was last read. Checking the file itself keeps the cache correct
+// when another process writes to the same data directory. Callers must hold s.mu.
+func (s *Storage) readApp(path string) (*models.AppInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	cached, ok := s.appCache[path]
+	s.cacheMu.Unlock()
+
+	if !ok || !cached.modTime.Equal(info.ModTime()) || cached.size != info.Size() {
+		data, err := s.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = cachedApp{modTime: info.ModTime(), size: info.Size()}
+		if err := json.Unmarshal(data, &cached.app); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal app data: %w", err)
+		}
+
+		s.cacheMu.Lock()
+		s.appCache[path] = cached
+		s.cacheMu.Unlock()
+	}
+
+	return cloneApp(&cached.app), nil
+}
+
+// cloneApp copies an app deeply, so callers can't change the cached app
+// through its pointers and slices
+func cloneApp(app *models.AppInfo) *models.AppInfo {
+	clone := *app
+	clone.SupportedDevices = slices.Clone(app.SupportedDevices)
+	clone.LanguageCodes = slices.Clone(app.LanguageCodes)
+	clone.InAppPurchases = slices.Clone(app.InAppPurchases)
+	if app.Rating != nil {
+		rating := *app.Rating
+		clone.Rating = &rating
+	}
+	if app.Beta != nil {
+		beta := *app.Beta
+		clone.Beta = &beta
+	}
+	if app.ArchivedAt != nil {
+		archivedAt := *app.ArchivedAt
+		clone.ArchivedAt = &archivedAt
+	}
+	return &clone
+}
+
+// forgetApp drops an app file from the cache after it is written or removed
+func (s *Storage) forgetApp(path string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	delete(s.appCache, path)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestLoadAppReturnsCopyOfCachedApp(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivedAt := time.Now().Add(-time.Hour).UTC()
+	app := &models.AppInfo{
+		BundleID:         "com.x",
+		Version:          "1.0",
+		SupportedDevices: []string{"iPhone"},
+		LanguageCodes:    []string{"EN"},
+		InAppPurchases:   []models.InAppPurchase{{Name: "Pro", Price: "$4.99"}},
+		Rating:           &models.RatingSnapshot{AverageRating: 4.5, RatingCount: 10},
+		TestFlightURL:    "https://testflight.apple.com/join/abc",
+		Beta:             &models.BetaStatus{State: "open"},
+		ArchivedAt:       &archivedAt,
+	}
+	if err := s.SaveApp(app); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := s.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.SupportedDevices[0] = "iPad"
+	loaded.LanguageCodes[0] = "DE"
+	loaded.InAppPurchases[0].Price = "$0.99"
+	loaded.Rating.AverageRating = 1
+	loaded.Beta.State = "full"
+	*loaded.ArchivedAt = time.Time{}
+
+	again, err := s.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.SupportedDevices[0] != "iPhone" || again.LanguageCodes[0] != "EN" {
+		t.Errorf("cached slices changed: %v %v", again.SupportedDevices, again.LanguageCodes)
+	}
+	if again.InAppPurchases[0].Price != "$4.99" {
+		t.Errorf("cached in-app purchase changed: %+v", again.InAppPurchases[0])
+	}
+	if again.Rating.AverageRating != 4.5 {
+		t.Errorf("cached rating changed: %+v", again.Rating)
+	}
+	if again.Beta.State != "open" {
+		t.Errorf("cached beta changed: %+v", again.Beta)
+	}
+	if !again.ArchivedAt.Equal(archivedAt) {
+		t.Errorf("cached archive time changed: %v", again.ArchivedAt)
+	}
+}
+
+func TestLoadAppSeesWritesFromAnotherInstance(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveApp(&models.AppInfo{BundleID: "com.x", Version: "1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if app, err := b.LoadApp("com.x"); err != nil || app.Version != "1.0" {
+		t.Fatalf("loaded %+v, %v", app, err)
+	}
+
+	if err := a.SaveApp(&models.AppInfo{BundleID: "com.x", Version: "1.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	app, err := b.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Version != "1.0.1" {
+		t.Errorf("version = %s, want the other instance's 1.0.1", app.Version)
+	}
+}
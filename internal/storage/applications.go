@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// applicationsFile holds the user's applications, keyed by ID
+const applicationsFile = "applications.json"
+
+// GetApplications returns all applications, sorted by name
+func (s *Storage) GetApplications() ([]models.Application, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	applications, err := s.loadApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Application, 0, len(applications))
+	for _, application := range applications {
+		list = append(list, application)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// SaveApplication records an application, replacing any with the same ID
+func (s *Storage) SaveApplication(application *models.Application) error {
+	return s.updateApplications(func(applications map[string]models.Application) {
+		applications[application.ID] = *application
+	})
+}
+
+// DeleteApplication removes an application; its apps stay tracked
+func (s *Storage) DeleteApplication(id string) error {
+	return s.updateApplications(func(applications map[string]models.Application) {
+		delete(applications, id)
+	})
+}
+
+// updateApplications applies change to the stored applications and saves them
+func (s *Storage) updateApplications(change func(map[string]models.Application)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applications, err := s.loadApplications()
+	if err != nil {
+		return err
+	}
+	change(applications)
+
+	data, err := json.MarshalIndent(applications, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal applications: %w", err)
+	}
+	if err := s.writeFile(filepath.Join(s.dataDir, applicationsFile), data); err != nil {
+		return fmt.Errorf("failed to write applications: %w", err)
+	}
+	return nil
+}
+
+// loadApplications reads the applications file; the caller must hold s.mu
+func (s *Storage) loadApplications() (map[string]models.Application, error) {
+	applications := make(map[string]models.Application)
+	data, err := s.readFile(filepath.Join(s.dataDir, applicationsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return applications, nil
+		}
+		return nil, fmt.Errorf("failed to read applications: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &applications); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal applications: %w", err)
+	}
+	return applications, nil
+}
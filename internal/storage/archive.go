@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rawArchiveDir is the directory under the data directory holding raw API responses
+const rawArchiveDir = "raw"
+
+// ArchiveRawResponse stores a gzip-compressed copy of a raw App Store API response
+// at data/raw/{bundleID}/{timestamp}.json.gz
+func (s *Storage) ArchiveRawResponse(bundleID string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.dataDir, rawArchiveDir, bundleID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create raw archive directory: %w", err)
+	}
+
+	name := time.Now().UTC().Format("20060102T150405.000000000Z") + ".json.gz"
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create raw archive file: %w", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	if _, err := gz.Write(body); err != nil {
+		return fmt.Errorf("failed to write raw archive file: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write raw archive file: %w", err)
+	}
+
+	return nil
+}
+
+// PruneRawResponses deletes archived raw responses older than the retention period
+// and returns the number of files removed
+func (s *Storage) PruneRawResponses(retention time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root := filepath.Join(s.dataDir, rawArchiveDir)
+	cutoff := time.Now().Add(-retention)
+	removed := 0
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json.gz") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to prune raw archive: %w", err)
+	}
+
+	return removed, nil
+}
+
+// ReadRawResponse reads and decompresses an archived raw response file
+func ReadRawResponse(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw archive file: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress raw archive file: %w", err)
+	}
+	defer gz.Close()
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raw archive file: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// changesFile holds the sync journal
+const changesFile = "changes.json"
+
+// maxChanges is how many journal entries are kept; older ones are dropped,
+// and clients whose cursor predates the oldest kept entry resync from scratch
+const maxChanges = 5000
+
+// changeJournal is the stored sync journal. NextSeq is kept separately so
+// sequence numbers keep increasing after old entries are dropped.
+type changeJournal struct {
+	NextSeq int64               `json:"next_seq"`
+	Changes []models.SyncChange `json:"changes"`
+}
+
+// ChangeRange is the span of sequence numbers in the sync journal. Oldest is
+// the first entry still kept and Latest the last one assigned; Oldest is
+// Latest+1 when the journal is empty.
+type ChangeRange struct {
+	Oldest int64
+	Latest int64
+}
+
+// AppendChanges adds changes to the sync journal, assigning their sequence
+// numbers, and drops the oldest entries beyond maxChanges
+func (s *Storage) AppendChanges(changes []models.SyncChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	journal, err := s.readChanges()
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		change.Seq = journal.NextSeq
+		journal.NextSeq++
+		journal.Changes = append(journal.Changes, change)
+	}
+	if len(journal.Changes) > maxChanges {
+		journal.Changes = journal.Changes[len(journal.Changes)-maxChanges:]
+	}
+
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+	if err := s.writeFile(filepath.Join(s.dataDir, changesFile), data); err != nil {
+		return fmt.Errorf("failed to write changes: %w", err)
+	}
+	return nil
+}
+
+// GetChanges returns up to limit journal entries after sequence number
+// after, oldest first, and the range of sequence numbers still kept
+func (s *Storage) GetChanges(after int64, limit int) ([]models.SyncChange, ChangeRange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	journal, err := s.readChanges()
+	if err != nil {
+		return nil, ChangeRange{}, err
+	}
+
+	span := ChangeRange{Oldest: journal.NextSeq, Latest: journal.NextSeq - 1}
+	if len(journal.Changes) > 0 {
+		span.Oldest = journal.Changes[0].Seq
+	}
+
+	var changes []models.SyncChange
+	for _, change := range journal.Changes {
+		if change.Seq <= after {
+			continue
+		}
+		if len(changes) == limit {
+			break
+		}
+		changes = append(changes, change)
+	}
+	return changes, span, nil
+}
+
+// readChanges reads the sync journal; the caller must hold s.mu
+func (s *Storage) readChanges() (*changeJournal, error) {
+	journal := &changeJournal{NextSeq: 1}
+	data, err := s.readFile(filepath.Join(s.dataDir, changesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return journal, nil
+		}
+		return nil, fmt.Errorf("failed to read changes: %w", err)
+	}
+
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal changes: %w", err)
+	}
+	return journal, nil
+}
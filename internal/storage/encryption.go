@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encryptedMagic prefixes every encrypted record so encrypted and plaintext
+// files can be told apart. Plaintext files are still read when a key is set,
+// and are encrypted the next time they are written.
+var encryptedMagic = []byte("MAVTENC1")
+
+// ErrEncryptionKeyRequired is returned when reading an encrypted record without a key
+var ErrEncryptionKeyRequired = errors.New("record is encrypted but no encryption key is configured")
+
+// Open creates a storage instance for dataDir, encrypting records with the key
+// in keyFile if one is given
+func Open(dataDir, keyFile string) (*Storage, error) {
+	if keyFile == "" {
+		return NewStorage(dataDir)
+	}
+
+	key, err := LoadKeyFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedStorage(dataDir, key)
+}
+
+// LoadKeyFile reads a 256-bit AES key from a file containing 32 raw bytes,
+// 64 hex characters or base64-encoded bytes
+func LoadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+
+	if len(data) == 32 {
+		return data, nil
+	}
+
+	text := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == 32 {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("encryption key must be 32 bytes (raw, hex or base64), e.g. from: openssl rand -hex 32")
+}
+
+// newCipher creates an AES-GCM cipher for a 256-bit key
+func newCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// readFile reads a record, decrypting it if it is encrypted
+func (s *Storage) readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(data, encryptedMagic) {
+		return data, nil
+	}
+	if s.aead == nil {
+		return nil, ErrEncryptionKeyRequired
+	}
+
+	data = data[len(encryptedMagic):]
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, fmt.Errorf("encrypted record is truncated")
+	}
+
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt record (wrong key?): %w", err)
+	}
+	return plaintext, nil
+}
+
+// writeFile writes a record, encrypting it if an encryption key is configured
+func (s *Storage) writeFile(path string, data []byte) error {
+	if s.aead == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := append([]byte{}, encryptedMagic...)
+	out = append(out, nonce...)
+	out = s.aead.Seal(out, nonce, data, nil)
+
+	return os.WriteFile(path, out, 0600)
+}
+
+// RewriteRecords rewrites every stored record with the current encryption
+// setting, encrypting existing plaintext records after a key is configured,
+// and returns the number of files written
+func (s *Storage) RewriteRecords() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := s.recordPaths()
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for _, path := range paths {
+		data, err := s.readFile(path)
+		if err != nil {
+			return written, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := s.writeFile(path, data); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+// recordPaths lists every record file in the data directory: each .json and
+// .json.gz file except the schema stamp and leases, which are read without
+// the key, and raw App Store responses, which are never encrypted. The
+// caller must hold s.mu.
+func (s *Storage) recordPaths() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(s.dataDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dataDir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel == "leases" || rel == rawArchiveDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == schemaFile || !(strings.HasSuffix(rel, ".json") || strings.HasSuffix(rel, ".json.gz")) {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored records: %w", err)
+	}
+	return paths, nil
+}
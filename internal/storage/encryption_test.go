@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewEncryptedStorage(dir, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveApp(&models.AppInfo{BundleID: "com.x", TrackName: "Secret", Version: "1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "apps", "com.x.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, encryptedMagic) || bytes.Contains(data, []byte("Secret")) {
+		t.Errorf("app record is stored in plaintext: %q", data)
+	}
+
+	reopened, err := NewEncryptedStorage(dir, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := reopened.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app == nil || app.TrackName != "Secret" {
+		t.Errorf("loaded %+v, want the saved app", app)
+	}
+
+	plain, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.LoadApp("com.x"); !errors.Is(err, ErrEncryptionKeyRequired) {
+		t.Errorf("loading without a key: got %v, want ErrEncryptionKeyRequired", err)
+	}
+
+	wrong, err := NewEncryptedStorage(dir, testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.LoadApp("com.x"); err == nil {
+		t.Error("loading with the wrong key succeeded")
+	}
+}
+
+func TestRewriteRecordsEncryptsPlaintext(t *testing.T) {
+	dir := t.TempDir()
+	plain, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.SaveApp(&models.AppInfo{BundleID: "com.x", Version: "1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.SaveVersionUpdate(&models.VersionUpdate{BundleID: "com.x", OldVersion: "0.9", NewVersion: "1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewEncryptedStorage(dir, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := s.RewriteRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written < 2 {
+		t.Errorf("rewrote %d records, want at least the app and its updates", written)
+	}
+
+	for _, rel := range []string{"apps/com.x.json", "updates/com.x.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(data, encryptedMagic) {
+			t.Errorf("%s was not encrypted", rel)
+		}
+	}
+	schema, err := os.ReadFile(filepath.Join(dir, schemaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.HasPrefix(schema, encryptedMagic) {
+		t.Error("schema stamp was encrypted")
+	}
+
+	updates, err := s.GetVersionUpdates("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].NewVersion != "1.0" {
+		t.Errorf("updates after rewrite = %+v", updates)
+	}
+}
@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// archivedUpdatesSuffix names the gzip file holding an app's version updates
+// older than the compression window, next to its plain updates file
+const archivedUpdatesSuffix = ".history.json.gz"
+
+// SetHistoryCompression enables compressing version updates older than
+// olderThan into a gzip file per app when CompactHistory runs. Zero disables
+// compaction; existing compressed history is always readable.
+func (s *Storage) SetHistoryCompression(olderThan time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compressAfter = olderThan
+}
+
+// CompactHistory moves version updates older than the compression window
+// from each app's updates file into its compressed history file and returns
+// the number of updates moved
+func (s *Storage) CompactHistory() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.compressAfter <= 0 {
+		return 0, nil
+	}
+
+	updatesDir := filepath.Join(s.dataDir, "updates")
+	entries, err := os.ReadDir(updatesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read updates directory: %w", err)
+	}
+
+	cutoff := time.Now().Add(-s.compressAfter)
+	moved := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		bundleID := strings.TrimSuffix(entry.Name(), ".json")
+
+		updatesFile := filepath.Join(updatesDir, entry.Name())
+		recent, err := s.readUpdatesFile(updatesFile)
+		if err != nil {
+			return moved, err
+		}
+
+		var keep, old []models.VersionUpdate
+		for _, update := range recent {
+			if update.UpdatedAt.Before(cutoff) {
+				old = append(old, update)
+			} else {
+				keep = append(keep, update)
+			}
+		}
+		if len(old) == 0 {
+			continue
+		}
+
+		archived, err := s.readArchivedUpdates(bundleID)
+		if err != nil {
+			return moved, err
+		}
+		if err := s.writeArchivedUpdates(bundleID, append(archived, old...)); err != nil {
+			return moved, err
+		}
+
+		// Keep the (possibly empty) updates file so the app's history is still listed
+		if keep == nil {
+			keep = []models.VersionUpdate{}
+		}
+		data, err := json.MarshalIndent(keep, "", "  ")
+		if err != nil {
+			return moved, fmt.Errorf("failed to marshal updates: %w", err)
+		}
+		if err := s.writeFile(updatesFile, data); err != nil {
+			return moved, fmt.Errorf("failed to write updates file: %w", err)
+		}
+
+		moved += len(old)
+	}
+
+	return moved, nil
+}
+
+// readAllUpdates returns an app's compressed and recent version updates,
+// oldest first. Callers must hold s.mu.
+func (s *Storage) readAllUpdates(bundleID string) ([]models.VersionUpdate, error) {
+	archived, err := s.readArchivedUpdates(bundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.readUpdatesFile(filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", bundleID)))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(archived, recent...), nil
+}
+
+// readUpdatesFile reads a plain updates file, returning nil if it doesn't exist
+func (s *Storage) readUpdatesFile(path string) ([]models.VersionUpdate, error) {
+	data, err := s.readFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read updates file: %w", err)
+	}
+
+	var updates []models.VersionUpdate
+	if err := json.Unmarshal(data, &updates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal updates: %w", err)
+	}
+	assignUpdateIDs(updates)
+	return updates, nil
+}
+
+// readArchivedUpdates reads an app's compressed history, returning nil if there is none
+func (s *Storage) readArchivedUpdates(bundleID string) ([]models.VersionUpdate, error) {
+	data, err := s.readFile(s.archivedUpdatesPath(bundleID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read compressed history: %w", err)
+	}
+
+	raw, err := gunzip(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress history: %w", err)
+	}
+
+	var updates []models.VersionUpdate
+	if err := json.Unmarshal(raw, &updates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal compressed history: %w", err)
+	}
+	assignUpdateIDs(updates)
+	return updates, nil
+}
+
+// assignUpdateIDs gives updates stored before IDs were introduced the ID they
+// would have been stored with
+func assignUpdateIDs(updates []models.VersionUpdate) {
+	for i := range updates {
+		if updates[i].ID == "" {
+			updates[i].ID = models.UpdateID(updates[i].BundleID, updates[i].NewVersion, updates[i].UpdatedAt)
+		}
+	}
+}
+
+// writeArchivedUpdates replaces an app's compressed history, sorted oldest first
+func (s *Storage) writeArchivedUpdates(bundleID string, updates []models.VersionUpdate) error {
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].UpdatedAt.Before(updates[j].UpdatedAt)
+	})
+
+	raw, err := json.Marshal(updates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updates: %w", err)
+	}
+
+	data, err := gzipData(raw)
+	if err != nil {
+		return fmt.Errorf("failed to compress history: %w", err)
+	}
+
+	if err := s.writeFile(s.archivedUpdatesPath(bundleID), data); err != nil {
+		return fmt.Errorf("failed to write compressed history: %w", err)
+	}
+	return nil
+}
+
+// gunzip decompresses gzip data
+func gunzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return io.ReadAll(gz)
+}
+
+// gzipData compresses data with gzip
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Storage) archivedUpdatesPath(bundleID string) string {
+	return filepath.Join(s.dataDir, "updates", bundleID+archivedUpdatesSuffix)
+}
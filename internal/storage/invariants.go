@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// ErrInvalidBundleID is returned when saving an app whose bundle ID can't
+// identify its record
+var ErrInvalidBundleID = errors.New("invalid bundle ID")
+
+// CheckAppInvariants enforces what must hold across saves of an app, given
+// its stored record (nil for a new app), fixing app where it can:
+//   - BundleID must be set and usable as a file name; it identifies the
+//     record, so an app is never renamed by saving it
+//   - FirstDiscovered is set once, defaulting to when the app is first
+//     saved; it can only move earlier, e.g. when merging another app's
+//     history into it
+//   - LastChecked never goes backwards
+//
+// Fixed writes are logged so the code path making them can be found.
+func CheckAppInvariants(stored, app *models.AppInfo) error {
+	if err := validateBundleID(app.BundleID); err != nil {
+		return err
+	}
+
+	if stored == nil {
+		if app.FirstDiscovered.IsZero() {
+			app.FirstDiscovered = app.LastChecked
+			if app.FirstDiscovered.IsZero() {
+				app.FirstDiscovered = time.Now()
+			}
+		}
+		return nil
+	}
+
+	if !stored.FirstDiscovered.IsZero() &&
+		(app.FirstDiscovered.IsZero() || app.FirstDiscovered.After(stored.FirstDiscovered)) {
+		log.Printf("Kept first discovered time of %q at %s instead of %s", app.BundleID,
+			stored.FirstDiscovered.Format(time.RFC3339), formatInvariantTime(app.FirstDiscovered))
+		app.FirstDiscovered = stored.FirstDiscovered
+	}
+	if app.LastChecked.Before(stored.LastChecked) {
+		log.Printf("Kept last checked time of %q at %s instead of %s", app.BundleID,
+			stored.LastChecked.Format(time.RFC3339), formatInvariantTime(app.LastChecked))
+		app.LastChecked = stored.LastChecked
+	}
+	return nil
+}
+
+// validateBundleID checks a bundle ID can name an app's files
+func validateBundleID(bundleID string) error {
+	if bundleID == "" || bundleID == "." || bundleID == ".." || strings.ContainsAny(bundleID, "/\\\x00") {
+		return fmt.Errorf("%w: %q", ErrInvalidBundleID, bundleID)
+	}
+	return nil
+}
+
+// formatInvariantTime formats a time for the invariant log, which also
+// shows unset times
+func formatInvariantTime(t time.Time) string {
+	if t.IsZero() {
+		return "unset"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// lastCheckedFile holds LastChecked times for apps whose metadata didn't
+// change, so a check cycle writes one small file instead of every app file
+const lastCheckedFile = "last_checked.json"
+
+// TouchApp records that an app was checked without rewriting its app file.
+// The time is kept in memory until FlushLastChecked is called, and is applied
+// to the app whenever it is loaded.
+func (s *Storage) TouchApp(bundleID string, checkedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastChecked[bundleID] = checkedAt
+	s.lastCheckedDirty = true
+}
+
+// FlushLastChecked writes pending LastChecked times to disk in a single write
+func (s *Storage) FlushLastChecked() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lastCheckedDirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.lastChecked, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal last checked times: %w", err)
+	}
+
+	if err := s.writeFile(filepath.Join(s.dataDir, lastCheckedFile), data); err != nil {
+		return fmt.Errorf("failed to write last checked file: %w", err)
+	}
+
+	s.lastCheckedDirty = false
+	return nil
+}
+
+// ReloadLastChecked merges LastChecked times flushed by another process
+// sharing the data directory, keeping the newer time for each app
+func (s *Storage) ReloadLastChecked() {
+	loaded := s.loadLastChecked()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for bundleID, checkedAt := range loaded {
+		if checkedAt.After(s.lastChecked[bundleID]) {
+			s.lastChecked[bundleID] = checkedAt
+		}
+	}
+}
+
+// applyLastChecked overlays a recorded LastChecked time newer than the one in
+// the app file. A TestFlight beta is read on every check, so its CheckedAt is
+// overlaid too, on a copy since the beta may be shared with the app cache.
+// Callers must hold s.mu, for reading at least.
+func (s *Storage) applyLastChecked(app *models.AppInfo) {
+	checkedAt, ok := s.lastChecked[app.BundleID]
+	if !ok {
+		return
+	}
+	if checkedAt.After(app.LastChecked) {
+		app.LastChecked = checkedAt
+	}
+	if app.Beta != nil && app.TestFlightURL != "" && checkedAt.After(app.Beta.CheckedAt) {
+		beta := *app.Beta
+		beta.CheckedAt = checkedAt
+		app.Beta = &beta
+	}
+}
+
+// loadLastChecked reads previously flushed LastChecked times
+func (s *Storage) loadLastChecked() map[string]time.Time {
+	lastChecked := make(map[string]time.Time)
+	if data, err := s.readFile(filepath.Join(s.dataDir, lastCheckedFile)); err == nil {
+		json.Unmarshal(data, &lastChecked)
+	}
+	return lastChecked
+}
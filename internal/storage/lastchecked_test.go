@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestLoadAppConcurrentlyAfterTouch(t *testing.T) {
+	s, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checked := time.Now().Add(-time.Hour)
+	app := &models.AppInfo{
+		BundleID:      "com.x",
+		Version:       "1.0",
+		TestFlightURL: "https://testflight.apple.com/join/abc",
+		Beta:          &models.BetaStatus{State: "open", CheckedAt: checked},
+	}
+	if err := s.SaveApp(app); err != nil {
+		t.Fatal(err)
+	}
+
+	touched := time.Now()
+	s.TouchApp("com.x", touched)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loaded, err := s.LoadApp("com.x")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !loaded.Beta.CheckedAt.Equal(touched) {
+				t.Errorf("beta checked at %v, want %v", loaded.Beta.CheckedAt, touched)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.mu.RLock()
+	cached, err := s.readApp(filepath.Join(s.dataDir, "apps", "com.x.json"))
+	s.mu.RUnlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached.Beta.CheckedAt.Equal(checked) {
+		t.Errorf("cached beta checked at %v, want the stored %v", cached.Beta.CheckedAt, checked)
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleLockAge is how old a lease lock file must be before it is assumed to
+// belong to a crashed process and removed
+const staleLockAge = 30 * time.Second
+
+// Lease records which instance holds a named lease and until when
+type Lease struct {
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AcquireLease takes or renews the named lease for holder until ttl from now
+// and reports whether holder has it. It fails while another holder's lease is
+// unexpired. Leases live in the data directory, so replicas sharing it agree
+// on a single holder; a short-lived lock file makes each acquire atomic.
+func (s *Storage) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	unlock, err := s.lockLease(name)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	path := s.leasePath(name)
+	var current Lease
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &current)
+	}
+
+	now := time.Now()
+	if current.Holder != "" && current.Holder != holder && now.Before(current.ExpiresAt) {
+		return false, nil
+	}
+
+	data, err := json.MarshalIndent(Lease{Holder: holder, ExpiresAt: now.Add(ttl)}, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal lease: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write lease: %w", err)
+	}
+	return true, nil
+}
+
+// ReleaseLease gives up the named lease if holder has it, so another instance
+// can take over without waiting for it to expire
+func (s *Storage) ReleaseLease(name, holder string) error {
+	unlock, err := s.lockLease(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := s.leasePath(name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read lease: %w", err)
+	}
+
+	var current Lease
+	if err := json.Unmarshal(data, &current); err != nil || current.Holder != holder {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lease: %w", err)
+	}
+	return nil
+}
+
+// lockLease creates the lease's lock file, waiting briefly if another process
+// holds it, and returns a function that removes it
+func (s *Storage) lockLease(name string) (func(), error) {
+	dir := filepath.Join(s.dataDir, "leases")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create leases directory: %w", err)
+	}
+
+	lockPath := s.leasePath(name) + ".lock"
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock lease: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lease lock %s", name)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (s *Storage) leasePath(name string) string {
+	return filepath.Join(s.dataDir, "leases", name+".json")
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyStats counts the records copied by CopyTo
+type CopyStats struct {
+	// Files is every record file copied, of every store
+	Files     int
+	Apps      int
+	Updates   int
+	Reviews   int
+	Snapshots int
+	Verified  bool
+}
+
+// CopyTo copies every stored record into dst, including applications,
+// vendors, views, watchlists, the change journal, webhook deliveries,
+// scheduler state and archived raw responses, then reads everything back
+// from dst and compares it with the source. dst may use a different
+// encryption key, and must be an empty data directory so no stale records
+// are left beside the copy.
+func (s *Storage) CopyTo(dst *Storage) (*CopyStats, error) {
+	if samePath(s.dataDir, dst.dataDir) {
+		return nil, fmt.Errorf("source and destination data directories are the same")
+	}
+
+	dst.mu.RLock()
+	existing, err := dst.recordPaths()
+	if err == nil {
+		var raw []string
+		raw, err = dst.rawPaths()
+		existing = append(existing, raw...)
+	}
+	dst.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("destination data directory %s already has data; migrate into an empty directory", dst.dataDir)
+	}
+
+	if err := s.FlushLastChecked(); err != nil {
+		return nil, err
+	}
+	stats, err := s.countRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	records, err := s.recordPaths()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := s.rawPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	// Records are copied as they are, re-encrypted for dst; raw responses
+	// are never encrypted and are copied byte for byte
+	for _, path := range records {
+		data, err := s.readFile(path)
+		if err != nil {
+			return stats, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := dst.copyFile(s.relPath(path), data, false); err != nil {
+			return stats, err
+		}
+		stats.Files++
+	}
+	for _, path := range raw {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return stats, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := dst.copyFile(s.relPath(path), data, true); err != nil {
+			return stats, err
+		}
+		stats.Files++
+	}
+
+	// dst loaded its LastChecked times before they were copied
+	dst.lastChecked = dst.loadLastChecked()
+	dst.lastCheckedDirty = false
+
+	if err := s.verifyCopy(dst, records, raw); err != nil {
+		return stats, fmt.Errorf("verification failed: %w", err)
+	}
+	stats.Verified = true
+
+	return stats, nil
+}
+
+// countRecords counts the apps and their updates, reviews and snapshots
+func (s *Storage) countRecords() (*CopyStats, error) {
+	apps, err := s.GetAllApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read apps: %w", err)
+	}
+
+	stats := &CopyStats{Apps: len(apps)}
+	for _, app := range apps {
+		updates, err := s.GetVersionUpdates(app.BundleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history for %s: %w", app.BundleID, err)
+		}
+		reviews, err := s.GetReviews(app.BundleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reviews for %s: %w", app.BundleID, err)
+		}
+		snapshots, err := s.GetAppSnapshots(app.BundleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshots for %s: %w", app.BundleID, err)
+		}
+		stats.Updates += len(updates)
+		stats.Reviews += len(reviews)
+		stats.Snapshots += len(snapshots)
+	}
+	return stats, nil
+}
+
+// copyFile writes a copied file to rel, relative to the data directory,
+// encrypting it if a key is configured unless it is a raw response. The
+// caller must hold s.mu.
+func (s *Storage) copyFile(rel string, data []byte, raw bool) error {
+	target := filepath.Join(s.dataDir, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", rel, err)
+	}
+
+	var err error
+	if raw {
+		err = os.WriteFile(target, data, 0644)
+	} else {
+		err = s.writeFile(target, data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	return nil
+}
+
+// relPath returns a path in the data directory relative to it
+func (s *Storage) relPath(path string) string {
+	rel, err := filepath.Rel(s.dataDir, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return rel
+}
+
+// verifyCopy checks that every record and raw response reads back from dst
+// exactly as it reads from the source. The caller must hold both locks.
+func (s *Storage) verifyCopy(dst *Storage, records, raw []string) error {
+	check := func(path string, read, readDst func(string) ([]byte, error)) error {
+		rel := s.relPath(path)
+		want, err := read(path)
+		if err != nil {
+			return err
+		}
+		got, err := readDst(filepath.Join(dst.dataDir, rel))
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("%s differs after copy", rel)
+		}
+		return nil
+	}
+
+	for _, path := range records {
+		if err := check(path, s.readFile, dst.readFile); err != nil {
+			return err
+		}
+	}
+	for _, path := range raw {
+		if err := check(path, os.ReadFile, os.ReadFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawPaths lists every archived raw App Store response. The caller must
+// hold s.mu.
+func (s *Storage) rawPaths() ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(filepath.Join(s.dataDir, rawArchiveDir), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list raw responses: %w", err)
+	}
+	return paths, nil
+}
+
+// samePath reports whether two paths refer to the same directory
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(a, b)
+	}
+	return absA == absB
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestCopyToReencrypts(t *testing.T) {
+	src, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SaveApp(&models.AppInfo{BundleID: "com.x", Version: "2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SaveVersionUpdate(&models.VersionUpdate{BundleID: "com.x", OldVersion: "1.0", NewVersion: "2.0", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	src.TouchApp("com.x", time.Now())
+
+	dstDir := t.TempDir()
+	dst, err := NewEncryptedStorage(dstDir, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := src.CopyTo(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.Verified || stats.Apps != 1 || stats.Updates != 1 {
+		t.Errorf("stats = %+v, want 1 app and 1 update verified", stats)
+	}
+
+	reopened, err := NewEncryptedStorage(dstDir, testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app, err := reopened.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app == nil || app.Version != "2.0" || app.LastChecked.IsZero() {
+		t.Errorf("copied app = %+v", app)
+	}
+
+	if _, err := src.CopyTo(reopened); err == nil {
+		t.Error("copied into a data directory that already has data")
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// schedulerFile holds the check scheduler's state so a restarted daemon
+// resumes its schedule instead of checking everything at boot
+const schedulerFile = "scheduler.json"
+
+// SchedulerState is the persisted state of the check scheduler
+type SchedulerState struct {
+	LastCycleStart time.Time `json:"last_cycle_start"`
+	LastCycleEnd   time.Time `json:"last_cycle_end"`
+
+	// AppFailures counts consecutive failed checks per bundle ID; apps whose
+	// last check succeeded are omitted
+	AppFailures map[string]int `json:"app_failures,omitempty"`
+
+	// DigestedWindows lists the maintenance windows whose held updates have
+	// been sent as a digest, so each digest is only sent once
+	DigestedWindows []string `json:"digested_windows,omitempty"`
+}
+
+// LoadSchedulerState returns the saved scheduler state, or an empty state if
+// none has been saved yet
+func (s *Storage) LoadSchedulerState() (*SchedulerState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := &SchedulerState{}
+	data, err := s.readFile(filepath.Join(s.dataDir, schedulerFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read scheduler state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduler state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveSchedulerState replaces the saved scheduler state
+func (s *Storage) SaveSchedulerState(state *SchedulerState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduler state: %w", err)
+	}
+
+	if err := s.writeFile(filepath.Join(s.dataDir, schedulerFile), data); err != nil {
+		return fmt.Errorf("failed to write scheduler state: %w", err)
+	}
+	return nil
+}
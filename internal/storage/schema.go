@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// schemaFile records which migrations the data directory has had
+const schemaFile = "schema.json"
+
+// schemaStamp is the stored schema version
+type schemaStamp struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// migration upgrades stored records from the previous schema version. Apply
+// works on the records' raw JSON rather than the current models, so fields a
+// later version renames or drops are still there for its migration, and it
+// must be safe to run again in case it is interrupted or another instance
+// sharing the data directory runs it too.
+type migration struct {
+	Description string
+	Apply       func(s *Storage) error
+}
+
+// migrations are applied in order; migration i upgrades version i to i+1.
+// Append new ones to the end and never reorder or remove them.
+var migrations = []migration{
+	{"Store IDs of updates recorded before update IDs", migrateUpdateIDs},
+	{"Set first discovered time of apps saved without one", migrateFirstDiscovered},
+}
+
+// SchemaVersion is the schema version this build writes
+var SchemaVersion = len(migrations)
+
+// migrate brings the data directory up to SchemaVersion. A new data
+// directory is stamped with it straight away; one from before schema stamps
+// starts at version 0. A data directory from a newer build is refused rather
+// than risk this build mangling records it doesn't understand.
+func (s *Storage) migrate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stamp, err := s.readSchema()
+	if err != nil {
+		return err
+	}
+	if stamp == nil {
+		stamp = &schemaStamp{}
+		if empty, err := s.isEmpty(); err != nil {
+			return err
+		} else if empty {
+			stamp.Version = SchemaVersion
+			return s.writeSchema(stamp)
+		}
+	}
+
+	if stamp.Version > SchemaVersion {
+		return fmt.Errorf("data directory has schema version %d but this version of MAVT supports up to %d; upgrade MAVT", stamp.Version, SchemaVersion)
+	}
+
+	for stamp.Version < SchemaVersion {
+		m := migrations[stamp.Version]
+		log.Printf("Migrating data to schema version %d: %s", stamp.Version+1, m.Description)
+		if err := m.Apply(s); err != nil {
+			return fmt.Errorf("failed to migrate data to schema version %d: %w", stamp.Version+1, err)
+		}
+		stamp.Version++
+		if err := s.writeSchema(stamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchema reads the schema stamp, or nil if there is none; the caller
+// must hold s.mu
+func (s *Storage) readSchema() (*schemaStamp, error) {
+	data, err := os.ReadFile(filepath.Join(s.dataDir, schemaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	var stamp schemaStamp
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema version: %w", err)
+	}
+	return &stamp, nil
+}
+
+// writeSchema saves the schema stamp, unencrypted so it can be read without
+// the key; the caller must hold s.mu
+func (s *Storage) writeSchema(stamp *schemaStamp) error {
+	stamp.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(stamp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema version: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dataDir, schemaFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write schema version: %w", err)
+	}
+	return nil
+}
+
+// isEmpty reports whether the data directory has no app records yet; the
+// caller must hold s.mu
+func (s *Storage) isEmpty() (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, "apps"))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read apps directory: %w", err)
+	}
+	return len(entries) == 0, nil
+}
+
+// rewriteRecords passes the raw JSON of each of a kind of record file (apps
+// or updates), including gzip-compressed .json.gz files such as compressed
+// history, to change, which returns the new JSON or nil to leave the file
+// as it is. Files change can't parse are logged and left alone, as reads
+// skip them too. The caller must hold s.mu.
+func (s *Storage) rewriteRecords(kind string, change func(data []byte) ([]byte, error)) error {
+	paths, err := filepath.Glob(filepath.Join(s.dataDir, kind, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	compressed, err := filepath.Glob(filepath.Join(s.dataDir, kind, "*.json.gz"))
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	paths = append(paths, compressed...)
+
+	for _, path := range paths {
+		data, err := s.readFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		gzipped := strings.HasSuffix(path, ".gz")
+		if gzipped {
+			if data, err = gunzip(data); err != nil {
+				log.Printf("Skipping unreadable record %s: %v", path, err)
+				continue
+			}
+		}
+		changed, err := change(data)
+		if err != nil {
+			log.Printf("Skipping unreadable record %s: %v", path, err)
+			continue
+		}
+		if changed == nil {
+			continue
+		}
+		if gzipped {
+			if changed, err = gzipData(changed); err != nil {
+				return fmt.Errorf("failed to compress %s: %w", path, err)
+			}
+		}
+		if err := s.writeFile(path, changed); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		s.forgetApp(path)
+	}
+	return nil
+}
+
+// migrateUpdateIDs stores the ID updates recorded before IDs existed are
+// given when read, so they no longer depend on being derived on every read
+func migrateUpdateIDs(s *Storage) error {
+	return s.rewriteRecords("updates", func(data []byte) ([]byte, error) {
+		var updates []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &updates); err != nil {
+			return nil, err
+		}
+
+		changed := false
+		for _, update := range updates {
+			if id, ok := update["id"]; ok && string(id) != `""` {
+				continue
+			}
+			var key struct {
+				BundleID   string    `json:"bundle_id"`
+				NewVersion string    `json:"new_version"`
+				UpdatedAt  time.Time `json:"updated_at"`
+			}
+			if err := remarshal(update, &key); err != nil {
+				return nil, err
+			}
+			id, _ := json.Marshal(models.UpdateID(key.BundleID, key.NewVersion, key.UpdatedAt))
+			update["id"] = id
+			changed = true
+		}
+		if !changed {
+			return nil, nil
+		}
+		return json.MarshalIndent(updates, "", "  ")
+	})
+}
+
+// migrateFirstDiscovered sets the first discovered time of apps saved
+// without one to when they were last checked, the earliest time known
+func migrateFirstDiscovered(s *Storage) error {
+	return s.rewriteRecords("apps", func(data []byte) ([]byte, error) {
+		var app map[string]json.RawMessage
+		if err := json.Unmarshal(data, &app); err != nil {
+			return nil, err
+		}
+
+		var times struct {
+			FirstDiscovered time.Time `json:"first_discovered"`
+			LastChecked     time.Time `json:"last_checked"`
+		}
+		if err := remarshal(app, &times); err != nil {
+			return nil, err
+		}
+		if !times.FirstDiscovered.IsZero() || times.LastChecked.IsZero() {
+			return nil, nil
+		}
+
+		app["first_discovered"] = app["last_checked"]
+		return json.MarshalIndent(app, "", "  ")
+	})
+}
+
+// remarshal decodes the fields of a raw record into v
+func remarshal(record map[string]json.RawMessage, v interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func writeRecord(t *testing.T, dir, rel string, data []byte) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateDataFromBeforeSchemaStamps(t *testing.T) {
+	dir := t.TempDir()
+	checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	writeRecord(t, dir, "apps/com.x.json",
+		[]byte(`{"bundle_id": "com.x", "version": "2.0", "last_checked": "2024-03-01T12:00:00Z"}`))
+	writeRecord(t, dir, "updates/com.x.json",
+		[]byte(`[{"bundle_id": "com.x", "old_version": "1.0", "new_version": "2.0", "updated_at": "2024-02-01T00:00:00Z"}]`))
+	archived, err := gzipData([]byte(`[{"bundle_id": "com.x", "old_version": "0.9", "new_version": "1.0", "updated_at": "2023-01-01T00:00:00Z"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeRecord(t, dir, "updates/com.x"+archivedUpdatesSuffix, archived)
+
+	s, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, schemaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stamp schemaStamp
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		t.Fatal(err)
+	}
+	if stamp.Version != SchemaVersion {
+		t.Errorf("schema version = %d, want %d", stamp.Version, SchemaVersion)
+	}
+
+	app, err := s.LoadApp("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !app.FirstDiscovered.Equal(checked) {
+		t.Errorf("first discovered = %v, want the last checked time %v", app.FirstDiscovered, checked)
+	}
+
+	// The stored IDs must be the ones derived before migration
+	wantIDs := map[string]string{
+		"2.0": models.UpdateID("com.x", "2.0", recent),
+		"1.0": models.UpdateID("com.x", "1.0", old),
+	}
+	for rel, version := range map[string]string{"updates/com.x.json": "2.0", "updates/com.x" + archivedUpdatesSuffix: "1.0"} {
+		data, err := s.readFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(rel) == ".gz" {
+			if data, err = gunzip(data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var updates []models.VersionUpdate
+		if err := json.Unmarshal(data, &updates); err != nil {
+			t.Fatal(err)
+		}
+		if len(updates) != 1 || updates[0].ID != wantIDs[version] {
+			t.Errorf("%s: stored %+v, want ID %s", rel, updates, wantIDs[version])
+		}
+	}
+}
+
+func TestNewDataDirectoryIsStampedWithoutMigrating(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewStorage(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, schemaFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stamp schemaStamp
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		t.Fatal(err)
+	}
+	if stamp.Version != SchemaVersion {
+		t.Errorf("schema version = %d, want %d", stamp.Version, SchemaVersion)
+	}
+}
+
+func TestNewerSchemaIsRefused(t *testing.T) {
+	dir := t.TempDir()
+	writeRecord(t, dir, schemaFile, []byte(`{"version": 999}`))
+
+	if _, err := NewStorage(dir); err == nil {
+		t.Error("opened a data directory from a newer schema")
+	}
+}
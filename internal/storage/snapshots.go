@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// maxSnapshots is how many snapshots are kept per app; the oldest are
+// dropped beyond it
+const maxSnapshots = 200
+
+// AddAppSnapshot appends a snapshot to an app's snapshots, dropping the
+// oldest beyond maxSnapshots
+func (s *Storage) AddAppSnapshot(snapshot *models.AppSnapshot) error {
+	if err := validateBundleID(snapshot.App.BundleID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots, err := s.readSnapshots(snapshot.App.BundleID)
+	if err != nil {
+		return err
+	}
+
+	stored := *snapshot
+	stored.Changes = nil
+	snapshots = append(snapshots, stored)
+	if len(snapshots) > maxSnapshots {
+		snapshots = snapshots[len(snapshots)-maxSnapshots:]
+	}
+
+	return s.writeSnapshots(snapshot.App.BundleID, snapshots)
+}
+
+// GetAppSnapshots returns an app's snapshots, oldest first
+func (s *Storage) GetAppSnapshots(bundleID string) ([]models.AppSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.readSnapshots(bundleID)
+}
+
+// snapshotsPath returns the path of an app's snapshots file
+func (s *Storage) snapshotsPath(bundleID string) string {
+	return filepath.Join(s.dataDir, "snapshots", fmt.Sprintf("%s.json", bundleID))
+}
+
+// readSnapshots reads an app's snapshots; the caller must hold s.mu
+func (s *Storage) readSnapshots(bundleID string) ([]models.AppSnapshot, error) {
+	data, err := s.readFile(s.snapshotsPath(bundleID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.AppSnapshot{}, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshots file: %w", err)
+	}
+
+	var snapshots []models.AppSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// writeSnapshots saves an app's snapshots; the caller must hold s.mu
+func (s *Storage) writeSnapshots(bundleID string, snapshots []models.AppSnapshot) error {
+	path := s.snapshotsPath(bundleID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshots directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshots: %w", err)
+	}
+	if err := s.writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to write snapshots file: %w", err)
+	}
+	return nil
+}
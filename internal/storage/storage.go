@@ -1,38 +1,145 @@
 package storage
 
 import (
+	"crypto/cipher"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/thomas/mavt/pkg/models"
 )
 
+// DuplicateUpdateWindow is how close together two identical old→new version
+// updates for an app must be to count as duplicates, e.g. from a replayed
+// check or a restored backup
+const DuplicateUpdateWindow = 24 * time.Hour
+
+// ErrDuplicateUpdate is returned by SaveVersionUpdate when an identical update
+// was already recorded within DuplicateUpdateWindow
+var ErrDuplicateUpdate = errors.New("duplicate version update")
+
+// ErrUpdateNotFound is returned when a version update doesn't exist, e.g. by
+// ModifyVersionUpdate when an app has no update to the given version
+var ErrUpdateNotFound = errors.New("version update not found")
+
 // Storage handles persistence of app information and version updates
 type Storage struct {
 	dataDir string
 	mu      sync.RWMutex
+
+	// aead encrypts records at rest when an encryption key is configured
+	aead cipher.AEAD
+
+	// Version updates older than this are compressed by CompactHistory
+	compressAfter time.Duration
+
+	// LastChecked times batched by TouchApp
+	lastChecked      map[string]time.Time
+	lastCheckedDirty bool
+
+	// Parsed app files, so listing apps doesn't re-read unchanged files
+	appCache map[string]cachedApp
+	cacheMu  sync.Mutex
 }
 
 // NewStorage creates a new storage instance
 func NewStorage(dataDir string) (*Storage, error) {
+	return NewEncryptedStorage(dataDir, nil)
+}
+
+// NewEncryptedStorage creates a storage instance that encrypts app, update and
+// review records with AES-GCM using a 256-bit key. A nil key disables encryption.
+func NewEncryptedStorage(dataDir string, key []byte) (*Storage, error) {
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	return &Storage{
-		dataDir: dataDir,
-	}, nil
+	s := &Storage{dataDir: dataDir, appCache: make(map[string]cachedApp)}
+	if key != nil {
+		aead, err := newCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		s.aead = aead
+	}
+	s.lastChecked = s.loadLastChecked()
+
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// OpenReadOnly opens an existing data directory for probes such as Ping. It
+// doesn't create the directory, load records or migrate them, so it is safe
+// to run beside the instance that owns the data and needs no encryption key.
+func OpenReadOnly(dataDir string) (*Storage, error) {
+	info, err := os.Stat(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("data directory %s is not a directory", dataDir)
+	}
+	return &Storage{dataDir: dataDir, appCache: make(map[string]cachedApp)}, nil
+}
+
+// Ping verifies the data directory is readable and writable
+func (s *Storage) Ping() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.CreateTemp(s.dataDir, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	name := file.Name()
+	file.Close()
+
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("failed to clean up healthcheck file: %w", err)
+	}
+
+	if _, err := os.ReadDir(s.dataDir); err != nil {
+		return fmt.Errorf("data directory is not readable: %w", err)
+	}
+
+	return nil
 }
 
-// SaveApp saves app information to disk
+// SaveApp saves app information to disk, first applying CheckAppInvariants
+// against the stored record, which can correct app
 func (s *Storage) SaveApp(app *models.AppInfo) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := validateBundleID(app.BundleID); err != nil {
+		return err
+	}
+	appFile := filepath.Join(s.dataDir, "apps", fmt.Sprintf("%s.json", app.BundleID))
+	stored, err := s.readApp(appFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read app file: %w", err)
+	}
+	if stored != nil {
+		s.applyLastChecked(stored)
+	}
+	if err := CheckAppInvariants(stored, app); err != nil {
+		return err
+	}
+
+	return s.writeApp(app)
+}
+
+// writeApp writes an app's file as it is; the caller must hold s.mu
+func (s *Storage) writeApp(app *models.AppInfo) error {
 	appFile := filepath.Join(s.dataDir, "apps", fmt.Sprintf("%s.json", app.BundleID))
 	if err := os.MkdirAll(filepath.Dir(appFile), 0755); err != nil {
 		return fmt.Errorf("failed to create apps directory: %w", err)
@@ -43,7 +150,8 @@ func (s *Storage) SaveApp(app *models.AppInfo) error {
 		return fmt.Errorf("failed to marshal app data: %w", err)
 	}
 
-	if err := os.WriteFile(appFile, data, 0644); err != nil {
+	s.forgetApp(appFile)
+	if err := s.writeFile(appFile, data); err != nil {
 		return fmt.Errorf("failed to write app file: %w", err)
 	}
 
@@ -56,20 +164,16 @@ func (s *Storage) LoadApp(bundleID string) (*models.AppInfo, error) {
 	defer s.mu.RUnlock()
 
 	appFile := filepath.Join(s.dataDir, "apps", fmt.Sprintf("%s.json", bundleID))
-	data, err := os.ReadFile(appFile)
+	app, err := s.readApp(appFile)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to read app file: %w", err)
 	}
+	s.applyLastChecked(app)
 
-	var app models.AppInfo
-	if err := json.Unmarshal(data, &app); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal app data: %w", err)
-	}
-
-	return &app, nil
+	return app, nil
 }
 
 // SaveVersionUpdate saves a version update event
@@ -82,13 +186,29 @@ func (s *Storage) SaveVersionUpdate(update *models.VersionUpdate) error {
 		return fmt.Errorf("failed to create updates directory: %w", err)
 	}
 
-	// Load existing updates
-	var updates []models.VersionUpdate
-	if data, err := os.ReadFile(updatesFile); err == nil {
-		json.Unmarshal(data, &updates)
+	// Load existing updates. Failing to read them must stop the write, which
+	// would otherwise replace them.
+	updates, err := s.readUpdatesFile(updatesFile)
+	if err != nil {
+		return err
+	}
+
+	// An update compacted into the compressed history since it was first
+	// saved is still a duplicate
+	archived, err := s.readArchivedUpdates(update.BundleID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range append(archived, updates...) {
+		if isDuplicateUpdate(&existing, update) {
+			return ErrDuplicateUpdate
+		}
 	}
 
 	// Append new update
+	if update.ID == "" {
+		update.ID = models.UpdateID(update.BundleID, update.NewVersion, update.UpdatedAt)
+	}
 	updates = append(updates, *update)
 
 	data, err := json.MarshalIndent(updates, "", "  ")
@@ -96,13 +216,150 @@ func (s *Storage) SaveVersionUpdate(update *models.VersionUpdate) error {
 		return fmt.Errorf("failed to marshal updates: %w", err)
 	}
 
-	if err := os.WriteFile(updatesFile, data, 0644); err != nil {
+	if err := s.writeFile(updatesFile, data); err != nil {
 		return fmt.Errorf("failed to write updates file: %w", err)
 	}
 
 	return nil
 }
 
+// isDuplicateUpdate reports whether two updates record the same version
+// transition within DuplicateUpdateWindow of each other
+func isDuplicateUpdate(a, b *models.VersionUpdate) bool {
+	if a.BundleID != b.BundleID || a.OldVersion != b.OldVersion || a.NewVersion != b.NewVersion || a.Kind != b.Kind {
+		return false
+	}
+
+	gap := a.UpdatedAt.Sub(b.UpdatedAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= DuplicateUpdateWindow
+}
+
+// DedupeVersionUpdates removes duplicate updates from every app's history,
+// recent and compressed, keeping the earliest of each set, and returns how
+// many were removed
+func (s *Storage) DedupeVersionUpdates() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bundleIDs, err := s.updateBundleIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, bundleID := range bundleIDs {
+		archived, err := s.readArchivedUpdates(bundleID)
+		if err != nil {
+			return removed, err
+		}
+		updatesFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", bundleID))
+		recent, err := s.readUpdatesFile(updatesFile)
+		if err != nil {
+			return removed, err
+		}
+
+		// Compare both files together, so a recent update duplicating an
+		// archived one is removed, but keep each update in its own file
+		all := append(append([]models.VersionUpdate{}, archived...), recent...)
+		sort.SliceStable(all, func(i, j int) bool {
+			return all[i].UpdatedAt.Before(all[j].UpdatedAt)
+		})
+
+		var kept []models.VersionUpdate
+		for i := range all {
+			duplicate := false
+			for j := range kept {
+				if isDuplicateUpdate(&kept[j], &all[i]) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				kept = append(kept, all[i])
+			}
+		}
+
+		if len(kept) == len(all) {
+			continue
+		}
+
+		keptIDs := make(map[string]int, len(kept))
+		for _, update := range kept {
+			keptIDs[update.ID]++
+		}
+		keptArchived := keepUpdates(archived, keptIDs)
+		keptRecent := keepUpdates(recent, keptIDs)
+
+		if len(keptArchived) != len(archived) {
+			if err := s.writeArchivedUpdates(bundleID, keptArchived); err != nil {
+				return removed, err
+			}
+		}
+		if len(keptRecent) != len(recent) {
+			data, err := json.MarshalIndent(keptRecent, "", "  ")
+			if err != nil {
+				return removed, fmt.Errorf("failed to marshal updates: %w", err)
+			}
+			if err := s.writeFile(updatesFile, data); err != nil {
+				return removed, fmt.Errorf("failed to write updates file: %w", err)
+			}
+		}
+		removed += len(all) - len(kept)
+	}
+
+	return removed, nil
+}
+
+// keepUpdates returns the updates whose IDs are counted in keptIDs, in order,
+// using up one count for each
+func keepUpdates(updates []models.VersionUpdate, keptIDs map[string]int) []models.VersionUpdate {
+	kept := make([]models.VersionUpdate, 0, len(updates))
+	for _, update := range updates {
+		if keptIDs[update.ID] > 0 {
+			keptIDs[update.ID]--
+			kept = append(kept, update)
+		}
+	}
+	return kept
+}
+
+// updateBundleIDs returns the bundle IDs of every app with a recent or
+// compressed updates file. Callers must hold s.mu.
+func (s *Storage) updateBundleIDs() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dataDir, "updates"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read updates directory: %w", err)
+	}
+
+	var bundleIDs []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var bundleID string
+		switch name := entry.Name(); {
+		case strings.HasSuffix(name, archivedUpdatesSuffix):
+			bundleID = strings.TrimSuffix(name, archivedUpdatesSuffix)
+		case filepath.Ext(name) == ".json":
+			bundleID = strings.TrimSuffix(name, ".json")
+		default:
+			continue
+		}
+		if !seen[bundleID] {
+			seen[bundleID] = true
+			bundleIDs = append(bundleIDs, bundleID)
+		}
+	}
+	return bundleIDs, nil
+}
+
 // GetAllApps returns all tracked apps
 func (s *Storage) GetAllApps() ([]*models.AppInfo, error) {
 	s.mu.RLock()
@@ -123,17 +380,16 @@ func (s *Storage) GetAllApps() ([]*models.AppInfo, error) {
 			continue
 		}
 
-		data, err := os.ReadFile(filepath.Join(appsDir, entry.Name()))
-		if err != nil {
-			continue
+		app, err := s.readApp(filepath.Join(appsDir, entry.Name()))
+		if errors.Is(err, ErrEncryptionKeyRequired) {
+			return nil, err
 		}
-
-		var app models.AppInfo
-		if err := json.Unmarshal(data, &app); err != nil {
+		if err != nil {
 			continue
 		}
+		s.applyLastChecked(app)
 
-		apps = append(apps, &app)
+		apps = append(apps, app)
 	}
 
 	return apps, nil
@@ -144,18 +400,12 @@ func (s *Storage) GetVersionUpdates(bundleID string) ([]models.VersionUpdate, er
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	updatesFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", bundleID))
-	data, err := os.ReadFile(updatesFile)
+	updates, err := s.readAllUpdates(bundleID)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []models.VersionUpdate{}, nil
-		}
-		return nil, fmt.Errorf("failed to read updates file: %w", err)
+		return nil, err
 	}
-
-	var updates []models.VersionUpdate
-	if err := json.Unmarshal(data, &updates); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal updates: %w", err)
+	if updates == nil {
+		return []models.VersionUpdate{}, nil
 	}
 
 	return updates, nil
@@ -166,33 +416,22 @@ func (s *Storage) GetRecentUpdates(since time.Duration) ([]models.VersionUpdate,
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	updatesDir := filepath.Join(s.dataDir, "updates")
-	entries, err := os.ReadDir(updatesDir)
+	bundleIDs, err := s.updateBundleIDs()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []models.VersionUpdate{}, nil
-		}
-		return nil, fmt.Errorf("failed to read updates directory: %w", err)
+		return nil, err
 	}
 
 	cutoff := time.Now().Add(-since)
-	var recentUpdates []models.VersionUpdate
+	recentUpdates := []models.VersionUpdate{}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		data, err := os.ReadFile(filepath.Join(updatesDir, entry.Name()))
+	// Read the compressed history too: it can hold updates newer than the
+	// compression window if the window was shortened since it was compacted
+	for _, bundleID := range bundleIDs {
+		updates, err := s.readAllUpdates(bundleID)
 		if err != nil {
 			continue
 		}
 
-		var updates []models.VersionUpdate
-		if err := json.Unmarshal(data, &updates); err != nil {
-			continue
-		}
-
 		for _, update := range updates {
 			if update.UpdatedAt.After(cutoff) {
 				recentUpdates = append(recentUpdates, update)
@@ -203,13 +442,156 @@ func (s *Storage) GetRecentUpdates(since time.Duration) ([]models.VersionUpdate,
 	return recentUpdates, nil
 }
 
+// ModifyVersionUpdate applies fn to an app's most recent update to newVersion
+// and saves it, whether it is in the recent or compressed history
+func (s *Storage) ModifyVersionUpdate(bundleID, newVersion string, fn func(*models.VersionUpdate)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updatesFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", bundleID))
+	recent, err := s.readUpdatesFile(updatesFile)
+	if err != nil {
+		return err
+	}
+	if i := LastUpdateTo(recent, newVersion); i >= 0 {
+		fn(&recent[i])
+
+		data, err := json.MarshalIndent(recent, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal updates: %w", err)
+		}
+		if err := s.writeFile(updatesFile, data); err != nil {
+			return fmt.Errorf("failed to write updates file: %w", err)
+		}
+		return nil
+	}
+
+	archived, err := s.readArchivedUpdates(bundleID)
+	if err != nil {
+		return err
+	}
+	if i := LastUpdateTo(archived, newVersion); i >= 0 {
+		fn(&archived[i])
+		return s.writeArchivedUpdates(bundleID, archived)
+	}
+
+	return ErrUpdateNotFound
+}
+
+// LastUpdateTo returns the index of the most recent update to newVersion, or
+// -1. It is the update ModifyVersionUpdate changes.
+func LastUpdateTo(updates []models.VersionUpdate, newVersion string) int {
+	found := -1
+	for i, update := range updates {
+		if update.NewVersion == newVersion && (found < 0 || !update.UpdatedAt.Before(updates[found].UpdatedAt)) {
+			found = i
+		}
+	}
+	return found
+}
+
+// MergeVersionUpdates moves the version history of one bundle ID into another,
+// re-keying the moved updates and keeping the combined history in date order.
+// The source history file is removed.
+func (s *Storage) MergeVersionUpdates(fromBundleID, toBundleID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fromFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", fromBundleID))
+	toFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", toBundleID))
+
+	fromUpdates, err := s.readAllUpdates(fromBundleID)
+	if err != nil {
+		return err
+	}
+	if fromUpdates == nil {
+		return nil
+	}
+
+	merged, err := s.readUpdatesFile(toFile)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range fromUpdates {
+		update.BundleID = toBundleID
+		merged = append(merged, update)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].UpdatedAt.Before(merged[j].UpdatedAt)
+	})
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal updates: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toFile), 0755); err != nil {
+		return fmt.Errorf("failed to create updates directory: %w", err)
+	}
+	if err := s.writeFile(toFile, data); err != nil {
+		return fmt.Errorf("failed to write updates file: %w", err)
+	}
+
+	for _, file := range []string{fromFile, s.archivedUpdatesPath(fromBundleID)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete updates file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AddVersionUpdates inserts past updates into an app's history, keeping it in
+// date order. Unlike SaveVersionUpdate it doesn't check for duplicates, so the
+// caller must leave out versions already recorded.
+func (s *Storage) AddVersionUpdates(bundleID string, updates []models.VersionUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updatesFile := filepath.Join(s.dataDir, "updates", fmt.Sprintf("%s.json", bundleID))
+	merged, err := s.readUpdatesFile(updatesFile)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range updates {
+		if update.ID == "" {
+			update.ID = models.UpdateID(update.BundleID, update.NewVersion, update.UpdatedAt)
+		}
+		merged = append(merged, update)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].UpdatedAt.Before(merged[j].UpdatedAt)
+	})
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal updates: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(updatesFile), 0755); err != nil {
+		return fmt.Errorf("failed to create updates directory: %w", err)
+	}
+	if err := s.writeFile(updatesFile, data); err != nil {
+		return fmt.Errorf("failed to write updates file: %w", err)
+	}
+	return nil
+}
+
 // DeleteApp removes an app and all its version history from storage
 func (s *Storage) DeleteApp(bundleID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if _, ok := s.lastChecked[bundleID]; ok {
+		delete(s.lastChecked, bundleID)
+		s.lastCheckedDirty = true
+	}
+
 	// Delete the app file
 	appFile := filepath.Join(s.dataDir, "apps", fmt.Sprintf("%s.json", bundleID))
+	s.forgetApp(appFile)
 	if err := os.Remove(appFile); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete app file: %w", err)
 	}
@@ -220,5 +602,157 @@ func (s *Storage) DeleteApp(bundleID string) error {
 		return fmt.Errorf("failed to delete updates file: %w", err)
 	}
 
+	// Delete the compressed history file
+	if err := os.Remove(s.archivedUpdatesPath(bundleID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete compressed history: %w", err)
+	}
+
+	// Delete the reviews file
+	reviewsFile := filepath.Join(s.dataDir, "reviews", fmt.Sprintf("%s.json", bundleID))
+	if err := os.Remove(reviewsFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete reviews file: %w", err)
+	}
+
+	// Delete the snapshots file
+	if err := os.Remove(s.snapshotsPath(bundleID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshots file: %w", err)
+	}
+
 	return nil
 }
+
+// AddReviews merges reviews into an app's stored reviews and returns the ones not seen before
+func (s *Storage) AddReviews(bundleID string, reviews []models.Review) ([]models.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reviewsFile := filepath.Join(s.dataDir, "reviews", fmt.Sprintf("%s.json", bundleID))
+	if err := os.MkdirAll(filepath.Dir(reviewsFile), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create reviews directory: %w", err)
+	}
+
+	// Load existing reviews. Failing to read them must stop the write, which
+	// would otherwise replace them.
+	existing, err := s.readReviewsFile(reviewsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, review := range existing {
+		seen[review.ID] = true
+	}
+
+	var added []models.Review
+	for _, review := range reviews {
+		if seen[review.ID] {
+			continue
+		}
+		seen[review.ID] = true
+		review.BundleID = bundleID
+		added = append(added, review)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.MarshalIndent(append(existing, added...), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reviews: %w", err)
+	}
+
+	if err := s.writeFile(reviewsFile, data); err != nil {
+		return nil, fmt.Errorf("failed to write reviews file: %w", err)
+	}
+
+	return added, nil
+}
+
+// GetReviews returns all stored reviews for a specific app
+func (s *Storage) GetReviews(bundleID string) ([]models.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reviews, err := s.readReviewsFile(filepath.Join(s.dataDir, "reviews", fmt.Sprintf("%s.json", bundleID)))
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		return []models.Review{}, nil
+	}
+
+	return reviews, nil
+}
+
+// readReviewsFile reads a reviews file, returning nil if it doesn't exist
+func (s *Storage) readReviewsFile(path string) ([]models.Review, error) {
+	data, err := s.readFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read reviews file: %w", err)
+	}
+
+	var reviews []models.Review
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// Verify checks every stored app, updates, reviews and snapshots file for corruption and
+// returns a description of each problem found. Unreadable or malformed files
+// are otherwise skipped silently when listing apps and updates.
+func (s *Storage) Verify() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var problems []string
+	for _, kind := range []string{"apps", "updates", "reviews", "snapshots"} {
+		dir := filepath.Join(s.dataDir, kind)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s directory: %w", kind, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+
+			path := filepath.Join(kind, entry.Name())
+			data, err := s.readFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: unreadable: %v", path, err))
+				continue
+			}
+
+			var target interface{}
+			switch kind {
+			case "apps":
+				target = &models.AppInfo{}
+			case "updates":
+				target = &[]models.VersionUpdate{}
+			case "snapshots":
+				target = &[]models.AppSnapshot{}
+			default:
+				target = &[]models.Review{}
+			}
+			if err := json.Unmarshal(data, target); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid JSON: %v", path, err))
+				continue
+			}
+
+			if app, ok := target.(*models.AppInfo); ok && app.BundleID+".json" != entry.Name() {
+				problems = append(problems, fmt.Sprintf("%s: bundle ID %q does not match file name", path, app.BundleID))
+			}
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// vendorsFile holds the user's vendor metadata, keyed by developer name
+const vendorsFile = "vendors.json"
+
+// GetVendors returns all recorded vendors, sorted by name
+func (s *Storage) GetVendors() ([]models.Vendor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vendors, err := s.loadVendors()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Vendor, 0, len(vendors))
+	for _, vendor := range vendors {
+		list = append(list, vendor)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// GetVendor returns the vendor recorded for a developer name, matched case-
+// insensitively, or nil if none is
+func (s *Storage) GetVendor(name string) (*models.Vendor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vendors, err := s.loadVendors()
+	if err != nil {
+		return nil, err
+	}
+
+	vendor, ok := vendors[vendorKey(name)]
+	if !ok {
+		return nil, nil
+	}
+	return &vendor, nil
+}
+
+// SaveVendor records a vendor, replacing any recorded under the same name. A
+// vendor with nothing recorded is removed.
+func (s *Storage) SaveVendor(vendor *models.Vendor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vendors, err := s.loadVendors()
+	if err != nil {
+		return err
+	}
+
+	if vendor.IsEmpty() {
+		delete(vendors, vendorKey(vendor.Name))
+	} else {
+		vendors[vendorKey(vendor.Name)] = *vendor
+	}
+
+	data, err := json.MarshalIndent(vendors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal vendors: %w", err)
+	}
+	if err := s.writeFile(filepath.Join(s.dataDir, vendorsFile), data); err != nil {
+		return fmt.Errorf("failed to write vendors: %w", err)
+	}
+	return nil
+}
+
+// loadVendors reads the vendors file; the caller must hold s.mu
+func (s *Storage) loadVendors() (map[string]models.Vendor, error) {
+	vendors := make(map[string]models.Vendor)
+	data, err := s.readFile(filepath.Join(s.dataDir, vendorsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vendors, nil
+		}
+		return nil, fmt.Errorf("failed to read vendors: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &vendors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vendors: %w", err)
+	}
+	return vendors, nil
+}
+
+// vendorKey is the key a vendor is stored under, so names differing only in
+// case or surrounding space match
+func vendorKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
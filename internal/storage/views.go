@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// viewsFile holds users' saved dashboard views, keyed by ID
+const viewsFile = "views.json"
+
+// GetSavedViews returns every saved view, sorted by user and then name
+func (s *Storage) GetSavedViews() ([]models.SavedView, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	views, err := s.loadSavedViews()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.SavedView, 0, len(views))
+	for _, view := range views {
+		list = append(list, view)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if a, b := strings.ToLower(list[i].User), strings.ToLower(list[j].User); a != b {
+			return a < b
+		}
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// SaveSavedView records a saved view, replacing any with the same ID
+func (s *Storage) SaveSavedView(view *models.SavedView) error {
+	return s.updateSavedViews(func(views map[string]models.SavedView) {
+		views[view.ID] = *view
+	})
+}
+
+// DeleteSavedView removes a saved view
+func (s *Storage) DeleteSavedView(id string) error {
+	return s.updateSavedViews(func(views map[string]models.SavedView) {
+		delete(views, id)
+	})
+}
+
+// updateSavedViews applies change to the saved views and writes them back
+func (s *Storage) updateSavedViews(change func(map[string]models.SavedView)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views, err := s.loadSavedViews()
+	if err != nil {
+		return err
+	}
+	change(views)
+
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved views: %w", err)
+	}
+	if err := s.writeFile(filepath.Join(s.dataDir, viewsFile), data); err != nil {
+		return fmt.Errorf("failed to write saved views: %w", err)
+	}
+	return nil
+}
+
+// loadSavedViews reads the saved views file; the caller must hold s.mu
+func (s *Storage) loadSavedViews() (map[string]models.SavedView, error) {
+	views := make(map[string]models.SavedView)
+	data, err := s.readFile(filepath.Join(s.dataDir, viewsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return views, nil
+		}
+		return nil, fmt.Errorf("failed to read saved views: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved views: %w", err)
+	}
+	return views, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// watchlistsFile holds users' keyword watchlists, keyed by user name
+const watchlistsFile = "watchlists.json"
+
+// GetWatchlists returns every user's watchlist, sorted by user
+func (s *Storage) GetWatchlists() ([]models.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	watchlists, err := s.loadWatchlists()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Watchlist, 0, len(watchlists))
+	for _, watchlist := range watchlists {
+		list = append(list, watchlist)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].User) < strings.ToLower(list[j].User)
+	})
+	return list, nil
+}
+
+// GetWatchlist returns a user's watchlist, matched case-insensitively, or nil
+// if they have none
+func (s *Storage) GetWatchlist(user string) (*models.Watchlist, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	watchlists, err := s.loadWatchlists()
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist, ok := watchlists[watchlistKey(user)]
+	if !ok {
+		return nil, nil
+	}
+	return &watchlist, nil
+}
+
+// SaveWatchlist records a user's watchlist, replacing their previous one. A
+// watchlist without keywords is removed.
+func (s *Storage) SaveWatchlist(watchlist *models.Watchlist) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watchlists, err := s.loadWatchlists()
+	if err != nil {
+		return err
+	}
+
+	if len(watchlist.Keywords) == 0 {
+		delete(watchlists, watchlistKey(watchlist.User))
+	} else {
+		watchlists[watchlistKey(watchlist.User)] = *watchlist
+	}
+
+	data, err := json.MarshalIndent(watchlists, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watchlists: %w", err)
+	}
+	if err := s.writeFile(filepath.Join(s.dataDir, watchlistsFile), data); err != nil {
+		return fmt.Errorf("failed to write watchlists: %w", err)
+	}
+	return nil
+}
+
+// loadWatchlists reads the watchlists file; the caller must hold s.mu
+func (s *Storage) loadWatchlists() (map[string]models.Watchlist, error) {
+	watchlists := make(map[string]models.Watchlist)
+	data, err := s.readFile(filepath.Join(s.dataDir, watchlistsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return watchlists, nil
+		}
+		return nil, fmt.Errorf("failed to read watchlists: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &watchlists); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watchlists: %w", err)
+	}
+	return watchlists, nil
+}
+
+// watchlistKey is the key a watchlist is stored under, so user names
+// differing only in case or surrounding space match
+func watchlistKey(user string) string {
+	return strings.ToLower(strings.TrimSpace(user))
+}
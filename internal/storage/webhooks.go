@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// webhookDeliveriesFile holds the most recent outbound webhook deliveries
+const webhookDeliveriesFile = "webhook_deliveries.json"
+
+// maxWebhookDeliveries is how many deliveries are kept; older ones are dropped
+const maxWebhookDeliveries = 200
+
+// ErrDeliveryNotFound is returned when no webhook delivery has the given ID
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// SaveWebhookDelivery appends a delivery to the delivery log, dropping the
+// oldest entries beyond the most recent maxWebhookDeliveries
+func (s *Storage) SaveWebhookDelivery(delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries, err := s.readWebhookDeliveries()
+	if err != nil {
+		return err
+	}
+
+	deliveries = append(deliveries, *delivery)
+	if len(deliveries) > maxWebhookDeliveries {
+		deliveries = deliveries[len(deliveries)-maxWebhookDeliveries:]
+	}
+
+	data, err := json.MarshalIndent(deliveries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook deliveries: %w", err)
+	}
+
+	if err := s.writeFile(filepath.Join(s.dataDir, webhookDeliveriesFile), data); err != nil {
+		return fmt.Errorf("failed to write webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the logged webhook deliveries, newest first
+func (s *Storage) GetWebhookDeliveries() ([]models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries, err := s.readWebhookDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery returns the logged delivery with the given ID, or
+// ErrDeliveryNotFound
+func (s *Storage) GetWebhookDelivery(id string) (*models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries, err := s.readWebhookDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		if deliveries[i].ID == id {
+			return &deliveries[i], nil
+		}
+	}
+	return nil, ErrDeliveryNotFound
+}
+
+// readWebhookDeliveries reads the delivery log, oldest first. The caller must
+// hold s.mu.
+func (s *Storage) readWebhookDeliveries() ([]models.WebhookDelivery, error) {
+	data, err := s.readFile(filepath.Join(s.dataDir, webhookDeliveriesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.WebhookDelivery{}, nil
+		}
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := json.Unmarshal(data, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/thomas/mavt/internal/version"
+)
+
+// instrumentationName identifies spans created by MAVT
+const instrumentationName = "github.com/thomas/mavt"
+
+// Enabled reports whether tracing is configured through the standard OTEL
+// environment variables (an OTLP endpoint is set and the SDK isn't disabled)
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider exporting spans via OTLP/HTTP. The
+// exporter, sampler and resource honour the standard OTEL_* environment
+// variables. The returned function flushes pending spans and must be called
+// on shutdown. When tracing isn't configured, Setup is a no-op.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceVersion(version.Version)}
+	// OTEL_SERVICE_NAME takes precedence; otherwise default to "mavt"
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		attrs = append(attrs, semconv.ServiceName("mavt"))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span using the global tracer provider. Without Setup this
+// returns a no-op span, so instrumented code doesn't need to check Enabled.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
@@ -0,0 +1,43 @@
+package tracker_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
+	"github.com/thomas/mavt/internal/version"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestAlertsArePublishedOnTheEventBus(t *testing.T) {
+	store := trackertest.NewMemStore()
+	store.SaveApp(&models.AppInfo{BundleID: "com.x", TrackName: "X", Version: "2.0"})
+	if err := store.SaveVersionUpdate(&models.VersionUpdate{BundleID: "com.x", OldVersion: "1.0", NewVersion: "2.0", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	notify := trackertest.NewFakeNotifier()
+	tr := tracker.NewTracker(&config.Config{}, store, notify)
+
+	var published []string
+	tr.Events().Subscribe(func(event *models.Event) {
+		published = append(published, event.Type)
+	})
+
+	if err := tr.AssignUpdate("com.x", "2.0", "alice", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	tr.NotifyRelease(&version.Release{Version: "9.9.9"})
+
+	if want := []string{models.EventAssignment, models.EventRelease}; !slices.Equal(published, want) {
+		t.Errorf("published %v, want %v", published, want)
+	}
+	if want := []string{"assignment com.x", "release 9.9.9"}; !slices.Equal(notify.Alerts, want) {
+		t.Errorf("notifier alerts %v, want %v", notify.Alerts, want)
+	}
+	if len(notify.Events) != 0 {
+		t.Errorf("alerts were recorded as webhook events: %v", notify.Events)
+	}
+}
@@ -0,0 +1,119 @@
+package tracker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// newApprovalTracker tracks com.x at 1.0, then publishes 2.0 and checks, so
+// the update to 2.0 is waiting for approval
+func newApprovalTracker(t *testing.T) (*tracker.Tracker, *trackertest.MemStore, *trackertest.FakeNotifier) {
+	t.Helper()
+	store := trackertest.NewMemStore()
+	notify := trackertest.NewFakeNotifier()
+	source := trackertest.NewFakeSource(&models.AppInfo{BundleID: "com.x", TrackID: 1, TrackName: "X", Version: "1.0"})
+	tr := tracker.NewTracker(&config.Config{RequireApproval: true}, store, notify)
+	tr.SetSource(source)
+
+	if err := tr.TrackApp("com.x"); err != nil {
+		t.Fatal(err)
+	}
+	source.SetApp(&models.AppInfo{BundleID: "com.x", TrackID: 1, TrackName: "X", Version: "2.0"})
+	if _, err := tr.CheckForUpdates(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return tr, store, notify
+}
+
+func approvalOf(t *testing.T, store *trackertest.MemStore) *models.Approval {
+	t.Helper()
+	updates, err := store.GetVersionUpdates("com.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates))
+	}
+	return updates[0].Approval
+}
+
+func approvedEvents(notify *trackertest.FakeNotifier) int {
+	n := 0
+	for _, event := range notify.Events {
+		if event.Type == models.EventApprovedUpdate {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNewUpdatesWaitForApproval(t *testing.T) {
+	_, store, _ := newApprovalTracker(t)
+
+	if approval := approvalOf(t, store); approval == nil || approval.State != models.ApprovalPending {
+		t.Errorf("approval = %+v, want pending", approval)
+	}
+}
+
+func TestApproveUpdate(t *testing.T) {
+	tr, store, notify := newApprovalTracker(t)
+
+	if err := tr.DecideUpdate("com.x", "2.0", models.ApprovalApproved, " alice ", "looks good"); err != nil {
+		t.Fatal(err)
+	}
+	approval := approvalOf(t, store)
+	if approval.State != models.ApprovalApproved || approval.By != "alice" || approval.Comment != "looks good" || approval.At == nil {
+		t.Errorf("approval = %+v, want approved by alice", approval)
+	}
+	if n := approvedEvents(notify); n != 1 {
+		t.Errorf("sent %d approved_update events, want 1", n)
+	}
+
+	if err := tr.DecideUpdate("com.x", "2.0", models.ApprovalRejected, "bob", ""); err == nil {
+		t.Error("deciding an approved update again succeeded")
+	}
+	if approval := approvalOf(t, store); approval.State != models.ApprovalApproved {
+		t.Errorf("state after second decision = %s, want approved", approval.State)
+	}
+}
+
+func TestRejectUpdate(t *testing.T) {
+	tr, store, notify := newApprovalTracker(t)
+
+	if err := tr.DecideUpdate("com.x", "2.0", models.ApprovalRejected, "bob", "breaks login"); err != nil {
+		t.Fatal(err)
+	}
+	if approval := approvalOf(t, store); approval.State != models.ApprovalRejected || approval.By != "bob" {
+		t.Errorf("approval = %+v, want rejected by bob", approval)
+	}
+	if n := approvedEvents(notify); n != 0 {
+		t.Errorf("rejecting sent %d approved_update events", n)
+	}
+}
+
+func TestInvalidDecisions(t *testing.T) {
+	tr, store, _ := newApprovalTracker(t)
+
+	tests := []struct {
+		name, state, by string
+	}{
+		{"pending state", models.ApprovalPending, "alice"},
+		{"unknown state", "maybe", "alice"},
+		{"no name", models.ApprovalApproved, "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tr.DecideUpdate("com.x", "2.0", tt.state, tt.by, ""); err == nil {
+				t.Error("decision succeeded")
+			}
+		})
+	}
+	if approval := approvalOf(t, store); approval.State != models.ApprovalPending {
+		t.Errorf("state after invalid decisions = %s, want pending", approval.State)
+	}
+}
@@ -0,0 +1,141 @@
+package tracker
+
+import (
+	"context"
+	"time"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/notifier"
+	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// Source looks up apps, their reviews, in-app purchases and TestFlight betas;
+// the App Store client in production.
+// The fakes in the trackertest package let tests run without the network.
+type Source interface {
+	LookupByBundleID(bundleID string) (*models.AppInfo, error)
+	LookupByBundleIDWithLocale(ctx context.Context, bundleID, country, lang string) (*models.AppInfo, error)
+	LookupByTrackID(trackID int64) (*models.AppInfo, error)
+	SearchApps(term string, limit int) ([]*models.AppInfo, error)
+	FetchReviews(trackID int64, country string) ([]models.Review, error)
+	FetchInAppPurchases(ctx context.Context, trackID int64, country string) ([]models.InAppPurchase, error)
+	FetchTestFlight(ctx context.Context, link string) (*models.BetaStatus, error)
+}
+
+// Store is the storage the tracker needs, implemented by *storage.Storage and
+// by trackertest.MemStore
+type Store interface {
+	AppStore
+	UpdateStore
+	ReviewStore
+	VendorStore
+	WatchlistStore
+	SavedViewStore
+	ApplicationStore
+	ChangeStore
+	SnapshotStore
+	StateStore
+}
+
+// AppStore stores tracked apps
+type AppStore interface {
+	LoadApp(bundleID string) (*models.AppInfo, error)
+	SaveApp(app *models.AppInfo) error
+	DeleteApp(bundleID string) error
+	GetAllApps() ([]*models.AppInfo, error)
+	TouchApp(bundleID string, checkedAt time.Time)
+	FlushLastChecked() error
+	ReloadLastChecked()
+}
+
+// UpdateStore stores version history
+type UpdateStore interface {
+	SaveVersionUpdate(update *models.VersionUpdate) error
+	AddVersionUpdates(bundleID string, updates []models.VersionUpdate) error
+	GetVersionUpdates(bundleID string) ([]models.VersionUpdate, error)
+	GetRecentUpdates(since time.Duration) ([]models.VersionUpdate, error)
+	ModifyVersionUpdate(bundleID, newVersion string, fn func(*models.VersionUpdate)) error
+	MergeVersionUpdates(fromBundleID, toBundleID string) error
+	CompactHistory() (int, error)
+}
+
+// ReviewStore stores customer reviews
+type ReviewStore interface {
+	AddReviews(bundleID string, reviews []models.Review) ([]models.Review, error)
+	GetReviews(bundleID string) ([]models.Review, error)
+}
+
+// VendorStore stores the user's vendor metadata
+type VendorStore interface {
+	GetVendors() ([]models.Vendor, error)
+	GetVendor(name string) (*models.Vendor, error)
+	SaveVendor(vendor *models.Vendor) error
+}
+
+// WatchlistStore stores users' keyword watchlists
+type WatchlistStore interface {
+	GetWatchlists() ([]models.Watchlist, error)
+	GetWatchlist(user string) (*models.Watchlist, error)
+	SaveWatchlist(watchlist *models.Watchlist) error
+}
+
+// SavedViewStore stores users' saved dashboard views
+type SavedViewStore interface {
+	GetSavedViews() ([]models.SavedView, error)
+	SaveSavedView(view *models.SavedView) error
+	DeleteSavedView(id string) error
+}
+
+// ApplicationStore stores the user's applications, which link store entries
+// of one product
+type ApplicationStore interface {
+	GetApplications() ([]models.Application, error)
+	SaveApplication(application *models.Application) error
+	DeleteApplication(id string) error
+}
+
+// ChangeStore stores the sync journal
+type ChangeStore interface {
+	AppendChanges(changes []models.SyncChange) error
+	GetChanges(after int64, limit int) ([]models.SyncChange, storage.ChangeRange, error)
+}
+
+// SnapshotStore stores snapshots of apps' metadata
+type SnapshotStore interface {
+	AddAppSnapshot(snapshot *models.AppSnapshot) error
+	GetAppSnapshots(bundleID string) ([]models.AppSnapshot, error)
+}
+
+// StateStore stores scheduler state, leases, raw responses and the webhook
+// delivery log
+type StateStore interface {
+	Ping() error
+	LoadSchedulerState() (*storage.SchedulerState, error)
+	SaveSchedulerState(state *storage.SchedulerState) error
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(name, holder string) error
+	ArchiveRawResponse(bundleID string, body []byte) error
+	PruneRawResponses(retention time.Duration) (int, error)
+	GetWebhookDeliveries() ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(id string) (*models.WebhookDelivery, error)
+}
+
+// Notifier sends notifications; *notifier.Notifier in production
+type Notifier interface {
+	// HandleEvent receives every event the tracker publishes, including
+	// the alert events it sends as notifications
+	HandleEvent(event *models.Event)
+
+	// HasWebhook and RedeliverWebhook serve API requests about webhook
+	// deliveries rather than anything the tracker observes
+	HasWebhook() bool
+	RedeliverWebhook(delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+}
+
+// Compile-time checks that the production implementations fit
+var (
+	_ Source   = (*appstore.Client)(nil)
+	_ Store    = (*storage.Storage)(nil)
+	_ Notifier = (*notifier.Notifier)(nil)
+)
@@ -0,0 +1,119 @@
+package tracker
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// UpdateFilter narrows version updates to those matching every set field.
+// It backs both the filters on /api/updates and the -recent flags, so the
+// two always agree.
+type UpdateFilter struct {
+	// BundleIDs, Vendors and Tags match updates to any of the apps, to apps
+	// from any of the vendors (developer names, ignoring case) and to apps in
+	// applications with any of the tags
+	BundleIDs []string
+	Vendors   []string
+	Tags      []string
+
+	// Severities matches updates of any of the severities
+	Severities []string
+
+	// Unacknowledged matches updates nobody has acknowledged, Assignee those
+	// assigned to someone (ignoring case) and Approval those in an approval
+	// state
+	Unacknowledged bool
+	Assignee       string
+	Approval       string
+}
+
+// UpdateMatcher matches updates against an UpdateFilter, with the vendors
+// and tags it needs looked up once
+type UpdateMatcher struct {
+	filter   UpdateFilter
+	vendorOf map[string]string
+	tagged   map[string]bool
+}
+
+// NewUpdateMatcher looks up what filter needs to match updates: the vendor
+// of every app, tracked or archived, and the applications with its tags
+func (t *Tracker) NewUpdateMatcher(filter UpdateFilter) (*UpdateMatcher, error) {
+	m := &UpdateMatcher{filter: filter}
+
+	if len(filter.Vendors) > 0 {
+		apps, err := t.storage.GetAllApps()
+		if err != nil {
+			return nil, err
+		}
+		m.vendorOf = make(map[string]string, len(apps))
+		for _, app := range apps {
+			m.vendorOf[app.BundleID] = app.ArtistName
+		}
+	}
+
+	if len(filter.Tags) > 0 {
+		applications, err := t.storage.GetApplications()
+		if err != nil {
+			return nil, err
+		}
+		m.tagged = make(map[string]bool)
+		for i := range applications {
+			for _, tag := range filter.Tags {
+				if applications[i].HasTag(tag) {
+					m.tagged[applications[i].Name] = true
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// MatchesApp reports whether updates to app can match, so callers can skip
+// reading the history of apps that can't
+func (m *UpdateMatcher) MatchesApp(app *models.AppInfo) bool {
+	if len(m.filter.BundleIDs) > 0 && !slices.ContainsFunc(m.filter.BundleIDs, func(bundleID string) bool {
+		return strings.EqualFold(bundleID, app.BundleID)
+	}) {
+		return false
+	}
+	if len(m.filter.Vendors) > 0 && !slices.ContainsFunc(m.filter.Vendors, func(vendor string) bool {
+		return strings.EqualFold(vendor, app.ArtistName)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Matches reports whether update matches every set field of the filter
+func (m *UpdateMatcher) Matches(update models.VersionUpdate) bool {
+	f := m.filter
+	if len(f.BundleIDs) > 0 && !slices.ContainsFunc(f.BundleIDs, func(bundleID string) bool {
+		return strings.EqualFold(bundleID, update.BundleID)
+	}) {
+		return false
+	}
+	if len(f.Vendors) > 0 && !slices.ContainsFunc(f.Vendors, func(vendor string) bool {
+		return strings.EqualFold(vendor, m.vendorOf[update.BundleID])
+	}) {
+		return false
+	}
+	if m.tagged != nil && !m.tagged[update.Application] {
+		return false
+	}
+	if len(f.Severities) > 0 && !slices.Contains(f.Severities, update.Severity) {
+		return false
+	}
+	if f.Unacknowledged && update.Acknowledged != nil {
+		return false
+	}
+	if f.Assignee != "" && (update.Assignment == nil || !strings.EqualFold(update.Assignment.To, f.Assignee)) {
+		return false
+	}
+	if f.Approval != "" && (update.Approval == nil || update.Approval.State != f.Approval) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package tracker_test
+
+import (
+	"testing"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/internal/tracker/trackertest"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+func TestUpdateMatcher(t *testing.T) {
+	store := trackertest.NewMemStore()
+	store.SaveApp(&models.AppInfo{BundleID: "com.bank", ArtistName: "Bank, Inc."})
+	store.SaveApp(&models.AppInfo{BundleID: "com.chat", ArtistName: "Chat Ltd"})
+	store.SaveApplication(&models.Application{ID: "banking", Name: "Banking", Tags: []string{"finance"}})
+	tr := tracker.NewTracker(&config.Config{}, store, trackertest.NewFakeNotifier())
+
+	bank := models.VersionUpdate{BundleID: "com.bank", Application: "Banking", Severity: models.SeverityCritical}
+	chat := models.VersionUpdate{BundleID: "com.chat", Severity: models.SeverityMinor,
+		Assignment: &models.Assignment{To: "alice"}}
+
+	tests := []struct {
+		name       string
+		filter     tracker.UpdateFilter
+		bank, chat bool
+	}{
+		{"no filter", tracker.UpdateFilter{}, true, true},
+		{"bundle ID", tracker.UpdateFilter{BundleIDs: []string{"COM.CHAT"}}, false, true},
+		{"vendor", tracker.UpdateFilter{Vendors: []string{"bank, inc."}}, true, false},
+		{"tag", tracker.UpdateFilter{Tags: []string{"Finance"}}, true, false},
+		{"severity", tracker.UpdateFilter{Severities: []string{models.SeverityMinor}}, false, true},
+		{"assignee", tracker.UpdateFilter{Assignee: "Alice"}, false, true},
+		{"every field", tracker.UpdateFilter{Tags: []string{"finance"}, Severities: []string{models.SeverityMinor}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := tr.NewUpdateMatcher(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := matcher.Matches(bank); got != tt.bank {
+				t.Errorf("bank update matches = %v, want %v", got, tt.bank)
+			}
+			if got := matcher.Matches(chat); got != tt.chat {
+				t.Errorf("chat update matches = %v, want %v", got, tt.chat)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// transientRetryDelay is how long to wait before retrying a lookup that
+	// failed with a temporary error
+	transientRetryDelay = 2 * time.Second
+
+	// throttleDelay is how long to back off when the App Store rate limits
+	// without saying for how long
+	throttleDelay = 30 * time.Second
+
+	// maxThrottleDelay caps the back-off Apple can ask for, so one check
+	// cycle can't stall for long
+	maxThrottleDelay = 2 * time.Minute
+)
+
+// lookupWithRetry looks up an app in one storefront, retrying once after a
+// short delay if the lookup fails with a temporary error
+func (t *Tracker) lookupWithRetry(ctx context.Context, bundleID, country, lang string) (*models.AppInfo, error) {
+	app, err := t.client.LookupByBundleIDWithLocale(ctx, bundleID, country, lang)
+	if err == nil || !errors.Is(err, appstore.ErrTransient) {
+		return app, err
+	}
+
+	delay := transientRetryDelay
+	if retryAfter := appstore.RetryAfter(err); retryAfter > delay && retryAfter <= maxThrottleDelay {
+		delay = retryAfter
+	}
+	log.Printf("Temporary error looking up %s, retrying in %s: %v", sanitizeForLog(bundleID), delay, err)
+	if !sleepContext(ctx, delay) {
+		return nil, err
+	}
+	return t.client.LookupByBundleIDWithLocale(ctx, bundleID, country, lang)
+}
+
+// throttleBackoff returns how long to wait after err rate limited a check
+func throttleBackoff(err error) time.Duration {
+	delay := appstore.RetryAfter(err)
+	if delay <= 0 {
+		delay = throttleDelay
+	}
+	if delay > maxThrottleDelay {
+		delay = maxThrottleDelay
+	}
+	return delay
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
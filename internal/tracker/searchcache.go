@@ -0,0 +1,174 @@
+package tracker
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+const (
+	// maxSearchCacheEntries caps how many searches are cached; the entry
+	// closest to expiring is dropped to make room
+	maxSearchCacheEntries = 500
+
+	// searchStaleFor is how long an expired entry is still served when the
+	// source rate limits a repeat of the search
+	searchStaleFor = time.Hour
+)
+
+// searchCache remembers search results for a short TTL so repeated searches,
+// e.g. from dashboard keystrokes or several users looking up the same term,
+// don't each reach the source. Concurrent identical searches share one
+// request.
+type searchCache struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]searchEntry
+	inflight map[string]*searchCall
+}
+
+// searchEntry is a cached search's results, stored as values so callers
+// can't change them through the pointers they get back
+type searchEntry struct {
+	source    string
+	apps      []models.AppInfo
+	expiresAt time.Time
+}
+
+// searchCall is a search in progress that identical searches wait for
+type searchCall struct {
+	done chan struct{}
+	apps []models.AppInfo
+	err  error
+}
+
+// newSearchCache creates a cache keeping results for ttl; a zero ttl
+// disables caching
+func newSearchCache(ttl time.Duration) *searchCache {
+	return &searchCache{
+		ttl:      ttl,
+		entries:  make(map[string]searchEntry),
+		inflight: make(map[string]*searchCall),
+	}
+}
+
+// search returns the cached results of a search, or runs it with fetch. An
+// expired entry is served instead of a rate limit error.
+func (c *searchCache) search(source, term string, limit int, fetch func() ([]*models.AppInfo, error)) ([]*models.AppInfo, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch()
+	}
+
+	key := source + "\x00" + strconv.Itoa(limit) + "\x00" + strings.ToLower(strings.TrimSpace(term))
+	now := time.Now()
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return appPointers(entry.apps), nil
+	}
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return appPointers(call.apps), call.err
+	}
+	call := &searchCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	apps, err := fetch()
+	if err == nil {
+		call.apps = appValues(apps)
+	} else {
+		call.err = err
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if err == nil {
+		c.store(key, searchEntry{source: source, apps: call.apps, expiresAt: now.Add(c.ttl)}, now)
+	} else if entry, ok := c.entries[key]; ok && errors.Is(err, appstore.ErrThrottled) && now.Before(entry.expiresAt.Add(searchStaleFor)) {
+		log.Printf("Search for %q rate limited; serving cached results", sanitizeForLog(term))
+		call.apps, call.err = entry.apps, nil
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return appPointers(call.apps), call.err
+}
+
+// store adds an entry, first dropping entries past serving stale and, if
+// the cache is still full, the one closest to expiring; the caller must hold
+// c.mu
+func (c *searchCache) store(key string, entry searchEntry, now time.Time) {
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxSearchCacheEntries {
+		oldest := ""
+		for k, e := range c.entries {
+			if now.After(e.expiresAt.Add(searchStaleFor)) {
+				delete(c.entries, k)
+			} else if oldest == "" || e.expiresAt.Before(c.entries[oldest].expiresAt) {
+				oldest = k
+			}
+		}
+		if len(c.entries) >= maxSearchCacheEntries {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// find returns a copy of the first unexpired cached result of a source's
+// searches that match accepts, or nil if there is none
+func (c *searchCache) find(source string, match func(*models.AppInfo) bool) *models.AppInfo {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range c.entries {
+		if entry.source != source || !now.Before(entry.expiresAt) {
+			continue
+		}
+		for i := range entry.apps {
+			if match(&entry.apps[i]) {
+				app := entry.apps[i]
+				return &app
+			}
+		}
+	}
+	return nil
+}
+
+// appValues copies search results for caching
+func appValues(apps []*models.AppInfo) []models.AppInfo {
+	values := make([]models.AppInfo, 0, len(apps))
+	for _, app := range apps {
+		if app != nil {
+			values = append(values, *app)
+		}
+	}
+	return values
+}
+
+// appPointers returns fresh copies of cached search results
+func appPointers(values []models.AppInfo) []*models.AppInfo {
+	if values == nil {
+		return nil
+	}
+	apps := make([]*models.AppInfo, len(values))
+	for i := range values {
+		app := values[i]
+		apps[i] = &app
+	}
+	return apps
+}
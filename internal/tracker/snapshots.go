@@ -0,0 +1,94 @@
+package tracker
+
+import (
+	"log"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// volatileSnapshotFields are app fields that change on most checks: ratings,
+// and the TestFlight beta's check time. A change to only these is snapshotted
+// once per snapshot interval rather than every time it is seen.
+var volatileSnapshotFields = map[string]bool{"rating": true, "beta": true}
+
+// recordSnapshot snapshots an app's metadata after it is saved with changes,
+// given its previous record (nil for a new app). An app without snapshots yet,
+// e.g. one tracked before snapshots were kept, first gets one of its previous
+// record so the change shows in its snapshots. Failures are logged, since
+// checks shouldn't fail over them.
+func (t *Tracker) recordSnapshot(previous, app *models.AppInfo) {
+	if t.snapshotInterval <= 0 {
+		return
+	}
+
+	snapshots, err := t.storage.GetAppSnapshots(app.BundleID)
+	if err != nil {
+		log.Printf("Failed to read snapshots of %s: %v", sanitizeForLog(app.BundleID), err)
+		return
+	}
+
+	var last *models.AppSnapshot
+	if len(snapshots) > 0 {
+		last = &snapshots[len(snapshots)-1]
+	} else if previous != nil {
+		takenAt := previous.LastChecked
+		if takenAt.IsZero() {
+			takenAt = previous.FirstDiscovered
+		}
+		last = &models.AppSnapshot{TakenAt: takenAt, App: *previous}
+		if !t.addSnapshot(last) {
+			return
+		}
+	}
+
+	if last != nil && !t.snapshotDue(last, app) {
+		return
+	}
+	t.addSnapshot(&models.AppSnapshot{TakenAt: time.Now(), App: *app})
+}
+
+// snapshotDue reports whether app differs from its last snapshot enough to
+// snapshot it again
+func (t *Tracker) snapshotDue(last *models.AppSnapshot, app *models.AppInfo) bool {
+	changes := models.DiffApps(&last.App, app)
+	for _, change := range changes {
+		if !volatileSnapshotFields[change.Field] {
+			return true
+		}
+	}
+	return len(changes) > 0 && time.Since(last.TakenAt) >= t.snapshotInterval
+}
+
+// addSnapshot stores a snapshot, logging a failure, and reports whether it
+// was stored
+func (t *Tracker) addSnapshot(snapshot *models.AppSnapshot) bool {
+	if err := t.storage.AddAppSnapshot(snapshot); err != nil {
+		log.Printf("Failed to snapshot %s: %v", sanitizeForLog(snapshot.App.BundleID), err)
+		return false
+	}
+	return true
+}
+
+// GetAppSnapshots returns a tracked app's snapshots, newest first, each with
+// the fields that changed since the one before it
+func (t *Tracker) GetAppSnapshots(bundleID string) ([]models.AppSnapshot, error) {
+	if _, err := t.loadTrackedApp(bundleID); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := t.storage.GetAppSnapshots(bundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	newestFirst := make([]models.AppSnapshot, len(snapshots))
+	for i := range snapshots {
+		snapshot := snapshots[i]
+		if i > 0 {
+			snapshot.Changes = models.DiffApps(&snapshots[i-1].App, &snapshot.App)
+		}
+		newestFirst[len(snapshots)-1-i] = snapshot
+	}
+	return newestFirst, nil
+}
@@ -0,0 +1,211 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// journalEvents maps the app events recorded in the sync journal to their
+// change types
+var journalEvents = map[string]string{
+	models.EventAppAdded:            models.SyncAppAdded,
+	models.EventAppRemoved:          models.SyncAppRemoved,
+	models.EventAppPulled:           models.SyncAppUpdated,
+	models.EventPriceChange:         models.SyncAppUpdated,
+	models.EventMetadataChange:      models.SyncAppUpdated,
+	models.EventInAppPurchaseChange: models.SyncAppUpdated,
+}
+
+// ErrInvalidCursor is returned for a sync cursor that isn't one MAVT issued
+var ErrInvalidCursor = errors.New("invalid sync cursor")
+
+// SyncPage is a page of changes for incremental replication
+type SyncPage struct {
+	Changes []models.SyncChange `json:"changes"`
+
+	// Cursor is passed back to get the changes after this page
+	Cursor string `json:"cursor"`
+
+	// HasMore is set when more changes are waiting after this page
+	HasMore bool `json:"has_more"`
+
+	// Reset is set when the page is a snapshot of every tracked app, as
+	// app_added changes, rather than the changes after the cursor, because
+	// no cursor was given or the changes after it are no longer kept. The
+	// client should replace what it has with the snapshot.
+	Reset bool `json:"reset"`
+}
+
+// journalEvent records an app event in the sync journal
+func (t *Tracker) journalEvent(event *models.Event) {
+	changeType, ok := journalEvents[event.Type]
+	if !ok || event.BundleID == "" {
+		return
+	}
+	t.recordChanges(models.SyncChange{Type: changeType, BundleID: event.BundleID, At: event.OccurredAt})
+}
+
+// journalUpdates records version updates in the sync journal
+func (t *Tracker) journalUpdates(updates []models.VersionUpdate) {
+	changes := make([]models.SyncChange, 0, len(updates))
+	for i := range updates {
+		update := updates[i]
+		changes = append(changes, models.SyncChange{
+			Type:     models.SyncVersionUpdate,
+			BundleID: update.BundleID,
+			At:       update.UpdatedAt,
+			Update:   &update,
+		})
+	}
+	t.recordChanges(changes...)
+}
+
+// journalApp records a change to an app the tracker makes without an event,
+// e.g. relabeling it
+func (t *Tracker) journalApp(changeType, bundleID string) {
+	t.recordChanges(models.SyncChange{Type: changeType, BundleID: bundleID, At: time.Now()})
+}
+
+// recordChanges appends changes to the sync journal. A failure is logged
+// rather than failing what changed, since clients can resync from scratch.
+func (t *Tracker) recordChanges(changes ...models.SyncChange) {
+	if len(changes) == 0 {
+		return
+	}
+	if err := t.storage.AppendChanges(changes); err != nil {
+		log.Printf("Failed to record changes for sync: %v", err)
+	}
+}
+
+// GetChanges returns up to limit changes after cursor, each with its app's
+// current state, or a snapshot of every tracked app if cursor is empty or
+// the changes after it are no longer kept
+func (t *Tracker) GetChanges(cursor string, limit int) (*SyncPage, error) {
+	after := int64(-1)
+	if cursor != "" {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || seq < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+		}
+		after = seq
+	}
+
+	changes, span, err := t.storage.GetChanges(after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changes: %w", err)
+	}
+
+	// A cursor ahead of the journal belongs to another data directory, and
+	// one before its oldest entry has missed dropped changes
+	if after < 0 || after > span.Latest || after < span.Oldest-1 {
+		return t.syncSnapshot(span.Latest)
+	}
+
+	page := &SyncPage{Changes: []models.SyncChange{}, Cursor: strconv.FormatInt(after, 10)}
+	if len(changes) > 0 {
+		page.Changes = changes
+		page.Cursor = strconv.FormatInt(changes[len(changes)-1].Seq, 10)
+		page.HasMore = changes[len(changes)-1].Seq < span.Latest
+	}
+	if err := t.hydrateChanges(page.Changes); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// UpdatesAfter returns the version updates among up to limit changes after
+// cursor, oldest first, and the cursor to read on from. An empty cursor, or
+// one ahead of the journal, e.g. of another data directory, starts at the
+// end of the journal. Unlike timestamps, cursors don't miss updates recorded
+// with an earlier detection time, such as those synced from an upstream.
+func (t *Tracker) UpdatesAfter(cursor string, limit int) ([]models.VersionUpdate, string, error) {
+	after := int64(math.MaxInt64)
+	if cursor != "" {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || seq < 0 {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+		}
+		after = seq
+	}
+
+	changes, span, err := t.storage.GetChanges(after, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load changes: %w", err)
+	}
+	if after > span.Latest {
+		return nil, strconv.FormatInt(span.Latest, 10), nil
+	}
+
+	var updates []models.VersionUpdate
+	for _, change := range changes {
+		if change.Type == models.SyncVersionUpdate && change.Update != nil {
+			updates = append(updates, *change.Update)
+		}
+		after = change.Seq
+	}
+	return updates, strconv.FormatInt(after, 10), nil
+}
+
+// syncSnapshot returns every tracked app as an app_added change, with a
+// cursor at the end of the journal
+func (t *Tracker) syncSnapshot(latest int64) (*SyncPage, error) {
+	apps, err := t.GetTrackedApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracked apps: %w", err)
+	}
+
+	page := &SyncPage{
+		Changes: make([]models.SyncChange, 0, len(apps)),
+		Cursor:  strconv.FormatInt(latest, 10),
+		Reset:   true,
+	}
+	for _, app := range apps {
+		page.Changes = append(page.Changes, models.SyncChange{
+			Type:     models.SyncAppAdded,
+			BundleID: app.BundleID,
+			At:       app.FirstDiscovered,
+			App:      app,
+		})
+	}
+	return page, nil
+}
+
+// hydrateChanges fills in each change's current app, and labels updates as
+// they are everywhere else
+func (t *Tracker) hydrateChanges(changes []models.SyncChange) error {
+	apps := make(map[string]*models.AppInfo)
+	var updates []models.VersionUpdate
+	for i := range changes {
+		bundleID := changes[i].BundleID
+		app, ok := apps[bundleID]
+		if !ok {
+			loaded, err := t.storage.LoadApp(bundleID)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", bundleID, err)
+			}
+			app = loaded
+			apps[bundleID] = app
+		}
+		changes[i].App = app
+		if changes[i].Update != nil {
+			updates = append(updates, *changes[i].Update)
+		}
+	}
+
+	if err := t.labelUpdates(updates); err != nil {
+		return err
+	}
+	for i := range changes {
+		if changes[i].Update != nil {
+			changes[i].Update = &updates[0]
+			updates = updates[1:]
+		}
+	}
+	return nil
+}
@@ -1,15 +1,36 @@
 package tracker
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/thomas/mavt/internal/appstore"
 	"github.com/thomas/mavt/internal/config"
-	"github.com/thomas/mavt/internal/notifier"
+	"github.com/thomas/mavt/internal/customsource"
+	"github.com/thomas/mavt/internal/events"
+	"github.com/thomas/mavt/internal/federation"
+	"github.com/thomas/mavt/internal/fleet"
+	"github.com/thomas/mavt/internal/osreleases"
+	"github.com/thomas/mavt/internal/packages"
+	"github.com/thomas/mavt/internal/recovery"
 	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/telemetry"
+	"github.com/thomas/mavt/internal/translate"
+	"github.com/thomas/mavt/internal/version"
 	"github.com/thomas/mavt/pkg/models"
 )
 
@@ -28,145 +49,2688 @@ func sanitizeForLog(s string) string {
 	return result.String()
 }
 
+// traceStorage runs a storage operation inside a span so slow disk access shows up in traces
+func traceStorage(ctx context.Context, op string, fn func() error) error {
+	_, span := telemetry.StartSpan(ctx, "storage."+op)
+	defer span.End()
+
+	err := fn()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
 // Tracker monitors app versions and detects updates
 type Tracker struct {
-	client   *appstore.Client
-	storage  *storage.Storage
-	notifier *notifier.Notifier
+	client   Source
+	storage  Store
+	notifier Notifier
+
+	// events receives what the tracker observes; the notifier subscribes to
+	// it and other integrations can too
+	events *events.Bus
+
+	// country is the default storefront; fallbackCountries are tried in
+	// order when an app isn't found in its own
+	country           string
+	fallbackCountries []string
+
+	fleet fleet.Profile
+
+	osClient    *osreleases.Client
+	osPlatforms []string
+
+	packageClient *packages.Client
+	packages      []packages.Package
+
+	customClient  *customsource.Client
+	customSources []customsource.Definition
+
+	// sources holds per-source settings; see SourceEnabled
+	sources map[string]config.SourceConfig
+
+	searchCache *searchCache
+
+	archiveRaw   bool
+	rawRetention time.Duration
+
+	detectReReleases bool
+	notifyRollbacks  bool
+	requireApproval  bool
+
+	// snapshotInterval is how often apps whose ratings alone changed are
+	// snapshotted; 0 disables snapshots
+	snapshotInterval time.Duration
+
+	// translator translates release notes into translateTarget; nil when
+	// translation is off
+	translator      translate.Translator
+	translateTarget string
+
+	maintenanceWindows []config.MaintenanceWindow
+
+	trackReviews         bool
+	reviewAlertThreshold int
+	reviewAlertWindow    time.Duration
+
+	trackInAppPurchases bool
+
+	sizeGrowthAlertPercent int
+
+	// Leader election between replicas sharing a data directory
+	leaderElection bool
+	instanceID     string
+
+	// Progress of the running check cycle, for status reporting
+	progressMu sync.Mutex
+	progress   CheckProgress
+
+	// updatesChanged is closed, and replaced on the next call to
+	// UpdatesChanged, whenever new updates are recorded
+	updatesMu      sync.Mutex
+	updatesChanged chan struct{}
+}
+
+// CheckProgress describes the check cycle in progress, if any
+type CheckProgress struct {
+	Running   bool      `json:"running"`
+	Checked   int       `json:"checked"`
+	Total     int       `json:"total"`
+	Failed    int       `json:"failed"`
+	StartedAt time.Time `json:"started_at,omitempty"`
 }
 
 // NewTracker creates a new app version tracker
-func NewTracker(cfg *config.Config, storage *storage.Storage, notifier *notifier.Notifier) *Tracker {
-	return &Tracker{
-		client:   appstore.NewClientWithCountry(cfg.Country),
-		storage:  storage,
+func NewTracker(cfg *config.Config, store Store, notifier Notifier) *Tracker {
+	client := appstore.NewClientWithLocale(cfg.Country, cfg.Language)
+	t := &Tracker{
+		client:   client,
+		storage:  store,
 		notifier: notifier,
+		events:   events.NewBus(),
+
+		country:           cfg.Country,
+		fallbackCountries: cfg.FallbackCountries,
+
+		detectReReleases: cfg.DetectReReleases,
+		notifyRollbacks:  cfg.NotifyRollbacks,
+		requireApproval:  cfg.RequireApproval,
+		snapshotInterval: cfg.SnapshotInterval,
+
+		translator:      translate.New(cfg),
+		translateTarget: cfg.TranslateTarget,
+
+		maintenanceWindows: cfg.MaintenanceWindows,
+
+		trackReviews:         cfg.TrackReviews,
+		reviewAlertThreshold: cfg.ReviewAlertThreshold,
+		reviewAlertWindow:    cfg.ReviewAlertWindow,
+
+		trackInAppPurchases: cfg.TrackInAppPurchases,
+
+		sizeGrowthAlertPercent: cfg.SizeGrowthAlertPercent,
+
+		fleet: cfg.Fleet,
+
+		osClient:    osreleases.NewClient(),
+		osPlatforms: cfg.OSPlatforms,
+
+		packageClient: packages.NewClient(cfg.Sources[config.SourceNPM].Token),
+		packages:      cfg.Packages,
+
+		customClient:  customsource.NewClient(),
+		customSources: cfg.CustomSources,
+
+		sources:     cfg.Sources,
+		searchCache: newSearchCache(cfg.SearchCacheTTL),
+
+		archiveRaw:   cfg.ArchiveRawResponses,
+		rawRetention: cfg.RawRetention,
+
+		leaderElection: cfg.LeaderElection,
+		instanceID:     cfg.InstanceID,
+	}
+	t.events.Subscribe(notifier.HandleEvent)
+	t.events.Subscribe(t.journalEvent, models.EventAppAdded, models.EventAppRemoved, models.EventAppPulled,
+		models.EventPriceChange, models.EventMetadataChange, models.EventInAppPurchaseChange)
+
+	if t.archiveRaw {
+		client.SetRawResponseHandler(func(bundleID string, body []byte) {
+			if err := t.storage.ArchiveRawResponse(bundleID, body); err != nil {
+				log.Printf("Failed to archive raw response for %s: %v", sanitizeForLog(bundleID), err)
+			}
+		})
 	}
+
+	return t
 }
 
+// SetSource replaces the App Store client apps are looked up with, e.g. with a
+// fake in tests. Raw response archiving only applies to the App Store client.
+func (t *Tracker) SetSource(source Source) {
+	t.client = source
+}
+
+// ErrAlreadyTracked is returned when tracking an app that is already
+// tracked, which leaves its record as it is; see RefreshApp
+var ErrAlreadyTracked = errors.New("app is already tracked")
+
+// ErrNotTracked is returned for an app that isn't tracked
+var ErrNotTracked = errors.New("app not tracked")
+
 // TrackApp adds an app to tracking by bundle ID
 func (t *Tracker) TrackApp(bundleID string) error {
-	app, err := t.client.LookupByBundleID(bundleID)
+	return t.TrackAppWithLocale(bundleID, "", "")
+}
+
+// TrackAppWithLocale adds an app to tracking using a specific storefront and
+// release notes language. Empty values keep the app's existing override, or
+// the configured default for newly tracked apps. Tracking an archived app
+// restores it. For an app already tracked it only applies changed
+// overrides, and returns ErrAlreadyTracked if there are none.
+func (t *Tracker) TrackAppWithLocale(bundleID, country, lang string) error {
+	// Check if we already have this app
+	existing, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load existing app: %w", err)
+	}
+
+	if existing != nil {
+		if existing.ArchivedAt == nil {
+			return t.retrack(existing, country, lang)
+		}
+		if country == "" {
+			country = existing.Country
+		}
+		if lang == "" {
+			lang = existing.Language
+		}
+	}
+
+	app, err := t.lookup(context.Background(), bundleID, country, lang)
 	if err != nil {
 		return fmt.Errorf("failed to lookup app: %w", err)
 	}
+	return t.saveTracked(existing, app, country, lang)
+}
+
+// retrack handles tracking an app that is already tracked. Its record isn't
+// replaced, which would skip recording a version change and reset what
+// checks keep on it; only a different storefront or language override is
+// saved, for the next check to use.
+func (t *Tracker) retrack(existing *models.AppInfo, country, lang string) error {
+	changed := false
+	if country != "" && !strings.EqualFold(country, existing.Country) {
+		existing.Country = country
+		changed = true
+	}
+	if lang != "" && lang != existing.Language {
+		existing.Language = lang
+		changed = true
+	}
+	if !changed {
+		return fmt.Errorf("%w: %s", ErrAlreadyTracked, existing.BundleID)
+	}
+
+	log.Printf("Changed storefront/language of %s to %s/%s", sanitizeForLog(existing.BundleID),
+		sanitizeForLog(existing.Country), sanitizeForLog(existing.Language))
+	if err := t.storage.SaveApp(existing); err != nil {
+		return fmt.Errorf("failed to save app: %w", err)
+	}
+	t.journalApp(models.SyncAppUpdated, existing.BundleID)
+	return nil
+}
+
+// TrackSearchResult tracks an app returned by SearchApps, identified by its
+// bundle ID or, without one, its App Store track ID. While the search is
+// cached the app is taken from there instead of being looked up again;
+// otherwise, or with a storefront or language other than the default ones
+// searched, it's looked up as usual. It returns the tracked app and whether
+// it was added or restored rather than already tracked, in which case the
+// error is as from TrackAppWithLocale.
+func (t *Tracker) TrackSearchResult(bundleID string, trackID int64, country, lang string) (*models.AppInfo, bool, error) {
+	cached := t.searchCache.find(SearchSourceAppStore, func(result *models.AppInfo) bool {
+		if bundleID != "" {
+			return result.BundleID == bundleID && (trackID == 0 || result.TrackID == trackID)
+		}
+		return result.TrackID == trackID
+	})
+	if bundleID == "" {
+		if cached != nil {
+			bundleID = cached.BundleID
+		} else {
+			found, err := t.client.LookupByTrackID(trackID)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to lookup app: %w", err)
+			}
+			bundleID = found.BundleID
+		}
+	}
 
-	// Check if we already have this app
 	existing, err := t.storage.LoadApp(bundleID)
 	if err != nil {
-		return fmt.Errorf("failed to load existing app: %w", err)
+		return nil, false, fmt.Errorf("failed to load existing app: %w", err)
+	}
+	if existing != nil && existing.ArchivedAt == nil {
+		return existing, false, t.retrack(existing, country, lang)
+	}
+	if existing != nil && country == "" && lang == "" {
+		country, lang = existing.Country, existing.Language
+	}
+
+	if cached == nil || country != "" || lang != "" {
+		if err := t.TrackAppWithLocale(bundleID, country, lang); err != nil {
+			return nil, false, err
+		}
+		app, err := t.storage.LoadApp(bundleID)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load app: %w", err)
+		}
+		return app, true, nil
+	}
+
+	app := cached
+	if app.Storefront == "" {
+		app.Storefront = strings.ToUpper(t.country)
+	}
+	app.LastChecked = time.Now()
+	if app.FirstDiscovered.IsZero() {
+		app.FirstDiscovered = app.LastChecked
+	}
+	if err := t.saveTracked(existing, app, country, lang); err != nil {
+		return nil, false, err
+	}
+	return app, true, nil
+}
+
+// RefreshApp checks a tracked App Store app now rather than at the next
+// cycle, recording and notifying a new version as a check does. It returns
+// the app as saved and the update found, if any.
+func (t *Tracker) RefreshApp(ctx context.Context, bundleID string) (*models.AppInfo, *models.VersionUpdate, error) {
+	existing, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !checksLocally(existing) {
+		return nil, nil, fmt.Errorf("only tracked App Store apps can be refreshed: %s", bundleID)
+	}
+	if !t.SourceEnabled(config.SourceAppStore) {
+		return nil, nil, fmt.Errorf("source %s is disabled", config.SourceAppStore)
+	}
+
+	update, err := t.checkSingleApp(ctx, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := t.storage.FlushLastChecked(); err != nil {
+		log.Printf("Failed to save last checked times: %v", err)
+	}
+	if update != nil {
+		updates := []models.VersionUpdate{*update}
+		t.publishUpdates(updates)
+		update = &updates[0]
+	}
+
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load app: %w", err)
 	}
+	return app, update, nil
+}
+
+// saveTracked saves a freshly fetched app as tracked, keeping what the user
+// set on it if it was tracked before
+func (t *Tracker) saveTracked(existing, app *models.AppInfo, country, lang string) error {
+	app.Country = country
+	app.Language = lang
 
 	if existing == nil {
 		// First time tracking this app
 		log.Printf("Now tracking %s (%s) - version %s",
 			sanitizeForLog(app.TrackName), sanitizeForLog(app.BundleID), sanitizeForLog(app.Version))
 	} else {
+		// Tracking an archived app again restores it
+		if existing.ArchivedAt != nil {
+			log.Printf("Restored archived app %s", sanitizeForLog(app.BundleID))
+		}
+
 		// Update first discovered time
 		app.FirstDiscovered = existing.FirstDiscovered
+		app.ReviewAlertedVersion = existing.ReviewAlertedVersion
+		app.DisplayName = existing.DisplayName
+		app.Notes = existing.Notes
 	}
 
 	if err := t.storage.SaveApp(app); err != nil {
 		return fmt.Errorf("failed to save app: %w", err)
 	}
+	t.recordSnapshot(existing, app)
+
+	if existing == nil || existing.ArchivedAt != nil {
+		t.emit(models.NewEvent(models.EventAppAdded, app,
+			fmt.Sprintf("Now tracking %s (version %s)", app.Name(), app.Version)))
+	}
 
 	return nil
 }
 
+// BackfillHistory adds past versions of a tracked app, e.g. from a CSV of its
+// release history, so the history doesn't start when tracking did. Each
+// update needs NewVersion and UpdatedAt; versions already in the history are
+// skipped and OldVersion is filled in from the version before. Backfilled
+// updates aren't notified. It returns how many were added.
+func (t *Tracker) BackfillHistory(bundleID string, past []models.VersionUpdate) (int, error) {
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return 0, err
+	}
+
+	history, err := t.storage.GetVersionUpdates(bundleID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get version history: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, update := range history {
+		known[update.NewVersion] = true
+	}
+
+	var added []models.VersionUpdate
+	for _, update := range past {
+		if update.NewVersion == "" || update.UpdatedAt.IsZero() || known[update.NewVersion] {
+			continue
+		}
+		known[update.NewVersion] = true
+
+		update.BundleID = app.BundleID
+		update.TrackID = app.TrackID
+		update.TrackName = app.TrackName
+		update.Backfilled = true
+		added = append(added, update)
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	// Chain each backfilled version to the one released before it
+	all := append(append([]models.VersionUpdate{}, history...), added...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].UpdatedAt.Before(all[j].UpdatedAt)
+	})
+	previous := make(map[string]string)
+	for i := 1; i < len(all); i++ {
+		previous[all[i].NewVersion] = all[i-1].NewVersion
+	}
+	for i := range added {
+		added[i].OldVersion = previous[added[i].NewVersion]
+	}
+
+	if err := t.storage.AddVersionUpdates(bundleID, added); err != nil {
+		return 0, err
+	}
+	return len(added), nil
+}
+
 // CheckForUpdates checks all tracked apps for version updates
-func (t *Tracker) CheckForUpdates() ([]models.VersionUpdate, error) {
-	apps, err := t.storage.GetAllApps()
+func (t *Tracker) CheckForUpdates(ctx context.Context) ([]models.VersionUpdate, error) {
+	ctx, span := telemetry.StartSpan(ctx, "check_cycle")
+	defer span.End()
+
+	var apps []*models.AppInfo
+	err := traceStorage(ctx, "GetAllApps", func() (err error) {
+		apps, err = t.storage.GetAllApps()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tracked apps: %w", err)
 	}
+	span.SetAttributes(attribute.Int("mavt.apps", len(apps)))
+
+	state, err := t.storage.LoadSchedulerState()
+	if err != nil {
+		log.Printf("Failed to load scheduler state: %v", err)
+		state = &storage.SchedulerState{}
+	}
+	state.LastCycleStart = time.Now()
+	if state.AppFailures == nil {
+		state.AppFailures = make(map[string]int)
+	}
+
+	// A disabled App Store source pauses app checks; other sources still run
+	checkAppStore := t.SourceEnabled(config.SourceAppStore)
+	total := 0
+	for _, app := range apps {
+		if checkAppStore && checksLocally(app) {
+			total++
+		}
+	}
+	t.startProgress(total, state.LastCycleStart)
+	defer t.finishProgress()
 
 	var updates []models.VersionUpdate
+	defer func() {
+		span.SetAttributes(attribute.Int("mavt.updates", len(updates)))
+	}()
+
+	if len(t.osPlatforms) > 0 && t.SourceEnabled(config.SourceOS) {
+		osUpdates, err := t.checkOSReleases(ctx)
+		if err != nil {
+			log.Printf("Error checking OS releases: %v", err)
+		}
+		updates = append(updates, osUpdates...)
+	}
+	updates = append(updates, t.checkPackages(ctx, apps)...)
+	if len(t.customSources) > 0 && t.SourceEnabled(config.SourceCustom) {
+		updates = append(updates, t.checkCustomSources(ctx)...)
+	}
 
+	throttled := false
 	for _, app := range apps {
-		update, err := t.checkSingleApp(app)
+		if !checkAppStore || !checksLocally(app) {
+			continue
+		}
+		if throttled {
+			// Leave the rest for the next cycle rather than adding to the load
+			t.advanceProgress(false)
+			continue
+		}
+
+		// A panic on one app (e.g. a malformed record) shouldn't stop the cycle
+		var update *models.VersionUpdate
+		check := func() error {
+			return recovery.Run("check "+app.BundleID, func() (err error) {
+				update, err = t.checkSingleApp(ctx, app)
+				return err
+			})
+		}
+		err := check()
+		if errors.Is(err, appstore.ErrThrottled) {
+			delay := throttleBackoff(err)
+			log.Printf("Rate limited by the App Store, backing off for %s", delay)
+			if sleepContext(ctx, delay) {
+				err = check()
+			}
+			if errors.Is(err, appstore.ErrThrottled) || ctx.Err() != nil {
+				// Not the app's fault, so it isn't counted as a failure
+				log.Printf("Still rate limited by the App Store, skipping the remaining apps until the next check")
+				throttled = true
+				t.advanceProgress(false)
+				continue
+			}
+		}
+		t.advanceProgress(err != nil)
 		if err != nil {
-			log.Printf("Error checking %s: %v", sanitizeForLog(app.BundleID), err)
+			state.AppFailures[app.BundleID]++
+			log.Printf("Error checking %s (%d consecutive failures): %v",
+				sanitizeForLog(app.BundleID), state.AppFailures[app.BundleID], err)
+			if state.AppFailures[app.BundleID] == 1 {
+				t.emitCheckFailure(app, err)
+			}
 			continue
 		}
+		delete(state.AppFailures, app.BundleID)
 
 		if update != nil {
 			updates = append(updates, *update)
 		}
+
+		if t.trackReviews {
+			if err := recovery.Run("reviews "+app.BundleID, func() error {
+				return t.checkReviews(app.BundleID)
+			}); err != nil {
+				log.Printf("Error checking reviews for %s: %v", sanitizeForLog(app.BundleID), err)
+			}
+		}
+	}
+
+	if err := traceStorage(ctx, "FlushLastChecked", t.storage.FlushLastChecked); err != nil {
+		log.Printf("Failed to save last checked times: %v", err)
+	}
+
+	// Forget failures of apps that are no longer checked
+	checked := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		checked[app.BundleID] = true
+	}
+	for bundleID := range state.AppFailures {
+		if !checked[bundleID] {
+			delete(state.AppFailures, bundleID)
+		}
+	}
+	state.LastCycleEnd = time.Now()
+	t.sendMaintenanceDigests(state)
+	if err := t.storage.SaveSchedulerState(state); err != nil {
+		log.Printf("Failed to save scheduler state: %v", err)
 	}
 
-	// Send notifications if updates were found
-	if len(updates) > 0 && t.notifier.IsEnabled() {
-		if err := t.notifier.NotifyUpdates(updates); err != nil {
-			log.Printf("Failed to send notifications: %v", err)
-			// Don't fail the whole operation if notification fails
+	if moved, err := t.storage.CompactHistory(); err != nil {
+		log.Printf("Failed to compress old version history: %v", err)
+	} else if moved > 0 {
+		log.Printf("Compressed %d old version update(s)", moved)
+	}
+
+	if t.archiveRaw {
+		if removed, err := t.storage.PruneRawResponses(t.rawRetention); err != nil {
+			log.Printf("Failed to prune raw response archive: %v", err)
+		} else if removed > 0 {
+			log.Printf("Pruned %d archived raw response(s)", removed)
 		}
 	}
 
+	t.publishUpdates(updates)
 	return updates, nil
 }
 
-// checkSingleApp checks a single app for updates
-func (t *Tracker) checkSingleApp(existingApp *models.AppInfo) (*models.VersionUpdate, error) {
-	// Fetch current version from App Store
-	currentApp, err := t.client.LookupByBundleID(existingApp.BundleID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch current version: %w", err)
+// publishUpdates records newly detected updates for sync and publishes them
+// so they are notified, with updates of linked apps labeled so they can be
+// notified together
+func (t *Tracker) publishUpdates(updates []models.VersionUpdate) {
+	t.journalUpdates(updates)
+
+	if err := t.annotateUpdates(updates); err != nil {
+		log.Printf("Failed to label updates with their applications: %v", err)
+	}
+	notify := t.notifiable(updates)
+	t.notifyWatchlists(notify)
+	if len(notify) > 0 {
+		t.events.Publish(models.NewUpdatesEvent(notify))
 	}
+	if len(updates) > 0 {
+		t.signalUpdates()
+	}
+}
 
-	// Preserve first discovered time
-	currentApp.FirstDiscovered = existingApp.FirstDiscovered
+// UpdatesChanged returns a channel that is closed once new updates are
+// recorded by this instance, e.g. for long-polling clients to wait on. Call
+// it again after it fires for the next change.
+func (t *Tracker) UpdatesChanged() <-chan struct{} {
+	t.updatesMu.Lock()
+	defer t.updatesMu.Unlock()
+	if t.updatesChanged == nil {
+		t.updatesChanged = make(chan struct{})
+	}
+	return t.updatesChanged
+}
 
-	// Check if version changed
-	if currentApp.Version != existingApp.Version {
-		update := &models.VersionUpdate{
-			BundleID:     currentApp.BundleID,
-			TrackID:      currentApp.TrackID,
-			TrackName:    currentApp.TrackName,
-			OldVersion:   existingApp.Version,
-			NewVersion:   currentApp.Version,
-			UpdatedAt:    time.Now(),
-			ReleaseNotes: currentApp.ReleaseNotes,
+// signalUpdates wakes everyone waiting on UpdatesChanged
+func (t *Tracker) signalUpdates() {
+	t.updatesMu.Lock()
+	defer t.updatesMu.Unlock()
+	if t.updatesChanged != nil {
+		close(t.updatesChanged)
+		t.updatesChanged = nil
+	}
+}
+
+// notifiable returns the updates to send notifications for, leaving out
+// rollbacks unless they are configured to be notified and updates held by a
+// maintenance window that hasn't ended yet
+func (t *Tracker) notifiable(updates []models.VersionUpdate) []models.VersionUpdate {
+	now := time.Now()
+	tags := t.maintenanceTags()
+
+	var notify []models.VersionUpdate
+	for _, update := range updates {
+		if update.Kind == models.UpdateKindRollback && !t.notifyRollbacks {
+			continue
+		}
+		if t.heldByMaintenance(update, tags[update.BundleID], now) {
+			continue
 		}
+		notify = append(notify, update)
+	}
+	return notify
+}
 
-		log.Printf("Version update detected for %s: %s -> %s",
-			sanitizeForLog(currentApp.TrackName),
-			sanitizeForLog(existingApp.Version),
-			sanitizeForLog(currentApp.Version))
+// emit publishes an event on the tracker's event bus
+func (t *Tracker) emit(event *models.Event) {
+	t.events.Publish(event)
+}
 
-		// Save the update
-		if err := t.storage.SaveVersionUpdate(update); err != nil {
-			return nil, fmt.Errorf("failed to save version update: %w", err)
+// emitCheckFailure sends an app_pulled event if an app is no longer on the App
+// Store, or a check_failed event for any other error. It is called when a run
+// of failed checks starts rather than on every failure.
+func (t *Tracker) emitCheckFailure(app *models.AppInfo, err error) {
+	var event *models.Event
+	if errors.Is(err, appstore.ErrNotFound) {
+		event = models.NewEvent(models.EventAppPulled, app,
+			fmt.Sprintf("%s is no longer available on the App Store", app.Name()))
+	} else {
+		event = models.NewEvent(models.EventCheckFailed, app,
+			fmt.Sprintf("Checking %s failed", app.Name()))
+	}
+	event.Details = map[string]string{"error": err.Error()}
+	t.emit(event)
+}
+
+// heldByMaintenance reports whether an update, whose application is tagged
+// tags, falls within a maintenance window that is still open at now, so it
+// waits for the window's digest
+func (t *Tracker) heldByMaintenance(update models.VersionUpdate, tags []string, now time.Time) bool {
+	for _, window := range t.maintenanceWindows {
+		if now.Before(window.End) && window.Covers(update.BundleID, tags, update.UpdatedAt) {
+			return true
 		}
+	}
+	return false
+}
 
-		// Update stored app info
-		if err := t.storage.SaveApp(currentApp); err != nil {
-			return nil, fmt.Errorf("failed to update app info: %w", err)
+// maintenanceTags returns the tags of the application each app is linked
+// into, by bundle ID, when a maintenance window is scoped by tag
+func (t *Tracker) maintenanceTags() map[string][]string {
+	if !slices.ContainsFunc(t.maintenanceWindows, func(window config.MaintenanceWindow) bool {
+		return len(window.Tags) > 0
+	}) {
+		return nil
+	}
+
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		log.Printf("Failed to load application tags for maintenance windows: %v", err)
+		return nil
+	}
+	tags := make(map[string][]string)
+	for _, application := range applications {
+		for _, member := range application.Members {
+			tags[member.BundleID] = application.Tags
 		}
+	}
+	return tags
+}
 
-		return update, nil
+// sendMaintenanceDigests sends the updates held during each maintenance window
+// that has ended as one batch notification, once per window, and records the
+// windows it has handled in state
+func (t *Tracker) sendMaintenanceDigests(state *storage.SchedulerState) {
+	digested := make(map[string]bool, len(state.DigestedWindows))
+	for _, key := range state.DigestedWindows {
+		digested[key] = true
 	}
 
-	// No version change, just update last checked time
-	currentApp.LastChecked = time.Now()
-	if err := t.storage.SaveApp(currentApp); err != nil {
-		return nil, fmt.Errorf("failed to update app info: %w", err)
+	// Only remember configured windows, so the list doesn't grow forever
+	state.DigestedWindows = nil
+	now := time.Now()
+	for _, window := range t.maintenanceWindows {
+		key := window.String()
+		if digested[key] {
+			state.DigestedWindows = append(state.DigestedWindows, key)
+			continue
+		}
+		if now.Before(window.End) {
+			continue
+		}
+
+		held, err := t.heldUpdates(window)
+		if err != nil {
+			log.Printf("Failed to collect updates held during maintenance window %s: %v", key, err)
+			continue
+		}
+		state.DigestedWindows = append(state.DigestedWindows, key)
+		if len(held) == 0 {
+			continue
+		}
+
+		log.Printf("Maintenance window %s ended, sending digest of %d held update(s)", key, len(held))
+		t.notifyWatchlists(held)
+		t.events.Publish(models.NewUpdatesEvent(held))
 	}
+}
 
-	return nil, nil
+// heldUpdates returns the notifiable updates recorded during a maintenance
+// window, oldest first
+func (t *Tracker) heldUpdates(window config.MaintenanceWindow) ([]models.VersionUpdate, error) {
+	updates, err := t.GetRecentUpdates(time.Since(window.Start))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := t.maintenanceTags()
+	var held []models.VersionUpdate
+	for _, update := range updates {
+		if update.Backfilled || !window.Covers(update.BundleID, tags[update.BundleID], update.UpdatedAt) {
+			continue
+		}
+		if update.Kind == models.UpdateKindRollback && !t.notifyRollbacks {
+			continue
+		}
+		held = append(held, update)
+	}
+	sort.Slice(held, func(i, j int) bool {
+		return held[i].UpdatedAt.Before(held[j].UpdatedAt)
+	})
+	return held, nil
 }
 
-// GetTrackedApps returns all apps being tracked
-func (t *Tracker) GetTrackedApps() ([]*models.AppInfo, error) {
-	return t.storage.GetAllApps()
+// checksLocally reports whether an app is looked up in each check cycle. OS
+// release, package and custom source pseudo-apps are checked separately, apps pulled from
+// upstream instances are checked there and archived apps aren't checked.
+func checksLocally(app *models.AppInfo) bool {
+	return !isPseudoApp(app) && !federation.IsUpstreamSource(app.Source) && app.ArchivedAt == nil
 }
 
-// GetVersionHistory returns version update history for an app
-func (t *Tracker) GetVersionHistory(bundleID string) ([]models.VersionUpdate, error) {
-	return t.storage.GetVersionUpdates(bundleID)
+// isPseudoApp reports whether an app is an OS, package or custom source
+// release stream rather than an App Store app
+func isPseudoApp(app *models.AppInfo) bool {
+	return app.Source == osreleases.Source || packages.IsSource(app.Source) || app.Source == customsource.Source
 }
 
-// RemoveApp removes an app from tracking and deletes all its history
-func (t *Tracker) RemoveApp(bundleID string) error {
-	log.Printf("Removing app from tracking: %s", sanitizeForLog(bundleID))
-	return t.storage.DeleteApp(bundleID)
+// checkSingleApp checks a single app for updates
+func (t *Tracker) checkSingleApp(ctx context.Context, existingApp *models.AppInfo) (*models.VersionUpdate, error) {
+	ctx, span := telemetry.StartSpan(ctx, "check_app", trace.WithAttributes(
+		attribute.String("app.bundle_id", existingApp.BundleID),
+	))
+	defer span.End()
+
+	// Fetch current version from App Store
+	currentApp, err := t.lookup(ctx, existingApp.BundleID, existingApp.Country, existingApp.Language)
+	if errors.Is(err, appstore.ErrNotFound) && existingApp.TrackID != 0 {
+		t.detectBundleIDChange(existingApp)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "lookup failed")
+		return nil, fmt.Errorf("failed to fetch current version: %w", err)
+	}
+
+	return t.compareAndSave(ctx, existingApp, currentApp)
+}
+
+// lookup looks an app up in the given storefront (the default if empty), then
+// in each fallback storefront if it isn't found there, and records the
+// storefront that found it. It returns the first storefront's error if no
+// storefront has the app.
+func (t *Tracker) lookup(ctx context.Context, bundleID, country, lang string) (*models.AppInfo, error) {
+	if country == "" {
+		country = t.country
+	}
+
+	app, err := t.lookupWithRetry(ctx, bundleID, country, lang)
+	if err == nil {
+		app.Storefront = strings.ToUpper(country)
+		return app, nil
+	}
+	if !errors.Is(err, appstore.ErrNotFound) {
+		return nil, err
+	}
+
+	for _, fallback := range t.fallbackCountries {
+		if strings.EqualFold(fallback, country) {
+			continue
+		}
+		app, fallbackErr := t.lookupWithRetry(ctx, bundleID, fallback, lang)
+		if fallbackErr != nil {
+			if !errors.Is(fallbackErr, appstore.ErrNotFound) {
+				log.Printf("Error looking up %s in the %s storefront: %v", sanitizeForLog(bundleID), fallback, fallbackErr)
+			}
+			continue
+		}
+		app.Storefront = fallback
+		return app, nil
+	}
+
+	return nil, err
+}
+
+// compareAndSave compares freshly fetched app info against the stored version,
+// records a version update if it changed and saves the new app info
+func (t *Tracker) compareAndSave(ctx context.Context, existingApp, currentApp *models.AppInfo) (*models.VersionUpdate, error) {
+	// Preserve first discovered time, locale overrides and the user's label
+	currentApp.FirstDiscovered = existingApp.FirstDiscovered
+	currentApp.Country = existingApp.Country
+	currentApp.Language = existingApp.Language
+	currentApp.ReviewAlertedVersion = existingApp.ReviewAlertedVersion
+	currentApp.DisplayName = existingApp.DisplayName
+	currentApp.Notes = existingApp.Notes
+	currentApp.TestFlightURL = existingApp.TestFlightURL
+	currentApp.Beta = existingApp.Beta
+
+	// CapturedAt is when the ratings were last seen changing, so unchanged
+	// ratings don't count as a metadata change
+	if currentApp.Rating.Same(existingApp.Rating) {
+		currentApp.Rating.CapturedAt = existingApp.Rating.CapturedAt
+	}
+
+	// A different trackId means the bundle ID now points at another listing
+	if existingApp.TrackID != 0 && currentApp.TrackID != existingApp.TrackID {
+		log.Printf("Warning: %s now resolves to a different App Store listing (trackId %d -> %d)",
+			sanitizeForLog(existingApp.BundleID), existingApp.TrackID, currentApp.TrackID)
+	}
+
+	t.checkMinOSVersion(existingApp, currentApp)
+	t.checkFleetDevices(existingApp, currentApp)
+	t.checkInAppPurchases(ctx, existingApp, currentApp)
+	t.checkBeta(ctx, currentApp)
+
+	if currentApp.Price != existingApp.Price {
+		event := models.NewEvent(models.EventPriceChange, currentApp, fmt.Sprintf("%s price changed from %s to %s",
+			currentApp.Name(), formatPrice(existingApp.Price), formatPrice(currentApp.Price)))
+		event.Details = map[string]string{
+			"old_price": formatPrice(existingApp.Price),
+			"new_price": formatPrice(currentApp.Price),
+			"currency":  currentApp.Currency,
+		}
+		event.PriceChange = &models.PriceChange{
+			BundleID:  currentApp.BundleID,
+			OldPrice:  existingApp.Price,
+			NewPrice:  currentApp.Price,
+			Currency:  currentApp.Currency,
+			ChangedAt: event.OccurredAt,
+		}
+		t.emit(event)
+	}
+
+	// Check if version changed. A version that is only written differently,
+	// e.g. "1.2" becoming "1.2.0", isn't a new release.
+	currentVersion := models.ParseVersion(currentApp.Version)
+	existingVersion := models.ParseVersion(existingApp.Version)
+	if currentApp.Version != existingApp.Version && !currentVersion.Equal(existingVersion) {
+		update := &models.VersionUpdate{
+			BundleID:     currentApp.BundleID,
+			TrackID:      currentApp.TrackID,
+			TrackName:    currentApp.TrackName,
+			OldVersion:   existingApp.Version,
+			NewVersion:   currentApp.Version,
+			UpdatedAt:    time.Now(),
+			ReleaseNotes: currentApp.ReleaseNotes,
+			Language:     currentApp.Language,
+			ReleasedAt:   releasedAt(currentApp),
+			DisplayName:  currentApp.DisplayName,
+			AppNotes:     currentApp.Notes,
+
+			OldFileSizeBytes: existingApp.FileSizeBytes,
+			FileSizeBytes:    currentApp.FileSizeBytes,
+
+			Approval: t.pendingApproval(),
+		}
+		update.DevicesAdded, update.DevicesRemoved = fleet.DiffDevices(existingApp.SupportedDevices, currentApp.SupportedDevices)
+
+		if currentVersion.Compare(existingVersion) < 0 {
+			update.Kind = models.UpdateKindRollback
+			log.Printf("Version rollback detected for %s: %s -> %s",
+				sanitizeForLog(currentApp.TrackName),
+				sanitizeForLog(existingApp.Version),
+				sanitizeForLog(currentApp.Version))
+		} else {
+			log.Printf("Version update detected for %s: %s -> %s",
+				sanitizeForLog(currentApp.TrackName),
+				sanitizeForLog(existingApp.Version),
+				sanitizeForLog(currentApp.Version))
+		}
+
+		t.translateNotes(ctx, update)
+
+		// Save the update. A duplicate (e.g. replaying a restored backup) is
+		// recorded already, so it is neither saved again nor notified.
+		err := traceStorage(ctx, "SaveVersionUpdate", func() error {
+			return t.storage.SaveVersionUpdate(update)
+		})
+		if errors.Is(err, storage.ErrDuplicateUpdate) {
+			log.Printf("Skipping duplicate update for %s: %s -> %s",
+				sanitizeForLog(currentApp.BundleID), sanitizeForLog(existingApp.Version), sanitizeForLog(currentApp.Version))
+			update = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to save version update: %w", err)
+		}
+		if update != nil && update.Kind == "" {
+			t.checkSizeGrowth(existingApp, currentApp)
+		}
+
+		// Update stored app info
+		if err := traceStorage(ctx, "SaveApp", func() error {
+			return t.storage.SaveApp(currentApp)
+		}); err != nil {
+			return nil, fmt.Errorf("failed to update app info: %w", err)
+		}
+		t.recordSnapshot(existingApp, currentApp)
+
+		return update, nil
+	}
+
+	// No version change. If nothing else changed either, only the last checked
+	// time needs recording, which is batched and flushed once per cycle.
+	currentApp.LastChecked = time.Now()
+	if sameMetadata(existingApp, currentApp) {
+		t.storage.TouchApp(currentApp.BundleID, currentApp.LastChecked)
+		return nil, nil
+	}
+
+	var update *models.VersionUpdate
+	if t.detectReReleases && isReRelease(existingApp, currentApp) {
+		update = &models.VersionUpdate{
+			BundleID:     currentApp.BundleID,
+			TrackID:      currentApp.TrackID,
+			TrackName:    currentApp.TrackName,
+			OldVersion:   existingApp.Version,
+			NewVersion:   currentApp.Version,
+			UpdatedAt:    currentApp.LastChecked,
+			ReleaseNotes: currentApp.ReleaseNotes,
+			Language:     currentApp.Language,
+			ReleasedAt:   releasedAt(currentApp),
+			Kind:         models.UpdateKindReRelease,
+			DisplayName:  currentApp.DisplayName,
+			AppNotes:     currentApp.Notes,
+		}
+
+		log.Printf("Re-release detected for %s %s: release notes or release date changed",
+			sanitizeForLog(currentApp.TrackName), sanitizeForLog(currentApp.Version))
+		t.translateNotes(ctx, update)
+
+		err := traceStorage(ctx, "SaveVersionUpdate", func() error {
+			return t.storage.SaveVersionUpdate(update)
+		})
+		if errors.Is(err, storage.ErrDuplicateUpdate) {
+			update = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to save version update: %w", err)
+		}
+	}
+
+	if err := traceStorage(ctx, "SaveApp", func() error {
+		return t.storage.SaveApp(currentApp)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update app info: %w", err)
+	}
+	t.recordSnapshot(existingApp, currentApp)
+
+	// Report other metadata changes, leaving out what the re-release or price
+	// change events already cover
+	if update == nil {
+		if fields := changedMetadata(existingApp, currentApp); len(fields) > 0 {
+			event := models.NewEvent(models.EventMetadataChange, currentApp,
+				fmt.Sprintf("%s changed %s", currentApp.Name(), strings.Join(fields, ", ")))
+			event.Details = map[string]string{"fields": strings.Join(fields, ",")}
+			event.MetadataChange = &models.MetadataChange{
+				BundleID:  currentApp.BundleID,
+				Fields:    fields,
+				ChangedAt: event.OccurredAt,
+			}
+			t.emit(event)
+		}
+	}
+
+	return update, nil
+}
+
+// unreportedFields are app fields left out of metadata_change events: the
+// check time, ratings and the TestFlight beta's check time change all the
+// time, prices and in-app purchases have their own events, and description
+// and screenshot changes are left to the app's snapshots
+var unreportedFields = map[string]bool{"last_checked": true, "rating": true, "price": true, "currency": true, "in_app_purchases": true, "beta": true, "description": true, "screenshot_count": true}
+
+// changedMetadata returns the JSON names of the app fields that differ between
+// two snapshots of an app, sorted
+func changedMetadata(a, b *models.AppInfo) []string {
+	var changed []string
+	for _, change := range models.DiffApps(a, b) {
+		if !unreportedFields[change.Field] {
+			changed = append(changed, change.Field)
+		}
+	}
+	return changed
+}
+
+// formatPrice formats an App Store price for events
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+// releasedAt returns the app's current version release date for recording on
+// an update, or nil if the App Store didn't provide one
+func releasedAt(app *models.AppInfo) *time.Time {
+	if app.ReleaseDate.IsZero() {
+		return nil
+	}
+	released := app.ReleaseDate
+	return &released
+}
+
+// isReRelease reports whether the App Store changed an app's release notes or
+// release date without changing its version. Apps stored without a release
+// date don't count, since there is nothing to compare against.
+func isReRelease(existingApp, currentApp *models.AppInfo) bool {
+	if existingApp.ReleaseDate.IsZero() {
+		return false
+	}
+	return !existingApp.ReleaseDate.Equal(currentApp.ReleaseDate) ||
+		strings.TrimSpace(existingApp.ReleaseNotes) != strings.TrimSpace(currentApp.ReleaseNotes)
+}
+
+// sameMetadata reports whether two snapshots of an app are identical apart
+// from their last checked time and when their beta was last checked
+func sameMetadata(a, b *models.AppInfo) bool {
+	aCopy, bCopy := *a, *b
+	aCopy.LastChecked, bCopy.LastChecked = time.Time{}, time.Time{}
+	aCopy.Beta, bCopy.Beta = uncheckedBeta(a.Beta), uncheckedBeta(b.Beta)
+
+	aJSON, errA := json.Marshal(aCopy)
+	bJSON, errB := json.Marshal(bCopy)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// uncheckedBeta returns a copy of a beta status without its check time
+func uncheckedBeta(beta *models.BetaStatus) *models.BetaStatus {
+	if beta == nil {
+		return nil
+	}
+	copied := *beta
+	copied.CheckedAt = time.Time{}
+	return &copied
+}
+
+// detectBundleIDChange looks up an app that is no longer found by bundle ID
+// using its stored trackId. If the listing now has a different bundle ID the
+// developer has migrated the app, so warn once and record where it moved.
+func (t *Tracker) detectBundleIDChange(existingApp *models.AppInfo) {
+	app, err := t.client.LookupByTrackID(existingApp.TrackID)
+	if err != nil || app.BundleID == existingApp.BundleID || app.BundleID == existingApp.MovedTo {
+		return
+	}
+
+	log.Printf("Warning: %s appears to have moved to bundle ID %s; run 'mavt -merge %s %s' to merge its history",
+		sanitizeForLog(existingApp.BundleID), sanitizeForLog(app.BundleID),
+		sanitizeForLog(existingApp.BundleID), sanitizeForLog(app.BundleID))
+
+	existingApp.MovedTo = app.BundleID
+	if err := t.storage.SaveApp(existingApp); err != nil {
+		log.Printf("Error saving moved bundle ID for %s: %v", sanitizeForLog(existingApp.BundleID), err)
+	}
+}
+
+// MergeApps folds an old bundle ID into a new one after a developer migrates an
+// app. The new bundle ID is tracked if needed, the old version history and
+// reviews are moved across and the old app is removed.
+func (t *Tracker) MergeApps(oldBundleID, newBundleID string) error {
+	if oldBundleID == newBundleID {
+		return fmt.Errorf("cannot merge %s into itself", oldBundleID)
+	}
+
+	oldApp, err := t.storage.LoadApp(oldBundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load app: %w", err)
+	}
+	if oldApp == nil {
+		return fmt.Errorf("app not tracked: %s", oldBundleID)
+	}
+
+	newApp, err := t.storage.LoadApp(newBundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load app: %w", err)
+	}
+	if newApp == nil {
+		if err := t.TrackAppWithLocale(newBundleID, oldApp.Country, oldApp.Language); err != nil {
+			return err
+		}
+		if newApp, err = t.storage.LoadApp(newBundleID); err != nil {
+			return fmt.Errorf("failed to load app: %w", err)
+		}
+	}
+
+	if err := t.storage.MergeVersionUpdates(oldBundleID, newBundleID); err != nil {
+		return fmt.Errorf("failed to merge version history: %w", err)
+	}
+
+	reviews, err := t.storage.GetReviews(oldBundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load reviews: %w", err)
+	}
+	if len(reviews) > 0 {
+		for i := range reviews {
+			reviews[i].BundleID = newBundleID
+		}
+		if _, err := t.storage.AddReviews(newBundleID, reviews); err != nil {
+			return fmt.Errorf("failed to merge reviews: %w", err)
+		}
+	}
+
+	// Keep the earliest discovery date across both bundle IDs, and the old
+	// app's label unless the new one has its own
+	changed := false
+	if !oldApp.FirstDiscovered.IsZero() && oldApp.FirstDiscovered.Before(newApp.FirstDiscovered) {
+		newApp.FirstDiscovered = oldApp.FirstDiscovered
+		changed = true
+	}
+	if newApp.DisplayName == "" && newApp.Notes == "" && (oldApp.DisplayName != "" || oldApp.Notes != "") {
+		newApp.DisplayName = oldApp.DisplayName
+		newApp.Notes = oldApp.Notes
+		changed = true
+	}
+	if changed {
+		if err := t.storage.SaveApp(newApp); err != nil {
+			return fmt.Errorf("failed to save app: %w", err)
+		}
+	}
+
+	log.Printf("Merged %s into %s", sanitizeForLog(oldBundleID), sanitizeForLog(newBundleID))
+	return t.storage.DeleteApp(oldBundleID)
+}
+
+// SyncUpstream pulls the apps tracked by another MAVT instance and its version
+// updates within since, and notifies about updates not seen before. Apps
+// tracked locally (or pulled from a different upstream) are left alone, and
+// apps the upstream no longer tracks are archived. It returns the number of
+// apps synced and new updates stored.
+func (t *Tracker) SyncUpstream(ctx context.Context, client *federation.Client, since time.Duration) (int, int, error) {
+	source := client.Source()
+
+	remoteApps, err := client.FetchApps(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	synced := make(map[string]bool, len(remoteApps))
+	for _, app := range remoteApps {
+		existing, err := t.storage.LoadApp(app.BundleID)
+		if err != nil {
+			return len(synced), 0, fmt.Errorf("failed to load app: %w", err)
+		}
+		if existing != nil && existing.Source != source {
+			continue
+		}
+
+		app.Source = source
+		app.ArchivedAt = nil
+		if existing != nil && existing.FirstDiscovered.Before(app.FirstDiscovered) {
+			app.FirstDiscovered = existing.FirstDiscovered
+		}
+		if err := t.storage.SaveApp(app); err != nil {
+			return len(synced), 0, fmt.Errorf("failed to save app: %w", err)
+		}
+		if existing == nil || existing.ArchivedAt != nil {
+			t.journalApp(models.SyncAppAdded, app.BundleID)
+		}
+		synced[app.BundleID] = true
+	}
+
+	apps, err := t.storage.GetAllApps()
+	if err != nil {
+		return len(synced), 0, fmt.Errorf("failed to load tracked apps: %w", err)
+	}
+	for _, app := range apps {
+		if app.Source == source && !synced[app.BundleID] && app.ArchivedAt == nil {
+			if err := t.RemoveApp(app.BundleID); err != nil {
+				log.Printf("Failed to archive %s no longer tracked upstream: %v", sanitizeForLog(app.BundleID), err)
+			}
+		}
+	}
+
+	remoteUpdates, err := client.FetchUpdates(ctx, since)
+	if err != nil {
+		return len(synced), 0, err
+	}
+
+	var added []models.VersionUpdate
+	for _, update := range remoteUpdates {
+		if !synced[update.BundleID] {
+			continue
+		}
+
+		// Acknowledgements, assignments and approvals belong to the
+		// upstream's team
+		update.Acknowledged = nil
+		update.Assignment = nil
+		update.Approval = t.pendingApproval()
+
+		err := t.storage.SaveVersionUpdate(&update)
+		if errors.Is(err, storage.ErrDuplicateUpdate) {
+			continue
+		}
+		if err != nil {
+			return len(synced), len(added), fmt.Errorf("failed to save version update: %w", err)
+		}
+		added = append(added, update)
+	}
+
+	t.journalUpdates(added)
+	if notify := t.notifiable(added); len(notify) > 0 {
+		t.events.Publish(models.NewUpdatesEvent(notify))
+	}
+	if len(added) > 0 {
+		t.signalUpdates()
+	}
+
+	return len(synced), len(added), nil
+}
+
+// Progress returns the progress of the running check cycle
+func (t *Tracker) Progress() CheckProgress {
+	t.progressMu.Lock()
+	defer t.progressMu.Unlock()
+	return t.progress
+}
+
+func (t *Tracker) startProgress(total int, startedAt time.Time) {
+	t.progressMu.Lock()
+	defer t.progressMu.Unlock()
+	t.progress = CheckProgress{Running: true, Total: total, StartedAt: startedAt}
+}
+
+func (t *Tracker) advanceProgress(failed bool) {
+	t.progressMu.Lock()
+	defer t.progressMu.Unlock()
+	t.progress.Checked++
+	if failed {
+		t.progress.Failed++
+	}
+}
+
+func (t *Tracker) finishProgress() {
+	t.progressMu.Lock()
+	defer t.progressMu.Unlock()
+	t.progress = CheckProgress{}
+}
+
+// SchedulerState returns the persisted state of the check scheduler
+func (t *Tracker) SchedulerState() (*storage.SchedulerState, error) {
+	return t.storage.LoadSchedulerState()
+}
+
+// Lead reports whether this instance should run the named singleton task,
+// e.g. a check cycle. Without leader election it always should; with it, the
+// instance must take or renew the task's lease, which it keeps for ttl.
+// Replicas that aren't leading reload shared state written by the leader.
+func (t *Tracker) Lead(task string, ttl time.Duration) bool {
+	if !t.leaderElection {
+		return true
+	}
+
+	leading, err := t.storage.AcquireLease(task, t.instanceID, ttl)
+	if err != nil {
+		log.Printf("Failed to acquire %s lease: %v", task, err)
+		return false
+	}
+	if !leading {
+		t.storage.ReloadLastChecked()
+	}
+	return leading
+}
+
+// ReleaseLead gives up the named task's lease so another replica can take
+// over straight away, e.g. on shutdown
+func (t *Tracker) ReleaseLead(task string) {
+	if !t.leaderElection {
+		return
+	}
+	if err := t.storage.ReleaseLease(task, t.instanceID); err != nil {
+		log.Printf("Failed to release %s lease: %v", task, err)
+	}
+}
+
+// checkOSReleases fetches the latest Apple OS releases for the configured platforms
+// and records them as pseudo-apps so they appear alongside app updates
+func (t *Tracker) checkOSReleases(ctx context.Context) ([]models.VersionUpdate, error) {
+	releases, err := t.osClient.FetchLatest(t.osPlatforms)
+	if err != nil {
+		return nil, err
+	}
+	return t.recordReleases(ctx, releases), nil
+}
+
+// checkPackages fetches the latest release of each configured npm and PyPI
+// package, and of each tracked from search, and records them as pseudo-apps.
+// A package that can't be fetched doesn't stop the others being checked.
+func (t *Tracker) checkPackages(ctx context.Context, apps []*models.AppInfo) []models.VersionUpdate {
+	pkgs := append([]packages.Package(nil), t.packages...)
+	configured := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		configured[pkg.BundleID()] = true
+	}
+	for _, app := range apps {
+		if packages.IsSource(app.Source) && app.ArchivedAt == nil && !configured[app.BundleID] {
+			pkgs = append(pkgs, packages.Package{Registry: app.Source, Name: app.TrackName})
+		}
+	}
+
+	var releases []*models.AppInfo
+	for _, pkg := range pkgs {
+		if !t.SourceEnabled(pkg.Registry) {
+			continue
+		}
+		release, err := t.packageClient.FetchLatest(ctx, pkg)
+		if err != nil {
+			log.Printf("Error checking package %s: %v", sanitizeForLog(pkg.String()), err)
+			continue
+		}
+		releases = append(releases, release)
+	}
+	return t.recordReleases(ctx, releases)
+}
+
+// checkCustomSources evaluates each custom JSON endpoint source and records
+// its current version as a pseudo-app
+func (t *Tracker) checkCustomSources(ctx context.Context) []models.VersionUpdate {
+	var releases []*models.AppInfo
+	for _, definition := range t.customSources {
+		release, err := t.customClient.FetchLatest(ctx, definition)
+		if err != nil {
+			log.Printf("Error checking custom source %s: %v", sanitizeForLog(definition.ID), err)
+			continue
+		}
+		releases = append(releases, release)
+	}
+	return t.recordReleases(ctx, releases)
+}
+
+// recordReleases saves pseudo-app releases, starting to track new ones and
+// comparing the rest with what was stored
+func (t *Tracker) recordReleases(ctx context.Context, releases []*models.AppInfo) []models.VersionUpdate {
+	var updates []models.VersionUpdate
+	for _, release := range releases {
+		existing, err := t.storage.LoadApp(release.BundleID)
+		if err != nil {
+			log.Printf("Error loading %s: %v", sanitizeForLog(release.BundleID), err)
+			continue
+		}
+
+		if existing == nil {
+			log.Printf("Now tracking %s releases - version %s",
+				sanitizeForLog(release.TrackName), sanitizeForLog(release.Version))
+			if err := t.storage.SaveApp(release); err != nil {
+				log.Printf("Error saving %s: %v", sanitizeForLog(release.BundleID), err)
+				continue
+			}
+			t.journalApp(models.SyncAppAdded, release.BundleID)
+			continue
+		}
+
+		update, err := t.compareAndSave(ctx, existing, release)
+		if err != nil {
+			log.Printf("Error checking %s: %v", sanitizeForLog(release.BundleID), err)
+			continue
+		}
+		if update != nil {
+			updates = append(updates, *update)
+		}
+	}
+
+	return updates
+}
+
+// checkMinOSVersion raises a compatibility alert when an app's minimum OS version
+// rises above the oldest OS version in the fleet
+func (t *Tracker) checkMinOSVersion(existingApp, currentApp *models.AppInfo) {
+	if !t.fleet.ExceedsMinOS(currentApp.MinOSVersion) || t.fleet.ExceedsMinOS(existingApp.MinOSVersion) {
+		return
+	}
+
+	log.Printf("Minimum OS for %s rose from %s to %s, above fleet minimum %s",
+		sanitizeForLog(currentApp.TrackName),
+		sanitizeForLog(existingApp.MinOSVersion),
+		sanitizeForLog(currentApp.MinOSVersion),
+		sanitizeForLog(t.fleet.MinOSVersion))
+
+	t.emit(models.NewAlertEvent(models.EventMinOSIncrease, &models.Alert{
+		App:        currentApp,
+		OldMinOS:   existingApp.MinOSVersion,
+		FleetMinOS: t.fleet.MinOSVersion,
+	}))
+}
+
+// checkFleetDevices raises a compatibility alert when an app drops support for
+// device models in the fleet, or for every model of a fleet device family
+func (t *Tracker) checkFleetDevices(existingApp, currentApp *models.AppInfo) {
+	dropped := t.fleet.Dropped(existingApp.SupportedDevices, currentApp.SupportedDevices)
+	if len(dropped) == 0 {
+		return
+	}
+
+	log.Printf("%s dropped support for fleet devices: %s",
+		sanitizeForLog(currentApp.TrackName), sanitizeForLog(strings.Join(dropped, ", ")))
+
+	t.emit(models.NewAlertEvent(models.EventDevicesDropped, &models.Alert{App: currentApp, Devices: dropped}))
+}
+
+// FleetProfile returns the fleet compatibility is checked against
+func (t *Tracker) FleetProfile() fleet.Profile {
+	return t.fleet
+}
+
+// GetCompatibilityRisks returns the tracked apps that some fleet devices
+// can't install or update
+func (t *Tracker) GetCompatibilityRisks() ([]fleet.Risk, error) {
+	apps, err := t.GetTrackedApps()
+	if err != nil {
+		return nil, err
+	}
+	return t.fleet.Risks(apps), nil
+}
+
+// checkSizeGrowth raises an alert when an update grows an app's download by at
+// least the configured percentage
+func (t *Tracker) checkSizeGrowth(existingApp, currentApp *models.AppInfo) {
+	if t.sizeGrowthAlertPercent <= 0 || existingApp.FileSizeBytes <= 0 || currentApp.FileSizeBytes <= existingApp.FileSizeBytes {
+		return
+	}
+
+	growth := float64(currentApp.FileSizeBytes-existingApp.FileSizeBytes) * 100 / float64(existingApp.FileSizeBytes)
+	if growth < float64(t.sizeGrowthAlertPercent) {
+		return
+	}
+
+	log.Printf("Download size of %s %s grew %.0f%% (%d -> %d bytes)",
+		sanitizeForLog(currentApp.TrackName), sanitizeForLog(currentApp.Version),
+		growth, existingApp.FileSizeBytes, currentApp.FileSizeBytes)
+
+	t.emit(models.NewAlertEvent(models.EventSizeGrowth, &models.Alert{App: currentApp, OldSize: existingApp.FileSizeBytes}))
+}
+
+// GetSizeHistory returns an app's download size at each version it was
+// updated to, oldest first, ending with its current size. Versions recorded
+// before sizes were stored are left out.
+func (t *Tracker) GetSizeHistory(bundleID string) ([]models.SizePoint, error) {
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, fmt.Errorf("app not tracked: %s", bundleID)
+	}
+
+	updates, err := t.storage.GetVersionUpdates(bundleID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].ReleaseTime().Before(updates[j].ReleaseTime())
+	})
+
+	points := []models.SizePoint{}
+	for _, update := range updates {
+		if update.Kind == models.UpdateKindReRelease || update.FileSizeBytes <= 0 {
+			continue
+		}
+		if len(points) == 0 && update.OldFileSizeBytes > 0 {
+			points = append(points, models.SizePoint{Version: update.OldVersion, FileSizeBytes: update.OldFileSizeBytes})
+		}
+		released := update.ReleaseTime()
+		points = append(points, models.SizePoint{
+			Version:       update.NewVersion,
+			FileSizeBytes: update.FileSizeBytes,
+			ReleasedAt:    &released,
+		})
+	}
+
+	if app.FileSizeBytes > 0 && (len(points) == 0 || points[len(points)-1].Version != app.Version) {
+		points = append(points, models.SizePoint{
+			Version:       app.Version,
+			FileSizeBytes: app.FileSizeBytes,
+			ReleasedAt:    releasedAt(app),
+		})
+	}
+	return points, nil
+}
+
+// checkInAppPurchases reads the in-app purchases on an App Store app's store
+// page into currentApp and reports changes from the stored list. The stored
+// list is kept if the page can't be read; the first list read is recorded
+// without reporting it as a change.
+func (t *Tracker) checkInAppPurchases(ctx context.Context, existingApp, currentApp *models.AppInfo) {
+	if !t.trackInAppPurchases || currentApp.Source != "" || currentApp.TrackID == 0 {
+		return
+	}
+
+	currentApp.InAppPurchases = existingApp.InAppPurchases
+	purchases, err := t.client.FetchInAppPurchases(ctx, currentApp.TrackID, currentApp.Storefront)
+	if err != nil {
+		log.Printf("Error reading in-app purchases of %s: %v", sanitizeForLog(currentApp.BundleID), err)
+		return
+	}
+	currentApp.InAppPurchases = purchases
+	if existingApp.InAppPurchases == nil {
+		return
+	}
+
+	change := diffInAppPurchases(existingApp.InAppPurchases, purchases)
+	if change == nil {
+		return
+	}
+
+	var parts []string
+	if len(change.Added) > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", len(change.Added)))
+	}
+	if len(change.Removed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", len(change.Removed)))
+	}
+	if len(change.Repriced) > 0 {
+		parts = append(parts, fmt.Sprintf("%d repriced", len(change.Repriced)))
+	}
+	log.Printf("In-app purchases of %s changed: %s", sanitizeForLog(currentApp.TrackName), strings.Join(parts, ", "))
+
+	event := models.NewEvent(models.EventInAppPurchaseChange, currentApp,
+		fmt.Sprintf("%s in-app purchases changed: %s", currentApp.Name(), strings.Join(parts, ", ")))
+	event.Details = map[string]string{
+		"added":    iapNames(change.Added),
+		"removed":  iapNames(change.Removed),
+		"repriced": iapRepricings(change.Repriced),
+	}
+	change.BundleID = currentApp.BundleID
+	change.ChangedAt = event.OccurredAt
+	event.InAppPurchaseChange = change
+	t.emit(event)
+}
+
+// checkBeta reads the state of an app's public TestFlight beta, if it has a
+// link, keeping the stored state if the page can't be read
+func (t *Tracker) checkBeta(ctx context.Context, app *models.AppInfo) {
+	if app.TestFlightURL == "" {
+		return
+	}
+
+	status, err := t.client.FetchTestFlight(ctx, app.TestFlightURL)
+	if err != nil {
+		log.Printf("Error reading TestFlight beta of %s: %v", sanitizeForLog(app.BundleID), err)
+		return
+	}
+	if status == nil {
+		return
+	}
+
+	now := time.Now()
+	status.CheckedAt = now
+	status.FirstSeen = now
+	if status.Same(app.Beta) {
+		status.FirstSeen = app.Beta.FirstSeen
+	} else if status.AheadOf(app.Version) {
+		log.Printf("TestFlight beta %s (%s) of %s is ahead of the App Store (%s)",
+			sanitizeForLog(status.Version), sanitizeForLog(status.Build), sanitizeForLog(app.TrackName), sanitizeForLog(app.Version))
+	}
+	app.Beta = status
+}
+
+// diffInAppPurchases compares two in-app purchase lists by name, returning
+// nil if they list the same purchases at the same prices
+func diffInAppPurchases(old, current []models.InAppPurchase) *models.InAppPurchaseChange {
+	oldPrices := make(map[string]string, len(old))
+	for _, purchase := range old {
+		oldPrices[purchase.Name] = purchase.Price
+	}
+	currentNames := make(map[string]bool, len(current))
+
+	change := &models.InAppPurchaseChange{}
+	for _, purchase := range current {
+		currentNames[purchase.Name] = true
+		oldPrice, ok := oldPrices[purchase.Name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, purchase)
+		case oldPrice != purchase.Price:
+			change.Repriced = append(change.Repriced, models.IAPRepricing{
+				Name:     purchase.Name,
+				OldPrice: oldPrice,
+				NewPrice: purchase.Price,
+			})
+		}
+	}
+	for _, purchase := range old {
+		if !currentNames[purchase.Name] {
+			change.Removed = append(change.Removed, purchase)
+		}
+	}
+
+	if len(change.Added) == 0 && len(change.Removed) == 0 && len(change.Repriced) == 0 {
+		return nil
+	}
+	return change
+}
+
+// iapNames formats in-app purchases for event details as "name (price)"
+func iapNames(purchases []models.InAppPurchase) string {
+	names := make([]string, len(purchases))
+	for i, purchase := range purchases {
+		names[i] = purchase.Name + " (" + purchase.Price + ")"
+	}
+	return strings.Join(names, "; ")
+}
+
+// iapRepricings formats repriced in-app purchases for event details as
+// "name: old -> new"
+func iapRepricings(repriced []models.IAPRepricing) string {
+	parts := make([]string, len(repriced))
+	for i, r := range repriced {
+		parts[i] = r.Name + ": " + r.OldPrice + " -> " + r.NewPrice
+	}
+	return strings.Join(parts, "; ")
+}
+
+// checkReviews stores new customer reviews for an app and alerts on a burst of
+// 1-star reviews shortly after its current version was released
+func (t *Tracker) checkReviews(bundleID string) error {
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil || app == nil {
+		return err
+	}
+
+	reviews, err := t.client.FetchReviews(app.TrackID, app.Country)
+	if err != nil {
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	added, err := t.storage.AddReviews(bundleID, reviews)
+	if err != nil {
+		return fmt.Errorf("failed to save reviews: %w", err)
+	}
+
+	if len(added) > 0 {
+		log.Printf("Stored %d new review(s) for %s", len(added), sanitizeForLog(app.TrackName))
+	}
+
+	if app.ReviewAlertedVersion == app.Version {
+		return nil
+	}
+
+	stored, err := t.storage.GetReviews(bundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load reviews: %w", err)
+	}
+
+	// Count 1-star reviews for the current version within the alert window after release
+	windowEnd := app.ReleaseDate.Add(t.reviewAlertWindow)
+	oneStar := 0
+	for _, review := range stored {
+		if review.Rating == 1 && review.Version == app.Version &&
+			!review.UpdatedAt.Before(app.ReleaseDate) && review.UpdatedAt.Before(windowEnd) {
+			oneStar++
+		}
+	}
+
+	if oneStar < t.reviewAlertThreshold {
+		return nil
+	}
+
+	log.Printf("Negative review burst detected for %s %s: %d one-star reviews",
+		sanitizeForLog(app.TrackName), sanitizeForLog(app.Version), oneStar)
+
+	t.emit(models.NewAlertEvent(models.EventReviewBurst, &models.Alert{App: app, OneStarCount: oneStar}))
+
+	app.ReviewAlertedVersion = app.Version
+	if err := t.storage.SaveApp(app); err != nil {
+		return fmt.Errorf("failed to update app info: %w", err)
+	}
+
+	return nil
+}
+
+// GetReviews returns stored reviews for an app, most recent first, with a rating summary
+func (t *Tracker) GetReviews(bundleID string) ([]models.Review, *models.ReviewSummary, error) {
+	reviews, err := t.storage.GetReviews(bundleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].UpdatedAt.After(reviews[j].UpdatedAt)
+	})
+
+	return reviews, summarizeReviews(bundleID, reviews), nil
+}
+
+// summarizeReviews computes the average rating and compares the most recent
+// reviews against the ones before them to derive a trend
+func summarizeReviews(bundleID string, reviews []models.Review) *models.ReviewSummary {
+	const window = 10
+
+	summary := &models.ReviewSummary{
+		BundleID:     bundleID,
+		TotalReviews: len(reviews),
+		Trend:        "insufficient data",
+	}
+
+	if len(reviews) == 0 {
+		return summary
+	}
+
+	average := func(rs []models.Review) float64 {
+		if len(rs) == 0 {
+			return 0
+		}
+		total := 0
+		for _, r := range rs {
+			total += r.Rating
+		}
+		return float64(total) / float64(len(rs))
+	}
+
+	summary.AverageRating = average(reviews)
+
+	recent := reviews
+	if len(recent) > window {
+		recent = reviews[:window]
+	}
+	summary.RecentAverage = average(recent)
+
+	if len(reviews) <= window {
+		return summary
+	}
+
+	prior := reviews[window:]
+	if len(prior) > window {
+		prior = prior[:window]
+	}
+	summary.PriorAverage = average(prior)
+
+	switch diff := summary.RecentAverage - summary.PriorAverage; {
+	case diff >= 0.5:
+		summary.Trend = "improving"
+	case diff <= -0.5:
+		summary.Trend = "declining"
+	default:
+		summary.Trend = "stable"
+	}
+
+	return summary
+}
+
+// SearchApps searches for apps by name
+func (t *Tracker) SearchApps(term string, limit int) ([]*models.AppInfo, error) {
+	return t.searchCache.search(SearchSourceAppStore, term, limit, func() ([]*models.AppInfo, error) {
+		return t.client.SearchApps(term, limit)
+	})
+}
+
+// Search sources, for SearchSource
+const (
+	SearchSourceAppStore = config.SourceAppStore
+	SearchSourceAll      = "all"
+)
+
+// SearchSources lists the sources SearchSource can search
+var SearchSources = []string{SearchSourceAppStore, packages.SourceNPM, packages.SourcePyPI}
+
+// SourceEnabled reports whether a source (one of config.SourceNames) is
+// enabled. Sources without settings, e.g. in configs built in code, are.
+func (t *Tracker) SourceEnabled(source string) bool {
+	settings, ok := t.sources[source]
+	return !ok || settings.Enabled
+}
+
+// SearchSource searches one source, or every enabled source with
+// SearchSourceAll. Results carry their source in AppInfo.Source, which is
+// empty for the App Store. With SearchSourceAll a failing source is logged
+// and skipped so the others' results are still returned.
+func (t *Tracker) SearchSource(ctx context.Context, source, term string, limit int) ([]*models.AppInfo, error) {
+	if source != SearchSourceAll && !t.SourceEnabled(source) {
+		return nil, fmt.Errorf("source %s is disabled", source)
+	}
+
+	switch source {
+	case SearchSourceAppStore:
+		return t.SearchApps(term, limit)
+	case packages.SourceNPM, packages.SourcePyPI:
+		return t.searchCache.search(source, term, limit, func() ([]*models.AppInfo, error) {
+			return t.packageClient.Search(ctx, source, term, limit)
+		})
+	case SearchSourceAll:
+	default:
+		return nil, fmt.Errorf("unknown search source %q", source)
+	}
+
+	results := make([][]*models.AppInfo, len(SearchSources))
+	var wg sync.WaitGroup
+	for i, source := range SearchSources {
+		if !t.SourceEnabled(source) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+			apps, err := t.SearchSource(ctx, source, term, limit)
+			if err != nil {
+				log.Printf("Error searching %s for %q: %v", source, sanitizeForLog(term), err)
+				return
+			}
+			results[i] = apps
+		}(i, source)
+	}
+	wg.Wait()
+
+	var apps []*models.AppInfo
+	for _, sourceApps := range results {
+		apps = append(apps, sourceApps...)
+	}
+	return apps, nil
+}
+
+// TrackPackage starts tracking an npm or PyPI package found by search. Its
+// releases are then checked alongside the MAVT_TRACK_PACKAGES packages.
+func (t *Tracker) TrackPackage(ctx context.Context, pkg packages.Package) (*models.AppInfo, error) {
+	existing, err := t.storage.LoadApp(pkg.BundleID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing package: %w", err)
+	}
+	if existing != nil && existing.ArchivedAt == nil {
+		return existing, nil
+	}
+
+	app, err := t.packageClient.FetchLatest(ctx, pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up package: %w", err)
+	}
+	if existing != nil {
+		log.Printf("Restored archived package %s", sanitizeForLog(pkg.String()))
+		app.FirstDiscovered = existing.FirstDiscovered
+		app.DisplayName = existing.DisplayName
+		app.Notes = existing.Notes
+	} else {
+		log.Printf("Now tracking %s releases - version %s", sanitizeForLog(pkg.String()), sanitizeForLog(app.Version))
+	}
+
+	if err := t.storage.SaveApp(app); err != nil {
+		return nil, fmt.Errorf("failed to save package: %w", err)
+	}
+	t.emit(models.NewEvent(models.EventAppAdded, app,
+		fmt.Sprintf("Now tracking %s (version %s)", app.Name(), app.Version)))
+	return app, nil
+}
+
+// ResolveApp looks up an app by bundle ID, or by searching for its name when no
+// bundle ID is given. Name searches prefer an exact name match over the top result.
+func (t *Tracker) ResolveApp(bundleID, name string) (*models.AppInfo, error) {
+	if bundleID != "" {
+		return t.lookup(context.Background(), bundleID, "", "")
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("bundle ID or app name is required")
+	}
+
+	results, err := t.client.SearchApps(name, 5)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no App Store match for %q", name)
+	}
+
+	for _, app := range results {
+		if strings.EqualFold(app.TrackName, name) {
+			return app, nil
+		}
+	}
+
+	return results[0], nil
+}
+
+// Ready reports whether the tracker can read and write its storage
+func (t *Tracker) Ready() error {
+	if err := t.storage.Ping(); err != nil {
+		return err
+	}
+	_, err := t.storage.GetAllApps()
+	return err
+}
+
+// GetTrackedApps returns all apps being tracked, excluding archived apps
+func (t *Tracker) GetTrackedApps() ([]*models.AppInfo, error) {
+	return t.filterApps(false)
+}
+
+// GetArchivedApps returns all archived apps
+func (t *Tracker) GetArchivedApps() ([]*models.AppInfo, error) {
+	return t.filterApps(true)
+}
+
+// filterApps returns the stored apps whose archived state matches archived
+func (t *Tracker) filterApps(archived bool) ([]*models.AppInfo, error) {
+	apps, err := t.storage.GetAllApps()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*models.AppInfo, 0, len(apps))
+	for _, app := range apps {
+		if (app.ArchivedAt != nil) == archived {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered, nil
+}
+
+// GetRecentUpdates returns version updates across all apps within the specified duration
+func (t *Tracker) GetRecentUpdates(since time.Duration) ([]models.VersionUpdate, error) {
+	updates, err := t.storage.GetRecentUpdates(since)
+	if err != nil {
+		return nil, err
+	}
+	return updates, t.labelUpdates(updates)
+}
+
+// Events returns the tracker's event bus, so integrations can subscribe to
+// version updates, app events and alerts
+func (t *Tracker) Events() *events.Bus {
+	return t.events
+}
+
+// WebhookEnabled reports whether an outbound webhook is configured
+func (t *Tracker) WebhookEnabled() bool {
+	return t.notifier.HasWebhook()
+}
+
+// GetWebhookDeliveries returns the logged outbound webhook deliveries, newest
+// first
+func (t *Tracker) GetWebhookDeliveries() ([]models.WebhookDelivery, error) {
+	return t.storage.GetWebhookDeliveries()
+}
+
+// RedeliverWebhook sends the payload of a logged webhook delivery again and
+// returns the new delivery, which is logged too. A failed redelivery returns
+// the delivery along with the error.
+func (t *Tracker) RedeliverWebhook(id string) (*models.WebhookDelivery, error) {
+	if !t.notifier.HasWebhook() {
+		return nil, fmt.Errorf("no outbound webhook is configured")
+	}
+
+	delivery, err := t.storage.GetWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.notifier.RedeliverWebhook(delivery)
+}
+
+// GetUpdate returns the version update with the given permalink ID from the
+// history of any tracked or archived app, or storage.ErrUpdateNotFound
+func (t *Tracker) GetUpdate(id string) (*models.VersionUpdate, error) {
+	apps, err := t.storage.GetAllApps()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps {
+		history, err := t.GetVersionHistory(app.BundleID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range history {
+			if history[i].ID == id {
+				return &history[i], nil
+			}
+		}
+	}
+	return nil, storage.ErrUpdateNotFound
+}
+
+// GetVersionHistory returns version update history for an app
+func (t *Tracker) GetVersionHistory(bundleID string) ([]models.VersionUpdate, error) {
+	updates, err := t.storage.GetVersionUpdates(bundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load app: %w", err)
+	}
+	if app != nil {
+		for i := range updates {
+			updates[i].DisplayName = app.DisplayName
+			updates[i].AppNotes = app.Notes
+		}
+	}
+	return updates, t.annotateUpdates(updates)
+}
+
+// labelUpdates sets each update's display name and notes from its app's
+// current label, so renaming an app relabels its whole history
+func (t *Tracker) labelUpdates(updates []models.VersionUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	apps, err := t.storage.GetAllApps()
+	if err != nil {
+		return fmt.Errorf("failed to load tracked apps: %w", err)
+	}
+
+	byBundleID := make(map[string]*models.AppInfo, len(apps))
+	for _, app := range apps {
+		byBundleID[app.BundleID] = app
+	}
+
+	for i := range updates {
+		if app, ok := byBundleID[updates[i].BundleID]; ok {
+			updates[i].DisplayName = app.DisplayName
+			updates[i].AppNotes = app.Notes
+		}
+	}
+	return t.annotateUpdates(updates)
+}
+
+// AcknowledgeUpdate marks an app's update to version as reviewed by the given person
+func (t *Tracker) AcknowledgeUpdate(bundleID, version, by string) error {
+	by = strings.TrimSpace(by)
+	if by == "" {
+		return fmt.Errorf("acknowledging an update requires a name")
+	}
+
+	now := time.Now()
+	return t.storage.ModifyVersionUpdate(bundleID, version, func(update *models.VersionUpdate) {
+		update.Acknowledged = &models.Acknowledgement{By: by, At: now}
+	})
+}
+
+// UnacknowledgeUpdate clears the acknowledgement of an app's update to version
+func (t *Tracker) UnacknowledgeUpdate(bundleID, version string) error {
+	return t.storage.ModifyVersionUpdate(bundleID, version, func(update *models.VersionUpdate) {
+		update.Acknowledged = nil
+	})
+}
+
+// AssignUpdate assigns an app's update to version to assignee and notifies
+// about it. by records who made the assignment; an empty assignee unassigns it.
+func (t *Tracker) AssignUpdate(bundleID, version, assignee, by string) error {
+	assignee = strings.TrimSpace(assignee)
+
+	var assigned models.VersionUpdate
+	err := t.storage.ModifyVersionUpdate(bundleID, version, func(update *models.VersionUpdate) {
+		if assignee == "" {
+			update.Assignment = nil
+		} else {
+			update.Assignment = &models.Assignment{To: assignee, By: strings.TrimSpace(by), At: time.Now()}
+		}
+		assigned = *update
+	})
+	if err != nil || assignee == "" {
+		return err
+	}
+
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil || app == nil {
+		app = &models.AppInfo{BundleID: bundleID, TrackName: assigned.TrackName}
+	}
+	assigned.DisplayName = app.DisplayName
+	assigned.AppNotes = app.Notes
+
+	event := models.NewAlertEvent(models.EventAssignment, &models.Alert{App: app})
+	event.Updates = []models.VersionUpdate{assigned}
+	t.emit(event)
+	return nil
+}
+
+// pendingApproval returns the approval state new updates start in: pending
+// when approval is required, otherwise none
+func (t *Tracker) pendingApproval() *models.Approval {
+	if !t.requireApproval {
+		return nil
+	}
+	return &models.Approval{State: models.ApprovalPending}
+}
+
+// DecideUpdate approves or rejects an app's update to version that is pending
+// approval, recording who decided and why. Approving sends an approved_update
+// event.
+func (t *Tracker) DecideUpdate(bundleID, version, state, by, comment string) error {
+	if state != models.ApprovalApproved && state != models.ApprovalRejected {
+		return fmt.Errorf("invalid approval state %q (must be %s or %s)", state, models.ApprovalApproved, models.ApprovalRejected)
+	}
+	by = strings.TrimSpace(by)
+	if by == "" {
+		return fmt.Errorf("approving or rejecting an update requires a name")
+	}
+
+	var decided models.VersionUpdate
+	var stateErr error
+	now := time.Now()
+	err := t.storage.ModifyVersionUpdate(bundleID, version, func(update *models.VersionUpdate) {
+		if update.Approval == nil || update.Approval.State != models.ApprovalPending {
+			stateErr = fmt.Errorf("update %s %s is not pending approval", bundleID, version)
+			return
+		}
+		update.Approval = &models.Approval{State: state, By: by, At: &now, Comment: strings.TrimSpace(comment)}
+		decided = *update
+	})
+	if err != nil {
+		return err
+	}
+	if stateErr != nil {
+		return stateErr
+	}
+
+	log.Printf("Update %s %s %s by %s", sanitizeForLog(bundleID), sanitizeForLog(version), state, sanitizeForLog(by))
+	if state != models.ApprovalApproved {
+		return nil
+	}
+
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil || app == nil {
+		app = &models.AppInfo{BundleID: bundleID, TrackName: decided.TrackName}
+	}
+	decided.DisplayName = app.DisplayName
+	decided.AppNotes = app.Notes
+
+	event := models.NewEvent(models.EventApprovedUpdate, app,
+		fmt.Sprintf("%s %s was approved by %s", decided.Name(), decided.NewVersion, by))
+	event.Details = map[string]string{
+		"update_id":   decided.ID,
+		"old_version": decided.OldVersion,
+		"new_version": decided.NewVersion,
+		"approved_by": by,
+		"comment":     decided.Approval.Comment,
+	}
+	event.Updates = []models.VersionUpdate{decided}
+	t.emit(event)
+	return nil
+}
+
+// RecordDeployment stores the outcome of pushing an app's update to version
+// to an MDM
+func (t *Tracker) RecordDeployment(bundleID, version string, deployment models.Deployment) error {
+	return t.storage.ModifyVersionUpdate(bundleID, version, func(update *models.VersionUpdate) {
+		update.Deployment = &deployment
+	})
+}
+
+// NotifyRelease publishes a release alert for a newer MAVT release
+func (t *Tracker) NotifyRelease(release *version.Release) {
+	t.emit(models.NewAlertEvent(models.EventRelease, &models.Alert{
+		CurrentVersion: version.Version,
+		NewVersion:     release.Version,
+		URL:            release.URL,
+	}))
+}
+
+// SetAppLabel sets an app's custom display name and notes. Empty values clear
+// them, so the App Store name is shown again.
+func (t *Tracker) SetAppLabel(bundleID, displayName, notes string) error {
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return err
+	}
+
+	app.DisplayName = strings.TrimSpace(displayName)
+	app.Notes = strings.TrimSpace(notes)
+	if err := t.storage.SaveApp(app); err != nil {
+		return err
+	}
+	t.journalApp(models.SyncAppUpdated, bundleID)
+	return nil
+}
+
+// SetTestFlightURL sets an app's public TestFlight link and reads its beta
+// state right away. An empty link stops tracking the beta.
+func (t *Tracker) SetTestFlightURL(ctx context.Context, bundleID, link string) (*models.AppInfo, error) {
+	link = strings.TrimSpace(link)
+	if link != "" && !appstore.ValidTestFlightURL(link) {
+		return nil, fmt.Errorf("invalid TestFlight link %q (expected https://testflight.apple.com/join/...)", link)
+	}
+
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if link != app.TestFlightURL {
+		app.TestFlightURL = link
+		app.Beta = nil
+	}
+	t.checkBeta(ctx, app)
+	if err := t.storage.SaveApp(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// GetVendors returns the recorded vendor metadata, sorted by name
+func (t *Tracker) GetVendors() ([]models.Vendor, error) {
+	return t.storage.GetVendors()
+}
+
+// GetVendor returns the metadata recorded for a developer, or nil if none is
+func (t *Tracker) GetVendor(name string) (*models.Vendor, error) {
+	return t.storage.GetVendor(name)
+}
+
+// SetVendor records metadata for a developer, shared by all of its apps.
+// Clearing every field removes it.
+func (t *Tracker) SetVendor(vendor models.Vendor) error {
+	vendor.Name = strings.TrimSpace(vendor.Name)
+	if vendor.Name == "" {
+		return fmt.Errorf("vendor name is required")
+	}
+
+	vendor.SupportContact = strings.TrimSpace(vendor.SupportContact)
+	vendor.SLA = strings.TrimSpace(vendor.SLA)
+	vendor.Owner = strings.TrimSpace(vendor.Owner)
+	vendor.UpdatedAt = time.Now()
+	return t.storage.SaveVendor(&vendor)
+}
+
+// ReconcileApps archives every tracked app whose bundle ID is not in keep and
+// returns the archived bundle IDs. OS release, package and custom source entries
+// are left alone since they are controlled by MAVT_TRACK_OS,
+// MAVT_TRACK_PACKAGES and MAVT_CUSTOM_SOURCES, as are apps pulled from upstream
+// instances.
+func (t *Tracker) ReconcileApps(keep []string) ([]string, error) {
+	apps, err := t.storage.GetAllApps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracked apps: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(keep))
+	for _, bundleID := range keep {
+		wanted[bundleID] = true
+	}
+
+	var removed []string
+	for _, app := range apps {
+		if wanted[app.BundleID] || isPseudoApp(app) || federation.IsUpstreamSource(app.Source) || app.ArchivedAt != nil {
+			continue
+		}
+		if err := t.RemoveApp(app.BundleID); err != nil {
+			return removed, fmt.Errorf("failed to archive %s: %w", app.BundleID, err)
+		}
+		removed = append(removed, app.BundleID)
+	}
+
+	return removed, nil
+}
+
+// RemoveApp stops tracking an app by archiving it. Its history is kept and
+// it can be restored with UnarchiveApp; use PurgeApp to delete it for good.
+func (t *Tracker) RemoveApp(bundleID string) error {
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return err
+	}
+	if app.ArchivedAt != nil {
+		return nil
+	}
+
+	log.Printf("Archiving app: %s", sanitizeForLog(bundleID))
+	now := time.Now()
+	app.ArchivedAt = &now
+	if err := t.storage.SaveApp(app); err != nil {
+		return err
+	}
+
+	t.emit(models.NewEvent(models.EventAppRemoved, app, fmt.Sprintf("%s is no longer tracked", app.Name())))
+	return nil
+}
+
+// UnarchiveApp restores an archived app so it is checked and listed again
+func (t *Tracker) UnarchiveApp(bundleID string) error {
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return err
+	}
+	if app.ArchivedAt == nil {
+		return fmt.Errorf("app is not archived: %s", bundleID)
+	}
+
+	log.Printf("Restoring archived app: %s", sanitizeForLog(bundleID))
+	app.ArchivedAt = nil
+	if err := t.storage.SaveApp(app); err != nil {
+		return err
+	}
+	t.journalApp(models.SyncAppAdded, bundleID)
+	return nil
+}
+
+// ApplicationStatus is an application with the current version of each of
+// its store entries
+type ApplicationStatus struct {
+	models.Application
+	Platforms []PlatformVersion `json:"platforms"`
+}
+
+// PlatformVersion is the current version of one of an application's store
+// entries
+type PlatformVersion struct {
+	Platform    string     `json:"platform"`
+	BundleID    string     `json:"bundle_id"`
+	Name        string     `json:"name"`
+	Version     string     `json:"version"`
+	ReleaseDate time.Time  `json:"release_date"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+
+	// Country is the storefront the entry was last found in, for apps
+	// tracked in more than one
+	Country string `json:"country,omitempty"`
+}
+
+// GetApplications returns every application with its entries' current
+// versions. Entries whose app is no longer stored are left out.
+func (t *Tracker) GetApplications() ([]ApplicationStatus, error) {
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ApplicationStatus, 0, len(applications))
+	for _, application := range applications {
+		status := ApplicationStatus{Application: application, Platforms: []PlatformVersion{}}
+		if status.Members == nil {
+			status.Members = []models.ApplicationMember{}
+		}
+		for _, member := range application.Members {
+			app, err := t.storage.LoadApp(member.BundleID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", member.BundleID, err)
+			}
+			if app == nil {
+				continue
+			}
+			status.Platforms = append(status.Platforms, PlatformVersion{
+				Platform:    member.Platform,
+				BundleID:    app.BundleID,
+				Name:        app.Name(),
+				Version:     app.Version,
+				ReleaseDate: app.ReleaseDate,
+				ArchivedAt:  app.ArchivedAt,
+				Country:     app.Storefront,
+			})
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// GetApplication returns an application with its entries' current versions,
+// or ErrApplicationNotFound
+func (t *Tracker) GetApplication(id string) (*ApplicationStatus, error) {
+	statuses, err := t.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+	for i := range statuses {
+		if statuses[i].ID == id {
+			return &statuses[i], nil
+		}
+	}
+	return nil, ErrApplicationNotFound
+}
+
+// CreateApplication records a new application with no entries yet; apps are
+// added to it with LinkApp. Its ID is derived from the name, which must not
+// be taken by another application.
+func (t *Tracker) CreateApplication(name, owner string, tags []string) (*models.Application, error) {
+	name = strings.TrimSpace(name)
+	id := models.ApplicationID(name)
+	if id == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+	for _, application := range applications {
+		if application.ID == id || strings.EqualFold(application.Name, name) {
+			return nil, fmt.Errorf("%w: %s", ErrApplicationExists, application.Name)
+		}
+	}
+
+	application := &models.Application{
+		ID:        id,
+		Name:      name,
+		Members:   []models.ApplicationMember{},
+		Owner:     strings.TrimSpace(owner),
+		Tags:      models.NormalizeTags(tags),
+		UpdatedAt: time.Now(),
+	}
+	if err := t.storage.SaveApplication(application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// UpdateApplication renames an application and replaces its owner and tags.
+// Its ID and entries are kept.
+func (t *Tracker) UpdateApplication(id, name, owner string, tags []string) (*models.Application, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	var application *models.Application
+	for i := range applications {
+		if applications[i].ID == id {
+			application = &applications[i]
+		} else if strings.EqualFold(applications[i].Name, name) {
+			return nil, fmt.Errorf("%w: %s", ErrApplicationExists, applications[i].Name)
+		}
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+
+	application.Name = name
+	application.Owner = strings.TrimSpace(owner)
+	application.Tags = models.NormalizeTags(tags)
+	application.UpdatedAt = time.Now()
+	if err := t.storage.SaveApplication(application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// DeleteApplication removes an application. Its apps stay tracked, unlinked.
+func (t *Tracker) DeleteApplication(id string) error {
+	if _, err := t.GetApplication(id); err != nil {
+		return err
+	}
+	return t.storage.DeleteApplication(id)
+}
+
+// GetApplicationOf returns the application an app is linked into, or nil if
+// it isn't linked
+func (t *Tracker) GetApplicationOf(bundleID string) (*ApplicationStatus, error) {
+	statuses, err := t.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+	for i := range statuses {
+		if statuses[i].Member(bundleID) != nil {
+			return &statuses[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// LinkApp links a tracked app into the application called name, e.g. its
+// iOS app into the same product's Android app, creating the application if
+// there is none by that name or ID. An app is in at most one application, so it is
+// moved out of any other. An empty platform is derived from the app.
+func (t *Tracker) LinkApp(bundleID, name, platform string) (*models.Application, error) {
+	name = strings.TrimSpace(name)
+	id := models.ApplicationID(name)
+	if id == "" {
+		return nil, fmt.Errorf("application name is required")
+	}
+
+	app, err := t.loadTrackedApp(bundleID)
+	if err != nil {
+		return nil, err
+	}
+	platform = strings.TrimSpace(platform)
+	if platform == "" {
+		platform = defaultPlatform(app)
+	}
+
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	var linked *models.Application
+	for i := range applications {
+		application := &applications[i]
+		if linked == nil && (application.ID == id || strings.EqualFold(application.Name, name)) {
+			linked = application
+			continue
+		}
+		if application.Member(bundleID) != nil {
+			if err := t.removeMember(application, bundleID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if linked == nil {
+		linked = &models.Application{ID: id, Name: name}
+	}
+
+	if member := linked.Member(bundleID); member != nil {
+		member.Platform = platform
+	} else {
+		linked.Members = append(linked.Members, models.ApplicationMember{BundleID: bundleID, Platform: platform})
+	}
+	linked.UpdatedAt = time.Now()
+	if err := t.storage.SaveApplication(linked); err != nil {
+		return nil, err
+	}
+	return linked, nil
+}
+
+// UnlinkApp removes an app from its application. The application is kept,
+// even with no entries left, until it is deleted. Unlinking an app that
+// isn't linked does nothing.
+func (t *Tracker) UnlinkApp(bundleID string) error {
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return err
+	}
+	for i := range applications {
+		if applications[i].Member(bundleID) != nil {
+			return t.removeMember(&applications[i], bundleID)
+		}
+	}
+	return nil
+}
+
+// removeMember removes an app from an application and saves it
+func (t *Tracker) removeMember(application *models.Application, bundleID string) error {
+	members := application.Members[:0]
+	for _, member := range application.Members {
+		if member.BundleID != bundleID {
+			members = append(members, member)
+		}
+	}
+	application.Members = members
+	application.UpdatedAt = time.Now()
+	return t.storage.SaveApplication(application)
+}
+
+// GetApplicationHistory returns the combined version history of an
+// application's entries, newest first, each update labeled with its platform
+func (t *Tracker) GetApplicationHistory(id string) ([]models.VersionUpdate, error) {
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, application := range applications {
+		if application.ID != id {
+			continue
+		}
+		var history []models.VersionUpdate
+		for _, member := range application.Members {
+			updates, err := t.GetVersionHistory(member.BundleID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load history of %s: %w", member.BundleID, err)
+			}
+			history = append(history, updates...)
+		}
+		sort.SliceStable(history, func(i, j int) bool {
+			return history[i].UpdatedAt.After(history[j].UpdatedAt)
+		})
+		return history, nil
+	}
+	return nil, ErrApplicationNotFound
+}
+
+var (
+	// ErrApplicationNotFound is returned for an unknown application ID
+	ErrApplicationNotFound = errors.New("application not found")
+
+	// ErrApplicationExists is returned when creating or renaming an
+	// application to a name already taken by another
+	ErrApplicationExists = errors.New("application already exists")
+)
+
+// annotateUpdates sets each update's severity, and its application and
+// platform from the application its app is linked into, if any
+func (t *Tracker) annotateUpdates(updates []models.VersionUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	for i := range updates {
+		updates[i].Severity = updates[i].ClassifySeverity()
+	}
+
+	applications, err := t.storage.GetApplications()
+	if err != nil {
+		return fmt.Errorf("failed to load applications: %w", err)
+	}
+
+	for i := range updates {
+		for _, application := range applications {
+			if member := application.Member(updates[i].BundleID); member != nil {
+				updates[i].Application = application.Name
+				updates[i].Platform = member.Platform
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// defaultPlatform labels an app's store entry when the user doesn't: App
+// Store apps listing iOS devices are "iOS", other sources are named after
+// the source
+func defaultPlatform(app *models.AppInfo) string {
+	switch app.Source {
+	case "":
+		if len(app.SupportedDevices) > 0 {
+			return "iOS"
+		}
+		return "App Store"
+	case packages.SourceNPM:
+		return "npm"
+	case packages.SourcePyPI:
+		return "PyPI"
+	case osreleases.Source:
+		return "Apple OS"
+	case customsource.Source:
+		return "Custom"
+	}
+	return app.Source
+}
+
+// PurgeApp permanently removes an app and all its history
+func (t *Tracker) PurgeApp(bundleID string) error {
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return fmt.Errorf("failed to load app: %w", err)
+	}
+
+	log.Printf("Purging app and its history: %s", sanitizeForLog(bundleID))
+	if err := t.storage.DeleteApp(bundleID); err != nil {
+		return err
+	}
+	if err := t.UnlinkApp(bundleID); err != nil {
+		log.Printf("Failed to unlink purged app %s: %v", sanitizeForLog(bundleID), err)
+	}
+
+	// An archived app was already reported as removed when it was archived
+	if app != nil && app.ArchivedAt == nil {
+		t.emit(models.NewEvent(models.EventAppRemoved, app, fmt.Sprintf("%s and its history were deleted", app.Name())))
+	}
+	return nil
+}
+
+// loadTrackedApp loads an app from storage, returning an error if it isn't stored
+func (t *Tracker) loadTrackedApp(bundleID string) (*models.AppInfo, error) {
+	app, err := t.storage.LoadApp(bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load app: %w", err)
+	}
+	if app == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotTracked, bundleID)
+	}
+	return app, nil
 }
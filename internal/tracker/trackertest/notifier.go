@@ -0,0 +1,95 @@
+package trackertest
+
+import (
+	"sync"
+
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// FakeNotifier records what would have been notified
+type FakeNotifier struct {
+	mu sync.Mutex
+
+	// Events are the events received from the tracker, in order
+	Events []models.Event
+
+	// Alerts describe the alert events received, e.g. "min_os com.example.app"
+	Alerts []string
+
+	// Webhook enables the fake outbound webhook; Redelivered records the
+	// deliveries sent again
+	Webhook     bool
+	Redelivered []models.WebhookDelivery
+}
+
+var _ tracker.Notifier = (*FakeNotifier)(nil)
+
+// NewFakeNotifier creates a notifier that records instead of sending
+func NewFakeNotifier() *FakeNotifier {
+	return &FakeNotifier{}
+}
+
+// Updates returns the version updates received in version_update events
+func (f *FakeNotifier) Updates() []models.VersionUpdate {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var updates []models.VersionUpdate
+	for _, event := range f.Events {
+		updates = append(updates, event.Updates...)
+	}
+	return updates
+}
+
+// HandleEvent records an event, or an alert event as an alert
+func (f *FakeNotifier) HandleEvent(event *models.Event) {
+	if models.IsAlertEvent(event.Type) {
+		f.alert(alertName(event))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Events = append(f.Events, *event)
+}
+
+// alertName describes an alert event, e.g. "min_os com.example.app" or
+// "watchlist alice com.example.app"
+func alertName(event *models.Event) string {
+	switch event.Type {
+	case models.EventMinOSIncrease:
+		return "min_os " + event.BundleID
+	case models.EventWatchlistMatch:
+		return "watchlist " + event.Alert.Watchlist.User + " " + event.BundleID
+	case models.EventRelease:
+		return "release " + event.Alert.NewVersion
+	}
+	return event.Type + " " + event.BundleID
+}
+
+// HasWebhook reports whether the fake webhook is enabled
+func (f *FakeNotifier) HasWebhook() bool {
+	return f.Webhook
+}
+
+// RedeliverWebhook records the redelivery and returns it as a successful
+// delivery
+func (f *FakeNotifier) RedeliverWebhook(delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	redelivery := *delivery
+	redelivery.ID = delivery.ID + "-redelivery"
+	redelivery.RedeliveryOf = delivery.ID
+	redelivery.StatusCode = 200
+	redelivery.Error = ""
+	f.Redelivered = append(f.Redelivered, redelivery)
+	return &redelivery, nil
+}
+
+func (f *FakeNotifier) alert(alert string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Alerts = append(f.Alerts, alert)
+}
@@ -0,0 +1,169 @@
+// Package trackertest provides in-memory fakes of the tracker's dependencies,
+// so the tracker and server can be exercised without the network or disk
+package trackertest
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/thomas/mavt/internal/appstore"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// FakeSource serves apps, reviews, in-app purchases and TestFlight betas from
+// memory in place of the App Store
+type FakeSource struct {
+	mu        sync.Mutex
+	apps      map[string]*models.AppInfo
+	reviews   map[int64][]models.Review
+	purchases map[int64][]models.InAppPurchase
+	betas     map[string]*models.BetaStatus
+	errs      map[string]error
+}
+
+var _ tracker.Source = (*FakeSource)(nil)
+
+// NewFakeSource creates a source serving the given apps
+func NewFakeSource(apps ...*models.AppInfo) *FakeSource {
+	f := &FakeSource{
+		apps:      make(map[string]*models.AppInfo),
+		reviews:   make(map[int64][]models.Review),
+		purchases: make(map[int64][]models.InAppPurchase),
+		betas:     make(map[string]*models.BetaStatus),
+		errs:      make(map[string]error),
+	}
+	for _, app := range apps {
+		f.SetApp(app)
+	}
+	return f
+}
+
+// SetApp adds or replaces the app served for its bundle ID, e.g. to publish a
+// new version before the next check
+func (f *FakeSource) SetApp(app *models.AppInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *app
+	f.apps[app.BundleID] = &copied
+}
+
+// RemoveApp stops serving an app, so lookups return appstore.ErrNotFound
+func (f *FakeSource) RemoveApp(bundleID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.apps, bundleID)
+}
+
+// SetError makes lookups of bundleID fail with err; nil clears it
+func (f *FakeSource) SetError(bundleID string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.errs, bundleID)
+		return
+	}
+	f.errs[bundleID] = err
+}
+
+// SetReviews sets the reviews served for an app's trackId
+func (f *FakeSource) SetReviews(trackID int64, reviews []models.Review) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reviews[trackID] = reviews
+}
+
+// SetBeta sets the beta status served for a TestFlight link
+func (f *FakeSource) SetBeta(link string, status *models.BetaStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.betas[link] = status
+}
+
+// SetInAppPurchases sets the in-app purchases served for an app's trackId
+func (f *FakeSource) SetInAppPurchases(trackID int64, purchases []models.InAppPurchase) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.purchases[trackID] = purchases
+}
+
+// LookupByBundleID returns a copy of the app with the given bundle ID
+func (f *FakeSource) LookupByBundleID(bundleID string) (*models.AppInfo, error) {
+	return f.LookupByBundleIDWithLocale(context.Background(), bundleID, "", "")
+}
+
+// LookupByBundleIDWithLocale returns a copy of the app with the given bundle
+// ID; the locale is ignored
+func (f *FakeSource) LookupByBundleIDWithLocale(_ context.Context, bundleID, _, _ string) (*models.AppInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.errs[bundleID]; err != nil {
+		return nil, err
+	}
+	app, ok := f.apps[bundleID]
+	if !ok {
+		return nil, appstore.ErrNotFound
+	}
+	copied := *app
+	return &copied, nil
+}
+
+// LookupByTrackID returns a copy of the app with the given trackId
+func (f *FakeSource) LookupByTrackID(trackID int64) (*models.AppInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, app := range f.apps {
+		if app.TrackID == trackID {
+			copied := *app
+			return &copied, nil
+		}
+	}
+	return nil, appstore.ErrNotFound
+}
+
+// SearchApps returns up to limit apps whose name contains term, ignoring case
+func (f *FakeSource) SearchApps(term string, limit int) ([]*models.AppInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var results []*models.AppInfo
+	for _, app := range f.apps {
+		if len(results) == limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(app.TrackName), strings.ToLower(term)) {
+			copied := *app
+			results = append(results, &copied)
+		}
+	}
+	return results, nil
+}
+
+// FetchReviews returns the reviews set for trackID
+func (f *FakeSource) FetchReviews(trackID int64, _ string) ([]models.Review, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.Review(nil), f.reviews[trackID]...), nil
+}
+
+// FetchInAppPurchases returns the in-app purchases set for trackID
+func (f *FakeSource) FetchInAppPurchases(_ context.Context, trackID int64, _ string) ([]models.InAppPurchase, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.InAppPurchase{}, f.purchases[trackID]...), nil
+}
+
+// FetchTestFlight returns the beta status set for link, or an error if none is
+func (f *FakeSource) FetchTestFlight(_ context.Context, link string) (*models.BetaStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status, ok := f.betas[link]
+	if !ok {
+		return nil, appstore.ErrNotFound
+	}
+	copied := *status
+	return &copied, nil
+}
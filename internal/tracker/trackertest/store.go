@@ -0,0 +1,575 @@
+package trackertest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/storage"
+	"github.com/thomas/mavt/internal/tracker"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// MemStore keeps apps, history and state in memory. It follows the file
+// storage's rules where the tracker relies on them, e.g. rejecting duplicate
+// updates with storage.ErrDuplicateUpdate, but has no history compression or
+// raw response archive.
+type MemStore struct {
+	mu           sync.RWMutex
+	apps         map[string]*models.AppInfo
+	updates      map[string][]models.VersionUpdate
+	reviews      map[string][]models.Review
+	vendors      map[string]models.Vendor
+	watchlists   map[string]models.Watchlist
+	views        map[string]models.SavedView
+	applications map[string]models.Application
+	state        storage.SchedulerState
+	leases       map[string]storage.Lease
+	deliveries   []models.WebhookDelivery
+	changes      []models.SyncChange
+	snapshots    map[string][]models.AppSnapshot
+}
+
+var _ tracker.Store = (*MemStore)(nil)
+
+// NewMemStore creates an empty in-memory store
+func NewMemStore() *MemStore {
+	return &MemStore{
+		apps:         make(map[string]*models.AppInfo),
+		updates:      make(map[string][]models.VersionUpdate),
+		reviews:      make(map[string][]models.Review),
+		vendors:      make(map[string]models.Vendor),
+		watchlists:   make(map[string]models.Watchlist),
+		views:        make(map[string]models.SavedView),
+		applications: make(map[string]models.Application),
+		leases:       make(map[string]storage.Lease),
+		snapshots:    make(map[string][]models.AppSnapshot),
+	}
+}
+
+// LoadApp returns a copy of a stored app, or nil if it isn't stored
+func (m *MemStore) LoadApp(bundleID string) (*models.AppInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	app, ok := m.apps[bundleID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *app
+	return &copied, nil
+}
+
+// SaveApp stores a copy of an app, applying storage.CheckAppInvariants
+func (m *MemStore) SaveApp(app *models.AppInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := storage.CheckAppInvariants(m.apps[app.BundleID], app); err != nil {
+		return err
+	}
+	copied := *app
+	m.apps[app.BundleID] = &copied
+	return nil
+}
+
+// DeleteApp removes an app with its history and reviews
+func (m *MemStore) DeleteApp(bundleID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.apps, bundleID)
+	delete(m.updates, bundleID)
+	delete(m.reviews, bundleID)
+	delete(m.snapshots, bundleID)
+	return nil
+}
+
+// GetAllApps returns copies of every stored app, sorted by bundle ID
+func (m *MemStore) GetAllApps() ([]*models.AppInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	apps := make([]*models.AppInfo, 0, len(m.apps))
+	for _, app := range m.apps {
+		copied := *app
+		apps = append(apps, &copied)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].BundleID < apps[j].BundleID
+	})
+	return apps, nil
+}
+
+// TouchApp records an app's last checked time immediately
+func (m *MemStore) TouchApp(bundleID string, checkedAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if app, ok := m.apps[bundleID]; ok {
+		app.LastChecked = checkedAt
+		if app.Beta != nil && app.TestFlightURL != "" {
+			beta := *app.Beta
+			beta.CheckedAt = checkedAt
+			app.Beta = &beta
+		}
+	}
+}
+
+// FlushLastChecked does nothing, since TouchApp isn't batched
+func (m *MemStore) FlushLastChecked() error {
+	return nil
+}
+
+// ReloadLastChecked does nothing, since TouchApp isn't batched
+func (m *MemStore) ReloadLastChecked() {}
+
+// SaveVersionUpdate appends an update to its app's history, assigning its ID,
+// or returns storage.ErrDuplicateUpdate
+func (m *MemStore) SaveVersionUpdate(update *models.VersionUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.updates[update.BundleID] {
+		if isDuplicate(&existing, update) {
+			return storage.ErrDuplicateUpdate
+		}
+	}
+
+	if update.ID == "" {
+		update.ID = models.UpdateID(update.BundleID, update.NewVersion, update.UpdatedAt)
+	}
+	m.updates[update.BundleID] = append(m.updates[update.BundleID], *update)
+	return nil
+}
+
+// isDuplicate matches the file storage's duplicate rule: the same transition
+// within storage.DuplicateUpdateWindow
+func isDuplicate(a, b *models.VersionUpdate) bool {
+	if a.OldVersion != b.OldVersion || a.NewVersion != b.NewVersion || a.Kind != b.Kind {
+		return false
+	}
+	gap := a.UpdatedAt.Sub(b.UpdatedAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= storage.DuplicateUpdateWindow
+}
+
+// AddVersionUpdates inserts past updates into an app's history in date order
+func (m *MemStore) AddVersionUpdates(bundleID string, updates []models.VersionUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := m.updates[bundleID]
+	for _, update := range updates {
+		if update.ID == "" {
+			update.ID = models.UpdateID(bundleID, update.NewVersion, update.UpdatedAt)
+		}
+		history = append(history, update)
+	}
+	sortByDate(history)
+	m.updates[bundleID] = history
+	return nil
+}
+
+// GetVersionUpdates returns an app's history
+func (m *MemStore) GetVersionUpdates(bundleID string) ([]models.VersionUpdate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.VersionUpdate{}, m.updates[bundleID]...), nil
+}
+
+// GetRecentUpdates returns every app's updates within since of now
+func (m *MemStore) GetRecentUpdates(since time.Duration) ([]models.VersionUpdate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cutoff := time.Now().Add(-since)
+	recent := []models.VersionUpdate{}
+	for _, updates := range m.updates {
+		for _, update := range updates {
+			if update.UpdatedAt.After(cutoff) {
+				recent = append(recent, update)
+			}
+		}
+	}
+	return recent, nil
+}
+
+// ModifyVersionUpdate applies fn to an app's most recent update to newVersion,
+// or returns storage.ErrUpdateNotFound
+func (m *MemStore) ModifyVersionUpdate(bundleID, newVersion string, fn func(*models.VersionUpdate)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updates := m.updates[bundleID]
+	found := -1
+	for i, update := range updates {
+		if update.NewVersion == newVersion && (found < 0 || !update.UpdatedAt.Before(updates[found].UpdatedAt)) {
+			found = i
+		}
+	}
+	if found < 0 {
+		return storage.ErrUpdateNotFound
+	}
+	fn(&updates[found])
+	return nil
+}
+
+// MergeVersionUpdates moves one app's history into another's, in date order
+func (m *MemStore) MergeVersionUpdates(fromBundleID, toBundleID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merged := m.updates[toBundleID]
+	for _, update := range m.updates[fromBundleID] {
+		update.BundleID = toBundleID
+		merged = append(merged, update)
+	}
+	sortByDate(merged)
+	m.updates[toBundleID] = merged
+	delete(m.updates, fromBundleID)
+	return nil
+}
+
+// CompactHistory does nothing; history is never compressed in memory
+func (m *MemStore) CompactHistory() (int, error) {
+	return 0, nil
+}
+
+// AddReviews stores reviews not seen before and returns them
+func (m *MemStore) AddReviews(bundleID string, reviews []models.Review) ([]models.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, review := range m.reviews[bundleID] {
+		seen[review.ID] = true
+	}
+
+	var added []models.Review
+	for _, review := range reviews {
+		if seen[review.ID] {
+			continue
+		}
+		seen[review.ID] = true
+		review.BundleID = bundleID
+		added = append(added, review)
+	}
+	m.reviews[bundleID] = append(m.reviews[bundleID], added...)
+	return added, nil
+}
+
+// GetReviews returns an app's stored reviews
+func (m *MemStore) GetReviews(bundleID string) ([]models.Review, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.Review{}, m.reviews[bundleID]...), nil
+}
+
+// GetVendors returns all vendors, sorted by name
+func (m *MemStore) GetVendors() ([]models.Vendor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vendors := make([]models.Vendor, 0, len(m.vendors))
+	for _, vendor := range m.vendors {
+		vendors = append(vendors, vendor)
+	}
+	sort.Slice(vendors, func(i, j int) bool {
+		return strings.ToLower(vendors[i].Name) < strings.ToLower(vendors[j].Name)
+	})
+	return vendors, nil
+}
+
+// GetVendor returns a copy of the vendor with a name, matched
+// case-insensitively, or nil if there is none
+func (m *MemStore) GetVendor(name string) (*models.Vendor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vendor, ok := m.vendors[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, nil
+	}
+	return &vendor, nil
+}
+
+// SaveVendor stores a vendor, removing it if nothing is recorded
+func (m *MemStore) SaveVendor(vendor *models.Vendor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(vendor.Name))
+	if vendor.IsEmpty() {
+		delete(m.vendors, key)
+	} else {
+		m.vendors[key] = *vendor
+	}
+	return nil
+}
+
+// GetWatchlists returns copies of all watchlists, sorted by user
+func (m *MemStore) GetWatchlists() ([]models.Watchlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	watchlists := make([]models.Watchlist, 0, len(m.watchlists))
+	for _, watchlist := range m.watchlists {
+		watchlist.Keywords = append([]string(nil), watchlist.Keywords...)
+		watchlists = append(watchlists, watchlist)
+	}
+	sort.Slice(watchlists, func(i, j int) bool {
+		return strings.ToLower(watchlists[i].User) < strings.ToLower(watchlists[j].User)
+	})
+	return watchlists, nil
+}
+
+// GetWatchlist returns a copy of a user's watchlist, matched
+// case-insensitively, or nil if there is none
+func (m *MemStore) GetWatchlist(user string) (*models.Watchlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	watchlist, ok := m.watchlists[strings.ToLower(strings.TrimSpace(user))]
+	if !ok {
+		return nil, nil
+	}
+	watchlist.Keywords = append([]string(nil), watchlist.Keywords...)
+	return &watchlist, nil
+}
+
+// SaveWatchlist stores a copy of a watchlist, removing it if it has no
+// keywords
+func (m *MemStore) SaveWatchlist(watchlist *models.Watchlist) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(watchlist.User))
+	if len(watchlist.Keywords) == 0 {
+		delete(m.watchlists, key)
+		return nil
+	}
+	stored := *watchlist
+	stored.Keywords = append([]string(nil), watchlist.Keywords...)
+	m.watchlists[key] = stored
+	return nil
+}
+
+// GetSavedViews returns all saved views, sorted by user and then name
+func (m *MemStore) GetSavedViews() ([]models.SavedView, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	views := make([]models.SavedView, 0, len(m.views))
+	for _, view := range m.views {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if a, b := strings.ToLower(views[i].User), strings.ToLower(views[j].User); a != b {
+			return a < b
+		}
+		return strings.ToLower(views[i].Name) < strings.ToLower(views[j].Name)
+	})
+	return views, nil
+}
+
+// SaveSavedView stores a saved view, replacing any with the same ID
+func (m *MemStore) SaveSavedView(view *models.SavedView) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.views[view.ID] = *view
+	return nil
+}
+
+// DeleteSavedView removes a saved view
+func (m *MemStore) DeleteSavedView(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.views, id)
+	return nil
+}
+
+// GetApplications returns all applications, sorted by name
+func (m *MemStore) GetApplications() ([]models.Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	applications := make([]models.Application, 0, len(m.applications))
+	for _, application := range m.applications {
+		application.Members = append([]models.ApplicationMember(nil), application.Members...)
+		applications = append(applications, application)
+	}
+	sort.Slice(applications, func(i, j int) bool {
+		return strings.ToLower(applications[i].Name) < strings.ToLower(applications[j].Name)
+	})
+	return applications, nil
+}
+
+// SaveApplication stores a copy of an application
+func (m *MemStore) SaveApplication(application *models.Application) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *application
+	stored.Members = append([]models.ApplicationMember(nil), application.Members...)
+	m.applications[application.ID] = stored
+	return nil
+}
+
+// DeleteApplication removes an application
+func (m *MemStore) DeleteApplication(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.applications, id)
+	return nil
+}
+
+// Ping always succeeds
+func (m *MemStore) Ping() error {
+	return nil
+}
+
+// LoadSchedulerState returns a copy of the scheduler state
+func (m *MemStore) LoadSchedulerState() (*storage.SchedulerState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state := m.state
+	return &state, nil
+}
+
+// SaveSchedulerState replaces the scheduler state
+func (m *MemStore) SaveSchedulerState(state *storage.SchedulerState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = *state
+	return nil
+}
+
+// AcquireLease takes or renews a lease unless another holder's is unexpired
+func (m *MemStore) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if current := m.leases[name]; current.Holder != "" && current.Holder != holder && now.Before(current.ExpiresAt) {
+		return false, nil
+	}
+	m.leases[name] = storage.Lease{Holder: holder, ExpiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLease gives up a lease held by holder
+func (m *MemStore) ReleaseLease(name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.leases[name].Holder == holder {
+		delete(m.leases, name)
+	}
+	return nil
+}
+
+// ArchiveRawResponse discards the response; there is no raw archive in memory
+func (m *MemStore) ArchiveRawResponse(string, []byte) error {
+	return nil
+}
+
+// PruneRawResponses does nothing; there is no raw archive in memory
+func (m *MemStore) PruneRawResponses(time.Duration) (int, error) {
+	return 0, nil
+}
+
+// SaveWebhookDelivery logs a webhook delivery, e.g. to test redelivery
+func (m *MemStore) SaveWebhookDelivery(delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, *delivery)
+	return nil
+}
+
+// GetWebhookDeliveries returns the logged deliveries, newest first
+func (m *MemStore) GetWebhookDeliveries() ([]models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	deliveries := make([]models.WebhookDelivery, 0, len(m.deliveries))
+	for i := len(m.deliveries) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, m.deliveries[i])
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery returns the logged delivery with the given ID, or
+// storage.ErrDeliveryNotFound
+func (m *MemStore) GetWebhookDelivery(id string) (*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == id {
+			delivery := m.deliveries[i]
+			return &delivery, nil
+		}
+	}
+	return nil, storage.ErrDeliveryNotFound
+}
+
+// AppendChanges adds changes to the sync journal, numbering them from 1. The
+// journal is never trimmed.
+func (m *MemStore) AppendChanges(changes []models.SyncChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, change := range changes {
+		change.Seq = int64(len(m.changes)) + 1
+		m.changes = append(m.changes, change)
+	}
+	return nil
+}
+
+// GetChanges returns up to limit journal entries after sequence number
+// after, oldest first, and the range of sequence numbers kept
+func (m *MemStore) GetChanges(after int64, limit int) ([]models.SyncChange, storage.ChangeRange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	span := storage.ChangeRange{Oldest: 1, Latest: int64(len(m.changes))}
+	var changes []models.SyncChange
+	for _, change := range m.changes {
+		if change.Seq > after && len(changes) < limit {
+			changes = append(changes, change)
+		}
+	}
+	return changes, span, nil
+}
+
+// AddAppSnapshot appends a snapshot to an app's snapshots, which are never
+// trimmed
+func (m *MemStore) AddAppSnapshot(snapshot *models.AppSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *snapshot
+	stored.Changes = nil
+	m.snapshots[snapshot.App.BundleID] = append(m.snapshots[snapshot.App.BundleID], stored)
+	return nil
+}
+
+// GetAppSnapshots returns copies of an app's snapshots, oldest first
+func (m *MemStore) GetAppSnapshots(bundleID string) ([]models.AppSnapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]models.AppSnapshot{}, m.snapshots[bundleID]...), nil
+}
+
+// sortByDate sorts updates oldest first
+func sortByDate(updates []models.VersionUpdate) {
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].UpdatedAt.Before(updates[j].UpdatedAt)
+	})
+}
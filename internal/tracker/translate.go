@@ -0,0 +1,46 @@
+package tracker
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// translateNotes machine translates an update's release notes into the
+// configured language before it is saved. Notes already in that language are
+// left alone, and failures are logged so the update is recorded untranslated
+// rather than lost.
+func (t *Tracker) translateNotes(ctx context.Context, update *models.VersionUpdate) {
+	notes := strings.TrimSpace(update.ReleaseNotes)
+	if t.translator == nil || notes == "" || sameLanguage(update.Language, t.translateTarget) {
+		return
+	}
+
+	translated, err := t.translator.Translate(ctx, notes, t.translateTarget)
+	if err != nil {
+		log.Printf("Failed to translate release notes of %s %s: %v",
+			sanitizeForLog(update.BundleID), sanitizeForLog(update.NewVersion), err)
+		return
+	}
+	translated = strings.TrimSpace(translated)
+	if translated == "" || translated == notes {
+		return
+	}
+	update.TranslatedNotes = translated
+	update.TranslatedLanguage = t.translateTarget
+}
+
+// sameLanguage reports whether a release notes language, e.g. "ja_jp", and a
+// translation target, e.g. "JA" or "en-US", name the same language
+func sameLanguage(notesLang, target string) bool {
+	base := func(lang string) string {
+		lang = strings.ToLower(lang)
+		if i := strings.IndexAny(lang, "_-"); i >= 0 {
+			lang = lang[:i]
+		}
+		return lang
+	}
+	return notesLang != "" && base(notesLang) == base(target)
+}
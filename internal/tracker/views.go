@@ -0,0 +1,130 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+var (
+	// ErrViewNotFound is returned for an unknown saved view ID
+	ErrViewNotFound = errors.New("saved view not found")
+
+	// ErrInvalidView is returned when saving a view without a user or name,
+	// or with filters the dashboard doesn't have
+	ErrInvalidView = errors.New("invalid saved view")
+)
+
+// GetSavedViews returns the saved views of a user, matched case-insensitively,
+// or everyone's if user is empty, sorted by user and then name
+func (t *Tracker) GetSavedViews(user string) ([]models.SavedView, error) {
+	views, err := t.storage.GetSavedViews()
+	if err != nil {
+		return nil, err
+	}
+	user = strings.TrimSpace(user)
+	if user == "" {
+		return views, nil
+	}
+
+	mine := []models.SavedView{}
+	for _, view := range views {
+		if strings.EqualFold(view.User, user) {
+			mine = append(mine, view)
+		}
+	}
+	return mine, nil
+}
+
+// GetSavedView returns a saved view by ID
+func (t *Tracker) GetSavedView(id string) (*models.SavedView, error) {
+	views, err := t.storage.GetSavedViews()
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].ID == id {
+			return &views[i], nil
+		}
+	}
+	return nil, ErrViewNotFound
+}
+
+// SaveView records a user's named view of the dashboard filters in query,
+// replacing the user's view of the same name
+func (t *Tracker) SaveView(user, name, query string) (*models.SavedView, error) {
+	user, name = strings.TrimSpace(user), strings.TrimSpace(name)
+	id := models.SavedViewID(user, name)
+	if id == "" {
+		return nil, fmt.Errorf("%w: user and name are required", ErrInvalidView)
+	}
+
+	filters, err := parseViewQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.SavedView{
+		ID:        id,
+		User:      user,
+		Name:      name,
+		Query:     filters.Encode(),
+		UpdatedAt: time.Now(),
+	}
+	if err := t.storage.SaveSavedView(view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// DeleteView removes a saved view
+func (t *Tracker) DeleteView(id string) error {
+	if _, err := t.GetSavedView(id); err != nil {
+		return err
+	}
+	return t.storage.DeleteSavedView(id)
+}
+
+// parseViewQuery parses and checks a saved view's filters, dropping empty ones
+func parseViewQuery(query string) (url.Values, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(query), "?"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+
+	filters := url.Values{}
+	for name, list := range values {
+		if !slices.Contains(models.ViewFilters, name) {
+			return nil, fmt.Errorf("%w: unknown filter %q (must be one of %s)", ErrInvalidView, name, strings.Join(models.ViewFilters, ", "))
+		}
+		value := strings.TrimSpace(list[len(list)-1])
+		if value == "" {
+			continue
+		}
+
+		switch name {
+		case "since":
+			if _, err := config.ParseDuration(value); err != nil {
+				return nil, fmt.Errorf("%w: since: %v", ErrInvalidView, err)
+			}
+		case "severity":
+			for _, severity := range strings.Split(value, ",") {
+				if !slices.Contains(models.Severities, severity) {
+					return nil, fmt.Errorf("%w: unknown severity %q", ErrInvalidView, severity)
+				}
+			}
+		case "sort":
+			if !slices.Contains(models.ViewSorts, value) {
+				return nil, fmt.Errorf("%w: sort must be one of %s", ErrInvalidView, strings.Join(models.ViewSorts, ", "))
+			}
+		}
+		filters.Set(name, value)
+	}
+	return filters, nil
+}
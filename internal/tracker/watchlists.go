@@ -0,0 +1,96 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/notifier"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// maxWatchlistKeywords caps the keywords on one watchlist
+const maxWatchlistKeywords = 50
+
+// ErrInvalidWatchlist is returned when setting a watchlist without a user or
+// with too many keywords
+var ErrInvalidWatchlist = errors.New("invalid watchlist")
+
+// GetWatchlists returns every user's watchlist, sorted by user
+func (t *Tracker) GetWatchlists() ([]models.Watchlist, error) {
+	return t.storage.GetWatchlists()
+}
+
+// GetWatchlist returns a user's watchlist, or nil if they have none
+func (t *Tracker) GetWatchlist(user string) (*models.Watchlist, error) {
+	return t.storage.GetWatchlist(user)
+}
+
+// SetWatchlist records a user's watchlist, replacing their previous one.
+// Keywords are trimmed and deduplicated case-insensitively; a watchlist left
+// without keywords is removed.
+func (t *Tracker) SetWatchlist(watchlist models.Watchlist) error {
+	watchlist.User = strings.TrimSpace(watchlist.User)
+	if watchlist.User == "" {
+		return fmt.Errorf("%w: user is required", ErrInvalidWatchlist)
+	}
+
+	seen := make(map[string]bool, len(watchlist.Keywords))
+	var keywords []string
+	for _, keyword := range watchlist.Keywords {
+		keyword = strings.Join(strings.Fields(keyword), " ")
+		if keyword == "" || seen[strings.ToLower(keyword)] {
+			continue
+		}
+		seen[strings.ToLower(keyword)] = true
+		keywords = append(keywords, keyword)
+	}
+	if len(keywords) > maxWatchlistKeywords {
+		return fmt.Errorf("%w: at most %d keywords are allowed", ErrInvalidWatchlist, maxWatchlistKeywords)
+	}
+
+	watchlist.Keywords = keywords
+	watchlist.NotifyURL = strings.TrimSpace(watchlist.NotifyURL)
+	if watchlist.NotifyURL != "" {
+		if err := notifier.ValidateServiceURLs(watchlist.NotifyURL); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+		}
+	}
+	watchlist.UpdatedAt = time.Now()
+	return t.storage.SaveWatchlist(&watchlist)
+}
+
+// notifyWatchlists notifies each user whose watchlist keywords the release
+// notes (or their translation) of notifiable updates mention: new updates
+// that rollbacks and maintenance windows don't hold back, and held updates
+// once their window ends, with a watchlist_match alert event each
+func (t *Tracker) notifyWatchlists(updates []models.VersionUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	watchlists, err := t.storage.GetWatchlists()
+	if err != nil {
+		log.Printf("Failed to read watchlists: %v", err)
+		return
+	}
+
+	for i := range updates {
+		update := &updates[i]
+		notes := update.ReleaseNotes + "\n" + update.TranslatedNotes
+		for j := range watchlists {
+			keywords := watchlists[j].Match(notes)
+			if len(keywords) == 0 {
+				continue
+			}
+			event := models.NewAlertEvent(models.EventWatchlistMatch, &models.Alert{
+				Watchlist: &watchlists[j],
+				Keywords:  keywords,
+			})
+			event.BundleID, event.AppName = update.BundleID, update.Name()
+			event.Updates = []models.VersionUpdate{*update}
+			t.emit(event)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package translate machine translates release notes through LibreTranslate
+// or DeepL, so notes published in another language can be read
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/thomas/mavt/internal/config"
+	"github.com/thomas/mavt/internal/httpclient"
+)
+
+const (
+	deepLURL     = "https://api.deepl.com/v2/translate"
+	deepLFreeURL = "https://api-free.deepl.com/v2/translate"
+)
+
+// Translator translates text into a target language, detecting the source
+// language itself
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (string, error)
+}
+
+// New returns the translator for the configured provider, or nil if none is
+// configured
+func New(cfg *config.Config) Translator {
+	switch cfg.TranslateProvider {
+	case config.TranslateProviderLibre:
+		return NewLibreTranslate(cfg.TranslateURL, cfg.TranslateAPIKey)
+	case config.TranslateProviderDeepL:
+		return NewDeepL(cfg.TranslateURL, cfg.TranslateAPIKey)
+	}
+	return nil
+}
+
+// LibreTranslate translates through a LibreTranslate server, e.g. a
+// self-hosted one
+type LibreTranslate struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewLibreTranslate creates a translator using the LibreTranslate server at
+// baseURL; apiKey is only needed if the server requires one
+func NewLibreTranslate(baseURL, apiKey string) *LibreTranslate {
+	return &LibreTranslate{
+		url:        strings.TrimRight(baseURL, "/") + "/translate",
+		apiKey:     apiKey,
+		httpClient: httpclient.New(30 * time.Second),
+	}
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, text, target string) (string, error) {
+	// LibreTranslate uses bare ISO 639-1 codes, e.g. "en" rather than "en-US"
+	target, _, _ = strings.Cut(strings.ToLower(target), "-")
+	request := map[string]string{"q": text, "source": "auto", "target": target, "format": "text"}
+	if l.apiKey != "" {
+		request["api_key"] = l.apiKey
+	}
+
+	var response struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := postJSON(ctx, l.httpClient, "LibreTranslate", l.url, nil, request, &response); err != nil {
+		return "", err
+	}
+	return response.TranslatedText, nil
+}
+
+// DeepL translates through the DeepL API
+type DeepL struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewDeepL creates a translator using the DeepL API with apiKey. An empty url
+// picks the free or pro API endpoint from the key; free keys end in ":fx".
+func NewDeepL(url, apiKey string) *DeepL {
+	if url == "" {
+		url = deepLURL
+		if strings.HasSuffix(apiKey, ":fx") {
+			url = deepLFreeURL
+		}
+	}
+	return &DeepL{
+		url:        url,
+		apiKey:     apiKey,
+		httpClient: httpclient.New(30 * time.Second),
+	}
+}
+
+func (d *DeepL) Translate(ctx context.Context, text, target string) (string, error) {
+	request := map[string]interface{}{
+		"text":        []string{text},
+		"target_lang": strings.ToUpper(target),
+	}
+	header := http.Header{"Authorization": {"DeepL-Auth-Key " + d.apiKey}}
+
+	var response struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := postJSON(ctx, d.httpClient, "DeepL", d.url, header, request, &response); err != nil {
+		return "", err
+	}
+	if len(response.Translations) == 0 {
+		return "", fmt.Errorf("DeepL returned no translation")
+	}
+	return response.Translations[0].Text, nil
+}
+
+// postJSON posts request as JSON to a provider and decodes its JSON response
+func postJSON(ctx context.Context, client *http.Client, provider, url string, header http.Header, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", provider, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", provider, err)
+	}
+	return nil
+}
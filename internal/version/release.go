@@ -0,0 +1,99 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thomas/mavt/internal/httpclient"
+	"github.com/thomas/mavt/pkg/models"
+)
+
+// releasesURL is the GitHub API endpoint for the latest published MAVT release
+const releasesURL = "https://api.github.com/repos/thomas/mavt/releases/latest"
+
+// Release describes a published MAVT release
+type Release struct {
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ReleaseChecker looks up the latest MAVT release on GitHub and remembers it
+type ReleaseChecker struct {
+	httpClient *http.Client
+	url        string
+
+	mu     sync.RWMutex
+	latest *Release
+}
+
+// NewReleaseChecker creates a checker for the MAVT GitHub releases feed
+func NewReleaseChecker() *ReleaseChecker {
+	return &ReleaseChecker{
+		httpClient: httpclient.New(30 * time.Second),
+		url:        releasesURL,
+	}
+}
+
+// githubRelease is the subset of the GitHub release response that MAVT uses
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Check fetches the latest release and returns it if it is newer than the
+// running version, or nil if MAVT is up to date
+func (c *ReleaseChecker) Check(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "mavt/"+Version)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode latest release: %w", err)
+	}
+
+	latest := &Release{
+		Version:     strings.TrimPrefix(release.TagName, "v"),
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+	}
+	c.mu.Lock()
+	c.latest = latest
+	c.mu.Unlock()
+
+	return c.Available(), nil
+}
+
+// Available returns the latest release seen by Check if it is newer than the
+// running version, or nil otherwise
+func (c *ReleaseChecker) Available() *Release {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.latest == nil || models.CompareVersions(c.latest.Version, Version) <= 0 {
+		return nil
+	}
+	return c.latest
+}
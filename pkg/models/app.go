@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
 
 // AppInfo represents an app's information from the App Store
 type AppInfo struct {
@@ -17,10 +22,78 @@ type AppInfo struct {
 	Currency        string    `json:"currency"`
 	LastChecked     time.Time `json:"last_checked"`
 	FirstDiscovered time.Time `json:"first_discovered"`
+
+	// Extended metadata used for compliance reporting
+	Genre            string   `json:"genre,omitempty"`
+	ContentRating    string   `json:"content_rating,omitempty"`
+	SupportedDevices []string `json:"supported_devices,omitempty"`
+	LanguageCodes    []string `json:"language_codes,omitempty"`
+
+	// Description is the App Store description and ScreenshotCount how many
+	// iPhone and iPad screenshots the listing has
+	Description     string `json:"description,omitempty"`
+	ScreenshotCount int    `json:"screenshot_count,omitempty"`
+
+	// IconURL is the App Store's 100x100 app icon
+	IconURL string `json:"icon_url,omitempty"`
+
+	// Rating is the App Store rating at the last lookup; nil for apps the
+	// App Store reports no ratings for
+	Rating *RatingSnapshot `json:"rating,omitempty"`
+
+	// InAppPurchases are the in-app purchases listed on the app's store page
+	// when MAVT_TRACK_IN_APP_PURCHASES is on. It is null until the page has
+	// been read, and an empty list for an app without any.
+	InAppPurchases []InAppPurchase `json:"in_app_purchases"`
+
+	// TestFlightURL is the app's public TestFlight link, set by the user, and
+	// Beta what it showed at the last check
+	TestFlightURL string      `json:"testflight_url,omitempty"`
+	Beta          *BetaStatus `json:"beta,omitempty"`
+
+	// Source identifies where version data comes from; empty means the App Store
+	Source string `json:"source,omitempty"`
+
+	// Country and Language override the default storefront and release notes
+	// language for this app; empty means the configured default
+	Country  string `json:"country,omitempty"`
+	Language string `json:"language,omitempty"`
+
+	// Storefront is the country the app was last found in, which differs
+	// from its own when it was only found in a fallback storefront
+	Storefront string `json:"storefront,omitempty"`
+
+	// ReviewAlertedVersion is the last version a negative review burst alert was sent for
+	ReviewAlertedVersion string `json:"review_alerted_version,omitempty"`
+
+	// MovedTo is the bundle ID this app's App Store listing now resolves to
+	// when the developer has migrated it to a new bundle ID
+	MovedTo string `json:"moved_to,omitempty"`
+
+	// ArchivedAt is set when the app is archived: it is no longer checked and
+	// is hidden by default, but its history is kept
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// DisplayName and Notes are set by the user to label an app, e.g. with
+	// the team that uses it and who to contact
+	DisplayName string `json:"display_name,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// Name returns the app's custom display name, or its App Store name if none is set
+func (a *AppInfo) Name() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+	return a.TrackName
 }
 
 // VersionUpdate represents a version change event
 type VersionUpdate struct {
+	// ID identifies the update in permalinks. It is derived from the update
+	// when first stored and kept, so it survives re-keying by a merge.
+	ID string `json:"id,omitempty"`
+
 	BundleID     string    `json:"bundle_id"`
 	TrackID      int64     `json:"track_id"`
 	TrackName    string    `json:"track_name"`
@@ -28,4 +101,209 @@ type VersionUpdate struct {
 	NewVersion   string    `json:"new_version"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	ReleaseNotes string    `json:"release_notes"`
+	Language     string    `json:"language,omitempty"`
+
+	// TranslatedNotes are the release notes machine translated into
+	// TranslatedLanguage when MAVT_TRANSLATE_PROVIDER is set; unset when the
+	// notes are already in that language or couldn't be translated
+	TranslatedNotes    string `json:"translated_notes,omitempty"`
+	TranslatedLanguage string `json:"translated_language,omitempty"`
+
+	// ReleasedAt is when Apple released the new version, from the App Store's
+	// current version release date; UpdatedAt is when MAVT noticed it. It is
+	// unset on updates recorded before release dates were stored.
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+
+	// OldFileSizeBytes and FileSizeBytes are the app's download size before
+	// and after the update; zero on updates recorded before sizes were stored
+	OldFileSizeBytes int64 `json:"old_file_size_bytes,omitempty"`
+	FileSizeBytes    int64 `json:"file_size_bytes,omitempty"`
+
+	// DevicesAdded and DevicesRemoved are the device models the update added
+	// or dropped support for, as App Store supportedDevices names
+	DevicesAdded   []string `json:"devices_added,omitempty"`
+	DevicesRemoved []string `json:"devices_removed,omitempty"`
+
+	// Kind distinguishes special events from a normal version change, which
+	// leaves it empty
+	Kind string `json:"kind,omitempty"`
+
+	// Backfilled is set on past versions imported when an app was tracked,
+	// rather than changes MAVT detected itself
+	Backfilled bool `json:"backfilled,omitempty"`
+
+	// DisplayName and AppNotes are the app's custom label and notes, filled
+	// in from the app when updates are read so they are always current
+	DisplayName string `json:"display_name,omitempty"`
+	AppNotes    string `json:"app_notes,omitempty"`
+
+	// Application and Platform name the application the app is linked into
+	// and the app's platform in it, filled in the same way
+	Application string `json:"application,omitempty"`
+	Platform    string `json:"platform,omitempty"`
+
+	// Severity is the update's ClassifySeverity, filled in when updates are read
+	Severity string `json:"severity,omitempty"`
+
+	// Acknowledged is set once someone has reviewed the update
+	Acknowledged *Acknowledgement `json:"acknowledged,omitempty"`
+
+	// Assignment is set when the update has been handed to someone to review
+	Assignment *Assignment `json:"assignment,omitempty"`
+
+	// Approval is set on updates detected while MAVT_REQUIRE_APPROVAL is on,
+	// and records whether the update has been cleared for deployment
+	Approval *Approval `json:"approval,omitempty"`
+
+	// Deployment is set once an approved update has been pushed to an MDM
+	Deployment *Deployment `json:"deployment,omitempty"`
+}
+
+// Kinds of version update other than a normal version change
+const (
+	// UpdateKindReRelease marks an update where the version is unchanged but
+	// its release notes or release date were edited, e.g. when a developer
+	// re-releases
+	UpdateKindReRelease = "re-release"
+
+	// UpdateKindRollback marks an update to an older version than the stored
+	// one, e.g. after a store rollback or a regional inconsistency
+	UpdateKindRollback = "rollback"
+)
+
+// Acknowledgement records who reviewed a version update and when
+type Acknowledgement struct {
+	By string    `json:"by"`
+	At time.Time `json:"at"`
+}
+
+// Assignment records who a version update was assigned to, by whom and when
+type Assignment struct {
+	To string    `json:"to"`
+	By string    `json:"by,omitempty"`
+	At time.Time `json:"at"`
+}
+
+// Approval states of a version update. An update starts pending and is then
+// approved or rejected once; the decision is final.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// Approval records an update's approval state and, once decided, who decided
+// it, when and why
+type Approval struct {
+	State   string     `json:"state"`
+	By      string     `json:"by,omitempty"`
+	At      *time.Time `json:"at,omitempty"`
+	Comment string     `json:"comment,omitempty"`
+}
+
+// Deployment records pushing an approved update to managed devices through an
+// MDM; Error is set if the push failed
+type Deployment struct {
+	Provider string    `json:"provider"`
+	Groups   []string  `json:"groups,omitempty"`
+	At       time.Time `json:"at"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// UpdateID derives a version update's permalink ID from its app, new version
+// and time, so the same update gets the same ID on every instance
+func UpdateID(bundleID, newVersion string, updatedAt time.Time) string {
+	sum := sha256.Sum256([]byte(bundleID + "\x00" + newVersion + "\x00" + updatedAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Name returns the name to show for the updated app
+func (u *VersionUpdate) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.TrackName
+}
+
+// ReleaseTime returns when the new version was released, falling back to when
+// the update was detected if the release date is unknown
+func (u *VersionUpdate) ReleaseTime() time.Time {
+	if u.ReleasedAt != nil && !u.ReleasedAt.IsZero() {
+		return *u.ReleasedAt
+	}
+	return u.UpdatedAt
+}
+
+// ReleaseCadence returns the average time between releases of new versions in
+// updates, by release date. Re-releases and rollbacks are not new versions and
+// are ignored. It returns 0 if there are fewer than two releases.
+func ReleaseCadence(updates []VersionUpdate) time.Duration {
+	var releases []time.Time
+	for i := range updates {
+		if updates[i].Kind == "" {
+			releases = append(releases, updates[i].ReleaseTime())
+		}
+	}
+	if len(releases) < 2 {
+		return 0
+	}
+
+	first, last := releases[0], releases[0]
+	for _, t := range releases[1:] {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last.Sub(first) / time.Duration(len(releases)-1)
+}
+
+// Update severities, most severe first
+const (
+	// SeverityCritical marks an update whose release notes mention a CVE
+	SeverityCritical = "critical"
+
+	// SeverityMajor, SeverityMinor and SeverityPatch mark a change in the
+	// first, second or a later component of the version
+	SeverityMajor = "major"
+	SeverityMinor = "minor"
+	SeverityPatch = "patch"
+)
+
+// Severities lists the update severities, most severe first
+var Severities = []string{SeverityCritical, SeverityMajor, SeverityMinor, SeverityPatch}
+
+// ClassifySeverity rates the update: critical if its release notes mention
+// a CVE, otherwise by the first version component that changed, in either
+// direction. Re-releases and versions that aren't numeric are patches.
+func (u *VersionUpdate) ClassifySeverity() string {
+	if strings.Contains(strings.ToUpper(u.ReleaseNotes), "CVE") {
+		return SeverityCritical
+	}
+
+	oldVersion, newVersion := ParseVersion(u.OldVersion), ParseVersion(u.NewVersion)
+	if !oldVersion.Numeric || !newVersion.Numeric {
+		return SeverityPatch
+	}
+	switch {
+	case partAt(oldVersion.Parts, 0) != partAt(newVersion.Parts, 0):
+		return SeverityMajor
+	case partAt(oldVersion.Parts, 1) != partAt(newVersion.Parts, 1):
+		return SeverityMinor
+	}
+	return SeverityPatch
+}
+
+// Change describes the version change, e.g. "1.1 → 1.2", "1.2 re-released"
+// or "1.2 → 1.1 (rollback)"
+func (u *VersionUpdate) Change() string {
+	switch u.Kind {
+	case UpdateKindReRelease:
+		return u.NewVersion + " re-released"
+	case UpdateKindRollback:
+		return u.OldVersion + " → " + u.NewVersion + " (rollback)"
+	}
+	return u.OldVersion + " → " + u.NewVersion
 }
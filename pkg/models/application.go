@@ -0,0 +1,99 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Application is one product tracked as several store entries, e.g. its iOS
+// and Android apps, linked so their versions, history and notifications are
+// seen together, and so reports and dashboards can be organized by product
+type Application struct {
+	// ID is derived from the name when the application is created and kept
+	// when it is renamed
+	ID      string              `json:"id"`
+	Name    string              `json:"name"`
+	Members []ApplicationMember `json:"members"`
+
+	// Owner is who is responsible for the product internally, and Tags
+	// are free-form lowercase labels, e.g. "finance" or "tier-1"
+	Owner string   `json:"owner,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ApplicationMember is one store entry of an application
+type ApplicationMember struct {
+	BundleID string `json:"bundle_id"`
+
+	// Platform labels the entry, e.g. "iOS" or "macOS"
+	Platform string `json:"platform"`
+}
+
+// Member returns the application's entry for a bundle ID, or nil if it has none
+func (a *Application) Member(bundleID string) *ApplicationMember {
+	for i := range a.Members {
+		if a.Members[i].BundleID == bundleID {
+			return &a.Members[i]
+		}
+	}
+	return nil
+}
+
+// HasTag reports whether the application is tagged tag, ignoring case
+func (a *Application) HasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary returns the application's owner and tags on one line each, e.g.
+// for reports, or nothing if neither is set
+func (a *Application) Summary() []string {
+	var lines []string
+	if a.Owner != "" {
+		lines = append(lines, "Owner: "+a.Owner)
+	}
+	if len(a.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(a.Tags, ", "))
+	}
+	return lines
+}
+
+// NormalizeTags trims and lowercases tags, dropping empty and duplicate ones
+// while keeping their order
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// ApplicationID derives an application ID from its name: lowercase letters
+// and digits, with runs of anything else replaced by "-"
+func ApplicationID(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
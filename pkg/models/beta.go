@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// States of a public TestFlight beta, as its join page shows them
+const (
+	BetaOpen   = "open"
+	BetaFull   = "full"
+	BetaClosed = "closed"
+)
+
+// BetaStatus is what an app's public TestFlight link showed at the last check
+type BetaStatus struct {
+	State string `json:"state"`
+
+	// Version and Build are the beta build, when the join page shows them
+	Version string `json:"version,omitempty"`
+	Build   string `json:"build,omitempty"`
+
+	// FirstSeen is when this state and build were first seen; CheckedAt is
+	// the last check
+	FirstSeen time.Time `json:"first_seen"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// AheadOf reports whether the beta build is a newer version than the one on
+// the App Store
+func (b *BetaStatus) AheadOf(storeVersion string) bool {
+	return b.Version != "" && storeVersion != "" && CompareVersions(b.Version, storeVersion) > 0
+}
+
+// Same reports whether two statuses show the same state and build
+func (b *BetaStatus) Same(other *BetaStatus) bool {
+	return other != nil && b.State == other.State && b.Version == other.Version && b.Build == other.Build
+}
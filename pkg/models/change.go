@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// PriceChange is an app's App Store price changing, carried by price_change
+// events
+type PriceChange struct {
+	BundleID  string    `json:"bundle_id"`
+	OldPrice  float64   `json:"old_price"`
+	NewPrice  float64   `json:"new_price"`
+	Currency  string    `json:"currency"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// MetadataChange is a change to an app's App Store listing other than its
+// version or price, carried by metadata_change events
+type MetadataChange struct {
+	BundleID string `json:"bundle_id"`
+
+	// Fields are the JSON names of the AppInfo fields that changed, sorted
+	Fields []string `json:"fields"`
+
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// RatingSnapshot is an app's App Store rating when it was last looked up
+type RatingSnapshot struct {
+	AverageRating float64 `json:"average_rating"`
+	RatingCount   int64   `json:"rating_count"`
+
+	// Version is the app version the current version figures are for
+	Version                     string  `json:"version,omitempty"`
+	CurrentVersionAverageRating float64 `json:"current_version_average_rating"`
+	CurrentVersionRatingCount   int64   `json:"current_version_rating_count"`
+
+	// CapturedAt is when these figures were first seen
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// Same reports whether two snapshots show the same ratings
+func (r *RatingSnapshot) Same(other *RatingSnapshot) bool {
+	return r != nil && other != nil &&
+		r.AverageRating == other.AverageRating && r.RatingCount == other.RatingCount &&
+		r.Version == other.Version &&
+		r.CurrentVersionAverageRating == other.CurrentVersionAverageRating &&
+		r.CurrentVersionRatingCount == other.CurrentVersionRatingCount
+}
+
+// InAppPurchase is an in-app purchase or subscription tier listed on an app's
+// App Store page
+type InAppPurchase struct {
+	Name string `json:"name"`
+
+	// Price is as displayed on the store page, e.g. "$4.99", since it isn't
+	// available as a number
+	Price string `json:"price"`
+}
+
+// InAppPurchaseChange is a change to the in-app purchases listed for an app,
+// carried by in_app_purchase_change events
+type InAppPurchaseChange struct {
+	BundleID string          `json:"bundle_id"`
+	Added    []InAppPurchase `json:"added,omitempty"`
+	Removed  []InAppPurchase `json:"removed,omitempty"`
+	Repriced []IAPRepricing  `json:"repriced,omitempty"`
+
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// IAPRepricing is an in-app purchase whose listed price changed
+type IAPRepricing struct {
+	Name     string `json:"name"`
+	OldPrice string `json:"old_price"`
+	NewPrice string `json:"new_price"`
+}
+
+// SizePoint is an app's download size at one version
+type SizePoint struct {
+	Version       string `json:"version"`
+	FileSizeBytes int64  `json:"file_size_bytes"`
+
+	// ReleasedAt is when the version was released; nil if unknown
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// ComplianceEntry compares a tracked app's latest version with the versions
+// installed across managed devices
+type ComplianceEntry struct {
+	BundleID      string          `json:"bundle_id"`
+	TrackName     string          `json:"track_name"`
+	LatestVersion string          `json:"latest_version"`
+	TotalDevices  int             `json:"total_devices"`
+	DevicesBehind []DeviceVersion `json:"devices_behind"`
+}
+
+// DeviceVersion represents an app version installed on a single managed device
+type DeviceVersion struct {
+	DeviceID         string `json:"device_id"`
+	DeviceName       string `json:"device_name"`
+	InstalledVersion string `json:"installed_version"`
+}
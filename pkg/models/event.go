@@ -0,0 +1,146 @@
+package models
+
+import "time"
+
+// Event types sent to the outbound webhook
+const (
+	EventVersionUpdate  = "version_update"
+	EventAppAdded       = "app_added"
+	EventAppRemoved     = "app_removed"
+	EventAppPulled      = "app_pulled"
+	EventPriceChange    = "price_change"
+	EventMetadataChange = "metadata_change"
+	EventCheckFailed    = "check_failed"
+
+	EventInAppPurchaseChange = "in_app_purchase_change"
+
+	// EventApprovedUpdate is sent when someone approves an update that
+	// required approval, e.g. to drive MDM deployment
+	EventApprovedUpdate = "approved_update"
+)
+
+// EventTypes lists every event type, for validating subscriptions
+var EventTypes = []string{
+	EventVersionUpdate,
+	EventAppAdded,
+	EventAppRemoved,
+	EventAppPulled,
+	EventPriceChange,
+	EventMetadataChange,
+	EventCheckFailed,
+	EventInAppPurchaseChange,
+	EventApprovedUpdate,
+}
+
+// Alert event types are published on the tracker's event bus for the
+// notifier to send as Apprise alerts. They aren't webhook events, so they
+// aren't in EventTypes and are never sent to the webhook.
+const (
+	EventMinOSIncrease  = "min_os_increase"
+	EventDevicesDropped = "devices_dropped"
+	EventSizeGrowth     = "size_growth"
+	EventReviewBurst    = "review_burst"
+	EventAssignment     = "assignment"
+	EventWatchlistMatch = "watchlist_match"
+	EventRelease        = "release"
+)
+
+// IsAlertEvent reports whether an event type is an alert event
+func IsAlertEvent(eventType string) bool {
+	switch eventType {
+	case EventMinOSIncrease, EventDevicesDropped, EventSizeGrowth, EventReviewBurst,
+		EventAssignment, EventWatchlistMatch, EventRelease:
+		return true
+	}
+	return false
+}
+
+// Alert is the typed detail of an alert event. App is set for alerts about
+// an app; the other fields are those the event type needs.
+type Alert struct {
+	App *AppInfo
+
+	// OldMinOS and FleetMinOS are a min_os_increase's previous minimum OS
+	// and the fleet's oldest OS
+	OldMinOS   string
+	FleetMinOS string
+
+	// Devices are the fleet devices a devices_dropped event's app dropped
+	Devices []string
+
+	// OldSize is a size_growth's previous download size in bytes
+	OldSize int64
+
+	// OneStarCount is how many one-star reviews a review_burst counted
+	OneStarCount int
+
+	// Watchlist and Keywords are a watchlist_match's watchlist and the
+	// keywords the update's release notes mention
+	Watchlist *Watchlist
+	Keywords  []string
+
+	// CurrentVersion, NewVersion and URL describe a release event's newer
+	// MAVT release
+	CurrentVersion string
+	NewVersion     string
+	URL            string
+}
+
+// Event is something that happened to a tracked app. Version updates found
+// together, e.g. in one check cycle, are a single event carrying Updates.
+type Event struct {
+	Type       string    `json:"event"`
+	BundleID   string    `json:"bundle_id"`
+	AppName    string    `json:"app_name"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Summary    string    `json:"summary"`
+
+	// Details holds event-specific values as strings, e.g. old_price and
+	// new_price, for the flat webhook format
+	Details map[string]string `json:"details,omitempty"`
+
+	// PriceChange, MetadataChange and InAppPurchaseChange are the typed
+	// details of price_change, metadata_change and in_app_purchase_change
+	// events
+	PriceChange         *PriceChange         `json:"price_change,omitempty"`
+	MetadataChange      *MetadataChange      `json:"metadata_change,omitempty"`
+	InAppPurchaseChange *InAppPurchaseChange `json:"in_app_purchase_change,omitempty"`
+
+	// Updates are the version updates of a version_update event, or the
+	// update of an approved_update, assignment or watchlist_match event
+	Updates []VersionUpdate `json:"updates,omitempty"`
+
+	// Alert is the detail of an alert event, which isn't sent as JSON
+	Alert *Alert `json:"-"`
+}
+
+// NewEvent creates an event of the given type for an app, occurring now
+func NewEvent(eventType string, app *AppInfo, summary string) *Event {
+	return &Event{
+		Type:       eventType,
+		BundleID:   app.BundleID,
+		AppName:    app.Name(),
+		OccurredAt: time.Now(),
+		Summary:    summary,
+	}
+}
+
+// NewAlertEvent creates an alert event of the given type, for alert's app if
+// it has one, occurring now
+func NewAlertEvent(eventType string, alert *Alert) *Event {
+	event := &Event{Type: eventType, OccurredAt: time.Now(), Alert: alert}
+	if alert.App != nil {
+		event.BundleID = alert.App.BundleID
+		event.AppName = alert.App.Name()
+	}
+	return event
+}
+
+// NewUpdatesEvent creates a version_update event for updates found together
+func NewUpdatesEvent(updates []VersionUpdate) *Event {
+	return &Event{
+		Type:       EventVersionUpdate,
+		OccurredAt: time.Now(),
+		Updates:    updates,
+	}
+}
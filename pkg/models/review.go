@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Review represents a customer review from the App Store reviews feed
+type Review struct {
+	ID        string    `json:"id"`
+	BundleID  string    `json:"bundle_id"`
+	Author    string    `json:"author"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Rating    int       `json:"rating"`
+	Version   string    `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewSummary summarises stored reviews and the recent rating trend for an app
+type ReviewSummary struct {
+	BundleID      string  `json:"bundle_id"`
+	TotalReviews  int     `json:"total_reviews"`
+	AverageRating float64 `json:"average_rating"`
+	RecentAverage float64 `json:"recent_average"`
+	PriorAverage  float64 `json:"prior_average"`
+	Trend         string  `json:"trend"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// AppSnapshot is an app's full metadata as stored at one point in time, so
+// changes to its listing can be inspected after the record has moved on
+type AppSnapshot struct {
+	TakenAt time.Time `json:"taken_at"`
+	App     AppInfo   `json:"app"`
+
+	// Changes are the fields that differ from the previous snapshot, filled
+	// in when snapshots are read rather than stored; they are unset on the
+	// oldest snapshot kept
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is an AppInfo field that differs between two snapshots of an
+// app, with its JSON values; a value is null when the field is unset
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+// DiffApps returns the AppInfo fields that differ between two snapshots of an
+// app, by JSON name and sorted, leaving out the last checked time
+func DiffApps(a, b *AppInfo) []FieldChange {
+	var aFields, bFields map[string]json.RawMessage
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil || json.Unmarshal(aJSON, &aFields) != nil || json.Unmarshal(bJSON, &bFields) != nil {
+		return nil
+	}
+
+	var changes []FieldChange
+	for name, value := range aFields {
+		if name != "last_checked" && !bytes.Equal(value, bFields[name]) {
+			changes = append(changes, FieldChange{Field: name, Old: value, New: fieldValue(bFields[name])})
+		}
+	}
+	for name, value := range bFields {
+		if _, ok := aFields[name]; !ok && name != "last_checked" {
+			changes = append(changes, FieldChange{Field: name, Old: fieldValue(nil), New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// fieldValue returns a field's JSON value, or null for a field left out
+func fieldValue(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
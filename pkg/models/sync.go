@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Types of sync journal entries
+const (
+	SyncAppAdded      = "app_added"
+	SyncAppUpdated    = "app_updated"
+	SyncAppRemoved    = "app_removed"
+	SyncVersionUpdate = "version_update"
+)
+
+// SyncChange is an entry in the journal of changes that external systems
+// replicate incrementally through /api/sync. Seq orders the journal and
+// serves as the sync cursor; it is unset on snapshot entries.
+type SyncChange struct {
+	Seq      int64     `json:"seq,omitempty"`
+	Type     string    `json:"type"`
+	BundleID string    `json:"bundle_id"`
+	At       time.Time `json:"at"`
+
+	// Update is the version update of a version_update change
+	Update *VersionUpdate `json:"update,omitempty"`
+
+	// App is the app's current state, filled in when the journal is read
+	// rather than stored with each change; it is nil once the app is deleted
+	App *AppInfo `json:"app,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Vendor is what the user records about an app developer, shared by all of
+// its apps: who to contact for support, the contract or SLA terms and who
+// owns the relationship internally
+type Vendor struct {
+	// Name is the developer name as the App Store gives it, i.e. the apps'
+	// ArtistName
+	Name string `json:"name"`
+
+	SupportContact string `json:"support_contact,omitempty"`
+	SLA            string `json:"sla,omitempty"`
+	Owner          string `json:"owner,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsEmpty reports whether nothing is recorded about the vendor
+func (v *Vendor) IsEmpty() bool {
+	return v.SupportContact == "" && v.SLA == "" && v.Owner == ""
+}
+
+// Summary returns the vendor's details on one line each, e.g. for
+// notifications, or nothing if none are recorded
+func (v *Vendor) Summary() []string {
+	var lines []string
+	if v.Owner != "" {
+		lines = append(lines, "Owner: "+v.Owner)
+	}
+	if v.SupportContact != "" {
+		lines = append(lines, "Vendor support: "+v.SupportContact)
+	}
+	if v.SLA != "" {
+		lines = append(lines, "SLA: "+strings.Join(strings.Fields(v.SLA), " "))
+	}
+	return lines
+}
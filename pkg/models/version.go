@@ -0,0 +1,185 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed App Store version string. App Store versions are
+// usually dotted numbers ("1.2", "17.4.1") but may carry a semver-style
+// pre-release ("2.0.0-beta.2") or build metadata ("3.1+451"), a leading "v",
+// or be free-form ("2024.05a").
+type Version struct {
+	// Raw is the version string as given
+	Raw string
+
+	// Parts are the dotted numeric components; nil unless Numeric
+	Parts []int
+
+	// PreRelease is the part after "-", e.g. "beta.2"
+	PreRelease string
+
+	// Build is the part after "+"; it is ignored when comparing
+	Build string
+
+	// Numeric is set when every dotted component is a number. Versions that
+	// aren't are compared with a natural sort of the whole string instead.
+	Numeric bool
+}
+
+// ParseVersion parses a version string; it never fails, since any string can
+// be compared lexically
+func ParseVersion(s string) Version {
+	v := Version{Raw: s}
+
+	rest := strings.TrimSpace(s)
+	if len(rest) > 1 && (rest[0] == 'v' || rest[0] == 'V') && rest[1] >= '0' && rest[1] <= '9' {
+		rest = rest[1:]
+	}
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		rest, v.Build = rest[:i], rest[i+1:]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		rest, v.PreRelease = rest[:i], rest[i+1:]
+	}
+	if rest == "" {
+		// An empty version counts as zero
+		v.Numeric = v.PreRelease == ""
+		return v
+	}
+
+	parts := make([]int, 0, 4)
+	for _, field := range strings.Split(rest, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return v
+		}
+		parts = append(parts, n)
+	}
+	v.Parts = parts
+	v.Numeric = true
+	return v
+}
+
+// String returns the version as given
+func (v Version) String() string {
+	return v.Raw
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as or newer than o.
+// Missing components count as zero, so "1.2" equals "1.2.0", and a
+// pre-release is older than the release it precedes. If either version isn't
+// numeric, the raw strings are compared with runs of digits as numbers.
+func (v Version) Compare(o Version) int {
+	if !v.Numeric || !o.Numeric {
+		return compareNatural(strings.TrimSpace(v.Raw), strings.TrimSpace(o.Raw))
+	}
+
+	for i := 0; i < len(v.Parts) || i < len(o.Parts); i++ {
+		if c := compareInts(partAt(v.Parts, i), partAt(o.Parts, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case v.PreRelease == o.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case o.PreRelease == "":
+		return -1
+	}
+	return comparePreRelease(v.PreRelease, o.PreRelease)
+}
+
+// Equal reports whether v and o are the same version, e.g. "1.2" and "1.2.0"
+func (v Version) Equal(o Version) bool {
+	return v.Compare(o) == 0
+}
+
+// CompareVersions compares two version strings, returning -1, 0 or 1. See
+// Version.Compare for the rules.
+func CompareVersions(a, b string) int {
+	return ParseVersion(a).Compare(ParseVersion(b))
+}
+
+func partAt(parts []int, i int) int {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePreRelease compares dot-separated pre-release identifiers as semver
+// does: numeric identifiers numerically and below alphanumeric ones, which
+// compare lexically, and a shorter list first when all else is equal
+func comparePreRelease(a, b string) int {
+	aIDs := strings.Split(a, ".")
+	bIDs := strings.Split(b, ".")
+
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aNum, aErr := strconv.Atoi(aIDs[i])
+		bNum, bErr := strconv.Atoi(bIDs[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInts(aNum, bNum)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aIDs), len(bIDs))
+}
+
+// compareNatural compares strings with runs of digits compared as numbers,
+// so "2024.9a" is older than "2024.10a"
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		aRun, aRest, aDigits := nextRun(a)
+		bRun, bRest, bDigits := nextRun(b)
+
+		var c int
+		if aDigits && bDigits {
+			aTrimmed := strings.TrimLeft(aRun, "0")
+			bTrimmed := strings.TrimLeft(bRun, "0")
+			c = compareInts(len(aTrimmed), len(bTrimmed))
+			if c == 0 {
+				c = strings.Compare(aTrimmed, bTrimmed)
+			}
+		} else {
+			c = strings.Compare(aRun, bRun)
+		}
+		if c != 0 {
+			return c
+		}
+		a, b = aRest, bRest
+	}
+	return compareInts(len(a), len(b))
+}
+
+// nextRun splits off the leading run of digits or non-digits
+func nextRun(s string) (run, rest string, digits bool) {
+	digits = s[0] >= '0' && s[0] <= '9'
+	i := 1
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9') == digits {
+		i++
+	}
+	return s[:i], s[i:], digits
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ViewFilters are the dashboard filters a saved view can set, by query
+// parameter name
+var ViewFilters = []string{"since", "severity", "tag", "unacknowledged", "mine", "approval", "sort"}
+
+// ViewSorts are the orders a saved view can sort updates in
+var ViewSorts = []string{"newest", "oldest", "app", "severity"}
+
+// SavedView is a user's named combination of dashboard filters and sort
+// order, e.g. "security-tagged apps updated this month"
+type SavedView struct {
+	// ID is derived from the user and name, so saving a view under a name
+	// the user already has replaces it; the dashboard opens a view at
+	// /#view/{id}
+	ID   string `json:"id"`
+	User string `json:"user"`
+	Name string `json:"name"`
+
+	// Query holds the filters as a URL query string, e.g.
+	// "since=30d&tag=security&sort=severity"
+	Query string `json:"query"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SavedViewID derives a saved view's ID from its user and name
+func SavedViewID(user, name string) string {
+	userID, nameID := ApplicationID(user), ApplicationID(name)
+	if userID == "" || nameID == "" {
+		return ""
+	}
+	return userID + "--" + nameID
+}
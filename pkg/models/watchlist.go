@@ -0,0 +1,74 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Watchlist is a user's keywords, e.g. "GDPR" or "crash fix": a new release
+// whose notes mention one is notified to the user, whatever the global
+// notification settings
+type Watchlist struct {
+	// User is the name the user goes by in the web interface, the one
+	// recorded with their acknowledgements and assignments
+	User     string   `json:"user"`
+	Keywords []string `json:"keywords"`
+
+	// NotifyURL is where the user's matches are sent: Apprise service URLs,
+	// e.g. mailto://..., passed to the Apprise API. Empty sends them to the
+	// services MAVT_APPRISE_URL notifies.
+	NotifyURL string `json:"notify_url,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Match returns the watchlist's keywords that text mentions, matched case-
+// insensitively as whole words or phrases
+func (w *Watchlist) Match(text string) []string {
+	text = strings.ToLower(text)
+	var matched []string
+	for _, keyword := range w.Keywords {
+		if containsWord(text, strings.ToLower(keyword)) {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
+
+// containsWord reports whether text contains word not as part of a longer
+// word, e.g. "login" in "fixed login" but not in "blogging"
+func containsWord(text, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		if !wordRuneBefore(text, start) && !wordRuneAt(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// wordRuneBefore reports whether the rune ending at i is part of a word
+func wordRuneBefore(text string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(text[:i])
+	return size > 0 && isWordRune(r)
+}
+
+// wordRuneAt reports whether the rune starting at i is part of a word
+func wordRuneAt(text string, i int) bool {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	return size > 0 && isWordRune(r)
+}
+
+// isWordRune reports whether r is a letter or digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
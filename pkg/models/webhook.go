@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDelivery records one attempt to deliver a payload to the outbound
+// webhook, for debugging failed deliveries and redelivering them
+type WebhookDelivery struct {
+	ID          string          `json:"id"`
+	DeliveredAt time.Time       `json:"delivered_at"`
+	DurationMs  int64           `json:"duration_ms"`
+	StatusCode  int             `json:"status_code,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Response    string          `json:"response,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+
+	// RedeliveryOf is the ID of the delivery this one repeated, if any
+	RedeliveryOf string `json:"redelivery_of,omitempty"`
+}
@@ -0,0 +1,59 @@
+// Package webhook lets receivers of MAVT's outbound webhooks check that a
+// delivery was sent by MAVT and not tampered with
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SignatureHeader is the header carrying a delivery's signature
+const SignatureHeader = "X-MAVT-Signature"
+
+// signaturePrefix names the signature algorithm, as in GitHub's webhooks
+const signaturePrefix = "sha256="
+
+// maxBodySize limits how much of a request body VerifyRequest reads
+const maxBodySize = 10 << 20
+
+// ErrInvalidSignature is returned by VerifyRequest when a delivery is unsigned
+// or its signature doesn't match the body
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the signature of a webhook body: "sha256=" followed by the
+// hex-encoded HMAC-SHA256 of the body keyed with the webhook secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature of body for secret,
+// comparing in constant time
+func Verify(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, body)))
+}
+
+// VerifyRequest reads a webhook delivery's body and checks its signature
+// header. It returns the body, or ErrInvalidSignature if the signature is
+// missing or wrong.
+func VerifyRequest(r *http.Request, secret string) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook body: %w", err)
+	}
+
+	if !Verify(secret, body, r.Header.Get(SignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+	return body, nil
+}